@@ -3,6 +3,7 @@ module github.com/dotandev/hintents
 go 1.24.0
 
 require (
+	github.com/atotto/clipboard v0.1.4
 	github.com/getsentry/sentry-go v0.31.1
 	github.com/google/pprof v0.0.0-20250317173921-a4b03ec1a45e
 	github.com/gorilla/rpc v1.2.1
@@ -10,6 +10,7 @@ import (
 
 	"github.com/dotandev/hintents/internal/cache"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +35,9 @@ Caching improves performance and enables offline analysis.
 
 Cache location: ~/.erst/cache (configurable via ERST_CACHE_DIR)
 
+Simulation results are cached separately in ~/.erst/sim_cache.db, keyed by a
+hash of the request and the simulator binary. Pass --no-sim-cache to bypass it.
+
 Available subcommands:
   status  - View cache size and usage statistics
   clean   - Remove old files using LRU strategy
@@ -83,7 +87,15 @@ var cacheStatusCmd = &cobra.Command{
 			fmt.Printf("\n[!]  Cache size exceeds maximum limit. Run 'erst cache clean' to free space.\n")
 		}
 
-		return nil
+		return withExistingSimCache(func(rc *simulator.ResultCache) error {
+			stats, err := rc.Stats()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to read simulation cache: %v", err))
+			}
+			fmt.Printf("\nSimulation results cached: %d\n", stats.Entries)
+			fmt.Printf("Simulation cache size: %s / %s\n", formatBytes(stats.Bytes), formatBytes(stats.MaxBytes))
+			return nil
+		})
 	},
 }
 
@@ -117,7 +129,16 @@ Use --force to skip the confirmation prompt.`,
 			fmt.Println("No files needed to be deleted")
 		}
 
-		return nil
+		return withExistingSimCache(func(rc *simulator.ResultCache) error {
+			removed, err := rc.Clean()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("simulation cache cleanup failed: %v", err))
+			}
+			if removed > 0 {
+				fmt.Printf("Removed %d cached simulation result(s)\n", removed)
+			}
+			return nil
+		})
 	},
 }
 
@@ -137,9 +158,12 @@ var cacheClearCmd = &cobra.Command{
 		cacheDir := getCacheDir()
 
 		// Check if cache exists
+		simCachePath, _ := simulator.DefaultResultCachePath()
 		if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-			fmt.Println("Cache directory does not exist")
-			return nil
+			if _, err := os.Stat(simCachePath); os.IsNotExist(err) {
+				fmt.Println("Cache directory does not exist")
+				return nil
+			}
 		}
 
 		// Get confirmation unless force flag is set
@@ -161,6 +185,14 @@ var cacheClearCmd = &cobra.Command{
 			return errors.WrapValidationError(fmt.Sprintf("failed to clear cache directory: %v", err))
 		}
 
+		err = withExistingSimCache(func(rc *simulator.ResultCache) error {
+			_, err := rc.Clear()
+			return err
+		})
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to clear simulation cache: %v", err))
+		}
+
 		fmt.Println("Cache cleared successfully")
 		return nil
 	},
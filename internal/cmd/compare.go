@@ -14,6 +14,7 @@ import (
 	"github.com/dotandev/hintents/internal/compare"
 	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/gasmodel"
 	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
@@ -34,6 +35,8 @@ var (
 	cmpSimPathFlag   string
 	cmpThemeFlag     string
 	cmpProtoFlag     uint32
+	cmpProtocolsFlag string
+	cmpGasModelFlag  string
 )
 
 // compareCmd implements `erst compare`.
@@ -61,13 +64,26 @@ Examples:
   erst compare <tx-hash> --wasm ./contract.wasm --network testnet --verbose
 
   # Override the protocol version used for both passes
-  erst compare <tx-hash> --wasm ./contract.wasm --protocol-version 22`,
+  erst compare <tx-hash> --wasm ./contract.wasm --protocol-version 22
+
+Protocol matrix:
+  With --protocols the transaction is replayed once per listed protocol
+  version in parallel and every version is diffed against a baseline (the
+  current protocol when listed, otherwise the lowest). --wasm is optional
+  in this mode and, when given, is used for every version.
+
+  erst compare <tx-hash> --protocols 20,21,22
+  erst compare <tx-hash> --protocols all --wasm ./contract.wasm`,
 	Args: cobra.ExactArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if cmpLocalWasmFlag == "" {
+		if cmpProtocolsFlag != "" {
+			if cmpProtoFlag > 0 {
+				return errors.WrapValidationError("--protocols and --protocol-version cannot be combined")
+			}
+		} else if cmpLocalWasmFlag == "" {
 			return errors.WrapValidationError("--wasm flag is required for compare mode")
 		}
-		if _, err := os.Stat(cmpLocalWasmFlag); os.IsNotExist(err) {
+		if _, err := os.Stat(cmpLocalWasmFlag); cmpLocalWasmFlag != "" && os.IsNotExist(err) {
 			return errors.WrapValidationError(fmt.Sprintf("WASM file not found: %s", cmpLocalWasmFlag))
 		}
 		if err := rpc.ValidateTransactionHash(args[0]); err != nil {
@@ -98,11 +114,15 @@ func init() {
 	compareCmd.Flags().BoolVarP(&cmpVerboseFlag, "verbose", "v", false,
 		"Print full simulation JSON for both passes")
 	compareCmd.Flags().StringVar(&cmpSimPathFlag, "sim-path", "",
-		"Path to erst-sim binary (overrides auto-discovery)")
+		"Path to erst-sim binary or registered simulator name (overrides auto-discovery)")
 	compareCmd.Flags().StringVar(&cmpThemeFlag, "theme", "",
 		"Colour theme (default, deuteranopia, protanopia, tritanopia, high-contrast)")
 	compareCmd.Flags().Uint32Var(&cmpProtoFlag, "protocol-version", 0,
 		"Override protocol version for both simulation passes (20, 21, 22, …)")
+	compareCmd.Flags().StringVar(&cmpProtocolsFlag, "protocols", "",
+		"Replay under each listed protocol version (e.g. 20,21,22 or all) and compare them")
+	compareCmd.Flags().StringVar(&cmpGasModelFlag, "gas-model", "",
+		"Gas model JSON file whose costs and limits apply to every pass")
 
 	rootCmd.AddCommand(compareCmd)
 }
@@ -130,9 +150,25 @@ func runCompare(cmd *cobra.Command, cmdArgs []string) error {
 	fmt.Printf("%s  Compare Replay\n", visualizer.Symbol("chart"))
 	fmt.Printf("Transaction : %s\n", txHash)
 	fmt.Printf("Network     : %s\n", cmpNetworkFlag)
-	fmt.Printf("Local WASM  : %s\n", cmpLocalWasmFlag)
+	if cmpLocalWasmFlag != "" {
+		fmt.Printf("Local WASM  : %s\n", cmpLocalWasmFlag)
+	}
 	fmt.Println()
 
+	var matrixVersions []uint32
+	if cmpProtocolsFlag != "" {
+		versions, err := parseProtocolList(cmpProtocolsFlag)
+		if err != nil {
+			return err
+		}
+		matrixVersions = versions
+	}
+
+	gasModel, err := loadGasModelFlag(cmpGasModelFlag)
+	if err != nil {
+		return err
+	}
+
 	// ── Build RPC client ────────────────────────────────────────────────────
 	token := cmpRPCTokenFlag
 	if token == "" {
@@ -190,9 +226,21 @@ func runCompare(cmd *cobra.Command, cmdArgs []string) error {
 	}
 
 	// ── Build simulator runner ───────────────────────────────────────────────
-	runner, err := simulator.NewRunner(cmpSimPathFlag, cmpVerboseFlag)
+	simPath, err := simPathFor(cmpSimPathFlag, cmpProtoFlag)
+	if err != nil {
+		return err
+	}
+	runner, closeRunner, err := newSimRunner(simPath, cmpVerboseFlag, 0)
 	if err != nil {
-		return errors.WrapSimulatorNotFound(err.Error())
+		return err
+	}
+	defer closeRunner()
+
+	if matrixVersions != nil {
+		fmt.Printf("%s Running %d protocol versions in parallel...\n\n", visualizer.Symbol("play"), len(matrixVersions))
+		req := buildSimRequest(txResp, ledgerEntries, &cmpLocalWasmFlag, cmpArgsFlag, gasModel)
+		compare.RenderMatrix(os.Stdout, runProtocolMatrix(ctx, runner, req, matrixVersions))
+		return nil
 	}
 
 	// ── Run two simulation passes in parallel ────────────────────────────────
@@ -200,7 +248,7 @@ func runCompare(cmd *cobra.Command, cmdArgs []string) error {
 	fmt.Printf("   Pass A – local WASM  : %s\n", cmpLocalWasmFlag)
 	fmt.Printf("   Pass B – on-chain WASM: (using network ledger state)\n\n")
 
-	localResult, onChainResult, runErr := runBothPasses(ctx, runner, txResp, ledgerEntries)
+	localResult, onChainResult, runErr := runBothPasses(ctx, runner, txResp, ledgerEntries, gasModel)
 	if runErr != nil {
 		return runErr
 	}
@@ -220,9 +268,10 @@ func runCompare(cmd *cobra.Command, cmdArgs []string) error {
 // runBothPasses executes the local and on-chain simulation concurrently.
 func runBothPasses(
 	ctx context.Context,
-	runner *simulator.Runner,
+	runner simulator.RunnerInterface,
 	txResp *rpc.TransactionResponse,
 	ledgerEntries map[string]string,
+	gasModel *gasmodel.GasModel,
 ) (localResult, onChainResult *simulator.SimulationResponse, err error) {
 	var wg sync.WaitGroup
 	var localErr, onChainErr error
@@ -232,14 +281,14 @@ func runBothPasses(
 	// Pass A – local WASM
 	go func() {
 		defer wg.Done()
-		req := buildSimRequest(txResp, ledgerEntries, &cmpLocalWasmFlag, cmpArgsFlag)
+		req := buildSimRequest(txResp, ledgerEntries, &cmpLocalWasmFlag, cmpArgsFlag, gasModel)
 		localResult, localErr = runner.Run(req)
 	}()
 
 	// Pass B – on-chain (no --wasm flag, uses whatever is in the ledger)
 	go func() {
 		defer wg.Done()
-		req := buildSimRequest(txResp, ledgerEntries, nil, nil)
+		req := buildSimRequest(txResp, ledgerEntries, nil, nil, gasModel)
 		onChainResult, onChainErr = runner.Run(req)
 	}()
 
@@ -254,17 +303,20 @@ func runBothPasses(
 	return localResult, onChainResult, nil
 }
 
-// buildSimRequest constructs a SimulationRequest with optional local WASM override.
+// buildSimRequest constructs a SimulationRequest with optional local WASM
+// and gas model overrides.
 func buildSimRequest(
 	txResp *rpc.TransactionResponse,
 	ledgerEntries map[string]string,
 	wasmPath *string,
 	mockArgs []string,
+	gasModel *gasmodel.GasModel,
 ) *simulator.SimulationRequest {
 	req := &simulator.SimulationRequest{
 		EnvelopeXdr:   txResp.EnvelopeXdr,
 		ResultMetaXdr: txResp.ResultMetaXdr,
 		LedgerEntries: ledgerEntries,
+		GasModel:      gasModel,
 	}
 	if wasmPath != nil && *wasmPath != "" {
 		req.WasmPath = wasmPath
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -54,6 +55,10 @@ var (
 	watchTimeoutFlag   int
 	mockBaseFeeFlag    uint32
 	mockGasPriceFlag   uint64
+	streamFlag         bool
+	simFlag            string
+
+	debugFootprintPreflightFlag bool
 )
 
 // DebugCommand holds dependencies for the debug command
@@ -179,6 +184,26 @@ Local WASM Replay Mode:
   # Compare execution across networks
   erst debug --network testnet --compare-network mainnet <tx-hash>
 
+  # Replay with edited ledger state
+  erst debug <tx-hash> --set "set account GABC... balance to 100 XLM"
+  erst debug <tx-hash> --override ./overrides.erst
+
+  # Replay under every supported protocol version and compare
+  erst debug <tx-hash> --protocol-matrix
+
+  # Simulate under a custom gas model
+  erst debug <tx-hash> --gas-model ./proposed-costs.json
+
+  # Find the exact time a time-locked call starts succeeding
+  erst debug <tx-hash> --sweep-time 2025-01-01T00:00:00Z..2025-02-01T00:00:00Z
+
+  # Bisect over ledger sequences instead
+  erst debug <tx-hash> --sweep-ledger 51000000..51200000
+
+  # Capture a run and reproduce it offline elsewhere
+  erst debug <tx-hash> --record run.cassette.json
+  erst debug <tx-hash> --replay run.cassette.json
+
   # Local WASM replay (no network required)
   erst debug --wasm ./contract.wasm --args "arg1" --args "arg2"
 
@@ -199,7 +224,20 @@ Local WASM Replay Mode:
 			return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash format: %v", err))
 		}
 
-		if !cmd.Flags().Changed("network") {
+		cassette, err := openDebugCassette(debugRecordFlag, debugReplayFlag)
+		if err != nil {
+			return err
+		}
+		debugCassette = cassette
+		if isReplaying(cassette) {
+			// Reuse the recorded endpoints; probing would need the network.
+			if net := cassette.Meta(cassetteMetaNetwork); net != "" && !cmd.Flags().Changed("network") {
+				networkFlag = net
+			}
+			if url := cassette.Meta(cassetteMetaRPCURL); url != "" && !cmd.Flags().Changed("rpc-url") {
+				rpcURLFlag = url
+			}
+		} else if !cmd.Flags().Changed("network") {
 			token := rpcTokenFlag
 			if token == "" {
 				token = os.Getenv("ERST_RPC_TOKEN")
@@ -252,7 +290,7 @@ Local WASM Replay Mode:
 
 		// Local WASM replay mode
 		if wasmPath != "" {
-			return runLocalWasmReplay()
+			return runLocalWasmReplay(cmd.Context())
 		}
 
 		// Network transaction replay mode
@@ -296,6 +334,7 @@ Local WASM Replay Mode:
 			rpc.WithNetwork(rpc.Network(networkFlag)),
 			rpc.WithToken(token),
 		}
+		opts = append(opts, cassetteClientOptions(debugCassette)...)
 
 		if rpcURLFlag != "" {
 			urls := strings.Split(rpcURLFlag, ",")
@@ -304,7 +343,7 @@ Local WASM Replay Mode:
 			}
 			opts = append(opts, rpc.WithAltURLs(urls))
 			horizonURL = urls[0]
-		} else {
+		} else if !isReplaying(debugCassette) {
 			cfg, err := config.Load()
 			if err == nil {
 				if len(cfg.RpcUrls) > 0 {
@@ -332,6 +371,18 @@ Local WASM Replay Mode:
 			fmt.Println("🚫 Cache disabled by --no-cache flag")
 		}
 
+		if debugCassette != nil {
+			// Every request must reach the cassette, so skip the ledger cache.
+			client.CacheEnabled = false
+			if isReplaying(debugCassette) {
+				fmt.Printf("Replaying RPC traffic from %s\n", debugCassette.Path())
+			} else {
+				debugCassette.SetMeta(cassetteMetaNetwork, networkFlag)
+				debugCassette.SetMeta(cassetteMetaRPCURL, strings.Join(client.AltURLs, ","))
+				fmt.Printf("Recording RPC traffic to %s\n", debugCassette.Path())
+			}
+		}
+
 		fmt.Printf("Debugging transaction: %s\n", txHash)
 		fmt.Printf("Primary Network: %s\n", networkFlag)
 		if compareNetworkFlag != "" {
@@ -385,10 +436,15 @@ Local WASM Replay Mode:
 		}
 
 		// Initialize Simulator Runner
-		runner, err := simulator.NewRunnerWithMockTime("", tracingEnabled, mockTimeFlag)
+		simPath, err := simPathFor(simFlag, protocolVersionFlag)
+		if err != nil {
+			return err
+		}
+		runner, closeRunner, err := newSimRunner(simPath, tracingEnabled, mockTimeFlag)
 		if err != nil {
-			return errors.WrapSimulatorNotFound(err.Error())
+			return err
 		}
+		defer closeRunner()
 
 		// Determine timestamps to simulate
 		timestamps := []int64{TimestampFlag}
@@ -400,6 +456,36 @@ Local WASM Replay Mode:
 			}
 		}
 
+		var netSettings *rpc.NetworkSettings
+		if snapshotFlag == "" {
+			netSettings, err = loadNetworkSettings(ctx, client, resp.LedgerSequence)
+			if err != nil {
+				return err
+			}
+			printNetworkSettingsSummary(os.Stdout, netSettings)
+			if r, ok := runner.(*simulator.Runner); ok && netSettings != nil && r.Capabilities != nil && !r.Capabilities.Has(simulator.FeatureBudgetLimits) {
+				fmt.Printf("Warning: %s does not apply the on-chain budget limits; rebuild erst-sim for a faithful replay\n", r.BinaryPath)
+			}
+		}
+		ledgerHeader := loadLedgerHeader(ctx, client, resp.LedgerSequence)
+
+		overrides, err := loadStateOverrides(overrideFileFlag, overrideSetFlags)
+		if err != nil {
+			return err
+		}
+
+		sweep, err := sweepSpecFromFlags()
+		if err != nil {
+			return err
+		}
+		gasModel, err := loadGasModelFlag(gasModelFlag)
+		if err != nil {
+			return err
+		}
+		if protocolMatrixFlag && (sweep != nil || compareNetworkFlag != "" || protocolVersionFlag > 0) {
+			return errors.WrapValidationError("--protocol-matrix cannot be combined with sweeps, --compare-network or --protocol-version")
+		}
+
 		var lastSimResp *simulator.SimulationResponse
 
 		for _, ts := range timestamps {
@@ -433,13 +519,24 @@ Local WASM Replay Mode:
 					}
 				}
 
+				var fetch func(context.Context, []string) (map[string]string, error)
+				if snapshotFlag == "" {
+					fetch = client.GetLedgerEntries
+				}
+				if err := overrides.apply(ctx, os.Stdout, ledgerEntries, fetch, resp.LedgerSequence); err != nil {
+					return err
+				}
+
 				fmt.Printf("Running simulation on %s...\n", networkFlag)
 				simReq := &simulator.SimulationRequest{
 					EnvelopeXdr:     resp.EnvelopeXdr,
 					ResultMetaXdr:   resp.ResultMetaXdr,
 					LedgerEntries:   ledgerEntries,
 					Timestamp:       ts,
+					LedgerSequence:  resp.LedgerSequence,
 					ProtocolVersion: nil,
+					NetworkSettings: netSettings,
+					GasModel:        gasModel,
 				}
 
 				// Apply protocol version override if specified
@@ -450,13 +547,43 @@ Local WASM Replay Mode:
 					simReq.ProtocolVersion = &protocolVersionFlag
 					fmt.Printf("Using protocol version override: %d\n", protocolVersionFlag)
 				}
+				fidelity := simulator.PinLedgerContext(simReq, ledgerHeader, client.GetNetworkPassphrase())
+				if ts == timestamps[0] {
+					printReplayFidelity(os.Stdout, fidelity)
+					if r, ok := runner.(*simulator.Runner); ok && r.Capabilities != nil && !r.Capabilities.Has(simulator.FeatureLedgerInfo) {
+						fmt.Printf("Warning: %s does not apply the ledger context; rebuild erst-sim for a faithful replay\n", r.BinaryPath)
+					}
+				}
 				applySimulationFeeMocks(simReq)
 
-				simResp, err = runner.Run(simReq)
+				if sweep != nil {
+					simResp, err = runSweep(ctx, os.Stdout, runner, simReq, sweep)
+					if err != nil {
+						return err
+					}
+				} else if protocolMatrixFlag {
+					simResp, err = runDebugProtocolMatrix(ctx, runner, simReq)
+					if err != nil {
+						return err
+					}
+				} else if streamFlag {
+					simResp, err = runSimulationStreaming(ctx, runner, simReq)
+				} else {
+					simResp, err = runner.RunContext(ctx, simReq)
+				}
 				if err != nil {
 					return errors.WrapSimulationFailed(err, "")
 				}
 				printSimulationResult(networkFlag, simResp)
+				reportFeeEstimate(os.Stdout, simulator.FeeInputs{
+					EnvelopeXdr:     resp.EnvelopeXdr,
+					ResultMetaXdr:   resp.ResultMetaXdr,
+					LedgerEntries:   ledgerEntries,
+					Response:        simResp,
+					Settings:        feeSettings(netSettings, ledgerEntries, resp.LedgerSequence),
+					BaseFee:         int64(simReq.BaseFee),
+					ProtocolVersion: ledgerProtocolVersion(ledgerHeader),
+				})
 				// Fetch contract bytecode on demand for any contract calls in the trace; cache via RPC client
 				if client != nil && simResp != nil && len(simResp.DiagnosticEvents) > 0 {
 					contractIDs := collectContractIDsFromDiagnosticEvents(simResp.DiagnosticEvents)
@@ -483,14 +610,22 @@ Local WASM Replay Mode:
 							return
 						}
 					}
+					if err := overrides.apply(ctx, os.Stdout, entries, client.GetLedgerEntries, resp.LedgerSequence); err != nil {
+						primaryErr = err
+						return
+					}
 					primaryReq := &simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: resp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
+						EnvelopeXdr:     resp.EnvelopeXdr,
+						ResultMetaXdr:   resp.ResultMetaXdr,
+						LedgerEntries:   entries,
+						Timestamp:       ts,
+						LedgerSequence:  resp.LedgerSequence,
+						NetworkSettings: netSettings,
+						GasModel:        gasModel,
 					}
+					simulator.PinLedgerContext(primaryReq, ledgerHeader, client.GetNetworkPassphrase())
 					applySimulationFeeMocks(primaryReq)
-					primaryResult, primaryErr = runner.Run(primaryReq)
+					primaryResult, primaryErr = runner.RunContext(ctx, primaryReq)
 				}()
 
 				go func() {
@@ -499,12 +634,13 @@ Local WASM Replay Mode:
 						rpc.WithNetwork(rpc.Network(compareNetworkFlag)),
 						rpc.WithToken(rpcTokenFlag),
 					}
+					compareOpts = append(compareOpts, cassetteClientOptions(debugCassette)...)
 					compareClient, clientErr := rpc.NewClient(compareOpts...)
 					if clientErr != nil {
 						compareErr = errors.WrapValidationError(fmt.Sprintf("failed to create compare client: %v", clientErr))
 						return
 					}
-					if noCacheFlag {
+					if noCacheFlag || debugCassette != nil {
 						compareClient.CacheEnabled = false
 					}
 
@@ -522,15 +658,30 @@ Local WASM Replay Mode:
 							return
 						}
 					}
+					if err := overrides.apply(ctx, io.Discard, entries, compareClient.GetLedgerEntries, compareResp.LedgerSequence); err != nil {
+						compareErr = err
+						return
+					}
+
+					compareSettings, settingsErr := loadNetworkSettings(ctx, compareClient, compareResp.LedgerSequence)
+					if settingsErr != nil {
+						compareErr = settingsErr
+						return
+					}
 
 					compareReq := &simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: compareResp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
+						EnvelopeXdr:     resp.EnvelopeXdr,
+						ResultMetaXdr:   compareResp.ResultMetaXdr,
+						LedgerEntries:   entries,
+						Timestamp:       ts,
+						LedgerSequence:  compareResp.LedgerSequence,
+						NetworkSettings: compareSettings,
+						GasModel:        gasModel,
 					}
+					simulator.PinLedgerContext(compareReq,
+						loadLedgerHeader(ctx, compareClient, compareResp.LedgerSequence), compareClient.GetNetworkPassphrase())
 					applySimulationFeeMocks(compareReq)
-					compareResult, compareErr = runner.Run(compareReq)
+					compareResult, compareErr = runner.RunContext(ctx, compareReq)
 				}()
 
 				wg.Wait()
@@ -623,6 +774,13 @@ Local WASM Replay Mode:
 			fmt.Println(report.MermaidFlowchart())
 		}
 
+		// Analysis: Footprint
+		if report, err := analyzeFootprint(ctx, client, resp.EnvelopeXdr, resp.ResultMetaXdr, lastSimResp, debugFootprintPreflightFlag); err != nil {
+			logger.Logger.Warn("Footprint analysis skipped", "error", err)
+		} else if report != nil {
+			printFootprintReport(os.Stdout, report)
+		}
+
 		// Session Management
 		simReq := &simulator.SimulationRequest{
 			EnvelopeXdr:   resp.EnvelopeXdr,
@@ -685,7 +843,7 @@ func runDemoMode(cmdArgs []string) error {
 	return nil
 }
 
-func runLocalWasmReplay() error {
+func runLocalWasmReplay(ctx context.Context) error {
 	fmt.Printf("%s  WARNING: Using Mock State (not mainnet data)\n", visualizer.Warning())
 	fmt.Println()
 
@@ -703,10 +861,15 @@ func runLocalWasmReplay() error {
 	checkLTOWarning(wasmPath)
 
 	// Create simulator runner
-	runner, err := simulator.NewRunner("", tracingEnabled)
+	simPath, err := simPathFor(simFlag, 0)
 	if err != nil {
-		return errors.WrapSimulatorNotFound(err.Error())
+		return err
 	}
+	runner, closeRunner, err := newSimRunner(simPath, tracingEnabled, 0)
+	if err != nil {
+		return err
+	}
+	defer closeRunner()
 
 	// Create simulation request with local WASM
 	req := &simulator.SimulationRequest{
@@ -720,7 +883,7 @@ func runLocalWasmReplay() error {
 
 	// Run simulation
 	fmt.Printf("%s Executing contract locally...\n", visualizer.Symbol("play"))
-	resp, err := runner.Run(req)
+	resp, err := runner.RunContext(ctx, req)
 	if err != nil {
 		fmt.Printf("%s Technical failure: %v\n", visualizer.Error(), err)
 		return err
@@ -1084,6 +1247,21 @@ func init() {
 	debugCmd.Flags().IntVar(&watchTimeoutFlag, "watch-timeout", 30, "Timeout in seconds for watch mode")
 	debugCmd.Flags().Uint32Var(&mockBaseFeeFlag, "mock-base-fee", 0, "Override base fee (stroops) for local fee sufficiency checks")
 	debugCmd.Flags().Uint64Var(&mockGasPriceFlag, "mock-gas-price", 0, "Override gas price multiplier for local fee sufficiency checks")
+	debugCmd.Flags().BoolVar(&streamFlag, "stream", false, "Print events, logs and budget checkpoints as the simulation runs")
+	debugCmd.Flags().StringVar(&simFlag, "sim", "", "Registered simulator name or erst-sim path (see erst sim list)")
+	debugCmd.Flags().StringVar(&overrideFileFlag, "override", "", "Apply ledger state overrides from a JSON file or override script")
+	debugCmd.Flags().StringArrayVar(&overrideSetFlags, "set", nil, "Apply a ledger state override statement (repeatable), e.g. \"set account G... balance to 100 XLM\"")
+	debugCmd.Flags().StringVar(&sweepTimeFlag, "sweep-time", "", "Bisect a timestamp range FROM..TO (Unix seconds or RFC 3339) for the point where the outcome changes")
+	debugCmd.Flags().StringVar(&sweepLedgerFlag, "sweep-ledger", "", "Bisect a ledger sequence range FROM..TO for the point where the outcome changes")
+	debugCmd.Flags().IntVar(&sweepProbesFlag, "sweep-probes", 5, "Evenly spaced probes in the first sweep pass, before bisection")
+	debugCmd.Flags().BoolVar(&protocolMatrixFlag, "protocol-matrix", false, "Simulate under every supported protocol version in parallel and compare the results")
+	debugCmd.Flags().StringVar(&gasModelFlag, "gas-model", "", "Gas model JSON file whose costs and limits override the protocol and network settings")
+	debugCmd.Flags().BoolVar(&staticCalibrationFlag, "static-calibration", false, "Use built-in protocol calibration instead of the network's on-chain config settings")
+	debugCmd.Flags().BoolVar(&allowUpgradedSettingsFlag, "allow-upgraded-settings", false, "Use current network settings even if they were upgraded after the transaction's ledger")
+	debugCmd.Flags().BoolVar(&strictSettingsFlag, "strict-settings", false, "Fail instead of falling back to static calibration when network settings were upgraded after the transaction's ledger")
+	debugCmd.Flags().BoolVar(&debugFootprintPreflightFlag, "footprint-preflight", false, "Take footprint read keys from a Soroban RPC preflight against current ledger state (extra network call)")
+	debugCmd.Flags().StringVar(&debugRecordFlag, "record", "", "Record every RPC and Horizon exchange into a cassette file (auth headers redacted)")
+	debugCmd.Flags().StringVar(&debugReplayFlag, "replay", "", "Serve RPC and Horizon requests from a cassette recorded with --record, without network access")
 
 	rootCmd.AddCommand(debugCmd)
 }
@@ -1112,6 +1290,8 @@ func checkLTOWarning(wasmFilePath string) {
 		}
 		dir = parent
 	}
+}
+
 func displaySourceLocation(loc *simulator.SourceLocation) {
 	fmt.Printf("%s Location: %s:%d:%d\n", visualizer.Symbol("location"), loc.File, loc.Line, loc.Column)
 
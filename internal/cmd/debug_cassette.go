@@ -0,0 +1,52 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// Cassette metadata keys written by `erst debug --record`.
+const (
+	cassetteMetaNetwork = "network"
+	cassetteMetaRPCURL  = "rpc_url"
+)
+
+var (
+	debugRecordFlag string
+	debugReplayFlag string
+
+	// debugCassette is shared by every client of one debug run.
+	debugCassette *rpc.Cassette
+)
+
+// openDebugCassette starts the cassette named by --record or loads the one
+// named by --replay. It returns nil when neither flag is set.
+func openDebugCassette(record, replay string) (*rpc.Cassette, error) {
+	switch {
+	case record != "" && replay != "":
+		return nil, errors.WrapValidationError("--record and --replay cannot be used together")
+	case replay != "":
+		return rpc.LoadCassette(replay)
+	case record != "":
+		return rpc.NewRecordingCassette(record), nil
+	}
+	return nil, nil
+}
+
+// isReplaying reports whether c serves a recorded run.
+func isReplaying(c *rpc.Cassette) bool {
+	return c != nil && c.Mode() == rpc.CassetteReplay
+}
+
+// cassetteClientOptions attaches c to a client. Ledger caching is left to
+// the caller to disable, since a cache hit would leave the request out of the
+// recording.
+func cassetteClientOptions(c *rpc.Cassette) []rpc.ClientOption {
+	if c == nil {
+		return nil
+	}
+	return []rpc.ClientOption{rpc.WithCassette(c)}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDebugCassette(t *testing.T) {
+	c, err := openDebugCassette("", "")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+	assert.Empty(t, cassetteClientOptions(c))
+
+	_, err = openDebugCassette("a.json", "b.json")
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	c, err = openDebugCassette(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.CassetteRecord, c.Mode())
+	assert.False(t, isReplaying(c))
+	assert.Len(t, cassetteClientOptions(c), 1)
+
+	_, err = openDebugCassette("", path)
+	assert.ErrorIs(t, err, errors.ErrValidationFailed, "nothing was recorded yet")
+}
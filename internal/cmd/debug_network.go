@@ -0,0 +1,118 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// staticCalibrationFlag disables fetching CONFIG_SETTING entries and keeps the
+// protocol registry's built-in limits and calibration.
+var staticCalibrationFlag bool
+
+// allowUpgradedSettingsFlag lets a replay use network settings that were
+// upgraded after the transaction's ledger.
+var allowUpgradedSettingsFlag bool
+
+// strictSettingsFlag turns settings upgraded after the transaction's ledger
+// into an error instead of a fallback to static calibration.
+var strictSettingsFlag bool
+
+// loadNetworkSettings fetches the network's CONFIG_SETTING values, which feed
+// the replay's host budget limits, the hash and signature calibration and the
+// fee estimate. RPC only serves current state, so settings upgraded after
+// ledgerSeq are dropped with a warning unless --allow-upgraded-settings is
+// set, or fail the replay with --strict-settings. A failed fetch also falls
+// back to the static protocol calibration.
+func loadNetworkSettings(ctx context.Context, client *rpc.Client, ledgerSeq uint32) (*rpc.NetworkSettings, error) {
+	if staticCalibrationFlag || client == nil {
+		return nil, nil
+	}
+
+	settings, err := client.GetNetworkSettings(ctx, ledgerSeq)
+	if err != nil {
+		logger.Logger.Warn("Failed to fetch network settings, using static calibration", "ledger", ledgerSeq, "error", err)
+		return nil, nil
+	}
+	if err := checkUpgradedSettings(settings, allowUpgradedSettingsFlag); err != nil {
+		if strictSettingsFlag {
+			return nil, err
+		}
+		logger.Logger.Warn("Network settings were upgraded after the transaction's ledger, using static calibration",
+			"ledger", ledgerSeq, "upgraded", strings.Join(settings.UpgradedAfter, ", "))
+		return nil, nil
+	}
+	return settings, nil
+}
+
+// checkUpgradedSettings rejects settings upgraded after the ledger they were
+// requested for, unless allow is set.
+func checkUpgradedSettings(settings *rpc.NetworkSettings, allow bool) error {
+	if settings == nil || len(settings.UpgradedAfter) == 0 || allow {
+		return nil
+	}
+	return errors.WrapValidationError(fmt.Sprintf(
+		"network settings were upgraded after ledger %d (%s), so the current values differ from those the transaction was charged with; "+
+			"re-run without --strict-settings to fall back to static calibration, or with --allow-upgraded-settings to use them anyway",
+		settings.LedgerSequence, strings.Join(settings.UpgradedAfter, ", ")))
+}
+
+// printNetworkSettingsSummary reports which settings a replay uses.
+func printNetworkSettingsSummary(w io.Writer, settings *rpc.NetworkSettings) {
+	if settings == nil {
+		fmt.Fprintln(w, "Calibration: static protocol defaults")
+		return
+	}
+
+	fmt.Fprintf(w, "Calibration: current on-chain network settings for ledger %d (host budget %d instructions, %d bytes of memory)\n",
+		settings.LedgerSequence, settings.TxMaxInstructions, settings.TxMemoryLimit)
+	fmt.Fprintln(w, "  Ledger read, write and size limits are not enforced by the replay")
+	if len(settings.UpgradedAfter) > 0 {
+		fmt.Fprintf(w, "Warning: settings upgraded after ledger %d, values may differ from those charged: %s\n",
+			settings.LedgerSequence, strings.Join(settings.UpgradedAfter, ", "))
+	}
+}
+
+// loadLedgerHeader fetches the header of the ledger the transaction was
+// applied in, so the replay sees the same env.ledger() values. A failure is
+// logged and the replay falls back to guessed values.
+func loadLedgerHeader(ctx context.Context, client *rpc.Client, ledgerSeq uint32) *rpc.LedgerHeaderResponse {
+	if client == nil || ledgerSeq == 0 {
+		return nil
+	}
+
+	header, err := client.GetLedgerHeader(ctx, ledgerSeq)
+	if err != nil {
+		logger.Logger.Warn("Failed to fetch ledger header, ledger context will be guessed", "ledger", ledgerSeq, "error", err)
+		return nil
+	}
+	return header
+}
+
+// printReplayFidelity lists the ledger context a replay runs with and which
+// fields could not be taken from the original ledger.
+func printReplayFidelity(w io.Writer, fidelity *simulator.ReplayFidelity) {
+	fmt.Fprintln(w, "Replay fidelity:")
+	for _, f := range fidelity.Fields {
+		marker := " "
+		if f.Guessed {
+			marker = "?"
+		}
+		fmt.Fprintf(w, "  %s %-18s %s (%s)\n", marker, f.Name, f.Value, f.Source)
+	}
+	if guessed := fidelity.Guessed(); len(guessed) > 0 {
+		fmt.Fprintf(w, "Warning: guessed %s; contracts reading env.ledger() may behave differently than on chain\n",
+			strings.Join(guessed, ", "))
+	} else {
+		fmt.Fprintln(w, "  Ledger context matches the original ledger")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintNetworkSettingsSummary(t *testing.T) {
+	var buf bytes.Buffer
+	printNetworkSettingsSummary(&buf, nil)
+	assert.Contains(t, buf.String(), "static protocol defaults")
+
+	buf.Reset()
+	printNetworkSettingsSummary(&buf, &rpc.NetworkSettings{
+		LedgerSequence:    1000,
+		TxMaxInstructions: 400000000,
+		TxMemoryLimit:     41943040,
+		UpgradedAfter:     []string{"ConfigSettingIdConfigSettingContractComputeV0"},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "current on-chain network settings for ledger 1000")
+	assert.Contains(t, out, "host budget 400000000 instructions, 41943040 bytes of memory")
+	assert.Contains(t, out, "not enforced by the replay")
+	assert.Contains(t, out, "upgraded after ledger 1000")
+}
+
+func TestLoadNetworkSettings_StaticFlag(t *testing.T) {
+	staticCalibrationFlag = true
+	defer func() { staticCalibrationFlag = false }()
+
+	settings, err := loadNetworkSettings(context.Background(), &rpc.Client{}, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+	staticCalibrationFlag = false
+	settings, err = loadNetworkSettings(context.Background(), nil, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+}
+
+func TestCheckUpgradedSettings(t *testing.T) {
+	upgraded := &rpc.NetworkSettings{
+		LedgerSequence: 1000,
+		UpgradedAfter:  []string{"ConfigSettingIdConfigSettingContractComputeV0"},
+	}
+	err := checkUpgradedSettings(upgraded, false)
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+	assert.ErrorContains(t, err, "--allow-upgraded-settings")
+	assert.ErrorContains(t, err, "--strict-settings")
+
+	assert.NoError(t, checkUpgradedSettings(upgraded, true))
+	assert.NoError(t, checkUpgradedSettings(&rpc.NetworkSettings{LedgerSequence: 1000}, false))
+	assert.NoError(t, checkUpgradedSettings(nil, false))
+}
+
+func TestPrintReplayFidelity(t *testing.T) {
+	var buf bytes.Buffer
+	printReplayFidelity(&buf, &simulator.ReplayFidelity{Fields: []simulator.ReplayField{
+		{Name: "sequence", Value: "100", Source: simulator.SourceLedgerHeader},
+		{Name: "base reserve", Value: "5000000 stroops", Source: simulator.SourceDefault, Guessed: true},
+	}})
+	out := buf.String()
+	assert.Contains(t, out, "Replay fidelity:")
+	assert.Contains(t, out, "? base reserve")
+	assert.Contains(t, out, "Warning: guessed base reserve")
+
+	buf.Reset()
+	printReplayFidelity(&buf, &simulator.ReplayFidelity{Fields: []simulator.ReplayField{
+		{Name: "sequence", Value: "100", Source: simulator.SourceLedgerHeader},
+	}})
+	assert.Contains(t, buf.String(), "matches the original ledger")
+}
+
+func TestLoadLedgerHeader_NoClient(t *testing.T) {
+	assert.Nil(t, loadLedgerHeader(context.Background(), nil, 1))
+	assert.Nil(t, loadLedgerHeader(context.Background(), &rpc.Client{}, 0))
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// runSimulationStreaming runs req in streaming mode, printing events, logs and
+// budget checkpoints to stdout as erst-sim produces them. Remote simulators
+// do not stream, so for them the run completes before anything is printed.
+func runSimulationStreaming(ctx context.Context, r simulator.RunnerInterface, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	runner, ok := r.(*simulator.Runner)
+	if !ok {
+		logger.Logger.Warn("Streaming is not supported by the remote simulator; running without it")
+		return r.RunContext(ctx, req)
+	}
+	if runner.Capabilities != nil && !runner.Capabilities.Has(simulator.FeatureStream) {
+		logger.Logger.Warn("erst-sim has no --stream mode; progress will only appear when the run finishes", "binary", runner.BinaryPath)
+	}
+
+	stream, err := runner.RunStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for rec := range stream.Records() {
+		printStreamRecord(os.Stdout, rec)
+	}
+
+	return stream.Wait()
+}
+
+// printStreamRecord writes a one-line progress summary for rec.
+func printStreamRecord(w io.Writer, rec simulator.StreamRecord) {
+	switch rec.Type {
+	case simulator.RecordEvent:
+		if rec.Event == nil {
+			return
+		}
+		contract := "-"
+		if rec.Event.ContractID != nil {
+			contract = *rec.Event.ContractID
+		}
+		fmt.Fprintf(w, "  [event]  %s %s [%s]\n", rec.Event.EventType, contract, strings.Join(rec.Event.Topics, ", "))
+	case simulator.RecordLog:
+		fmt.Fprintf(w, "  [log]    %s\n", rec.Log)
+	case simulator.RecordBudget:
+		if rec.Budget == nil {
+			return
+		}
+		fmt.Fprintf(w, "  [budget] cpu %d/%d, mem %d/%d\n",
+			rec.Budget.CPUInstructions, rec.Budget.CPULimit,
+			rec.Budget.MemoryBytes, rec.Budget.MemoryLimit)
+	}
+}
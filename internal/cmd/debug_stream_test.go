@@ -0,0 +1,34 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintStreamRecord(t *testing.T) {
+	contract := "CABC"
+	var buf bytes.Buffer
+
+	printStreamRecord(&buf, simulator.StreamRecord{
+		Type:  simulator.RecordEvent,
+		Event: &simulator.DiagnosticEvent{EventType: "contract", ContractID: &contract, Topics: []string{"transfer", "from"}},
+	})
+	printStreamRecord(&buf, simulator.StreamRecord{Type: simulator.RecordLog, Log: "host initialised"})
+	printStreamRecord(&buf, simulator.StreamRecord{
+		Type:   simulator.RecordBudget,
+		Budget: &simulator.BudgetUsage{CPUInstructions: 5, CPULimit: 10, MemoryBytes: 1, MemoryLimit: 2},
+	})
+	printStreamRecord(&buf, simulator.StreamRecord{Type: simulator.RecordBudget})
+
+	out := buf.String()
+	assert.Contains(t, out, "[event]  contract CABC [transfer, from]")
+	assert.Contains(t, out, "[log]    host initialised")
+	assert.Contains(t, out, "[budget] cpu 5/10, mem 1/2")
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")))
+}
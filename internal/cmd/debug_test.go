@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"os"
@@ -168,6 +169,10 @@ func (m *MockRunner) Run(req *simulator.SimulationRequest) (*simulator.Simulatio
 	return args.Get(0).(*simulator.SimulationResponse), args.Error(1)
 }
 
+func (m *MockRunner) RunContext(ctx context.Context, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	return m.Run(req)
+}
+
 func TestDebugCommand_Setup(t *testing.T) {
 	// Test that the debugCmd is properly initialized
 	assert.NotNil(t, debugCmd)
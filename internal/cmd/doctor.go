@@ -4,13 +4,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +23,10 @@ type DependencyStatus struct {
 	Version   string
 	Path      string
 	FixHint   string
+	// Details are extra lines shown under an installed dependency.
+	Details []string
+	// Warning flags an installed dependency that cannot be used as is.
+	Warning string
 }
 
 var doctorCmd = &cobra.Command{
@@ -30,7 +37,7 @@ var doctorCmd = &cobra.Command{
 This command verifies:
   - Go installation and version
   - Rust toolchain (cargo, rustc)
-  - Simulator binary (erst-sim)
+  - Simulator binary (erst-sim) and the capabilities it reports
 
 Use this to troubleshoot installation issues or verify your setup.`,
 	Example: `  # Check environment status
@@ -76,6 +83,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		if verbose && dep.Path != "" {
 			fmt.Printf("  Path: %s\n", dep.Path)
 		}
+		for _, line := range dep.Details {
+			fmt.Printf("  %s\n", line)
+		}
+		if dep.Warning != "" {
+			allOK = false
+			fmt.Printf("  \033[33m⚠ %s\033[0m\n", dep.Warning)
+		}
 
 		if !dep.Installed && dep.FixHint != "" {
 			fmt.Printf("  \033[33m→ %s\033[0m\n", dep.FixHint)
@@ -206,6 +220,7 @@ func checkSimulator(verbose bool) DependencyStatus {
 		dep.Installed = true
 		dep.Path = simPath
 		dep.Version = "in PATH"
+		describeSimCapabilities(&dep)
 		return dep
 	}
 
@@ -216,6 +231,7 @@ func checkSimulator(verbose bool) DependencyStatus {
 			dep.Installed = true
 			dep.Path = absPath
 			dep.Version = "local build"
+			describeSimCapabilities(&dep)
 			return dep
 		}
 	}
@@ -223,6 +239,55 @@ func checkSimulator(verbose bool) DependencyStatus {
 	return dep
 }
 
+// describeSimCapabilities runs the capabilities handshake against the
+// simulator found at dep.Path and records the result on dep.
+func describeSimCapabilities(dep *DependencyStatus) {
+	caps, err := simulator.QueryCapabilities(context.Background(), dep.Path)
+	if err != nil {
+		dep.Warning = fmt.Sprintf("Could not query capabilities: %v", err)
+		return
+	}
+	dep.Details = simCapabilityDetails(caps)
+
+	if caps.Version != "" {
+		dep.Version = caps.Version
+	}
+	if err := caps.Compatible(); err != nil {
+		dep.Warning = err.Error()
+	} else if caps.Legacy {
+		dep.Warning = "Simulator predates the capabilities handshake; rebuild it to enable protocol checks"
+	}
+}
+
+func simCapabilityDetails(caps *simulator.Capabilities) []string {
+	if caps.Legacy {
+		return []string{"Capabilities: unknown (legacy build)"}
+	}
+
+	schema := fmt.Sprintf("Schema version: %d", caps.SchemaVersion)
+	if caps.SchemaVersion == simulator.SimSchemaVersion {
+		schema += " (compatible)"
+	} else {
+		schema += fmt.Sprintf(" (erst requires %d)", simulator.SimSchemaVersion)
+	}
+	lines := []string{schema}
+	if caps.HostVersion != "" {
+		lines = append(lines, "soroban-env-host: "+caps.HostVersion)
+	}
+	if protocols := caps.SupportedProtocols(); len(protocols) > 0 {
+		parts := make([]string, len(protocols))
+		for i, p := range protocols {
+			parts[i] = strconv.FormatUint(uint64(p), 10)
+		}
+		lines = append(lines, "Protocols: "+strings.Join(parts, ", "))
+	}
+	features := "none"
+	if len(caps.Features) > 0 {
+		features = strings.Join(caps.Features, ", ")
+	}
+	return append(lines, "Features: "+features)
+}
+
 func init() {
 	rootCmd.AddCommand(doctorCmd)
 	doctorCmd.Flags().BoolP("verbose", "v", false, "Show detailed diagnostic information")
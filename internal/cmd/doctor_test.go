@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
 )
 
 func TestCheckGo(t *testing.T) {
@@ -121,3 +123,31 @@ func TestDoctorCommand(t *testing.T) {
 		t.Error("doctor command should have --verbose flag")
 	}
 }
+
+func TestSimCapabilityDetails(t *testing.T) {
+	details := simCapabilityDetails(&simulator.Capabilities{
+		SchemaVersion: simulator.SimSchemaVersion,
+		HostVersion:   "22.0.0",
+		Protocols:     []uint32{21, 22},
+		Features:      []string{simulator.FeatureFlamegraph},
+	})
+	want := []string{
+		"Schema version: 1 (compatible)",
+		"soroban-env-host: 22.0.0",
+		"Protocols: 21, 22",
+		"Features: flamegraph",
+	}
+	if len(details) != len(want) {
+		t.Fatalf("simCapabilityDetails() = %v, want %v", details, want)
+	}
+	for i := range want {
+		if details[i] != want[i] {
+			t.Errorf("simCapabilityDetails()[%d] = %q, want %q", i, details[i], want[i])
+		}
+	}
+
+	legacy := simCapabilityDetails(&simulator.Capabilities{Legacy: true})
+	if len(legacy) != 1 || legacy[0] != "Capabilities: unknown (legacy build)" {
+		t.Errorf("simCapabilityDetails() for a legacy build = %v", legacy)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
@@ -36,7 +37,8 @@ This command:
   1) Loads a base64-encoded TransactionEnvelope XDR from a local file
   2) Fetches required ledger entries from the configured Soroban RPC
   3) Replays the transaction locally via the Rust simulator
-  4) Prints an estimated required fee based on the observed resource usage
+  4) Prices the observed resource usage with the network's fee settings and
+     compares it with the fee the envelope declares
 
 Example:
   erst dry-run ./tx.xdr --network testnet`,
@@ -125,10 +127,11 @@ func runDryRun(cmd *cobra.Command, args []string) error {
 		return errors.WrapRPCConnectionFailed(err)
 	}
 
-	runner, err := simulator.NewRunner("", false)
+	runner, closeRunner, err := newSimRunner("", false, 0)
 	if err != nil {
-		return errors.WrapSimulatorNotFound(err.Error())
+		return err
 	}
+	defer closeRunner()
 
 	// The current Rust simulator requires a non-empty result_meta_xdr.
 	// For dry-run we don't have it (tx not on-chain), so we use a placeholder.
@@ -147,13 +150,29 @@ func runDryRun(cmd *cobra.Command, args []string) error {
 		return errors.WrapSimulationLogicError("simulator did not return budget usage")
 	}
 
+	fmt.Printf("Budget usage: CPU=%d, MEM=%d\n", resp.BudgetUsage.CPUInstructions, resp.BudgetUsage.MemoryBytes)
+
+	// Price the observed usage with the network's fee configuration when it
+	// is available, otherwise fall back to the budget heuristic.
+	if settings, err := client.GetNetworkSettings(ctx, 0); err == nil {
+		est, err := simulator.EstimateFees(simulator.FeeInputs{
+			EnvelopeXdr:   envXdrB64,
+			LedgerEntries: ledgerEntries,
+			Response:      resp,
+			Settings:      settings,
+		})
+		if err == nil {
+			printFeeEstimate(os.Stdout, est)
+			return nil
+		}
+		logger.Logger.Warn("Fee estimate failed, using budget heuristic", "error", err)
+	}
+
 	est, err := estimateFeeFromBudget(*resp.BudgetUsage)
 	if err != nil {
 		return err
 	}
-
 	fmt.Printf("Estimated required fee (stroops): %d\n", est)
-	fmt.Printf("Budget usage: CPU=%d, MEM=%d\n", resp.BudgetUsage.CPUInstructions, resp.BudgetUsage.MemoryBytes)
 
 	return nil
 }
@@ -0,0 +1,277 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/override"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// eventsLookback is how many ledgers back `erst events` starts when neither
+// --start-ledger nor --cursor is given: about one hour.
+const eventsLookback = 720
+
+var (
+	eventsNetworkFlag      string
+	eventsRPCURLFlag       string
+	eventsRPCTokenFlag     string
+	eventsContractFlags    []string
+	eventsTopicFlags       []string
+	eventsTypeFlag         string
+	eventsStartLedgerFlag  uint32
+	eventsEndLedgerFlag    uint32
+	eventsCursorFlag       string
+	eventsLimitFlag        uint
+	eventsFollowFlag       bool
+	eventsPollIntervalFlag time.Duration
+	eventsJSONFlag         bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Query contract events from Soroban RPC",
+	Long: `Query contract events with Soroban RPC getEvents and print their topics and
+data as readable values.
+
+Topic patterns are comma-separated segments matched against an event's topics
+in order. A segment is a typed literal such as Address(G...) or U32(5), a bare
+word for a symbol, "*" for any single topic, or a trailing "**" for any number
+of remaining topics. --topic may be repeated; an event matching any pattern is
+shown.
+
+Without --start-ledger or --cursor the query starts about an hour back. With
+--follow the command keeps polling for new events until interrupted.`,
+	Example: `  # Transfers emitted by a token contract
+  erst events --contract CABC... --topic transfer,*,*
+
+  # Everything a contract emitted since ledger 500000, as JSON lines
+  erst events --contract CABC... --start-ledger 500000 --json
+
+  # Watch for new events live
+  erst events --contract CABC... --topic transfer,** --follow`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := rpc.EventFilter{Type: eventsTypeFlag, ContractIDs: eventsContractFlags}
+		for _, pattern := range eventsTopicFlags {
+			topic, err := parseTopicPattern(pattern)
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid --topic %q: %v", pattern, err))
+			}
+			filter.Topics = append(filter.Topics, topic)
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(eventsNetworkFlag)),
+			rpc.WithToken(eventsRPCTokenFlag),
+		}
+		if eventsRPCURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(eventsRPCURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		query := rpc.EventsQuery{
+			StartLedger: eventsStartLedgerFlag,
+			EndLedger:   eventsEndLedgerFlag,
+			Filters:     []rpc.EventFilter{filter},
+			Cursor:      eventsCursorFlag,
+			Limit:       eventsLimitFlag,
+		}
+		if query.StartLedger == 0 && query.Cursor == "" {
+			health, err := client.GetHealth(ctx)
+			if err != nil {
+				return errors.WrapRPCConnectionFailed(err)
+			}
+			query.StartLedger = eventsStartLedger(health.Result.LatestLedger, health.Result.OldestLedger, eventsFollowFlag)
+		}
+		if err := query.Validate(); err != nil {
+			return err
+		}
+
+		return streamEvents(ctx, client, query, os.Stdout)
+	},
+}
+
+// eventsStartLedger picks the default start ledger: the latest ledger when
+// following, otherwise eventsLookback ledgers back, clamped to what the RPC
+// node retains.
+func eventsStartLedger(latest, oldest uint32, follow bool) uint32 {
+	if follow {
+		return latest
+	}
+	start := oldest
+	if latest > eventsLookback && latest-eventsLookback > start {
+		start = latest - eventsLookback
+	}
+	if start == 0 {
+		start = 1
+	}
+	return start
+}
+
+// streamEvents pages through the events matching query and writes each one
+// to w. With --follow it keeps polling after the last page.
+func streamEvents(ctx context.Context, client *rpc.Client, query rpc.EventsQuery, w io.Writer) error {
+	for {
+		page, err := client.GetEvents(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, ev := range page.Events {
+			if err := writeEvent(w, ev); err != nil {
+				return err
+			}
+		}
+
+		if page.Cursor != "" {
+			query.Cursor = page.Cursor
+		}
+		limit := query.Limit
+		if limit == 0 {
+			limit = rpc.DefaultEventsLimit
+		}
+		if uint(len(page.Events)) >= limit {
+			continue
+		}
+		if !eventsFollowFlag {
+			if !eventsJSONFlag && query.Cursor != "" {
+				fmt.Fprintf(w, "Latest ledger: %d. Resume with --cursor %s\n", page.LatestLedger, query.Cursor)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventsPollIntervalFlag):
+		}
+	}
+}
+
+// decodedEvent is the --json form of an event.
+type decodedEvent struct {
+	rpc.Event
+	DecodedTopics []string `json:"decoded_topics"`
+	DecodedValue  string   `json:"decoded_value"`
+}
+
+func writeEvent(w io.Writer, ev rpc.Event) error {
+	topics := make([]string, len(ev.Topic))
+	for i, t := range ev.Topic {
+		topics[i] = decoder.FormatScValBase64(t)
+	}
+	value := decoder.FormatScValBase64(ev.Value)
+
+	if eventsJSONFlag {
+		data, err := json.Marshal(decodedEvent{Event: ev, DecodedTopics: topics, DecodedValue: value})
+		if err != nil {
+			return errors.WrapMarshalFailed(err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	status := ""
+	if !ev.InSuccessfulContractCall {
+		status = " (failed call)"
+	}
+	fmt.Fprintf(w, "ledger %d  %s  %s  tx %s%s\n", ev.Ledger, ev.LedgerClosedAt, ev.ContractID, ev.TxHash, status)
+	fmt.Fprintf(w, "  topics: %s\n", strings.Join(topics, ", "))
+	_, err := fmt.Fprintf(w, "  data:   %s\n", value)
+	return err
+}
+
+// parseTopicPattern turns a comma-separated topic pattern into the base64
+// ScVal segments getEvents expects. Wildcards pass through, bare words are
+// symbols and anything else is a typed literal.
+func parseTopicPattern(pattern string) ([]string, error) {
+	var segments []string
+	for _, seg := range splitTopicSegments(pattern) {
+		seg = strings.TrimSpace(seg)
+		switch {
+		case seg == "":
+			return nil, fmt.Errorf("empty topic segment")
+		case seg == rpc.EventTopicWildcard || seg == rpc.EventTopicMultiWildcard:
+			segments = append(segments, seg)
+			continue
+		}
+
+		literal := seg
+		if !strings.Contains(seg, "(") && !strings.EqualFold(seg, "void") {
+			literal = "Symbol(" + seg + ")"
+		}
+		val, err := override.ParseScVal(literal)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := xdr.MarshalBase64(val)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, encoded)
+	}
+	return segments, nil
+}
+
+// splitTopicSegments splits on commas outside parentheses and quotes, so
+// Vec(U32(1), U32(2)) stays one segment.
+func splitTopicSegments(s string) []string {
+	var parts []string
+	depth, start, quoted := 0, 0, false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func init() {
+	eventsCmd.Flags().StringVarP(&eventsNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	eventsCmd.Flags().StringVar(&eventsRPCURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	eventsCmd.Flags().StringVar(&eventsRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	eventsCmd.Flags().StringSliceVar(&eventsContractFlags, "contract", nil, "Contract ID (C...) to show events for; may be repeated")
+	eventsCmd.Flags().StringArrayVar(&eventsTopicFlags, "topic", nil, "Topic pattern, e.g. transfer,*,* ; may be repeated")
+	eventsCmd.Flags().StringVar(&eventsTypeFlag, "type", "", "Event type: contract, system or diagnostic (default all)")
+	eventsCmd.Flags().Uint32Var(&eventsStartLedgerFlag, "start-ledger", 0, "First ledger to search")
+	eventsCmd.Flags().Uint32Var(&eventsEndLedgerFlag, "end-ledger", 0, "Ledger to stop before")
+	eventsCmd.Flags().StringVar(&eventsCursorFlag, "cursor", "", "Resume after the event with this cursor")
+	eventsCmd.Flags().UintVar(&eventsLimitFlag, "limit", rpc.DefaultEventsLimit, "Events fetched per request")
+	eventsCmd.Flags().BoolVarP(&eventsFollowFlag, "follow", "f", false, "Keep polling for new events")
+	eventsCmd.Flags().DurationVar(&eventsPollIntervalFlag, "poll-interval", 5*time.Second, "Delay between polls with --follow")
+	eventsCmd.Flags().BoolVar(&eventsJSONFlag, "json", false, "Print one JSON object per event")
+
+	rootCmd.AddCommand(eventsCmd)
+}
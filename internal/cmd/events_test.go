@@ -0,0 +1,97 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopicPattern(t *testing.T) {
+	topic, err := parseTopicPattern("transfer, *, Vec(U32(1), U32(2)), **")
+	require.NoError(t, err)
+	require.Len(t, topic, 4)
+	assert.Equal(t, "*", topic[1])
+	assert.Equal(t, "**", topic[3])
+
+	var sym xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(topic[0], &sym))
+	require.NotNil(t, sym.Sym)
+	assert.Equal(t, "transfer", string(*sym.Sym))
+
+	var vec xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(topic[2], &vec))
+	assert.Equal(t, xdr.ScValTypeScvVec, vec.Type)
+
+	_, err = parseTopicPattern("transfer,,*")
+	assert.Error(t, err)
+	_, err = parseTopicPattern("U32(x)")
+	assert.Error(t, err)
+}
+
+func TestEventsStartLedger(t *testing.T) {
+	assert.Equal(t, uint32(5000), eventsStartLedger(5000, 100, true))
+	assert.Equal(t, uint32(5000-eventsLookback), eventsStartLedger(5000, 100, false))
+	assert.Equal(t, uint32(4900), eventsStartLedger(5000, 4900, false), "clamped to the retention window")
+	assert.Equal(t, uint32(1), eventsStartLedger(10, 0, false))
+}
+
+func TestStreamEvents(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	topic, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	require.NoError(t, err)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Method == "getLatestLedger" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":9}}`))
+			return
+		}
+		calls++
+		events := []rpc.Event{}
+		if calls == 1 {
+			events = append(events, rpc.Event{Ledger: 7, ContractID: "CABC", TxHash: "deadbeef", Topic: []string{topic}, Value: "AAAAAQ==", InSuccessfulContractCall: true})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  rpc.EventsPage{Events: events, LatestLedger: 9, Cursor: "0000000007-0000000001"},
+		})
+	}))
+	defer server.Close()
+
+	client, err := rpc.NewClient(rpc.WithNetwork(rpc.Testnet), rpc.WithHorizonURL(server.URL), rpc.WithSorobanURL(server.URL))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, streamEvents(context.Background(), client, rpc.EventsQuery{StartLedger: 1, Limit: 1}, &buf))
+	out := buf.String()
+	assert.Equal(t, 2, calls, "a full page is followed by another request")
+	assert.Contains(t, out, "ledger 7")
+	assert.Contains(t, out, "topics: Symbol(transfer)")
+	assert.Contains(t, out, "data:   Void")
+	assert.Contains(t, out, "--cursor 0000000007-0000000001")
+
+	eventsJSONFlag = true
+	defer func() { eventsJSONFlag = false }()
+	calls = 0
+	buf.Reset()
+	require.NoError(t, streamEvents(context.Background(), client, rpc.EventsQuery{StartLedger: 1, Limit: 1}, &buf))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes.SplitN(buf.Bytes(), []byte("\n"), 2)[0], &decoded))
+	assert.Equal(t, []interface{}{"Symbol(transfer)"}, decoded["decoded_topics"])
+}
@@ -126,10 +126,11 @@ func explainFromNetwork(cmd *cobra.Command, txHash string) error {
 		}
 	}
 
-	runner, err := simulator.NewRunner("", false)
+	runner, closeRunner, err := newSimRunner("", false, 0)
 	if err != nil {
 		return fmt.Errorf("failed to initialize simulator: %w", err)
 	}
+	defer closeRunner()
 
 	simResp, err := runner.Run(&simulator.SimulationRequest{
 		EnvelopeXdr:   resp.EnvelopeXdr,
@@ -0,0 +1,89 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// feeSettings returns the fee configuration to price a replay with: the
+// fetched network settings, or the CONFIG_SETTING entries of a snapshot.
+func feeSettings(fetched *rpc.NetworkSettings, entries map[string]string, ledgerSeq uint32) *rpc.NetworkSettings {
+	if fetched != nil {
+		return fetched
+	}
+	if settings, ok := rpc.ParseNetworkSettings(entries, ledgerSeq); ok {
+		return settings
+	}
+	return nil
+}
+
+// ledgerProtocolVersion is the protocol of the fetched ledger header, or zero
+// when the header could not be fetched.
+func ledgerProtocolVersion(header *rpc.LedgerHeaderResponse) uint32 {
+	if header == nil {
+		return 0
+	}
+	return header.ProtocolVersion
+}
+
+// reportFeeEstimate estimates the fee the transaction needed and prints it
+// against the declared fee. --mock-base-fee replaces the ledger's base fee.
+// Classic transactions and replays without fee settings are skipped.
+func reportFeeEstimate(w io.Writer, in simulator.FeeInputs) {
+	if in.Settings == nil {
+		logger.Logger.Debug("Skipping fee estimate: no network fee settings")
+		return
+	}
+	if mockBaseFeeFlag > 0 {
+		in.BaseFee = int64(mockBaseFeeFlag)
+	}
+	est, err := simulator.EstimateFees(in)
+	if err != nil {
+		logger.Logger.Debug("Skipping fee estimate", "error", err)
+		return
+	}
+	printFeeEstimate(w, est)
+}
+
+func printFeeEstimate(w io.Writer, est *simulator.FeeEstimate) {
+	res, fee := est.Resources, est.Fee
+
+	fmt.Fprintf(w, "\nFee Estimate (stroops):\n")
+	fmt.Fprintf(w, "  %-22s %10d  (%d instructions)\n", "Compute", fee.Compute, res.Instructions)
+	fmt.Fprintf(w, "  %-22s %10d  (%d disk read, %d write)\n", "Ledger entry reads", fee.DiskReadEntries, res.DiskReadEntries, res.WriteEntries)
+	fmt.Fprintf(w, "  %-22s %10d  (%d entries)\n", "Ledger entry writes", fee.WriteEntries, res.WriteEntries)
+	fmt.Fprintf(w, "  %-22s %10d  (%d bytes)\n", "Disk read bytes", fee.DiskReadBytes, res.DiskReadBytes)
+	fmt.Fprintf(w, "  %-22s %10d  (%d bytes)\n", "Write bytes", fee.WriteBytes, res.WriteBytes)
+	fmt.Fprintf(w, "  %-22s %10d  (%d bytes)\n", "Historical", fee.Historical, res.TxSizeBytes)
+	fmt.Fprintf(w, "  %-22s %10d  (%d bytes)\n", "Bandwidth", fee.Bandwidth, res.TxSizeBytes)
+	fmt.Fprintf(w, "  %-22s %10d  (%d bytes, refundable)\n", "Contract events", fee.Events, res.ContractEventsBytes)
+	fmt.Fprintf(w, "  %-22s %10d  (non-refundable %d)\n", "Resource fee", fee.Total(), fee.NonRefundable())
+	fmt.Fprintf(w, "  %-22s %10d\n", "Inclusion fee", est.InclusionFee)
+
+	fmt.Fprintf(w, "\n  Declared resourceFee: %d", est.DeclaredResourceFee)
+	if short := est.ResourceFeeShortfall(); short > 0 {
+		fmt.Fprintf(w, "  %s insufficient by %d stroops (recommended: %d)\n",
+			visualizer.Error(), short, est.RecommendedResourceFee())
+	} else {
+		fmt.Fprintf(w, "  %s sufficient\n", visualizer.Success())
+	}
+	fmt.Fprintf(w, "  Declared fee:         %d", est.DeclaredFee)
+	if short := est.FeeShortfall(); short > 0 {
+		fmt.Fprintf(w, "  %s insufficient by %d stroops (recommended: %d)\n",
+			visualizer.Error(), short, est.RecommendedFee())
+	} else {
+		fmt.Fprintf(w, "  %s sufficient\n", visualizer.Success())
+	}
+
+	for _, note := range est.Notes {
+		fmt.Fprintf(w, "  Note: %s\n", note)
+	}
+}
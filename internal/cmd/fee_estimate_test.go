@@ -0,0 +1,68 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintFeeEstimate_Insufficient(t *testing.T) {
+	est := &simulator.FeeEstimate{
+		Resources:           simulator.FeeResources{Instructions: 1000000},
+		Fee:                 simulator.ResourceFee{Compute: 2500, Events: 500},
+		InclusionFee:        100,
+		DeclaredResourceFee: 2000,
+		DeclaredFee:         2100,
+		Notes:               []string{"rent for new or extended entries is not included"},
+	}
+
+	var buf bytes.Buffer
+	printFeeEstimate(&buf, est)
+	out := buf.String()
+	assert.Contains(t, out, "insufficient by 1000 stroops (recommended: 3000)")
+	assert.Contains(t, out, "insufficient by 1000 stroops (recommended: 3100)")
+	assert.Contains(t, out, "(non-refundable 2500)")
+	assert.Contains(t, out, "Note: rent")
+}
+
+func TestPrintFeeEstimate_Sufficient(t *testing.T) {
+	est := &simulator.FeeEstimate{
+		Fee:                 simulator.ResourceFee{Compute: 2500},
+		InclusionFee:        100,
+		DeclaredResourceFee: 5000,
+		DeclaredFee:         5100,
+	}
+
+	var buf bytes.Buffer
+	printFeeEstimate(&buf, est)
+	assert.NotContains(t, buf.String(), "insufficient")
+	assert.Contains(t, buf.String(), "sufficient")
+}
+
+func TestFeeSettings(t *testing.T) {
+	fetched := &rpc.NetworkSettings{FeeTxSize1Kb: 1}
+	assert.Same(t, fetched, feeSettings(fetched, nil, 10))
+
+	setting := xdr.ConfigSettingEntry{
+		ConfigSettingId:   xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+		ContractBandwidth: &xdr.ConfigSettingContractBandwidthV0{TxMaxSizeBytes: 132096, FeeTxSize1Kb: 1624},
+	}
+	entry, err := rpc.EncodeLedgerEntry(xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &setting},
+	})
+	require.NoError(t, err)
+
+	settings := feeSettings(nil, map[string]string{"k": entry}, 10)
+	require.NotNil(t, settings, "snapshots carry their own fee settings")
+	assert.Equal(t, int64(1624), settings.FeeTxSize1Kb)
+
+	assert.Nil(t, feeSettings(nil, map[string]string{}, 10))
+}
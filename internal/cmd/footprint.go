@@ -0,0 +1,184 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/footprint"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	footprintNetworkFlag   string
+	footprintRPCURLFlag    string
+	footprintRPCTokenFlag  string
+	footprintPreflightFlag bool
+)
+
+var footprintCmd = &cobra.Command{
+	Use:   "footprint <transaction-hash>",
+	Short: "Compare a transaction's declared footprint with the ledger keys it accessed",
+	Long: `Compare the footprint declared in a Soroban transaction's SorobanTransactionData
+with the ledger keys it actually read and wrote.
+
+Written keys come from the entries the transaction changed on chain. With
+--preflight, read keys come from a Soroban RPC simulateTransaction preflight.
+The preflight runs against current ledger state, so its reads can differ from
+what the transaction read when it was applied. The report lists:
+  missing              keys accessed but not declared (footprint errors)
+  written read-only    keys declared read-only but written
+  unused               declared keys never accessed (wasted fees)
+  read-write only read read-write keys that could be read-only`,
+	Example: `  erst footprint <tx-hash> --network testnet
+  erst footprint <tx-hash> --network testnet --preflight`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := rpc.ValidateTransactionHash(args[0]); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash: %v", err))
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(footprintNetworkFlag)),
+			rpc.WithToken(footprintRPCTokenFlag),
+		}
+		if footprintRPCURLFlag != "" {
+			opts = append(opts, rpc.WithAltURLs(splitTrimmed(footprintRPCURLFlag)))
+		} else if cfg, err := config.Load(); err == nil {
+			if len(cfg.RpcUrls) > 0 {
+				opts = append(opts, rpc.WithAltURLs(cfg.RpcUrls))
+			} else if cfg.RpcUrl != "" {
+				opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
+			}
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		ctx := cmd.Context()
+		resp, err := client.GetTransaction(ctx, args[0])
+		if err != nil {
+			return errors.WrapRPCConnectionFailed(err)
+		}
+
+		report, err := analyzeFootprint(ctx, client, resp.EnvelopeXdr, resp.ResultMetaXdr, nil, footprintPreflightFlag)
+		if err != nil {
+			return err
+		}
+		if report == nil {
+			fmt.Println("Transaction has no Soroban footprint")
+			return nil
+		}
+		printFootprintReport(os.Stdout, report)
+		return nil
+	},
+}
+
+// analyzeFootprint compares the envelope's declared footprint with the keys
+// the transaction accessed. Access comes from the simulator when it records
+// it, plus the writes in the result meta. When preflight is set and the
+// simulator recorded nothing, reads come from an RPC preflight against
+// current state instead. It returns nil for classic transactions.
+func analyzeFootprint(
+	ctx context.Context,
+	client *rpc.Client,
+	envelopeXdr, resultMetaXdr string,
+	simResp *simulator.SimulationResponse,
+	preflight bool,
+) (*footprint.Report, error) {
+	declared, ok, err := footprint.Declared(envelopeXdr)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	access := &footprint.Access{}
+	switch {
+	case simResp != nil && simResp.LedgerAccess != nil:
+		access.Merge(&footprint.Access{
+			Read:       simResp.LedgerAccess.Read,
+			Written:    simResp.LedgerAccess.Written,
+			ReadsKnown: true,
+			Sources:    []string{"simulator"},
+		})
+	case preflight && client != nil:
+		simulated, err := client.SimulateTransaction(ctx, envelopeXdr)
+		switch {
+		case err != nil:
+			logger.Logger.Warn("RPC preflight failed, footprint reads unknown", "error", err)
+		case simulated.Result.TransactionData == "":
+			logger.Logger.Warn("RPC preflight returned no transaction data, footprint reads unknown")
+		default:
+			recorded, err := footprint.AccessFromTransactionData(simulated.Result.TransactionData)
+			if err != nil {
+				return nil, err
+			}
+			access.Merge(recorded)
+		}
+	}
+
+	if resultMetaXdr != "" {
+		if written, err := footprint.AccessFromMeta(resultMetaXdr); err != nil {
+			logger.Logger.Warn("Could not read writes from result meta", "error", err)
+		} else {
+			access.Merge(written)
+		}
+	}
+
+	if len(access.Sources) == 0 {
+		return nil, errors.WrapSimulationLogicError("no ledger access information available")
+	}
+	return footprint.Analyze(declared, access), nil
+}
+
+func printFootprintReport(w io.Writer, r *footprint.Report) {
+	fmt.Fprintf(w, "\n=== Footprint Analysis ===\n")
+	fmt.Fprintf(w, "Declared: %d read-only, %d read-write (accessed keys from %s)\n",
+		len(r.Declared.ReadOnly), len(r.Declared.ReadWrite), strings.Join(r.Sources, " + "))
+
+	section := func(title, why string, keys []footprint.Key) {
+		if len(keys) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s (%d) - %s:\n", title, len(keys), why)
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s\n", k.Description)
+		}
+	}
+	section(visualizer.Error()+" Missing", "accessed but not declared, the transaction would fail", r.Missing)
+	section(visualizer.Error()+" Written read-only", "declared read-only but written", r.WrittenReadOnly)
+	section(visualizer.Warning()+" Unused read-only", "declared but never accessed, wasted fees", r.UnusedReadOnly)
+	section(visualizer.Warning()+" Unused read-write", "declared but never accessed, wasted fees", r.UnusedReadWrite)
+	section(visualizer.Warning()+" Read-write only read", "could be declared read-only", r.ReadWriteOnlyRead)
+
+	if !r.HasErrors() && !r.HasWaste() {
+		fmt.Fprintf(w, "%s Declared footprint matches the keys accessed\n", visualizer.Success())
+	}
+	if !r.ReadsKnown {
+		fmt.Fprintf(w, "Note: only writes could be observed; unused read-only keys are not reported\n")
+	}
+	if slices.Contains(r.Sources, footprint.SourcePreflight) {
+		fmt.Fprintf(w, "Note: reads come from a current-state preflight, not the replay; they may differ from what the transaction read on chain\n")
+	}
+}
+
+func init() {
+	footprintCmd.Flags().StringVarP(&footprintNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	footprintCmd.Flags().StringVar(&footprintRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL(s), comma-separated")
+	footprintCmd.Flags().StringVar(&footprintRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	footprintCmd.Flags().BoolVar(&footprintPreflightFlag, "preflight", false, "Take read keys from a Soroban RPC preflight against current ledger state")
+
+	rootCmd.AddCommand(footprintCmd)
+}
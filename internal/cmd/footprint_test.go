@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/footprint"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func footprintTestEnvelope(t *testing.T, readWrite []xdr.LedgerKey) string {
+	t.Helper()
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+		Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{},
+		}}},
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadWrite: readWrite}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}})
+	require.NoError(t, err)
+	return b64
+}
+
+func TestAnalyzeFootprint_FromSimulator(t *testing.T) {
+	declared := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{
+		AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+	}}
+	missing := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{
+		AccountId: xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+	}}
+	missingXDR, err := xdr.MarshalBase64(missing)
+	require.NoError(t, err)
+
+	simResp := &simulator.SimulationResponse{LedgerAccess: &simulator.LedgerAccess{Read: []string{missingXDR}}}
+	report, err := analyzeFootprint(context.Background(), nil, footprintTestEnvelope(t, []xdr.LedgerKey{declared}), "", simResp, false)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, []string{"simulator"}, report.Sources)
+	require.Len(t, report.Missing, 1)
+	require.Len(t, report.UnusedReadWrite, 1)
+
+	var buf bytes.Buffer
+	printFootprintReport(&buf, report)
+	out := buf.String()
+	assert.Contains(t, out, "Missing (1)")
+	assert.Contains(t, out, "account GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	assert.Contains(t, out, "Unused read-write (1)")
+}
+
+func TestAnalyzeFootprint_NoAccessInformation(t *testing.T) {
+	_, err := analyzeFootprint(context.Background(), nil, footprintTestEnvelope(t, nil), "", nil, true)
+	assert.Error(t, err)
+}
+
+func TestPrintFootprintReport_LabelsPreflight(t *testing.T) {
+	var buf bytes.Buffer
+	printFootprintReport(&buf, &footprint.Report{ReadsKnown: true, Sources: []string{footprint.SourcePreflight}})
+	out := buf.String()
+	assert.Contains(t, out, "accessed keys from current-state preflight")
+	assert.Contains(t, out, "not the replay")
+}
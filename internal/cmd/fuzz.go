@@ -4,8 +4,12 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
@@ -19,6 +23,11 @@ var (
 	fuzzInputXDR       string
 	fuzzEnableCov      bool
 	fuzzTargetContract string
+	fuzzSimPool        int
+	fuzzCorpusDir      string
+	fuzzStructured     bool
+	fuzzWasmPath       string
+	fuzzGasModel       string
 )
 
 var fuzzCmd = &cobra.Command{
@@ -34,10 +43,25 @@ other issues in contract code.
 Fuzzing can be started with a base XDR input which will be mutated for
 subsequent iterations, or fuzzing can be run on random inputs.
 
+With --corpus, inputs that reach new behaviour (new events, error categories,
+trap sites or resource magnitudes) are kept in the given directory and
+mutated preferentially. Crashes are deduplicated by trap kind and stack
+signature, minimized, and written to <corpus>/crashes. Re-running with the
+same directory resumes the campaign; an interrupted run saves its progress.
+
+With --structured, the InvokeContract arguments of the base envelope are
+decoded and mutated as typed values (integer boundaries, empty and huge
+collections, random addresses and symbols) so inputs keep reaching contract
+code. Argument types are read from the contractspecv0 section of --wasm, or
+inferred from the original arguments when no WASM is given.
+
 Examples:
   erst fuzz --iterations 10000
   erst fuzz --iterations 50000 --workers 8
-  erst fuzz --xdr <hex-encoded-xdr> --iterations 5000`,
+  erst fuzz --xdr <hex-encoded-xdr> --iterations 5000
+  erst fuzz --iterations 50000 --sim-pool 4
+  erst fuzz --xdr <hex-encoded-xdr> --iterations 50000 --corpus ./fuzz-corpus
+  erst fuzz --xdr <hex-encoded-xdr> --structured --wasm contract.wasm --corpus ./fuzz-corpus`,
 	RunE: runFuzz,
 }
 
@@ -59,10 +83,20 @@ func runFuzz(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Target Contract: %s\n", fuzzTargetContract)
 	}
 
-	// Initialize simulator runner
-	runner, err := simulator.NewRunner("", false)
+	// Initialize simulator runner, backed by a worker pool when requested
+	poolSize := resolveSimPoolSize(cmd, fuzzSimPool)
+	if poolSize > 0 {
+		fmt.Printf("  Simulator Pool: %d workers\n", poolSize)
+	}
+	runner, cleanup, err := newBatchRunner(poolSize)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	gasModel, err := loadGasModelFlag(fuzzGasModel)
 	if err != nil {
-		return fmt.Errorf("failed to initialize simulator: %w", err)
+		return err
 	}
 
 	// Create fuzzing configuration
@@ -72,11 +106,24 @@ func runFuzz(cmd *cobra.Command, args []string) error {
 		MaxInputSize:     fuzzMaxSize,
 		EnableCoverage:   fuzzEnableCov,
 		TargetContractID: fuzzTargetContract,
+		GasModel:         gasModel,
 	}
 
 	// Create fuzzing harness
 	harness := simulator.NewFuzzingHarness(runner, config)
 
+	if fuzzStructured {
+		mutator, err := newStructuredMutator()
+		if err != nil {
+			return err
+		}
+		harness.ArgMutator = mutator
+	}
+
+	if fuzzCorpusDir != "" {
+		return runCorpusFuzz(cmd, harness)
+	}
+
 	// If specific XDR is provided, validate and fuzz it
 	if fuzzInputXDR != "" {
 		// Validate it's valid hex
@@ -115,16 +162,7 @@ func runFuzz(cmd *cobra.Command, args []string) error {
 		Args:          []string{},
 	}
 
-	ctx := cmd.Context()
-
-	// Note: This is a simplified version. Production fuzzing would:
-	// 1. Use libFuzzer binaries directly
-	// 2. Have proper corpus management
-	// 3. Integrate with coverage feedback
-
-	_ = ctx // Use context for potential future cancellation
-
-	results, crashingInputs, err := harness.Fuzz(baseInput)
+	results, crashingInputs, err := harness.FuzzContext(cmd.Context(), baseInput)
 	if err != nil {
 		return fmt.Errorf("fuzzing campaign failed: %w", err)
 	}
@@ -160,6 +198,87 @@ func runFuzz(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newStructuredMutator validates the --structured inputs and loads the
+// contract spec from --wasm when given.
+func newStructuredMutator() (*simulator.ScValMutator, error) {
+	if fuzzInputXDR == "" {
+		return nil, fmt.Errorf("--structured requires a base envelope via --xdr")
+	}
+	if fuzzWasmPath == "" {
+		fmt.Println("  Structured Mode: argument types inferred from the base envelope")
+		return simulator.NewScValMutator(nil), nil
+	}
+
+	spec, err := simulator.LoadContractSpec(fuzzWasmPath)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("  Structured Mode: %d functions from %s\n", len(spec.Functions), fuzzWasmPath)
+	return simulator.NewScValMutator(spec), nil
+}
+
+// runCorpusFuzz runs a resumable coverage-guided campaign backed by the
+// --corpus directory.
+func runCorpusFuzz(cmd *cobra.Command, harness *simulator.FuzzingHarness) error {
+	envelope := ""
+	if fuzzInputXDR != "" {
+		raw, err := hex.DecodeString(fuzzInputXDR)
+		if err != nil {
+			return fmt.Errorf("invalid XDR hex encoding: %w", err)
+		}
+		envelope = base64.StdEncoding.EncodeToString(raw)
+	}
+
+	corpus, err := simulator.OpenFuzzCorpus(fuzzCorpusDir)
+	if err != nil {
+		return err
+	}
+	harness.Corpus = corpus
+
+	fmt.Printf("  Corpus: %s\n", fuzzCorpusDir)
+	if corpus.Iterations() > 0 {
+		fmt.Printf("  Resuming: %d inputs, %d features, %d unique crashes, %d previous iterations\n",
+			corpus.Len(), corpus.FeatureCount(), len(corpus.Crashes()), corpus.Iterations())
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	baseInput := &simulator.FuzzerInput{
+		EnvelopeXdr:   envelope,
+		LedgerEntries: make(map[string]string),
+		Args:          []string{},
+	}
+
+	fmt.Println("\nStarting coverage-guided fuzzing campaign...")
+	_, newCrashes, err := harness.FuzzContext(ctx, baseInput)
+	if err != nil {
+		return fmt.Errorf("fuzzing campaign failed: %w", err)
+	}
+	if ctx.Err() != nil {
+		fmt.Println("\nInterrupted - corpus saved, re-run with the same --corpus to resume")
+	}
+
+	fmt.Println("\n" + harness.Summary())
+	fmt.Printf("  Corpus Inputs: %d\n", corpus.Len())
+	fmt.Printf("  Features Reached: %d\n", corpus.FeatureCount())
+
+	crashes := corpus.Crashes()
+	if len(crashes) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%d unique crash(es) in corpus (%d new this run):\n", len(crashes), len(newCrashes))
+	for _, rec := range crashes {
+		fmt.Printf("  %s  %-7s hits=%-4d %s\n", rec.Signature, rec.Status, rec.Hits, rec.Description)
+		fmt.Printf("      %s\n", corpus.CrashPath(rec.Signature))
+	}
+	if len(newCrashes) > 0 {
+		return fmt.Errorf("fuzzing found %d new unique crashes", len(newCrashes))
+	}
+	return nil
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -210,5 +329,40 @@ func init() {
 		"Optional target contract ID to focus fuzzing on",
 	)
 
+	fuzzCmd.Flags().IntVar(
+		&fuzzSimPool,
+		"sim-pool",
+		0,
+		"Number of persistent simulator processes to reuse across iterations (0 disables, default from sim_pool_size config)",
+	)
+
+	fuzzCmd.Flags().StringVar(
+		&fuzzCorpusDir,
+		"corpus",
+		"",
+		"Directory for a persistent, resumable corpus of interesting inputs and minimized crashes",
+	)
+
+	fuzzCmd.Flags().BoolVar(
+		&fuzzStructured,
+		"structured",
+		false,
+		"Mutate InvokeContract arguments as typed ScVals instead of raw bytes",
+	)
+
+	fuzzCmd.Flags().StringVar(
+		&fuzzWasmPath,
+		"wasm",
+		"",
+		"Contract WASM whose contractspecv0 section provides argument types for --structured",
+	)
+
+	fuzzCmd.Flags().StringVar(
+		&fuzzGasModel,
+		"gas-model",
+		"",
+		"Gas model JSON file whose costs and limits apply to every fuzzed simulation",
+	)
+
 	rootCmd.AddCommand(fuzzCmd)
 }
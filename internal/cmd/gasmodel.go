@@ -0,0 +1,269 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gasModelFlag string
+
+	gasModelDiffTxFlag       string
+	gasModelDiffNetworkFlag  string
+	gasModelDiffRPCURLFlag   string
+	gasModelDiffRPCTokenFlag string
+)
+
+var gasModelCmd = &cobra.Command{
+	Use:   "gasmodel",
+	Short: "Work with custom gas model files",
+	Long: `Work with gas model files describing the CPU, host and ledger costs and the
+resource limits of a network.
+
+A gas model is applied to a simulation with --gas-model on debug, compare and
+fuzz. It overrides the protocol's built-in calibration and the network's
+on-chain settings:
+  - max_cpu_insns and max_memory replace the host budget's limits
+  - cpu_costs and host_costs named after a host cost type (e.g.
+    "WasmInsnExec" or "wasm_insn_exec"; "sha256", "keccak256" and "ed25519"
+    are accepted too) replace that cost type's CPU cost model
+  - ledger_costs, max_txn_size, max_ledger_entries and costs with any other
+    name are ignored; they are listed when the model is loaded
+
+Available subcommands:
+  diff  - Show cost and limit changes between two models`,
+	Example: `  # Show what a proposed cost model changes
+  erst gasmodel diff current.json proposed.json
+
+  # ...and how a transaction behaves under each
+  erst gasmodel diff current.json proposed.json --tx <tx-hash> --network testnet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var gasModelDiffCmd = &cobra.Command{
+	Use:   "diff <a.json> <b.json>",
+	Short: "Show cost deltas between two gas models and optionally re-simulate a transaction",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := loadGasModel(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := loadGasModel(args[1])
+		if err != nil {
+			return err
+		}
+
+		printGasModelDiff(os.Stdout, args[0], args[1], gasmodel.Diff(a, b))
+
+		if gasModelDiffTxFlag == "" {
+			return nil
+		}
+		return resimulateUnderGasModels(cmd.Context(), gasModelDiffTxFlag, args[0], a, args[1], b)
+	},
+}
+
+// loadGasModel parses and validates a gas model file.
+func loadGasModel(path string) (*gasmodel.GasModel, error) {
+	model, err := gasmodel.ParseGasModel(path)
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	if result := model.Validate(); !result.Valid {
+		return nil, errors.WrapValidationError(fmt.Sprintf("%s: %s", path, strings.TrimSpace(result.ErrorsAsString())))
+	}
+	return model, nil
+}
+
+// loadGasModelFlag loads the model named by a --gas-model flag, or returns
+// nil when the flag is empty.
+func loadGasModelFlag(path string) (*gasmodel.GasModel, error) {
+	if path == "" {
+		return nil, nil
+	}
+	model, err := loadGasModel(path)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Using gas model %s (version %s, network %s)\n", path, model.Version, model.NetworkID)
+	if ignored := simulator.IgnoredGasModelEntries(model); len(ignored) > 0 {
+		fmt.Printf("Warning: the replay ignores these gas model entries: %s\n", strings.Join(ignored, ", "))
+	}
+	return model, nil
+}
+
+func printGasModelDiff(w io.Writer, nameA, nameB string, d *gasmodel.ModelDiff) {
+	fmt.Fprintf(w, "Gas model diff: %s -> %s\n", nameA, nameB)
+	if d.Empty() {
+		fmt.Fprintf(w, "  No cost or limit changes\n")
+		return
+	}
+
+	if len(d.Costs) > 0 {
+		fmt.Fprintf(w, "\nCosts:\n")
+		fmt.Fprintf(w, "  %-12s  %-28s  %-23s  %-23s  %s\n", "CATEGORY", "NAME", "LINEAR", "CONST", "CHANGE")
+		for _, c := range d.Costs {
+			change := "changed"
+			switch {
+			case c.Old == nil:
+				change = "added"
+			case c.New == nil:
+				change = "removed"
+			}
+			fmt.Fprintf(w, "  %-12s  %-28s  %-23s  %-23s  %s\n",
+				c.Category, c.Name,
+				costTransition(c.Old, c.New, func(g *gasmodel.GasCost) uint64 { return g.Linear }, c.LinearDelta()),
+				costTransition(c.Old, c.New, func(g *gasmodel.GasCost) uint64 { return g.Const }, c.ConstDelta()),
+				change)
+		}
+	}
+
+	if len(d.Limits) > 0 {
+		fmt.Fprintf(w, "\nResource limits:\n")
+		for _, l := range d.Limits {
+			fmt.Fprintf(w, "  %-20s  %s -> %s\n", l.Name, limitString(l.Old), limitString(l.New))
+		}
+	}
+}
+
+func costTransition(old, new *gasmodel.GasCost, field func(*gasmodel.GasCost) uint64, delta int64) string {
+	value := func(g *gasmodel.GasCost) string {
+		if g == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%d", field(g))
+	}
+	if delta == 0 {
+		return value(new)
+	}
+	return fmt.Sprintf("%s->%s (%+d)", value(old), value(new), delta)
+}
+
+func limitString(v uint64) string {
+	if v == 0 {
+		return "unset"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// resimulateUnderGasModels replays txHash once under each model and prints
+// how the results differ.
+func resimulateUnderGasModels(ctx context.Context, txHash, nameA string, a *gasmodel.GasModel, nameB string, b *gasmodel.GasModel) error {
+	if err := rpc.ValidateTransactionHash(txHash); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash: %v", err))
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(gasModelDiffNetworkFlag)),
+		rpc.WithToken(gasModelDiffRPCTokenFlag),
+	}
+	if gasModelDiffRPCURLFlag != "" {
+		opts = append(opts, rpc.WithAltURLs(splitTrimmed(gasModelDiffRPCURLFlag)))
+	} else if cfg, err := config.Load(); err == nil {
+		if len(cfg.RpcUrls) > 0 {
+			opts = append(opts, rpc.WithAltURLs(cfg.RpcUrls))
+		} else if cfg.RpcUrl != "" {
+			opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
+		}
+	}
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	fmt.Printf("\nRe-simulating %s on %s under both models...\n", txHash, gasModelDiffNetworkFlag)
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	entries, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+	if err != nil {
+		logger.Logger.Warn("Falling back to live ledger entry fetch", "error", err)
+		keys, keyErr := extractLedgerKeys(resp.ResultMetaXdr)
+		if keyErr != nil {
+			return errors.WrapUnmarshalFailed(keyErr, "result meta")
+		}
+		if entries, err = client.GetLedgerEntries(ctx, keys); err != nil {
+			return errors.WrapRPCConnectionFailed(err)
+		}
+	}
+
+	runner, closeRunner, err := newSimRunner("", false, 0)
+	if err != nil {
+		return err
+	}
+	defer closeRunner()
+
+	run := func(model *gasmodel.GasModel) (*simulator.SimulationResponse, error) {
+		return runner.RunContext(ctx, &simulator.SimulationRequest{
+			EnvelopeXdr:    resp.EnvelopeXdr,
+			ResultMetaXdr:  resp.ResultMetaXdr,
+			LedgerEntries:  entries,
+			LedgerSequence: resp.LedgerSequence,
+			GasModel:       model,
+		})
+	}
+	resA, err := run(a)
+	if err != nil {
+		return errors.WrapSimulationFailed(err, "")
+	}
+	resB, err := run(b)
+	if err != nil {
+		return errors.WrapSimulationFailed(err, "")
+	}
+
+	printGasModelRuns(os.Stdout, nameA, resA, nameB, resB)
+	return nil
+}
+
+func printGasModelRuns(w io.Writer, nameA string, a *simulator.SimulationResponse, nameB string, b *simulator.SimulationResponse) {
+	d := compare.Diff(b, a)
+
+	fmt.Fprintf(w, "\n  %-10s  %-30s  %-30s\n", "", nameA, nameB)
+	fmt.Fprintf(w, "  %-10s  %-30s  %-30s\n", "status", a.Status, b.Status)
+	if d.BudgetDiff != nil {
+		bd := d.BudgetDiff
+		fmt.Fprintf(w, "  %-10s  %-30d  %-30s\n", "cpu", bd.OnChainCPU, fmt.Sprintf("%d (%+d)", bd.LocalCPU, bd.CPUDelta))
+		fmt.Fprintf(w, "  %-10s  %-30d  %-30s\n", "memory", bd.OnChainMem, fmt.Sprintf("%d (%+d)", bd.LocalMem, bd.MemoryDelta))
+	}
+	if a.Error != "" || b.Error != "" {
+		fmt.Fprintf(w, "  %-10s  %-30s  %-30s\n", "error", a.Error, b.Error)
+	}
+
+	switch {
+	case !d.StatusDiff.Match:
+		fmt.Fprintf(w, "\nOutcome changes between the models\n")
+	case d.HasDivergence:
+		fmt.Fprintf(w, "\nSame outcome, but %d of %d events differ\n", d.DivergentEvents, d.TotalEvents)
+	default:
+		fmt.Fprintf(w, "\nSame outcome and events under both models\n")
+	}
+}
+
+func init() {
+	gasModelDiffCmd.Flags().StringVar(&gasModelDiffTxFlag, "tx", "", "Transaction hash to re-simulate under both models")
+	gasModelDiffCmd.Flags().StringVarP(&gasModelDiffNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	gasModelDiffCmd.Flags().StringVar(&gasModelDiffRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL(s), comma-separated")
+	gasModelDiffCmd.Flags().StringVar(&gasModelDiffRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+
+	gasModelCmd.AddCommand(gasModelDiffCmd)
+	rootCmd.AddCommand(gasModelCmd)
+}
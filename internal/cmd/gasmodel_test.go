@@ -0,0 +1,63 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGasModel(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"version":"1.0","network_id":"n","cpu_costs":[{"name":"sha256","linear":1,"const":2}]}`), 0644))
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"version":"1.0"}`), 0644))
+
+	model, err := loadGasModel(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "n", model.NetworkID)
+
+	_, err = loadGasModel(invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "network_id is required")
+
+	model, err = loadGasModelFlag("")
+	assert.NoError(t, err)
+	assert.Nil(t, model)
+}
+
+func TestPrintGasModelDiff(t *testing.T) {
+	a := &gasmodel.GasModel{CPUCosts: []gasmodel.GasCost{{Name: "sha256", Linear: 30, Const: 4000}}}
+	b := &gasmodel.GasModel{
+		CPUCosts:       []gasmodel.GasCost{{Name: "sha256", Linear: 30, Const: 4500}},
+		ResourceLimits: gasmodel.ResourceLimits{MaxMemory: 8192},
+	}
+
+	var out bytes.Buffer
+	printGasModelDiff(&out, "a.json", "b.json", gasmodel.Diff(a, b))
+	assert.Contains(t, out.String(), "4000->4500 (+500)")
+	assert.Contains(t, out.String(), "max_memory            unset -> 8192")
+
+	out.Reset()
+	printGasModelDiff(&out, "a.json", "a.json", gasmodel.Diff(a, a))
+	assert.Contains(t, out.String(), "No cost or limit changes")
+}
+
+func TestPrintGasModelRuns(t *testing.T) {
+	a := &simulator.SimulationResponse{Status: "success", BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 1000, MemoryBytes: 10}}
+	b := &simulator.SimulationResponse{Status: "error", Error: "Budget, ExceededLimit", BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 1500, MemoryBytes: 10}}
+
+	var out bytes.Buffer
+	printGasModelRuns(&out, "a.json", a, "b.json", b)
+	assert.Contains(t, out.String(), "1500 (+500)")
+	assert.Contains(t, out.String(), "Outcome changes between the models")
+}
@@ -4,12 +4,27 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/override"
+)
+
+var (
+	overrideFileFlag string
+	overrideSetFlags []string
 )
 
 type OverrideData struct {
 	LedgerEntries map[string]string `json:"ledger_entries,omitempty"`
+	// Set holds override statements such as
+	// "set account G... balance to 100 XLM"; see package override.
+	Set []string `json:"set,omitempty"`
 }
 
 func loadOverrideState(path string) (map[string]string, error) {
@@ -25,3 +40,91 @@ func loadOverrideState(path string) (map[string]string, error) {
 
 	return override.LedgerEntries, nil
 }
+
+// stateOverrides are the edits requested with --override and --set.
+type stateOverrides struct {
+	raw        map[string]string
+	statements []override.Statement
+}
+
+func (o *stateOverrides) empty() bool {
+	return o == nil || (len(o.raw) == 0 && len(o.statements) == 0)
+}
+
+// loadStateOverrides reads the override file and --set statements. A JSON
+// file may hold raw ledger_entries and a "set" list; any other file is read
+// as an override script with one statement per line. --set statements run
+// after the file's.
+func loadStateOverrides(path string, sets []string) (*stateOverrides, error) {
+	o := &stateOverrides{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to read override file: %v", err))
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			var od OverrideData
+			if err := json.Unmarshal(data, &od); err != nil {
+				return nil, errors.WrapUnmarshalFailed(err, path)
+			}
+			o.raw = od.LedgerEntries
+			for _, s := range od.Set {
+				stmt, err := override.ParseStatement(s)
+				if err != nil {
+					return nil, errors.WrapValidationError(fmt.Sprintf("%s: %v", path, err))
+				}
+				o.statements = append(o.statements, stmt)
+			}
+		} else {
+			stmts, err := override.Parse(string(data))
+			if err != nil {
+				return nil, errors.WrapValidationError(fmt.Sprintf("%s: %v", path, err))
+			}
+			o.statements = stmts
+		}
+	}
+
+	for _, s := range sets {
+		stmt, err := override.ParseStatement(s)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("--set %q: %v", s, err))
+		}
+		o.statements = append(o.statements, stmt)
+	}
+	return o, nil
+}
+
+// apply merges the raw entries into entries and then runs the statements,
+// printing what each one changed to w.
+func (o *stateOverrides) apply(
+	ctx context.Context,
+	w io.Writer,
+	entries map[string]string,
+	fetch func(ctx context.Context, keys []string) (map[string]string, error),
+	ledgerSeq uint32,
+) error {
+	if o.empty() {
+		return nil
+	}
+
+	for k, v := range o.raw {
+		entries[k] = v
+	}
+	changes, err := override.Apply(ctx, o.statements, entries, override.Options{
+		LedgerSequence: ledgerSeq,
+		Fetch:          fetch,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Applied %d state override(s):\n", len(o.raw)+len(changes))
+	if len(o.raw) > 0 {
+		fmt.Fprintf(w, "  %d raw ledger entries from override file\n", len(o.raw))
+	}
+	for _, c := range changes {
+		fmt.Fprintf(w, "  %s\n", c.Description)
+	}
+	return nil
+}
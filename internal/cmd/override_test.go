@@ -0,0 +1,59 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const overrideTestAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func TestLoadStateOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	script := filepath.Join(dir, "overrides.erst")
+	require.NoError(t, os.WriteFile(script, []byte("# fund the account\nset account "+overrideTestAccount+" balance to 10 XLM\n"), 0644))
+
+	o, err := loadStateOverrides(script, []string{"set account " + overrideTestAccount + " sequence 5"})
+	require.NoError(t, err)
+	require.Len(t, o.statements, 2)
+	assert.Equal(t, 2, o.statements[0].Line)
+
+	jsonFile := filepath.Join(dir, "overrides.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{
+		"ledger_entries": {"a2V5": "dmFsdWU="},
+		"set": ["set account `+overrideTestAccount+` balance 1"]
+	}`), 0644))
+
+	o, err = loadStateOverrides(jsonFile, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a2V5": "dmFsdWU="}, o.raw)
+	require.Len(t, o.statements, 1)
+
+	entries := map[string]string{}
+	var out bytes.Buffer
+	require.NoError(t, o.apply(context.Background(), &out, entries, nil, 100))
+	assert.Len(t, entries, 2)
+	assert.Contains(t, out.String(), "Applied 2 state override(s)")
+	assert.Contains(t, out.String(), "balance 0.0000000 -> 1.0000000 XLM (new entry)")
+}
+
+func TestLoadStateOverrides_Errors(t *testing.T) {
+	_, err := loadStateOverrides("", []string{"set account nope balance 1"})
+	assert.Error(t, err)
+
+	_, err = loadStateOverrides(filepath.Join(t.TempDir(), "missing"), nil)
+	assert.Error(t, err)
+
+	o, err := loadStateOverrides("", nil)
+	require.NoError(t, err)
+	assert.True(t, o.empty())
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var protocolCmd = &cobra.Command{
+	Use:   "protocol",
+	Short: "Inspect supported Soroban protocol versions",
+	Long: `Inspect the protocol versions erst can simulate and how they differ.
+
+The protocol table is embedded in erst and can be extended or overridden by
+placing a registry file at ~/.erst/protocols.json. Entries in that file replace
+built-in protocols with the same version, so a new testnet protocol can be
+simulated before an erst release ships it.
+
+Available subcommands:
+  list  - Show supported protocols and their features
+  diff  - Show feature and limit changes between two protocols`,
+	Example: `  # List supported protocols
+  erst protocol list
+
+  # Show what changed between protocol 21 and 22
+  erst protocol diff 21 22`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// loadUserProtocolRegistry overlays ~/.erst/protocols.json, when present, on
+// the embedded protocol table. An invalid file is reported and ignored.
+func loadUserProtocolRegistry() {
+	path, ok := simulator.UserRegistryPath()
+	if !ok {
+		return
+	}
+	if err := simulator.LoadRegistryOverride(path); err != nil {
+		logger.Logger.Warn("Ignoring invalid protocol registry override", "path", path, "error", err)
+	}
+}
+
+var protocolListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List supported protocol versions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printProtocolList(os.Stdout)
+		return nil
+	},
+}
+
+var protocolDiffCmd = &cobra.Command{
+	Use:   "diff <from> <to>",
+	Short: "Show feature and limit changes between two protocol versions",
+	Example: `  erst protocol diff 21 22
+  erst protocol diff 22 23`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseProtocolArg(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := parseProtocolArg(args[1])
+		if err != nil {
+			return err
+		}
+
+		changes, err := simulator.DiffProtocols(from, to)
+		if err != nil {
+			return err
+		}
+		printProtocolDiff(os.Stdout, from, to, changes)
+		return nil
+	},
+}
+
+func parseProtocolArg(arg string) (uint32, error) {
+	v, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return 0, errors.WrapValidationError(fmt.Sprintf("invalid protocol version %q", arg))
+	}
+	return uint32(v), nil
+}
+
+func printProtocolList(w io.Writer) {
+	fmt.Fprintf(w, "Protocol registry: %s\n\n", simulator.RegistrySource())
+
+	for _, v := range simulator.Supported() {
+		p, err := simulator.Get(v)
+		if err != nil {
+			continue
+		}
+
+		marker := ""
+		if v == simulator.LatestVersion() {
+			marker = " (default)"
+		}
+		fmt.Fprintf(w, "%d  %s%s\n", p.Version, p.Name, marker)
+
+		features := simulator.FlattenFeatures(p.Features)
+		keys := make([]string, 0, len(features))
+		for k := range features {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "    %-36s %v\n", k, features[k])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func printProtocolDiff(w io.Writer, from, to uint32, changes []simulator.FeatureChange) {
+	fmt.Fprintf(w, "Protocol %d -> %d\n", from, to)
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "  No feature or limit changes")
+		return
+	}
+
+	for _, c := range changes {
+		switch c.Kind {
+		case "added":
+			fmt.Fprintf(w, "  + %-36s %v\n", c.Key, c.New)
+		case "removed":
+			fmt.Fprintf(w, "  - %-36s %v\n", c.Key, c.Old)
+		default:
+			fmt.Fprintf(w, "  ~ %-36s %v -> %v\n", c.Key, c.Old, c.New)
+		}
+	}
+}
+
+func init() {
+	protocolCmd.AddCommand(protocolListCmd)
+	protocolCmd.AddCommand(protocolDiffCmd)
+
+	rootCmd.AddCommand(protocolCmd)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+var protocolMatrixFlag bool
+
+// parseProtocolList parses a comma-separated list of protocol versions, or
+// "all" for every version in simulator.Supported(). The result is sorted and
+// free of duplicates.
+func parseProtocolList(s string) ([]uint32, error) {
+	if strings.TrimSpace(s) == "all" {
+		return simulator.Supported(), nil
+	}
+
+	var versions []uint32
+	for _, part := range splitTrimmed(s) {
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid protocol version %q", part))
+		}
+		if err := simulator.Validate(uint32(v)); err != nil {
+			return nil, err
+		}
+		versions = append(versions, uint32(v))
+	}
+	if len(versions) < 2 {
+		return nil, errors.WrapValidationError("a protocol matrix needs at least two versions")
+	}
+	slices.Sort(versions)
+	return slices.Compact(versions), nil
+}
+
+// matrixBaseline picks the version the others are compared against: the
+// registry's current version when it is part of the matrix, otherwise the
+// lowest one.
+func matrixBaseline(versions []uint32) uint32 {
+	if latest := simulator.LatestVersion(); slices.Contains(versions, latest) {
+		return latest
+	}
+	return versions[0]
+}
+
+// runProtocolMatrix simulates base once per protocol version in parallel and
+// diffs the results against the baseline version.
+func runProtocolMatrix(
+	ctx context.Context,
+	runner simulator.RunnerInterface,
+	base *simulator.SimulationRequest,
+	versions []uint32,
+) *compare.Matrix {
+	reqs := make([]*simulator.SimulationRequest, len(versions))
+	for i, v := range versions {
+		req := *base
+		req.ProtocolVersion = &v
+		reqs[i] = &req
+	}
+	// RunBatch only fails when it has to create its own runner.
+	batch, _ := simulator.RunBatch(ctx, reqs, simulator.BatchOptions{Runner: runner, Concurrency: len(reqs)})
+
+	runs := make([]compare.ProtocolRun, len(versions))
+	for i, item := range batch.Items {
+		runs[i] = compare.ProtocolRun{Protocol: versions[i], Response: item.Response, Err: item.Err}
+	}
+	return compare.NewMatrix(matrixBaseline(versions), runs)
+}
+
+// runDebugProtocolMatrix runs the debug request under every supported
+// protocol and returns the baseline response for the analysis that follows.
+func runDebugProtocolMatrix(ctx context.Context, runner simulator.RunnerInterface, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	versions := simulator.Supported()
+	fmt.Printf("Running protocol matrix across %d versions...\n\n", len(versions))
+	matrix := runProtocolMatrix(ctx, runner, req, versions)
+	compare.RenderMatrix(os.Stdout, matrix)
+
+	for _, row := range matrix.Rows {
+		if row.Protocol == matrix.Baseline {
+			if row.Response == nil {
+				return nil, errors.WrapSimulationLogicError(fmt.Sprintf("baseline protocol %d failed: %s", row.Protocol, row.RunError))
+			}
+			return row.Response, nil
+		}
+	}
+	return nil, errors.WrapSimulationLogicError("protocol matrix produced no results")
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProtocolList(t *testing.T) {
+	supported := simulator.Supported()
+	require.GreaterOrEqual(t, len(supported), 2)
+
+	got, err := parseProtocolList("all")
+	require.NoError(t, err)
+	assert.Equal(t, supported, got)
+
+	a, b := supported[0], supported[1]
+	got, err = parseProtocolList(formatUint32s(b, a, b))
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{a, b}, got)
+
+	_, err = parseProtocolList(formatUint32s(a))
+	assert.Error(t, err, "one version is not a matrix")
+	_, err = parseProtocolList("20,abc")
+	assert.Error(t, err)
+	_, err = parseProtocolList(formatUint32s(a, 9999))
+	assert.Error(t, err)
+}
+
+func TestRunProtocolMatrix(t *testing.T) {
+	versions := simulator.Supported()
+	var mu sync.Mutex
+	seen := map[uint32]bool{}
+	runner := &simulator.MockRunner{RunFunc: func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		require.NotNil(t, req.ProtocolVersion)
+		seen[*req.ProtocolVersion] = true
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	}}
+
+	base := &simulator.SimulationRequest{EnvelopeXdr: "AAAA"}
+	m := runProtocolMatrix(context.Background(), runner, base, versions)
+	assert.Len(t, seen, len(versions))
+	assert.Nil(t, base.ProtocolVersion)
+	assert.Equal(t, simulator.LatestVersion(), m.Baseline)
+	assert.False(t, m.HasDivergence())
+}
+
+func formatUint32s(vs ...uint32) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = strconv.FormatUint(uint64(v), 10)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintProtocolList(t *testing.T) {
+	var buf bytes.Buffer
+	printProtocolList(&buf)
+
+	out := buf.String()
+	assert.Contains(t, out, "20  Soroban Protocol 20")
+	assert.Contains(t, out, "22  Soroban Protocol 22 (default)")
+	assert.Contains(t, out, "23  Soroban Protocol 23\n")
+	assert.Contains(t, out, "resource_calibration.sha256_fixed")
+}
+
+func TestPrintProtocolDiff(t *testing.T) {
+	changes, err := simulator.DiffProtocols(21, 22)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	printProtocolDiff(&buf, 21, 22, changes)
+
+	out := buf.String()
+	assert.Contains(t, out, "Protocol 21 -> 22")
+	assert.Regexp(t, `~ max_contract_size\s+65536 -> 131072`, out)
+	assert.Regexp(t, `\+ optimized_storage\s+true`, out)
+
+	buf.Reset()
+	printProtocolDiff(&buf, 22, 22, nil)
+	assert.Contains(t, buf.String(), "No feature or limit changes")
+}
+
+func TestParseProtocolArg(t *testing.T) {
+	v, err := parseProtocolArg("23")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(23), v)
+
+	_, err = parseProtocolArg("latest")
+	assert.Error(t, err)
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	regressBaselineFlag     string
+	regressTxFileFlag       string
+	regressNetworkFlag      string
+	regressRPCURLFlag       string
+	regressWorkersFlag      int
+	regressProtocolFlag     uint32
+	regressCPUToleranceFlag float64
+	regressMemToleranceFlag float64
+	regressJUnitFlag        string
+	regressJSONFlag         string
+)
+
+var regressCmd = &cobra.Command{
+	Use:   "regress",
+	Short: "Record and verify golden simulator baselines",
+	Long: `Record simulator output for a set of transactions and verify later builds
+against it.
+
+'record' fetches each transaction with its ledger state and ledger context,
+simulates it, and stores those inputs together with its status, contract events
+and budget usage in a baseline file. 'verify' re-simulates the recorded inputs
+offline and reports every difference; budget usage may drift within a
+configurable percentage. Results can be written as JUnit XML and JSON for CI
+gating.
+
+Available subcommands:
+  record  - Simulate transactions and write a golden baseline
+  verify  - Re-simulate the recorded inputs and diff against the baseline`,
+	Example: `  # Record a baseline from a list of transaction hashes
+  erst regress record --tx-file txs.txt --baseline testdata/baseline.json
+
+  # Verify a new simulator build, allowing 2% CPU drift
+  erst regress verify --baseline testdata/baseline.json --cpu-tolerance 2 \
+    --junit report.xml --json report.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var regressRecordCmd = &cobra.Command{
+	Use:   "record [tx-hash...]",
+	Short: "Simulate transactions and write a golden baseline",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hashes, err := collectRegressHashes(args, regressTxFileFlag)
+		if err != nil {
+			return err
+		}
+
+		client, err := newRegressClient()
+		if err != nil {
+			return err
+		}
+		harness, closeHarness, err := newRegressHarness(client)
+		if err != nil {
+			return err
+		}
+		defer closeHarness()
+
+		baseline, suite, err := harness.RecordBaseline(cmd.Context(), hashes, regressProtocolOverride())
+		if err != nil {
+			return err
+		}
+		baseline.Network = regressNetworkFlag
+
+		if err := baseline.Save(regressBaselineFlag); err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %d of %d transaction(s) to %s\n", len(baseline.Records), len(hashes), regressBaselineFlag)
+
+		if err := writeRegressReports(suite); err != nil {
+			return err
+		}
+		printRegressFailures(os.Stdout, suite)
+		if suite.ErrorTests > 0 {
+			return errors.WrapSimulationLogicError(fmt.Sprintf("%d transaction(s) could not be recorded", suite.ErrorTests))
+		}
+		return nil
+	},
+}
+
+var regressVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Re-simulate the recorded inputs and diff against the recorded output",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseline, err := simulator.LoadRegressionBaseline(regressBaselineFlag)
+		if err != nil {
+			return err
+		}
+
+		harness, closeHarness, err := newRegressHarness(nil)
+		if err != nil {
+			return err
+		}
+		defer closeHarness()
+
+		tol := simulator.BudgetTolerance{
+			CPUPercent:    regressCPUToleranceFlag,
+			MemoryPercent: regressMemToleranceFlag,
+		}
+		suite, err := harness.VerifyBaseline(cmd.Context(), baseline, tol, regressProtocolOverride())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(suite.Summary())
+		if err := writeRegressReports(suite); err != nil {
+			return err
+		}
+		printRegressFailures(os.Stdout, suite)
+
+		if n := suite.FailedTests + suite.ErrorTests; n > 0 {
+			return errors.WrapSimulationLogicError(fmt.Sprintf("regression verify failed for %d transaction(s)", n))
+		}
+		fmt.Println("\nAll transactions match the baseline.")
+		return nil
+	},
+}
+
+// collectRegressHashes merges hashes given as arguments with those listed in
+// file, one per line. Blank lines and lines starting with '#' are ignored.
+func collectRegressHashes(args []string, file string) ([]string, error) {
+	seen := make(map[string]bool)
+	var hashes []string
+	add := func(h string) {
+		h = strings.TrimSpace(h)
+		if h == "" || strings.HasPrefix(h, "#") || seen[h] {
+			return
+		}
+		seen[h] = true
+		hashes = append(hashes, h)
+	}
+
+	for _, a := range args {
+		add(a)
+	}
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to open tx file: %v", err))
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			add(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to read tx file: %v", err))
+		}
+	}
+
+	if len(hashes) == 0 {
+		return nil, errors.WrapValidationError("no transaction hashes given; pass them as arguments or with --tx-file")
+	}
+	return hashes, nil
+}
+
+// newRegressClient builds the RPC client regress record fetches
+// transactions with.
+func newRegressClient() (*rpc.Client, error) {
+	opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(regressNetworkFlag))}
+	if regressRPCURLFlag != "" {
+		opts = append(opts, rpc.WithAltURLs(splitTrimmed(regressRPCURLFlag)))
+	} else if cfg, err := config.Load(); err == nil {
+		if len(cfg.RpcUrls) > 0 {
+			opts = append(opts, rpc.WithAltURLs(cfg.RpcUrls))
+		} else if cfg.RpcUrl != "" {
+			opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
+		}
+	}
+
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to create RPC client: %v", err))
+	}
+	return client, nil
+}
+
+// newRegressHarness builds the harness for regress record and verify; verify
+// passes a nil client as it never touches the network. The returned function
+// releases the simulator and must always be called.
+func newRegressHarness(client *rpc.Client) (*simulator.RegressionHarness, func(), error) {
+	runner, closeRunner, err := newSimRunner("", false, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	harness := simulator.NewRegressionHarness(runner, client, regressWorkersFlag)
+	harness.Verbose = verbose
+	return harness, closeRunner, nil
+}
+
+func regressProtocolOverride() *uint32 {
+	if regressProtocolFlag == 0 {
+		return nil
+	}
+	v := regressProtocolFlag
+	return &v
+}
+
+func writeRegressReports(suite *simulator.RegressionTestSuite) error {
+	if regressJUnitFlag != "" {
+		if err := writeRegressReport(regressJUnitFlag, func(w io.Writer) error {
+			return suite.WriteJUnit(w, "erst.regress")
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("JUnit report written to %s\n", regressJUnitFlag)
+	}
+	if regressJSONFlag != "" {
+		if err := writeRegressReport(regressJSONFlag, suite.WriteJSON); err != nil {
+			return err
+		}
+		fmt.Printf("JSON report written to %s\n", regressJSONFlag)
+	}
+	return nil
+}
+
+func writeRegressReport(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create report: %v", err))
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printRegressFailures(w io.Writer, suite *simulator.RegressionTestSuite) {
+	failed := suite.FailedResults()
+	if len(failed) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%d transaction(s) did not pass:\n", len(failed))
+	for _, r := range failed {
+		fmt.Fprintf(w, "  [%s] %s: %s\n", strings.ToUpper(r.Status), r.TransactionHash, r.ErrorMessage)
+		for _, d := range r.Diffs {
+			fmt.Fprintf(w, "      %s\n", d)
+		}
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{regressRecordCmd, regressVerifyCmd} {
+		c.Flags().StringVar(&regressBaselineFlag, "baseline", "regress-baseline.json", "Path to the baseline file")
+		c.Flags().IntVar(&regressWorkersFlag, "workers", 4, "Number of parallel simulations")
+		c.Flags().Uint32Var(&regressProtocolFlag, "protocol-version", 0, "Protocol version override for all transactions")
+		c.Flags().StringVar(&regressJUnitFlag, "junit", "", "Write results as JUnit XML to this path")
+		c.Flags().StringVar(&regressJSONFlag, "json", "", "Write results as JSON to this path")
+	}
+	regressRecordCmd.Flags().StringVarP(&regressNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet)")
+	regressRecordCmd.Flags().StringVar(&regressRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated")
+	regressRecordCmd.Flags().StringVar(&regressTxFileFlag, "tx-file", "", "File listing transaction hashes, one per line")
+	regressVerifyCmd.Flags().Float64Var(&regressCPUToleranceFlag, "cpu-tolerance", simulator.DefaultBudgetTolerance.CPUPercent, "Allowed CPU instruction drift in percent")
+	regressVerifyCmd.Flags().Float64Var(&regressMemToleranceFlag, "mem-tolerance", simulator.DefaultBudgetTolerance.MemoryPercent, "Allowed memory usage drift in percent")
+
+	regressCmd.AddCommand(regressRecordCmd)
+	regressCmd.AddCommand(regressVerifyCmd)
+
+	rootCmd.AddCommand(regressCmd)
+}
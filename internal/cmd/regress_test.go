@@ -0,0 +1,25 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectRegressHashes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "txs.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# mainnet failures\nabc\n\n  def  \nabc\n"), 0644))
+
+	hashes, err := collectRegressHashes([]string{"xyz", "def"}, file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"xyz", "def", "abc"}, hashes)
+
+	_, err = collectRegressHashes(nil, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayLedgerNetworkFlag  string
+	replayLedgerRPCURLFlag   string
+	replayLedgerRPCTokenFlag string
+	replayLedgerProtocolFlag uint32
+	replayLedgerStopFlag     bool
+	replayLedgerJSONFlag     bool
+)
+
+var replayLedgerCmd = &cobra.Command{
+	Use:   "replay-ledger <sequence>",
+	Short: "Replay every transaction of a ledger with state threaded between them",
+	Long: `Replay all transactions in a ledger, in apply order, and report where local
+results diverge from the on-chain outcome.
+
+Each transaction is simulated with the ledger state it saw on-chain: entries
+written or removed by earlier transactions in the same ledger are carried into
+the next request, so failures caused by an earlier transaction reproduce.
+Classic (non-Soroban) transactions are not simulated, but their changes are
+still threaded.`,
+	Example: `  # Replay ledger 51234567 on mainnet
+  erst replay-ledger 51234567
+
+  # Stop at the first divergence and emit JSON
+  erst replay-ledger 51234567 --network testnet --stop-on-divergence --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplayLedger,
+}
+
+func runReplayLedger(cmd *cobra.Command, args []string) error {
+	seq, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil || seq == 0 {
+		return errors.WrapValidationError(fmt.Sprintf("invalid ledger sequence %q", args[0]))
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(replayLedgerNetworkFlag)),
+		rpc.WithToken(replayLedgerRPCTokenFlag),
+	}
+	if replayLedgerRPCURLFlag != "" {
+		opts = append(opts, rpc.WithAltURLs(splitTrimmed(replayLedgerRPCURLFlag)))
+	} else if cfg, err := config.Load(); err == nil {
+		if len(cfg.RpcUrls) > 0 {
+			opts = append(opts, rpc.WithAltURLs(cfg.RpcUrls))
+		} else if cfg.RpcUrl != "" {
+			opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
+		}
+	}
+
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	runner, closeRunner, err := newSimRunner("", false, 0)
+	if err != nil {
+		return err
+	}
+	defer closeRunner()
+
+	replayer := simulator.NewLedgerReplayer(runner, client)
+	replayer.StopOnDivergence = replayLedgerStopFlag
+	if replayLedgerProtocolFlag > 0 {
+		if err := simulator.Validate(replayLedgerProtocolFlag); err != nil {
+			return err
+		}
+		v := replayLedgerProtocolFlag
+		replayer.ProtocolVersion = &v
+	}
+
+	if !replayLedgerJSONFlag {
+		fmt.Printf("Replaying ledger %d on %s...\n\n", seq, replayLedgerNetworkFlag)
+	}
+	report, err := replayer.Replay(cmd.Context(), uint32(seq))
+	if err != nil {
+		return err
+	}
+
+	if replayLedgerJSONFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return errors.WrapMarshalFailed(err)
+		}
+	} else {
+		printLedgerReplayReport(os.Stdout, report)
+	}
+
+	if report.Diverged > 0 {
+		return errors.WrapSimulationLogicError(fmt.Sprintf("%d transaction(s) diverged from on-chain results", report.Diverged))
+	}
+	return nil
+}
+
+func printLedgerReplayReport(w io.Writer, report *simulator.LedgerReplayReport) {
+	for _, r := range report.Results {
+		onChain := "failed"
+		if r.OnChainSuccess {
+			onChain = "success"
+		}
+		fmt.Fprintf(w, "  #%-3d %-9s %s  on-chain=%s", r.Index, "["+r.Status+"]", r.TransactionHash, onChain)
+		if r.LocalStatus != "" {
+			fmt.Fprintf(w, " local=%s", r.LocalStatus)
+		}
+		if r.ThreadedEntries > 0 {
+			fmt.Fprintf(w, " threaded=%d", r.ThreadedEntries)
+		}
+		fmt.Fprintln(w)
+		if r.Detail != "" && r.Status != "match" && r.Status != "skipped" {
+			fmt.Fprintf(w, "        %s\n", r.Detail)
+		}
+	}
+
+	fmt.Fprintf(w, "\nLedger %d: %d matched, %d diverged, %d skipped, %d errors\n",
+		report.Sequence, report.Matched, report.Diverged, report.Skipped, report.Errors)
+	if report.FirstDivergence >= 0 {
+		first := report.Results[report.FirstDivergence]
+		fmt.Fprintf(w, "First divergence: #%d %s\n", first.Index, first.TransactionHash)
+	}
+}
+
+func init() {
+	replayLedgerCmd.Flags().StringVarP(&replayLedgerNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	replayLedgerCmd.Flags().StringVar(&replayLedgerRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL(s), comma-separated")
+	replayLedgerCmd.Flags().StringVar(&replayLedgerRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	replayLedgerCmd.Flags().Uint32Var(&replayLedgerProtocolFlag, "protocol-version", 0, "Override the ledger's protocol version")
+	replayLedgerCmd.Flags().BoolVar(&replayLedgerStopFlag, "stop-on-divergence", false, "Stop at the first transaction that diverges")
+	replayLedgerCmd.Flags().BoolVar(&replayLedgerJSONFlag, "json", false, "Output the report as JSON")
+
+	rootCmd.AddCommand(replayLedgerCmd)
+}
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"github.com/dotandev/hintents/internal/localization"
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/updater"
 	"github.com/spf13/cobra"
 )
@@ -45,6 +46,9 @@ Get started with 'erst debug --help' or visit the documentation.`,
 			return err
 		}
 
+		// Apply the user's protocol registry before any command simulates
+		loadUserProtocolRegistry()
+
 		// Check for updates asynchronously (non-blocking)
 		checkForUpdatesAsync()
 
@@ -93,6 +97,20 @@ func init() {
 		"Enable CPU/Memory profiling and generate a flamegraph SVG",
 	)
 
+	rootCmd.PersistentFlags().BoolVar(
+		&NoSimCacheFlag,
+		"no-sim-cache",
+		false,
+		"Always run the simulator instead of reusing cached results",
+	)
+
+	rootCmd.PersistentFlags().Uint64Var(
+		&SimMemoryMBFlag,
+		"sim-memory-mb",
+		simulator.DefaultMemoryLimit>>20,
+		"Address-space limit for the local simulator in MB (Linux only, 0 disables it)",
+	)
+
 	// Register commands
 	rootCmd.AddCommand(statsCmd)
 }
@@ -0,0 +1,196 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	rpcServePortFlag            string
+	rpcServeSnapshotFlags       []string
+	rpcServeSessionsFlag        bool
+	rpcServeNetworkFlag         string
+	rpcServeLedgerFlag          uint32
+	rpcServeProtocolVersionFlag uint32
+)
+
+var rpcServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local Soroban RPC endpoint from snapshots and saved sessions",
+	Long: `Serve a read-only Soroban JSON-RPC endpoint backed by local data, so
+stellar-cli, dashboards or erst itself can be pointed at it for offline
+debugging and CI.
+
+Ledger entries come from --snapshot files (repeatable; later files win) and,
+with --sessions, from the ledger state saved with each debug session.
+Transactions come from the saved sessions of the selected network. Data is
+loaded once at startup.
+
+Methods: ` + strings.Join(rpc.StandInMethods, ", ") + `
+
+Not served: getLedgers, simulateTransaction and the Horizon REST API. Clients
+that need them fail or degrade. Pointed at this endpoint, "erst debug" cannot
+read the original ledger header (the replay context is guessed), falls back
+to static calibration, and cannot run --footprint-preflight.
+
+Example:
+  erst rpc serve --snapshot state.json --sessions --network testnet
+  curl -s http://localhost:8000 -H 'Content-Type: application/json' \
+    -d '{"jsonrpc":"2.0","id":1,"method":"getTransaction","params":{"hash":"<tx-hash>"}}'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(rpcServeSnapshotFlags) == 0 && !rpcServeSessionsFlag {
+			return errors.WrapValidationError("nothing to serve: pass --snapshot and/or --sessions")
+		}
+
+		netCfg, err := rpcServeNetworkConfig(rpcServeNetworkFlag)
+		if err != nil {
+			return err
+		}
+		cfg := rpc.StandInConfig{
+			NetworkPassphrase: netCfg.NetworkPassphrase,
+			ProtocolVersion:   rpcServeProtocolVersionFlag,
+			LatestLedger:      rpcServeLedgerFlag,
+			Entries:           make(map[string]string),
+			Transactions:      make(map[string]*rpc.TransactionResponse),
+		}
+		if cfg.ProtocolVersion == 0 {
+			cfg.ProtocolVersion = simulator.LatestVersion()
+		}
+
+		if rpcServeSessionsFlag {
+			store, err := session.NewStore()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+			}
+			sessions, err := store.List(cmd.Context(), session.DefaultMaxSessions)
+			store.Close()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to list sessions: %v", err))
+			}
+			n := addSessionsToStandIn(&cfg, sessions, rpcServeNetworkFlag)
+			fmt.Printf("Loaded %d of %d saved sessions for %s\n", n, len(sessions), rpcServeNetworkFlag)
+		}
+
+		// Snapshots are applied last so an explicit file overrides the state
+		// captured in sessions.
+		for _, path := range rpcServeSnapshotFlags {
+			snap, err := snapshot.Load(path)
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to load snapshot: %v", err))
+			}
+			for key, value := range snap.ToMap() {
+				cfg.Entries[key] = value
+			}
+			fmt.Printf("Loaded %d ledger entries from %s\n", len(snap.LedgerEntries), path)
+		}
+
+		standIn := rpc.NewStandIn(cfg)
+		srv := &http.Server{Addr: ":" + rpcServePortFlag, Handler: standIn}
+
+		ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		entries, txs := standIn.Stats()
+		fmt.Printf("Serving Soroban RPC on http://localhost:%s (%d ledger entries, %d transactions)\n", rpcServePortFlag, entries, txs)
+
+		select {
+		case err := <-errCh:
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to start server: %v", err))
+			}
+			return nil
+		case <-ctx.Done():
+		}
+		fmt.Println("\nShutting down RPC stand-in...")
+		return srv.Shutdown(context.Background())
+	},
+}
+
+func rpcServeNetworkConfig(network string) (rpc.NetworkConfig, error) {
+	switch rpc.Network(network) {
+	case rpc.Testnet:
+		return rpc.TestnetConfig, nil
+	case rpc.Mainnet:
+		return rpc.MainnetConfig, nil
+	case rpc.Futurenet:
+		return rpc.FuturenetConfig, nil
+	}
+	return rpc.NetworkConfig{}, errors.WrapInvalidNetwork(network)
+}
+
+// addSessionsToStandIn adds the transactions and captured ledger state of
+// the sessions recorded on network to cfg. Entries already in cfg are kept.
+// It returns how many sessions were used.
+func addSessionsToStandIn(cfg *rpc.StandInConfig, sessions []*session.SessionData, network string) int {
+	used := 0
+	for _, s := range sessions {
+		if s.Network != network || s.TxHash == "" {
+			continue
+		}
+		used++
+
+		tx := &rpc.TransactionResponse{
+			Hash:          s.TxHash,
+			EnvelopeXdr:   s.EnvelopeXdr,
+			ResultXdr:     s.ResultXdr,
+			ResultMetaXdr: s.ResultMetaXdr,
+		}
+		var result xdr.TransactionResult
+		if err := xdr.SafeUnmarshalBase64(s.ResultXdr, &result); err == nil {
+			tx.Successful = result.Successful()
+		}
+
+		if req, err := s.ToSimulationRequest(); err == nil {
+			tx.LedgerSequence = req.LedgerSequence
+			for key, value := range req.LedgerEntries {
+				if _, ok := cfg.Entries[key]; !ok {
+					cfg.Entries[key] = value
+				}
+			}
+		} else {
+			logger.Logger.Debug("Session has no simulation request", "id", s.ID, "error", err)
+		}
+
+		// Sessions are listed newest first; keep the newest per hash.
+		if _, ok := cfg.Transactions[s.TxHash]; !ok {
+			cfg.Transactions[s.TxHash] = tx
+		}
+	}
+	return used
+}
+
+func init() {
+	rpcServeCmd.Flags().StringVarP(&rpcServePortFlag, "port", "p", "8000", "Port to listen on")
+	rpcServeCmd.Flags().StringArrayVar(&rpcServeSnapshotFlags, "snapshot", nil, "Snapshot file to serve ledger entries from (repeatable)")
+	rpcServeCmd.Flags().BoolVar(&rpcServeSessionsFlag, "sessions", false, "Serve transactions and ledger state from saved debug sessions")
+	rpcServeCmd.Flags().StringVarP(&rpcServeNetworkFlag, "network", "n", string(rpc.Testnet), "Network to report and to select sessions from (testnet, mainnet, futurenet)")
+	rpcServeCmd.Flags().Uint32Var(&rpcServeLedgerFlag, "ledger", 0, "Latest ledger to report (default: highest ledger among served transactions)")
+	rpcServeCmd.Flags().Uint32Var(&rpcServeProtocolVersionFlag, "protocol-version", 0, "Protocol version to report (default: latest supported)")
+
+	rpcCmd.AddCommand(rpcServeCmd)
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSessionsToStandIn(t *testing.T) {
+	success, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}},
+	})
+	require.NoError(t, err)
+	reqJSON, err := json.Marshal(simulator.SimulationRequest{
+		LedgerSequence: 500,
+		LedgerEntries:  map[string]string{"K1": "FROM_SESSION", "K2": "FROM_SESSION"},
+	})
+	require.NoError(t, err)
+
+	sessions := []*session.SessionData{
+		{ID: "new", Network: "testnet", TxHash: "aa", ResultXdr: success, EnvelopeXdr: "NEW", SimRequestJSON: string(reqJSON)},
+		{ID: "old", Network: "testnet", TxHash: "aa", EnvelopeXdr: "OLD"},
+		{ID: "failed", Network: "testnet", TxHash: "bb", ResultXdr: "not-xdr"},
+		{ID: "other", Network: "mainnet", TxHash: "cc"},
+	}
+	cfg := rpc.StandInConfig{
+		Entries:      map[string]string{"K1": "FROM_SNAPSHOT"},
+		Transactions: map[string]*rpc.TransactionResponse{},
+	}
+
+	assert.Equal(t, 3, addSessionsToStandIn(&cfg, sessions, "testnet"))
+	require.Len(t, cfg.Transactions, 2)
+	assert.Equal(t, "NEW", cfg.Transactions["aa"].EnvelopeXdr, "newest session wins")
+	assert.True(t, cfg.Transactions["aa"].Successful)
+	assert.Equal(t, uint32(500), cfg.Transactions["aa"].LedgerSequence)
+	assert.False(t, cfg.Transactions["bb"].Successful)
+	assert.Equal(t, map[string]string{"K1": "FROM_SNAPSHOT", "K2": "FROM_SESSION"}, cfg.Entries)
+}
+
+func TestRPCServeNetworkConfig(t *testing.T) {
+	cfg, err := rpcServeNetworkConfig("futurenet")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.FuturenetConfig.NetworkPassphrase, cfg.NetworkPassphrase)
+
+	_, err = rpcServeNetworkConfig("localnet")
+	assert.ErrorIs(t, err, errors.ErrInvalidNetwork)
+}
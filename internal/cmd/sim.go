@@ -0,0 +1,219 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simRegisterProtocolsFlag []uint
+	simRegisterLinkFlag      bool
+)
+
+var simCmd = &cobra.Command{
+	Use:   "sim",
+	Short: "Manage registered erst-sim binaries",
+	Long: `Register several erst-sim builds, for example one per soroban-env-host
+version, and switch between them without juggling --sim-path by hand.
+
+Registered simulators live in ~/.erst/sims together with the version and
+protocols each binary reports. debug and compare accept a registered name
+wherever they take a simulator path, and pick a registered simulator that
+supports the requested --protocol-version automatically.
+
+Available subcommands:
+  register  - Register an erst-sim binary under a name
+  list      - Show registered simulators
+  use       - Make a registered simulator the default
+  remove    - Unregister a simulator`,
+	Example: `  # Register two builds and make one the default
+  erst sim register host22 ./target/release/erst-sim
+  erst sim register host23 ~/builds/erst-sim-23
+  erst sim use host23
+
+  # Debug against a specific build
+  erst debug <tx-hash> --sim host22`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var simRegisterCmd = &cobra.Command{
+	Use:   "register <name> <path>",
+	Short: "Register an erst-sim binary under a name",
+	Long: `Register the erst-sim binary at path. The binary is asked for its version
+with --version; the protocols it supports follow from the soroban-env-host it
+embeds. Use --protocols when the binary predates --version.
+
+The binary is copied into ~/.erst/sims so rebuilding the original does not
+change the registered simulator. Pass --link to reference it in place.`,
+	Example: `  erst sim register host22 ./simulator/target/release/erst-sim
+  erst sim register legacy ./old/erst-sim --protocols 20,21 --link`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, path := args[0], args[1]
+
+		info, err := simulator.ProbeSimBinary(cmd.Context(), path)
+		if err != nil {
+			logger.Logger.Warn("Could not read simulator version", "path", path, "error", err)
+		}
+		if len(simRegisterProtocolsFlag) > 0 {
+			info.Protocols = info.Protocols[:0]
+			for _, p := range simRegisterProtocolsFlag {
+				info.Protocols = append(info.Protocols, uint32(p))
+			}
+		}
+
+		reg, err := simulator.LoadSimRegistry()
+		if err != nil {
+			return err
+		}
+		b, err := reg.Register(name, path, info, !simRegisterLinkFlag)
+		if err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Registered %s: %s\n", b.Name, describeSimBinary(b))
+		if len(b.SupportedProtocols()) == 0 {
+			fmt.Println("Supported protocols are unknown; it will only be used when selected by name.")
+		}
+		return nil
+	},
+}
+
+var simListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered simulators",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reg, err := simulator.LoadSimRegistry()
+		if err != nil {
+			return err
+		}
+		printSimRegistry(os.Stdout, reg)
+		return nil
+	},
+}
+
+var simUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a registered simulator the default",
+	Long: `Make a registered simulator the one used when no --sim-path is given.
+ERST_SIM_PATH still takes precedence over the default.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reg, err := simulator.LoadSimRegistry()
+		if err != nil {
+			return err
+		}
+		if err := reg.Use(args[0]); err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Default simulator: %s\n", args[0])
+		return nil
+	},
+}
+
+var simRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Unregister a simulator",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reg, err := simulator.LoadSimRegistry()
+		if err != nil {
+			return err
+		}
+		if err := reg.Remove(args[0]); err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed simulator %s\n", args[0])
+		return nil
+	},
+}
+
+func printSimRegistry(w io.Writer, reg *simulator.SimRegistry) {
+	binaries := reg.List()
+	if len(binaries) == 0 {
+		fmt.Fprintln(w, "No simulators registered. Add one with: erst sim register <name> <path>")
+		return
+	}
+	for _, b := range binaries {
+		marker := "  "
+		if b.Name == reg.Default {
+			marker = "* "
+		}
+		fmt.Fprintf(w, "%s%-16s %s\n", marker, b.Name, describeSimBinary(b))
+		fmt.Fprintf(w, "    %s\n", b.Path)
+	}
+}
+
+func describeSimBinary(b *simulator.SimBinary) string {
+	version := b.Version
+	if version == "" {
+		version = "unknown version"
+	}
+	if b.HostVersion != "" {
+		version += ", soroban-env-host " + b.HostVersion
+	}
+
+	protocols := "unknown"
+	if supported := b.SupportedProtocols(); len(supported) > 0 {
+		parts := make([]string, len(supported))
+		for i, p := range supported {
+			parts[i] = strconv.FormatUint(uint64(p), 10)
+		}
+		protocols = strings.Join(parts, ",")
+	}
+	return fmt.Sprintf("%s (protocols %s)", version, protocols)
+}
+
+// simPathFor resolves the simulator override for commands that accept one:
+// simFlag is a binary path or registered name. Without it, and unless a
+// remote simulator is configured, the registered simulator supporting
+// protocol is picked.
+func simPathFor(simFlag string, protocol uint32) (string, error) {
+	if simFlag == "" && remoteSimulatorConfigured() {
+		return "", nil
+	}
+	path, err := simulator.SelectSimBinary(simFlag, protocol)
+	if err != nil {
+		return "", err
+	}
+	if simFlag == "" && path != "" {
+		logger.Logger.Info("Using registered simulator for protocol", "protocol", protocol, "path", path)
+	}
+	return path, nil
+}
+
+func init() {
+	simRegisterCmd.Flags().UintSliceVar(&simRegisterProtocolsFlag, "protocols", nil, "Protocol versions the binary supports (overrides what it reports)")
+	simRegisterCmd.Flags().BoolVar(&simRegisterLinkFlag, "link", false, "Reference the binary in place instead of copying it")
+
+	simCmd.AddCommand(simRegisterCmd)
+	simCmd.AddCommand(simListCmd)
+	simCmd.AddCommand(simUseCmd)
+	simCmd.AddCommand(simRemoveCmd)
+
+	rootCmd.AddCommand(simCmd)
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// NoSimCacheFlag disables the simulation result cache for the current command.
+var NoSimCacheFlag bool
+
+// openSimCache opens the simulation result cache using the configured size
+// limit. It returns nil if the cache cannot be opened.
+func openSimCache() *simulator.ResultCache {
+	path, err := simulator.DefaultResultCachePath()
+	if err != nil {
+		logger.Logger.Warn("Simulation cache unavailable", "error", err)
+		return nil
+	}
+
+	var maxBytes int64
+	if cfg, err := config.Load(); err == nil && cfg.SimCacheMaxMB > 0 {
+		maxBytes = int64(cfg.SimCacheMaxMB) << 20
+	}
+
+	rc, err := simulator.OpenResultCache(path, maxBytes)
+	if err != nil {
+		logger.Logger.Warn("Simulation cache unavailable", "error", err)
+		return nil
+	}
+	return rc
+}
+
+// attachSimCache enables result caching on runner unless --no-sim-cache is
+// set or runner is not a local Runner. The returned function closes the cache
+// and must always be called.
+func attachSimCache(r simulator.RunnerInterface) func() {
+	runner, ok := r.(*simulator.Runner)
+	if !ok || NoSimCacheFlag {
+		return func() {}
+	}
+	rc := openSimCache()
+	if rc == nil {
+		return func() {}
+	}
+	runner.Cache = rc
+	return func() { _ = rc.Close() }
+}
+
+// withExistingSimCache calls fn with the simulation result cache if one has
+// already been created on disk; it does nothing otherwise.
+func withExistingSimCache(fn func(rc *simulator.ResultCache) error) error {
+	path, err := simulator.DefaultResultCachePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	rc := openSimCache()
+	if rc == nil {
+		return nil
+	}
+	defer rc.Close()
+	return fn(rc)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+// resolveSimPoolSize returns the --sim-pool flag value if it was set on cmd,
+// otherwise the sim_pool_size from the user's configuration.
+func resolveSimPoolSize(cmd *cobra.Command, flagValue int) int {
+	if cmd.Flags().Changed("sim-pool") {
+		return flagValue
+	}
+	if cfg, err := config.Load(); err == nil {
+		return cfg.SimPoolSize
+	}
+	return 0
+}
+
+// SimMemoryMBFlag caps the address space of local erst-sim processes in
+// megabytes; 0 disables the limit.
+var SimMemoryMBFlag uint64
+
+// resolveSimMemoryLimit returns the erst-sim address-space limit in bytes
+// from --sim-memory-mb, or else sim_memory_mb from the user's configuration.
+// It reports false when neither is set or ERST_SIM_MEMORY_MB is, leaving the
+// runner's limit in place.
+func resolveSimMemoryLimit() (uint64, bool) {
+	if rootCmd.PersistentFlags().Changed("sim-memory-mb") {
+		return SimMemoryMBFlag << 20, true
+	}
+	if os.Getenv("ERST_SIM_MEMORY_MB") != "" {
+		return 0, false
+	}
+	if cfg, err := config.Load(); err == nil && cfg.SimMemoryMB > 0 {
+		return uint64(cfg.SimMemoryMB) << 20, true
+	}
+	return 0, false
+}
+
+// newSimRunner creates the runner used for single simulations. When no
+// simPath override is given and simulator_url is configured, simulations are
+// sent to that remote service; otherwise the local erst-sim binary is used.
+// mockTime, when non-zero, overrides the ledger timestamp of every request.
+// Local runners get the result cache attached; the returned cleanup function
+// closes it and must always be called.
+func newSimRunner(simPath string, debug bool, mockTime int64) (simulator.RunnerInterface, func(), error) {
+	if simPath == "" {
+		if cfg, err := config.Load(); err == nil && cfg.SimulatorURL != "" {
+			remote, err := simulator.NewRemoteRunner(cfg.SimulatorURL, cfg.SimulatorToken)
+			if err != nil {
+				return nil, nil, err
+			}
+			remote.MockTime = mockTime
+			if debug {
+				logger.Logger.Debug("Using remote simulator", "url", cfg.SimulatorURL)
+			}
+			return remote, func() {}, nil
+		}
+	}
+
+	runner, err := simulator.NewRunnerWithMockTime(simPath, debug, mockTime)
+	if err != nil {
+		return nil, nil, errors.WrapSimulatorNotFound(err.Error())
+	}
+	if limit, ok := resolveSimMemoryLimit(); ok {
+		runner.MemoryLimit = limit
+	}
+	return runner, attachSimCache(runner), nil
+}
+
+// newBatchRunner creates the runner used by commands that issue many
+// simulations. A positive poolSize keeps that many erst-sim processes alive
+// for the lifetime of the command; otherwise, or when a remote simulator is
+// configured, the runner from newSimRunner is returned. The returned cleanup
+// function must always be called.
+func newBatchRunner(poolSize int) (simulator.RunnerInterface, func(), error) {
+	if poolSize <= 0 || remoteSimulatorConfigured() {
+		runner, closeRunner, err := newSimRunner("", false, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize simulator: %w", err)
+		}
+		return runner, closeRunner, nil
+	}
+
+	cfg := simulator.PoolConfig{Size: poolSize}
+	if limit, ok := resolveSimMemoryLimit(); ok {
+		cfg.MemoryLimit = &limit
+	}
+	pool, err := simulator.NewPool("", false, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start simulator pool: %w", err)
+	}
+	return pool, func() { _ = pool.Close() }, nil
+}
+
+// remoteSimulatorConfigured reports whether simulator_url is set.
+func remoteSimulatorConfigured() bool {
+	cfg, err := config.Load()
+	return err == nil && cfg.SimulatorURL != ""
+}
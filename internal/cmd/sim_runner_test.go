@@ -0,0 +1,63 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSimRunner_AttachesCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ERST_SIMULATOR_URL", "")
+
+	simPath := filepath.Join(t.TempDir(), "erst-sim")
+	require.NoError(t, os.WriteFile(simPath, []byte("#!/bin/sh\n"), 0o755))
+
+	runner, closeRunner, err := newSimRunner(simPath, false, 0)
+	require.NoError(t, err)
+	local, ok := runner.(*simulator.Runner)
+	require.True(t, ok)
+	assert.NotNil(t, local.Cache)
+	closeRunner()
+
+	NoSimCacheFlag = true
+	defer func() { NoSimCacheFlag = false }()
+	runner, closeRunner, err = newSimRunner(simPath, false, 0)
+	require.NoError(t, err)
+	defer closeRunner()
+	assert.Nil(t, runner.(*simulator.Runner).Cache)
+}
+
+func TestNewSimRunner_MemoryLimit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ERST_SIMULATOR_URL", "")
+	t.Setenv("ERST_SIM_MEMORY_MB", "")
+
+	simPath := filepath.Join(t.TempDir(), "erst-sim")
+	require.NoError(t, os.WriteFile(simPath, []byte("#!/bin/sh\n"), 0o755))
+
+	runner, closeRunner, err := newSimRunner(simPath, false, 0)
+	require.NoError(t, err)
+	closeRunner()
+	assert.Equal(t, simulator.DefaultMemoryLimit, runner.(*simulator.Runner).MemoryLimit)
+
+	flag := rootCmd.PersistentFlags().Lookup("sim-memory-mb")
+	require.NoError(t, flag.Value.Set("512"))
+	flag.Changed = true
+	defer func() {
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	}()
+	runner, closeRunner, err = newSimRunner(simPath, false, 0)
+	require.NoError(t, err)
+	defer closeRunner()
+	assert.Equal(t, uint64(512<<20), runner.(*simulator.Runner).MemoryLimit)
+}
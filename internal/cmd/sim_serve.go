@@ -0,0 +1,129 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simServePortFlag      string
+	simServeAuthTokenFlag string
+	simServeSimPathFlag   string
+	simServePoolFlag      int
+)
+
+var simServeCmd = &cobra.Command{
+	Use:   "sim-serve",
+	Short: "Serve the local simulator over HTTP for remote runners",
+	Long: `Expose the local erst-sim binary as a remote simulator service.
+
+Machines without erst-sim can point simulator_url (or ERST_SIMULATOR_URL) at
+this service and every simulation is executed here instead. Run limits come
+from ERST_SIM_TIMEOUT and ERST_SIM_MEMORY_MB as for local simulations.
+
+Without --auth-token the service only listens on 127.0.0.1; with a token it
+listens on all interfaces and rejects requests that do not carry it. Clients
+send contract WASM inline, so requests naming a local wasm_path are refused.
+
+Endpoints:
+  - POST /simulate: run a simulation request
+  - GET  /health:   liveness check
+
+Example:
+  erst sim-serve --port 8090 --auth-token secret123 --pool 4`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner, cleanup, err := newSimServeRunner()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		mux := http.NewServeMux()
+		mux.Handle("/simulate", simulator.NewRemoteHandler(runner, simServeAuthTokenFlag))
+		mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		})
+		srv := &http.Server{Addr: simServeAddr(), Handler: mux}
+
+		ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		fmt.Printf("Serving simulator on %s\n", srv.Addr)
+		if simServeAuthTokenFlag != "" {
+			fmt.Println("Authentication: enabled")
+		} else {
+			fmt.Println("Authentication: disabled, accepting local connections only (set --auth-token to listen on all interfaces)")
+		}
+
+		select {
+		case err := <-errCh:
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to start server: %v", err))
+			}
+			return nil
+		case <-ctx.Done():
+		}
+		fmt.Println("\nShutting down simulator service...")
+		return srv.Shutdown(context.Background())
+	},
+}
+
+// simServeAddr is the listen address. Without a token anyone who can reach
+// the port could run simulations, so the service then binds to loopback only.
+func simServeAddr() string {
+	if simServeAuthTokenFlag == "" {
+		return "127.0.0.1:" + simServePortFlag
+	}
+	return ":" + simServePortFlag
+}
+
+// newSimServeRunner creates the local runner behind sim-serve. It never
+// resolves to a remote runner, so a configured simulator_url cannot make the
+// service forward to itself.
+func newSimServeRunner() (simulator.RunnerInterface, func(), error) {
+	if simServePoolFlag > 0 {
+		pool, err := simulator.NewPool(simServeSimPathFlag, false, simulator.PoolConfig{Size: simServePoolFlag})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to start simulator pool: %w", err)
+		}
+		return pool, func() { _ = pool.Close() }, nil
+	}
+
+	runner, err := simulator.NewRunner(simServeSimPathFlag, false)
+	if err != nil {
+		return nil, nil, errors.WrapSimulatorNotFound(err.Error())
+	}
+	logger.Logger.Info("Simulator resolved", "path", runner.BinaryPath)
+	return runner, attachSimCache(runner), nil
+}
+
+func init() {
+	simServeCmd.Flags().StringVarP(&simServePortFlag, "port", "p", "8090", "Port to listen on")
+	simServeCmd.Flags().StringVar(&simServeAuthTokenFlag, "auth-token", "", "Bearer token clients must send; required to listen beyond 127.0.0.1")
+	simServeCmd.Flags().StringVar(&simServeSimPathFlag, "sim-path", "", "Path to the erst-sim binary")
+	simServeCmd.Flags().IntVar(&simServePoolFlag, "pool", 0, "Keep this many erst-sim processes warm (0 runs one process per request)")
+
+	rootCmd.AddCommand(simServeCmd)
+}
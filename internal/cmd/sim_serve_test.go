@@ -0,0 +1,21 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimServeAddr_LoopbackWithoutToken(t *testing.T) {
+	oldPort, oldToken := simServePortFlag, simServeAuthTokenFlag
+	t.Cleanup(func() { simServePortFlag, simServeAuthTokenFlag = oldPort, oldToken })
+
+	simServePortFlag, simServeAuthTokenFlag = "8090", ""
+	assert.Equal(t, "127.0.0.1:8090", simServeAddr())
+
+	simServeAuthTokenFlag = "secret"
+	assert.Equal(t, ":8090", simServeAddr())
+}
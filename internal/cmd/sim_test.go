@@ -0,0 +1,34 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintSimRegistry(t *testing.T) {
+	var buf bytes.Buffer
+	printSimRegistry(&buf, &simulator.SimRegistry{})
+	assert.Contains(t, buf.String(), "No simulators registered")
+
+	reg := &simulator.SimRegistry{
+		Default: "host22",
+		Binaries: map[string]*simulator.SimBinary{
+			"host22": {Name: "host22", Path: "/sims/host22/erst-sim", SimBinaryInfo: simulator.SimBinaryInfo{
+				Version: "0.1.0", HostVersion: "22.0.0", Protocols: []uint32{21, 22},
+			}},
+			"legacy": {Name: "legacy", Path: "/opt/erst-sim"},
+		},
+	}
+	buf.Reset()
+	printSimRegistry(&buf, reg)
+	out := buf.String()
+	assert.Contains(t, out, "* host22           0.1.0, soroban-env-host 22.0.0 (protocols 21,22)")
+	assert.Contains(t, out, "  legacy           unknown version (protocols unknown)")
+	assert.Contains(t, out, "/sims/host22/erst-sim")
+}
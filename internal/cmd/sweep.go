@@ -0,0 +1,170 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+var (
+	sweepTimeFlag   string
+	sweepLedgerFlag string
+	sweepProbesFlag int
+)
+
+// sweepSpec is a parsed --sweep-time or --sweep-ledger range.
+type sweepSpec struct {
+	axis     simulator.SweepAxis
+	from, to int64
+}
+
+// sweepSpecFromFlags returns the requested sweep, or nil when none was asked for.
+func sweepSpecFromFlags() (*sweepSpec, error) {
+	switch {
+	case sweepTimeFlag != "" && sweepLedgerFlag != "":
+		return nil, errors.WrapValidationError("--sweep-time and --sweep-ledger cannot be combined")
+	case sweepTimeFlag == "" && sweepLedgerFlag == "":
+		return nil, nil
+	case compareNetworkFlag != "":
+		return nil, errors.WrapValidationError("sweeps cannot be combined with --compare-network")
+	case WindowFlag > 0:
+		return nil, errors.WrapValidationError("sweeps replace --window; use one or the other")
+	case sweepProbesFlag < 2:
+		return nil, errors.WrapValidationError("--sweep-probes must be at least 2")
+	}
+
+	spec := &sweepSpec{axis: simulator.SweepTimestamp}
+	raw, parse := sweepTimeFlag, parseSweepTime
+	if sweepLedgerFlag != "" {
+		spec.axis = simulator.SweepLedger
+		raw, parse = sweepLedgerFlag, parseSweepLedger
+	}
+
+	fromStr, toStr, ok := strings.Cut(raw, "..")
+	if !ok {
+		return nil, errors.WrapValidationError(fmt.Sprintf("sweep range %q must look like FROM..TO", raw))
+	}
+	var err error
+	if spec.from, err = parse(strings.TrimSpace(fromStr)); err != nil {
+		return nil, err
+	}
+	if spec.to, err = parse(strings.TrimSpace(toStr)); err != nil {
+		return nil, err
+	}
+	if spec.to < spec.from {
+		return nil, errors.WrapValidationError(fmt.Sprintf("sweep range %q ends before it starts", raw))
+	}
+	return spec, nil
+}
+
+// parseSweepTime accepts Unix seconds or an RFC 3339 time.
+func parseSweepTime(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n >= 0 {
+		return n, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, errors.WrapValidationError(fmt.Sprintf("invalid sweep time %q: use Unix seconds or RFC 3339", s))
+	}
+	return t.Unix(), nil
+}
+
+func parseSweepLedger(s string) (int64, error) {
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, errors.WrapValidationError(fmt.Sprintf("invalid sweep ledger sequence %q", s))
+	}
+	return int64(n), nil
+}
+
+// runSweep sweeps base across the range, prints the probe table, and returns
+// the response of the first failing probe (or the last probe when all pass)
+// for the detailed analysis that follows.
+func runSweep(ctx context.Context, w io.Writer, runner simulator.RunnerInterface, base *simulator.SimulationRequest, spec *sweepSpec) (*simulator.SimulationResponse, error) {
+	if err := checkSweepRunner(runner, spec.axis); err != nil {
+		return nil, err
+	}
+
+	sweeper := simulator.NewSweeper(runner, base, spec.axis)
+	sweeper.InitialProbes = sweepProbesFlag
+
+	fmt.Fprintf(w, "Sweeping %s %d..%d...\n", spec.axis, spec.from, spec.to)
+	result, err := sweeper.Sweep(ctx, spec.from, spec.to)
+	if err != nil {
+		return nil, err
+	}
+	printSweepResult(w, result)
+
+	shown := result.FirstFailure()
+	if shown == nil {
+		shown = &result.Probes[len(result.Probes)-1]
+	}
+	fmt.Fprintf(w, "\nShowing details for the probe at %s %s\n", spec.axis, formatSweepValue(spec.axis, shown.Value))
+	return shown.Response, nil
+}
+
+// checkSweepRunner rejects runners that would run every probe with the same
+// ledger: an erst-sim that does not apply the ledger sequence and timestamp
+// to the host, or a mock time that replaces the swept timestamp.
+func checkSweepRunner(runner simulator.RunnerInterface, axis simulator.SweepAxis) error {
+	var mockTime int64
+	switch r := runner.(type) {
+	case *simulator.Runner:
+		if r.Capabilities != nil && !r.Capabilities.Has(simulator.FeatureLedgerInfo) {
+			return errors.WrapSimIncompatible(fmt.Sprintf(
+				"%s does not apply the ledger sequence or timestamp, so every probe would be identical; rebuild erst-sim", r.BinaryPath))
+		}
+		mockTime = r.MockTime
+	case *simulator.RemoteRunner:
+		mockTime = r.MockTime
+	}
+
+	if axis == simulator.SweepTimestamp && mockTime != 0 {
+		return errors.WrapValidationError("--sweep-time cannot be combined with a mock time, which replaces the timestamp of every probe")
+	}
+	return nil
+}
+
+func printSweepResult(w io.Writer, result *simulator.SweepResult) {
+	fmt.Fprintf(w, "\n=== Sweep: %s %d..%d (%d probes) ===\n", result.Axis, result.From, result.To, len(result.Probes))
+	fmt.Fprintf(w, "  %-4s %-32s %-8s %14s %12s  %s\n", "STEP", strings.ToUpper(string(result.Axis)), "STATUS", "CPU", "MEMORY", "ERROR")
+	for _, p := range result.Probes {
+		fmt.Fprintf(w, "  %-4d %-32s %-8s %14d %12d  %s\n",
+			p.Step, formatSweepValue(result.Axis, p.Value), p.Status, p.CPUInstructions, p.MemoryBytes, p.Error)
+	}
+
+	if len(result.Boundaries) == 0 {
+		outcome := ""
+		if len(result.Probes) > 0 {
+			outcome = result.Probes[0].Outcome()
+		}
+		fmt.Fprintf(w, "\nNo outcome change across the range (every probe: %s)\n", outcome)
+		return
+	}
+
+	fmt.Fprintf(w, "\nOutcome changes:\n")
+	for _, b := range result.Boundaries {
+		if b.Exact {
+			fmt.Fprintf(w, "  at %s: %s -> %s\n", formatSweepValue(result.Axis, b.First), b.BeforeOutcome, b.AfterOutcome)
+		} else {
+			fmt.Fprintf(w, "  between %s and %s (probe limit reached): %s -> %s\n",
+				formatSweepValue(result.Axis, b.Last), formatSweepValue(result.Axis, b.First), b.BeforeOutcome, b.AfterOutcome)
+		}
+	}
+}
+
+func formatSweepValue(axis simulator.SweepAxis, v int64) string {
+	if axis == simulator.SweepTimestamp {
+		return fmt.Sprintf("%d (%s)", v, time.Unix(v, 0).UTC().Format(time.RFC3339))
+	}
+	return strconv.FormatInt(v, 10)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setSweepFlags(t *testing.T, timeRange, ledgerRange string) {
+	t.Helper()
+	oldTime, oldLedger, oldProbes := sweepTimeFlag, sweepLedgerFlag, sweepProbesFlag
+	t.Cleanup(func() { sweepTimeFlag, sweepLedgerFlag, sweepProbesFlag = oldTime, oldLedger, oldProbes })
+	sweepTimeFlag, sweepLedgerFlag, sweepProbesFlag = timeRange, ledgerRange, 5
+}
+
+func TestSweepSpecFromFlags(t *testing.T) {
+	setSweepFlags(t, "", "")
+	spec, err := sweepSpecFromFlags()
+	require.NoError(t, err)
+	assert.Nil(t, spec)
+
+	setSweepFlags(t, "1700000000..2024-01-01T00:00:00Z", "")
+	spec, err = sweepSpecFromFlags()
+	require.NoError(t, err)
+	assert.Equal(t, &sweepSpec{axis: simulator.SweepTimestamp, from: 1700000000, to: 1704067200}, spec)
+
+	setSweepFlags(t, "", "100..200")
+	spec, err = sweepSpecFromFlags()
+	require.NoError(t, err)
+	assert.Equal(t, &sweepSpec{axis: simulator.SweepLedger, from: 100, to: 200}, spec)
+
+	for _, tc := range [][2]string{
+		{"1..2", "3..4"},
+		{"10", ""},
+		{"20..10", ""},
+		{"yesterday..1", ""},
+		{"", "1..99999999999"},
+	} {
+		setSweepFlags(t, tc[0], tc[1])
+		_, err := sweepSpecFromFlags()
+		assert.Error(t, err, "%v", tc)
+	}
+}
+
+func TestRunSweep_ShowsFirstFailure(t *testing.T) {
+	setSweepFlags(t, "", "")
+	runner := &simulator.MockRunner{RunFunc: func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		if req.LedgerSequence >= 150 {
+			return &simulator.SimulationResponse{Status: "error", Error: "expired"}, nil
+		}
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	}}
+
+	var out bytes.Buffer
+	resp, err := runSweep(context.Background(), &out, runner, &simulator.SimulationRequest{},
+		&sweepSpec{axis: simulator.SweepLedger, from: 100, to: 200})
+	require.NoError(t, err)
+	assert.Equal(t, "expired", resp.Error)
+	assert.Contains(t, out.String(), "at 150: success -> error: expired")
+	assert.Contains(t, out.String(), "Showing details for the probe at ledger 150")
+}
+
+func TestRunSweep_RejectsRunnersThatIgnoreTheSweptField(t *testing.T) {
+	spec := &sweepSpec{axis: simulator.SweepTimestamp, from: 1700000000, to: 1700000100}
+
+	old := &simulator.Runner{BinaryPath: "erst-sim", Capabilities: &simulator.Capabilities{Features: []string{simulator.FeatureServe}}}
+	_, err := runSweep(context.Background(), &bytes.Buffer{}, old, &simulator.SimulationRequest{}, spec)
+	assert.ErrorContains(t, err, "does not apply the ledger sequence or timestamp")
+
+	mocked := &simulator.Runner{
+		BinaryPath:   "erst-sim",
+		Capabilities: &simulator.Capabilities{Features: []string{simulator.FeatureLedgerInfo}},
+		MockTime:     1600000000,
+	}
+	_, err = runSweep(context.Background(), &bytes.Buffer{}, mocked, &simulator.SimulationRequest{}, spec)
+	assert.ErrorContains(t, err, "cannot be combined with a mock time")
+}
@@ -88,10 +88,11 @@ Example:
 		fmt.Println("Injected new WASM code into simulation state.")
 
 		// 6. Run Simulation
-		runner, err := simulator.NewRunner("", false)
+		runner, closeRunner, err := newSimRunner("", false, 0)
 		if err != nil {
-			return errors.WrapSimulatorNotFound(err.Error())
+			return err
 		}
+		defer closeRunner()
 
 		simReq := &simulator.SimulationRequest{
 			EnvelopeXdr:   resp.EnvelopeXdr,
@@ -0,0 +1,7 @@
+package cmd
+
+var (
+	themeFlag           string
+	mockTimeFlag        int64
+	protocolVersionFlag uint32
+)
@@ -0,0 +1,172 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// ProtocolRun is the outcome of simulating a transaction under one protocol
+// version.
+type ProtocolRun struct {
+	Protocol uint32
+	Response *simulator.SimulationResponse
+	Err      error
+}
+
+// MatrixRow is one protocol version of a Matrix.
+type MatrixRow struct {
+	Protocol uint32
+	Response *simulator.SimulationResponse
+	// RunError is set when the simulator itself failed for this version.
+	RunError string
+	// Diff compares this version (the "local" side) against the baseline
+	// (the "on-chain" side). It is nil for the baseline row and for rows
+	// without a response.
+	Diff *DiffResult
+}
+
+// Diverges reports whether the row differs from the baseline.
+func (r *MatrixRow) Diverges() bool {
+	return r.RunError != "" || (r.Diff != nil && r.Diff.HasDivergence)
+}
+
+// Matrix compares the same transaction across protocol versions.
+type Matrix struct {
+	Baseline uint32
+	Rows     []MatrixRow
+}
+
+// NewMatrix diffs every run against the baseline version's run. Rows are
+// ordered by protocol version. When the baseline has no response the rows
+// carry no diffs.
+func NewMatrix(baseline uint32, runs []ProtocolRun) *Matrix {
+	m := &Matrix{Baseline: baseline}
+
+	var base *simulator.SimulationResponse
+	for _, r := range runs {
+		if r.Protocol == baseline && r.Err == nil {
+			base = r.Response
+		}
+	}
+
+	for _, r := range runs {
+		row := MatrixRow{Protocol: r.Protocol, Response: r.Response}
+		switch {
+		case r.Err != nil:
+			row.RunError = r.Err.Error()
+			row.Response = nil
+		case r.Response != nil && base != nil && r.Protocol != baseline:
+			row.Diff = Diff(r.Response, base)
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	sort.Slice(m.Rows, func(i, j int) bool { return m.Rows[i].Protocol < m.Rows[j].Protocol })
+	return m
+}
+
+// HasDivergence reports whether any version differs from the baseline.
+func (m *Matrix) HasDivergence() bool {
+	for i := range m.Rows {
+		if m.Rows[i].Diverges() {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderMatrix prints the matrix table followed by a short account of how
+// each diverging version differs from the baseline.
+func RenderMatrix(w io.Writer, m *Matrix) {
+	if m == nil {
+		return
+	}
+
+	fmt.Fprintln(w, sectionTitle(fmt.Sprintf("Protocol Matrix (baseline: protocol %d)", m.Baseline)))
+	fmt.Fprintf(w, "  %-8s  %-8s  %14s  %12s  %12s  %10s  %-9s  %s\n",
+		"PROTOCOL", "STATUS", "CPU", "ΔCPU", "MEMORY", "ΔMEM", "EVENTS", "VS BASELINE")
+	fmt.Fprintf(w, "  %s\n", strings.Repeat("-", 100))
+
+	for _, row := range m.Rows {
+		label := fmt.Sprintf("%d", row.Protocol)
+		if row.Protocol == m.Baseline {
+			label += "*"
+		}
+		if row.Response == nil {
+			fmt.Fprintf(w, "  %-8s  %-8s  %s\n", label, "failed", visualizer.Colorize(truncate(row.RunError, 80), "red"))
+			continue
+		}
+
+		resp := row.Response
+		var cpu, mem uint64
+		if resp.BudgetUsage != nil {
+			cpu, mem = resp.BudgetUsage.CPUInstructions, resp.BudgetUsage.MemoryBytes
+		}
+
+		cpuDelta, memDelta, events, verdict := "", "", fmt.Sprintf("%d", len(resp.Events)), visualizer.Colorize("baseline", "dim")
+		if d := row.Diff; d != nil {
+			if d.BudgetDiff != nil {
+				cpuDelta = colorizeDelta(fmt.Sprintf("%12s", formatDelta(d.BudgetDiff.CPUDelta)), d.BudgetDiff.CPUDelta)
+				memDelta = colorizeDelta(fmt.Sprintf("%10s", formatDelta(d.BudgetDiff.MemoryDelta)), d.BudgetDiff.MemoryDelta)
+			}
+			events = fmt.Sprintf("%d/%d", d.DivergentEvents, d.TotalEvents)
+			if d.HasDivergence {
+				verdict = visualizer.Colorize("[DIFF]", "red")
+			} else {
+				verdict = visualizer.Colorize("[MATCH]", "green")
+			}
+		} else if row.Protocol != m.Baseline {
+			verdict = visualizer.Colorize("no baseline", "dim")
+		}
+		if cpuDelta == "" {
+			cpuDelta = fmt.Sprintf("%12s", "")
+		}
+		if memDelta == "" {
+			memDelta = fmt.Sprintf("%10s", "")
+		}
+
+		fmt.Fprintf(w, "  %-8s  %-8s  %14d  %s  %12d  %s  %-9s  %s\n",
+			label, resp.Status, cpu, cpuDelta, mem, memDelta, events, verdict)
+	}
+	fmt.Fprintf(w, "  (* baseline; EVENTS shows divergent/total events against the baseline)\n")
+
+	for _, row := range m.Rows {
+		if row.Diff == nil || !row.Diff.HasDivergence {
+			continue
+		}
+		d := row.Diff
+		fmt.Fprintf(w, "\n  Protocol %d vs %d:\n", row.Protocol, m.Baseline)
+		if !d.StatusDiff.Match {
+			fmt.Fprintf(w, "    status: %s -> %s\n",
+				statusLine(d.StatusDiff.OnChainStatus, d.StatusDiff.OnChainError),
+				statusLine(d.StatusDiff.LocalStatus, d.StatusDiff.LocalError))
+		}
+		if d.DivergentEvents > 0 {
+			first := -1
+			for _, e := range d.EventDiffs {
+				if e.Divergent {
+					first = e.Index
+					break
+				}
+			}
+			fmt.Fprintf(w, "    events: %d of %d differ, first at [%d]\n", d.DivergentEvents, d.TotalEvents, first+1)
+		}
+		for _, p := range d.CallPathDivergences {
+			fmt.Fprintf(w, "    call path at event [%d]: %s\n", p.EventIndex+1, p.Reason)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if m.HasDivergence() {
+		fmt.Fprintf(w, "  %s  Execution differs between protocol versions\n", visualizer.Warning())
+	} else {
+		fmt.Fprintf(w, "  %s  All protocol versions agree with the baseline\n", visualizer.Success())
+	}
+}
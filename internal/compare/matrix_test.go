@@ -0,0 +1,59 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMatrix_DiffsAgainstBaseline(t *testing.T) {
+	budget := func(cpu uint64) *simulator.BudgetUsage {
+		return &simulator.BudgetUsage{CPUInstructions: cpu, MemoryBytes: 100}
+	}
+	runs := []ProtocolRun{
+		{Protocol: 22, Response: makeResp("success", []string{"a", "c"}, nil, budget(1200))},
+		{Protocol: 21, Response: makeResp("success", []string{"a", "b"}, nil, budget(1000))},
+		{Protocol: 20, Response: makeResp("success", []string{"a", "b"}, nil, budget(900))},
+		{Protocol: 23, Err: errors.New("unsupported host")},
+	}
+
+	m := NewMatrix(21, runs)
+	require.Len(t, m.Rows, 4)
+	assert.Equal(t, []uint32{20, 21, 22, 23}, []uint32{m.Rows[0].Protocol, m.Rows[1].Protocol, m.Rows[2].Protocol, m.Rows[3].Protocol})
+
+	assert.Nil(t, m.Rows[1].Diff, "the baseline is not diffed against itself")
+	require.NotNil(t, m.Rows[0].Diff)
+	assert.False(t, m.Rows[0].Diverges())
+	assert.Equal(t, int64(-100), m.Rows[0].Diff.BudgetDiff.CPUDelta)
+
+	require.NotNil(t, m.Rows[2].Diff)
+	assert.True(t, m.Rows[2].Diverges())
+	assert.Equal(t, 1, m.Rows[2].Diff.DivergentEvents)
+
+	assert.True(t, m.Rows[3].Diverges())
+	assert.True(t, m.HasDivergence())
+
+	var out bytes.Buffer
+	RenderMatrix(&out, m)
+	assert.Contains(t, out.String(), "baseline: protocol 21")
+	assert.Contains(t, out.String(), "Protocol 22 vs 21:")
+	assert.Contains(t, out.String(), "events: 1 of 2 differ, first at [2]")
+	assert.Contains(t, out.String(), "unsupported host")
+	assert.NotContains(t, out.String(), "Protocol 20 vs 21:")
+}
+
+func TestNewMatrix_FailedBaseline(t *testing.T) {
+	m := NewMatrix(21, []ProtocolRun{
+		{Protocol: 21, Err: errors.New("boom")},
+		{Protocol: 22, Response: makeResp("success", nil, nil, nil)},
+	})
+	assert.Nil(t, m.Rows[1].Diff)
+	assert.True(t, m.HasDivergence())
+}
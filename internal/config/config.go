@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/dotandev/hintents/internal/errors"
@@ -47,6 +48,26 @@ type Config struct {
 	// CrashSentryDSN is a Sentry Data Source Name for crash reporting.
 	// Set via crash_sentry_dsn in config or ERST_SENTRY_DSN.
 	CrashSentryDSN string `json:"crash_sentry_dsn,omitempty"`
+	// SimPoolSize is the number of persistent erst-sim workers used by
+	// batch-style commands such as fuzz. Zero disables the pool.
+	// Set via sim_pool_size in config or ERST_SIM_POOL_SIZE.
+	SimPoolSize int `json:"sim_pool_size,omitempty"`
+	// SimCacheMaxMB caps the simulation result cache in megabytes.
+	// Set via sim_cache_max_mb in config or ERST_SIM_CACHE_MAX_MB.
+	SimCacheMaxMB int `json:"sim_cache_max_mb,omitempty"`
+	// SimMemoryMB caps the address space of local erst-sim processes in
+	// megabytes. Zero keeps the simulator's default limit; ERST_SIM_MEMORY_MB
+	// and --sim-memory-mb take precedence.
+	// Set via sim_memory_mb in config.
+	SimMemoryMB int `json:"sim_memory_mb,omitempty"`
+	// SimulatorURL points at a remote erst simulator service (erst daemon or
+	// erst sim-serve). When set, simulations run there instead of a local
+	// erst-sim binary unless --sim-path is given.
+	// Set via simulator_url in config or ERST_SIMULATOR_URL.
+	SimulatorURL string `json:"simulator_url,omitempty"`
+	// SimulatorToken is sent as a bearer token to SimulatorURL.
+	// Set via simulator_token in config or ERST_SIMULATOR_TOKEN.
+	SimulatorToken string `json:"simulator_token,omitempty"`
 }
 
 var defaultConfig = &Config{
@@ -102,6 +123,8 @@ func Load() (*Config, error) {
 		RPCToken:       getEnv("ERST_RPC_TOKEN", ""),
 		CrashEndpoint:  getEnv("ERST_CRASH_ENDPOINT", ""),
 		CrashSentryDSN: getEnv("ERST_SENTRY_DSN", ""),
+		SimulatorURL:   getEnv("ERST_SIMULATOR_URL", ""),
+		SimulatorToken: getEnv("ERST_SIMULATOR_TOKEN", ""),
 	}
 
 	// ERST_CRASH_REPORTING is a boolean env var; parse it explicitly.
@@ -110,6 +133,18 @@ func Load() (*Config, error) {
 		cfg.CrashReporting = true
 	}
 
+	if sizeEnv := os.Getenv("ERST_SIM_POOL_SIZE"); sizeEnv != "" {
+		if size, err := strconv.Atoi(sizeEnv); err == nil {
+			cfg.SimPoolSize = size
+		}
+	}
+
+	if sizeEnv := os.Getenv("ERST_SIM_CACHE_MAX_MB"); sizeEnv != "" {
+		if size, err := strconv.Atoi(sizeEnv); err == nil {
+			cfg.SimCacheMaxMB = size
+		}
+	}
+
 	if urlsEnv := os.Getenv("ERST_RPC_URLS"); urlsEnv != "" {
 		cfg.RpcUrls = strings.Split(urlsEnv, ",")
 		for i := range cfg.RpcUrls {
@@ -218,6 +253,22 @@ func (c *Config) parseTOML(content string) error {
 			c.CrashEndpoint = value
 		case "crash_sentry_dsn":
 			c.CrashSentryDSN = value
+		case "sim_pool_size":
+			if size, err := strconv.Atoi(value); err == nil {
+				c.SimPoolSize = size
+			}
+		case "sim_cache_max_mb":
+			if size, err := strconv.Atoi(value); err == nil {
+				c.SimCacheMaxMB = size
+			}
+		case "sim_memory_mb":
+			if size, err := strconv.Atoi(value); err == nil {
+				c.SimMemoryMB = size
+			}
+		case "simulator_url":
+			c.SimulatorURL = value
+		case "simulator_token":
+			c.SimulatorToken = value
 		}
 	}
 
@@ -259,6 +310,22 @@ func (c *Config) Validate() error {
 		return errors.WrapInvalidNetwork(string(c.Network))
 	}
 
+	if c.SimPoolSize < 0 {
+		return errors.WrapValidationError("sim_pool_size cannot be negative")
+	}
+
+	if c.SimCacheMaxMB < 0 {
+		return errors.WrapValidationError("sim_cache_max_mb cannot be negative")
+	}
+
+	if c.SimMemoryMB < 0 {
+		return errors.WrapValidationError("sim_memory_mb cannot be negative")
+	}
+
+	if c.SimulatorURL != "" && !strings.HasPrefix(c.SimulatorURL, "http://") && !strings.HasPrefix(c.SimulatorURL, "https://") {
+		return errors.WrapValidationError("simulator_url must start with http:// or https://")
+	}
+
 	return nil
 }
 
@@ -447,3 +447,85 @@ func TestLoad_CrashReportingOffByDefault(t *testing.T) {
 		t.Error("CrashReporting should be off by default")
 	}
 }
+
+// ---- Simulator pool config --------------------------------------------------
+
+func TestParseTOML_SimPoolSize(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML("sim_pool_size = 4\nsim_cache_max_mb = 64\nsim_memory_mb = 2048"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SimPoolSize != 4 {
+		t.Errorf("expected SimPoolSize=4, got %d", cfg.SimPoolSize)
+	}
+	if cfg.SimCacheMaxMB != 64 {
+		t.Errorf("expected SimCacheMaxMB=64, got %d", cfg.SimCacheMaxMB)
+	}
+	if cfg.SimMemoryMB != 2048 {
+		t.Errorf("expected SimMemoryMB=2048, got %d", cfg.SimMemoryMB)
+	}
+}
+
+func TestLoad_SimPoolSizeEnvVar(t *testing.T) {
+	t.Setenv("ERST_SIM_POOL_SIZE", "3")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SimPoolSize != 3 {
+		t.Errorf("expected SimPoolSize=3 from ERST_SIM_POOL_SIZE, got %d", cfg.SimPoolSize)
+	}
+}
+
+func TestConfigValidation_NegativeSimPoolSize(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SimPoolSize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative sim_pool_size")
+	}
+}
+
+func TestConfigValidation_NegativeSimMemory(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SimMemoryMB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative sim_memory_mb")
+	}
+}
+
+// ---- Remote simulator config ------------------------------------------------
+
+func TestParseTOML_SimulatorURL(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML("simulator_url = \"https://sim.example.com\"\nsimulator_token = \"s3cret\""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SimulatorURL != "https://sim.example.com" {
+		t.Errorf("expected SimulatorURL to be parsed, got %q", cfg.SimulatorURL)
+	}
+	if cfg.SimulatorToken != "s3cret" {
+		t.Errorf("expected SimulatorToken to be parsed, got %q", cfg.SimulatorToken)
+	}
+}
+
+func TestLoad_SimulatorURLEnvVar(t *testing.T) {
+	t.Setenv("ERST_SIMULATOR_URL", "http://localhost:8090")
+	t.Setenv("ERST_SIMULATOR_TOKEN", "tok")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SimulatorURL != "http://localhost:8090" || cfg.SimulatorToken != "tok" {
+		t.Errorf("expected simulator URL and token from env, got %q / %q", cfg.SimulatorURL, cfg.SimulatorToken)
+	}
+}
+
+func TestConfigValidation_SimulatorURLScheme(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SimulatorURL = "localhost:8090"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for simulator_url without http(s) scheme")
+	}
+}
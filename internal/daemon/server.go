@@ -8,9 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/httpauth"
 	"github.com/dotandev/hintents/internal/logger"
 	stellarrpc "github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
@@ -92,18 +92,13 @@ func (s *Server) authenticate(r *http.Request) bool {
 		return true // No auth required
 	}
 
-	auth := r.Header.Get("Authorization")
-	if auth == "" {
-		return false
-	}
-
-	// Support "Bearer <token>" format
-	if strings.HasPrefix(auth, "Bearer ") {
-		token := strings.TrimPrefix(auth, "Bearer ")
-		return token == s.authToken
+	if httpauth.BearerTokenValid(r, s.authToken) {
+		return true
 	}
 
-	return auth == s.authToken
+	// The daemon has always accepted the bare token as well
+	auth := r.Header.Get("Authorization")
+	return auth != "" && httpauth.TokenEqual(auth, s.authToken)
 }
 
 // DebugTransaction handles debug_transaction RPC calls
@@ -180,6 +175,9 @@ func (s *Server) Start(ctx context.Context, port string) error {
 
 	http.Handle("/rpc", server)
 
+	// Remote simulation endpoint used by simulator.RemoteRunner
+	http.Handle("/simulate", simulator.NewRemoteHandler(s.simulator, s.authToken))
+
 	// Health check endpoint
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
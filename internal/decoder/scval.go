@@ -0,0 +1,125 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FormatScVal renders v as a typed literal such as Symbol(transfer),
+// I128(-5) or Vec(U32(1), Address(G...)), the syntax override files use for
+// values. Maps render as Map(key: value, ...).
+func FormatScVal(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvVoid:
+		return "Void"
+	case xdr.ScValTypeScvBool:
+		if v.B != nil {
+			return fmt.Sprintf("Bool(%t)", *v.B)
+		}
+	case xdr.ScValTypeScvU32:
+		if v.U32 != nil {
+			return fmt.Sprintf("U32(%d)", uint32(*v.U32))
+		}
+	case xdr.ScValTypeScvI32:
+		if v.I32 != nil {
+			return fmt.Sprintf("I32(%d)", int32(*v.I32))
+		}
+	case xdr.ScValTypeScvU64:
+		if v.U64 != nil {
+			return fmt.Sprintf("U64(%d)", uint64(*v.U64))
+		}
+	case xdr.ScValTypeScvI64:
+		if v.I64 != nil {
+			return fmt.Sprintf("I64(%d)", int64(*v.I64))
+		}
+	case xdr.ScValTypeScvTimepoint:
+		if v.Timepoint != nil {
+			return fmt.Sprintf("Timepoint(%d)", uint64(*v.Timepoint))
+		}
+	case xdr.ScValTypeScvDuration:
+		if v.Duration != nil {
+			return fmt.Sprintf("Duration(%d)", uint64(*v.Duration))
+		}
+	case xdr.ScValTypeScvU128:
+		if v.U128 != nil {
+			return fmt.Sprintf("U128(%s)", int128String(uint64(v.U128.Hi), uint64(v.U128.Lo), false))
+		}
+	case xdr.ScValTypeScvI128:
+		if v.I128 != nil {
+			return fmt.Sprintf("I128(%s)", int128String(uint64(v.I128.Hi), uint64(v.I128.Lo), true))
+		}
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym != nil {
+			return fmt.Sprintf("Symbol(%s)", string(*v.Sym))
+		}
+	case xdr.ScValTypeScvString:
+		if v.Str != nil {
+			return fmt.Sprintf("String(%q)", string(*v.Str))
+		}
+	case xdr.ScValTypeScvBytes:
+		if v.Bytes != nil {
+			return fmt.Sprintf("Bytes(%s)", hex.EncodeToString(*v.Bytes))
+		}
+	case xdr.ScValTypeScvAddress:
+		if v.Address != nil {
+			if addr, err := v.Address.String(); err == nil {
+				return fmt.Sprintf("Address(%s)", addr)
+			}
+		}
+	case xdr.ScValTypeScvVec:
+		if v.Vec != nil && *v.Vec != nil {
+			parts := make([]string, len(**v.Vec))
+			for i, item := range **v.Vec {
+				parts[i] = FormatScVal(item)
+			}
+			return fmt.Sprintf("Vec(%s)", strings.Join(parts, ", "))
+		}
+		return "Vec()"
+	case xdr.ScValTypeScvMap:
+		if v.Map != nil && *v.Map != nil {
+			parts := make([]string, len(**v.Map))
+			for i, entry := range **v.Map {
+				parts[i] = FormatScVal(entry.Key) + ": " + FormatScVal(entry.Val)
+			}
+			return fmt.Sprintf("Map(%s)", strings.Join(parts, ", "))
+		}
+		return "Map()"
+	case xdr.ScValTypeScvError:
+		if v.Error != nil {
+			if v.Error.Type == xdr.ScErrorTypeSceContract && v.Error.ContractCode != nil {
+				return fmt.Sprintf("Error(Contract, %d)", uint32(*v.Error.ContractCode))
+			}
+			if v.Error.Code != nil {
+				return fmt.Sprintf("Error(%s, %s)", v.Error.Type, *v.Error.Code)
+			}
+		}
+	}
+	return v.Type.String()
+}
+
+// FormatScValBase64 decodes base64 ScVal XDR and renders it with
+// FormatScVal. Undecodable input is returned as is.
+func FormatScValBase64(b64 string) string {
+	var v xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(b64, &v); err != nil {
+		return b64
+	}
+	return FormatScVal(v)
+}
+
+func int128String(hi, lo uint64, signed bool) string {
+	n := new(big.Int).SetUint64(hi)
+	n.Lsh(n, 64)
+	n.Or(n, new(big.Int).SetUint64(lo))
+	if signed && hi>>63 == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	return n.String()
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatScVal(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	u32 := xdr.Uint32(7)
+	str := xdr.ScString("hi")
+	neg := xdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(^uint64(0) - 4)}
+	big := xdr.UInt128Parts{Hi: 1, Lo: 0}
+	items := xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, {Type: xdr.ScValTypeScvU32, U32: &u32}}
+	vec := &items
+	entries := xdr.ScMap{{Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, Val: xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}}}
+	m := &entries
+
+	tests := []struct {
+		val  xdr.ScVal
+		want string
+	}{
+		{xdr.ScVal{Type: xdr.ScValTypeScvVoid}, "Void"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, "Symbol(transfer)"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &neg}, "I128(-5)"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &big}, "U128(18446744073709551616)"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}, "Vec(Symbol(transfer), U32(7))"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}, `Map(Symbol(transfer): String("hi"))`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatScVal(tt.val))
+	}
+}
+
+func TestFormatScValBase64(t *testing.T) {
+	sym := xdr.ScSymbol("mint")
+	encoded, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Symbol(mint)", FormatScValBase64(encoded))
+	assert.Equal(t, "not-xdr", FormatScValBase64("not-xdr"))
+}
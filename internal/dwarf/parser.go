@@ -69,7 +69,6 @@ type Frame struct {
 // Parser handles DWARF debug information extraction
 type Parser struct {
 	data       *dwarf.Data
-	unit       *dwarf.Unit
 	reader     *dwarf.Reader
 	binaryType string // "wasm", "elf", "macho", "pe"
 }
@@ -126,14 +125,6 @@ func parseWASM(data []byte) (*Parser, error) {
 	var dwarfData *dwarf.Data
 	var err error
 	
-	// Look for .debug_info section; dwarf.New expects the 8 canonical DWARF sections.
-	if infoSection, ok := sections[".debug_info"]; ok {
-		abbrev, _ := sections[".debug_abbrev"]
-		line, _ := sections[".debug_line"]
-		ranges, _ := sections[".debug_ranges"]
-		str, _ := sections[".debug_str"]
-		dwarfData, err = dwarf.New(abbrev, nil, nil, infoSection, line, nil, ranges, str)
-
 	// Extract primary DWARF sections from WASM custom sections
 	infoSec := sections[".debug_info"]
 	lineSec := sections[".debug_line"]
@@ -200,6 +191,15 @@ func parseWASMSections(data []byte) map[string][]byte {
 	return sections
 }
 
+// WASMCustomSections returns the payloads of the custom sections of a WASM
+// module keyed by name, or nil if data is not a WASM module.
+func WASMCustomSections(data []byte) map[string][]byte {
+	if len(data) < 8 || data[0] != 0x00 || data[1] != 0x61 || data[2] != 0x73 || data[3] != 0x6d {
+		return nil
+	}
+	return parseWASMSections(data)
+}
+
 // readULEB128 decodes an unsigned LEB128 value from buf.
 // Returns the value and the number of bytes consumed; 0 bytes means the buffer
 // was too short.
@@ -606,11 +606,3 @@ func (p *Parser) BinaryType() string {
 	return p.binaryType
 }
 
-// NewParserFromFile creates a new DWARF parser from a filesystem path.
-func NewParserFromFile(path string) (*Parser, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
-	return NewParser(data)
-}
\ No newline at end of file
@@ -6,6 +6,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // New is a proxy to the standard errors.New
@@ -32,6 +33,10 @@ var (
 	ErrSimulatorNotFound    = errors.New("simulator binary not found")
 	ErrSimulationFailed     = errors.New("simulation execution failed")
 	ErrSimCrash             = errors.New("simulator process crashed")
+	ErrSimTimeout           = errors.New("simulator execution timed out")
+	ErrSimOutOfMemory       = errors.New("simulator exceeded its memory limit")
+	ErrSimKilled            = errors.New("simulator process was killed")
+	ErrSimIncompatible      = errors.New("simulator is incompatible with this erst")
 	ErrInvalidNetwork       = errors.New("invalid network")
 	ErrMarshalFailed        = errors.New("failed to marshal request")
 	ErrUnmarshalFailed      = errors.New("failed to unmarshal response")
@@ -49,6 +54,7 @@ var (
 	ErrRPCResponseTooLarge  = errors.New("RPC response too large")
 	ErrConfigFailed         = errors.New("configuration error")
 	ErrNetworkNotFound      = errors.New("network not found")
+	ErrCassetteMiss         = errors.New("request not found in cassette")
 )
 
 type LedgerNotFoundError struct {
@@ -155,6 +161,39 @@ func WrapSimCrash(err error, stderr string) error {
 	return fmt.Errorf("%w: %w", ErrSimCrash, err)
 }
 
+// WrapSimTimeout reports a simulator run that was stopped because it exceeded
+// its wall-clock limit. A zero limit means the caller's own deadline expired.
+func WrapSimTimeout(limit time.Duration) error {
+	if limit > 0 {
+		return fmt.Errorf("%w after %s", ErrSimTimeout, limit)
+	}
+	return ErrSimTimeout
+}
+
+// WrapSimOutOfMemory reports a simulator run that failed to allocate memory
+// under an address-space limit of limitBytes.
+func WrapSimOutOfMemory(limitBytes uint64, stderr string) error {
+	if stderr != "" {
+		return fmt.Errorf("%w (limit %d bytes), stderr: %s", ErrSimOutOfMemory, limitBytes, stderr)
+	}
+	return fmt.Errorf("%w (limit %d bytes)", ErrSimOutOfMemory, limitBytes)
+}
+
+// WrapSimKilled reports a simulator process that was terminated externally,
+// either by cancellation of the caller's context or by a signal.
+func WrapSimKilled(err error, stderr string) error {
+	if stderr != "" {
+		return fmt.Errorf("%w: %w, stderr: %s", ErrSimKilled, err, stderr)
+	}
+	return fmt.Errorf("%w: %w", ErrSimKilled, err)
+}
+
+// WrapSimIncompatible reports a request the selected simulator binary cannot
+// serve, such as an unsupported schema or protocol version.
+func WrapSimIncompatible(msg string) error {
+	return fmt.Errorf("%w: %s", ErrSimIncompatible, msg)
+}
+
 func WrapValidationError(msg string) error {
 	return fmt.Errorf("%w: %s", ErrValidationFailed, msg)
 }
@@ -213,6 +252,12 @@ func WrapNetworkNotFound(network string) error {
 	return fmt.Errorf("%w: %s", ErrNetworkNotFound, network)
 }
 
+// WrapCassetteMiss reports a request made during replay that the cassette
+// has no recording of.
+func WrapCassetteMiss(path, method, url string) error {
+	return fmt.Errorf("%w: %s %s was not recorded in %s; record it again with --record", ErrCassetteMiss, method, url, path)
+}
+
 // WrapRPCResponseTooLarge wraps an HTTP 413 response into a readable message
 // explaining that the Soroban RPC response exceeded the server's size limit.
 func WrapRPCResponseTooLarge(url string) error {
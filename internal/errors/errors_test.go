@@ -4,9 +4,11 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +99,24 @@ func TestWrapRPCResponseTooLarge(t *testing.T) {
 	assert.True(t, errors.As(err, &rte))
 	assert.Equal(t, url, rte.URL)
 }
+
+func TestWrapSimResourceErrors(t *testing.T) {
+	err := WrapSimTimeout(5 * time.Second)
+	assert.True(t, errors.Is(err, ErrSimTimeout))
+	assert.Contains(t, err.Error(), "5s")
+	assert.True(t, errors.Is(WrapSimTimeout(0), ErrSimTimeout))
+
+	err = WrapSimOutOfMemory(1<<20, "memory allocation of 4096 bytes failed")
+	assert.True(t, errors.Is(err, ErrSimOutOfMemory))
+	assert.Contains(t, err.Error(), "1048576")
+	assert.Contains(t, err.Error(), "memory allocation")
+
+	err = WrapSimKilled(context.Canceled, "")
+	assert.True(t, errors.Is(err, ErrSimKilled))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, ErrSimCrash))
+
+	err = WrapSimIncompatible("schema 2")
+	assert.True(t, errors.Is(err, ErrSimIncompatible))
+	assert.Contains(t, err.Error(), "schema 2")
+}
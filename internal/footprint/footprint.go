@@ -0,0 +1,334 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package footprint compares the Soroban footprint a transaction declares
+// with the ledger keys it actually accessed.
+package footprint
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Footprint is a set of read-only and read-write ledger keys, each a base64
+// LedgerKey.
+type Footprint struct {
+	ReadOnly  []string `json:"read_only"`
+	ReadWrite []string `json:"read_write"`
+}
+
+// Access records the ledger keys a transaction touched during execution.
+type Access struct {
+	Read    []string `json:"read"`
+	Written []string `json:"written"`
+	// ReadsKnown is false when only writes could be observed, in which case
+	// unused read-only keys cannot be reported.
+	ReadsKnown bool `json:"reads_known"`
+	// Sources names where the access information came from.
+	Sources []string `json:"sources"`
+}
+
+// Merge adds the keys of other to a.
+func (a *Access) Merge(other *Access) {
+	if other == nil {
+		return
+	}
+	a.Read = append(a.Read, other.Read...)
+	a.Written = append(a.Written, other.Written...)
+	a.ReadsKnown = a.ReadsKnown || other.ReadsKnown
+	a.Sources = append(a.Sources, other.Sources...)
+}
+
+// Key is a ledger key in a report.
+type Key struct {
+	XDR         string `json:"xdr"`
+	Description string `json:"description"`
+}
+
+// Report is the result of comparing a declared footprint with the keys
+// accessed.
+type Report struct {
+	Declared Footprint `json:"declared"`
+	// Missing keys were accessed but not declared; the transaction fails
+	// with a footprint error on the first one.
+	Missing []Key `json:"missing,omitempty"`
+	// WrittenReadOnly keys were declared read-only but written.
+	WrittenReadOnly []Key `json:"written_read_only,omitempty"`
+	// UnusedReadOnly and UnusedReadWrite keys were declared but never
+	// accessed and only add to the fee.
+	UnusedReadOnly  []Key `json:"unused_read_only,omitempty"`
+	UnusedReadWrite []Key `json:"unused_read_write,omitempty"`
+	// ReadWriteOnlyRead keys were declared read-write but only read; they
+	// could be read-only.
+	ReadWriteOnlyRead []Key    `json:"read_write_only_read,omitempty"`
+	ReadsKnown        bool     `json:"reads_known"`
+	Sources           []string `json:"sources"`
+}
+
+// HasErrors reports whether the footprint would make the transaction fail.
+func (r *Report) HasErrors() bool {
+	return len(r.Missing) > 0 || len(r.WrittenReadOnly) > 0
+}
+
+// HasWaste reports whether the footprint declares keys it does not need.
+func (r *Report) HasWaste() bool {
+	return len(r.UnusedReadOnly) > 0 || len(r.UnusedReadWrite) > 0 || len(r.ReadWriteOnlyRead) > 0
+}
+
+// Declared returns the footprint of a base64 TransactionEnvelope. ok is false
+// for transactions without Soroban data.
+func Declared(envelopeXdr string) (*Footprint, bool, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, false, errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+	}
+
+	var ext xdr.TransactionExt
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		ext = env.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		ext = env.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	default:
+		return nil, false, nil
+	}
+	data, ok := ext.GetSorobanData()
+	if !ok {
+		return nil, false, nil
+	}
+	fp, err := fromLedgerFootprint(data.Resources.Footprint)
+	if err != nil {
+		return nil, false, err
+	}
+	return fp, true, nil
+}
+
+// SourcePreflight names access taken from a Soroban RPC simulateTransaction
+// preflight. The preflight runs against the current ledger state, not the
+// state the transaction originally saw.
+const SourcePreflight = "current-state preflight"
+
+// AccessFromTransactionData reads the footprint recorded by a Soroban RPC
+// simulateTransaction call, given its base64 SorobanTransactionData. The
+// recorded read-only keys were read and the read-write keys written.
+func AccessFromTransactionData(transactionData string) (*Access, error) {
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(transactionData, &data); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "SorobanTransactionData")
+	}
+	fp, err := fromLedgerFootprint(data.Resources.Footprint)
+	if err != nil {
+		return nil, err
+	}
+	return &Access{
+		Read:       fp.ReadOnly,
+		Written:    fp.ReadWrite,
+		ReadsKnown: true,
+		Sources:    []string{SourcePreflight},
+	}, nil
+}
+
+// AccessFromMeta collects the keys a transaction wrote from its base64
+// TransactionResultMeta. The meta only records changes, so reads are
+// unknown.
+func AccessFromMeta(resultMetaXdr string) (*Access, error) {
+	var meta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "result meta")
+	}
+
+	var sets []xdr.LedgerEntryChanges
+	tm := meta.TxApplyProcessing
+	switch tm.V {
+	case 3:
+		if tm.V3 != nil {
+			for _, op := range tm.V3.Operations {
+				sets = append(sets, op.Changes)
+			}
+		}
+	case 4:
+		if tm.V4 != nil {
+			for _, op := range tm.V4.Operations {
+				sets = append(sets, op.Changes)
+			}
+		}
+	}
+
+	access := &Access{Sources: []string{"result meta"}}
+	for _, changes := range sets {
+		for _, change := range changes {
+			var key xdr.LedgerKey
+			var err error
+			switch change.Type {
+			case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+				key, err = change.Created.LedgerKey()
+			case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+				key, err = change.Updated.LedgerKey()
+			case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+				key = *change.Removed
+			case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+				key, err = change.Restored.LedgerKey()
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, errors.WrapUnmarshalFailed(err, "ledger entry change")
+			}
+			// TTL entries are written for extensions of declared keys and
+			// are never part of a footprint themselves.
+			if key.Type == xdr.LedgerEntryTypeTtl {
+				continue
+			}
+			encoded, err := encodeKey(key)
+			if err != nil {
+				return nil, err
+			}
+			access.Written = append(access.Written, encoded)
+		}
+	}
+	return access, nil
+}
+
+// Analyze compares the declared footprint with the keys accessed.
+func Analyze(declared *Footprint, access *Access) *Report {
+	r := &Report{
+		Declared:   *declared,
+		ReadsKnown: access.ReadsKnown,
+		Sources:    access.Sources,
+	}
+
+	readOnly := toSet(declared.ReadOnly)
+	readWrite := toSet(declared.ReadWrite)
+	read := toSet(access.Read)
+	written := toSet(access.Written)
+
+	for k := range union(read, written) {
+		if !readOnly[k] && !readWrite[k] {
+			r.Missing = append(r.Missing, newKey(k))
+		}
+	}
+	for k := range written {
+		if readOnly[k] {
+			r.WrittenReadOnly = append(r.WrittenReadOnly, newKey(k))
+		}
+	}
+	for k := range readWrite {
+		switch {
+		case written[k]:
+		case read[k]:
+			r.ReadWriteOnlyRead = append(r.ReadWriteOnlyRead, newKey(k))
+		case access.ReadsKnown:
+			r.UnusedReadWrite = append(r.UnusedReadWrite, newKey(k))
+		}
+	}
+	if access.ReadsKnown {
+		for k := range readOnly {
+			if !read[k] && !written[k] {
+				r.UnusedReadOnly = append(r.UnusedReadOnly, newKey(k))
+			}
+		}
+	}
+
+	for _, keys := range []*[]Key{&r.Missing, &r.WrittenReadOnly, &r.UnusedReadOnly, &r.UnusedReadWrite, &r.ReadWriteOnlyRead} {
+		sort.Slice(*keys, func(i, j int) bool { return (*keys)[i].Description < (*keys)[j].Description })
+	}
+	return r
+}
+
+func fromLedgerFootprint(lf xdr.LedgerFootprint) (*Footprint, error) {
+	fp := &Footprint{}
+	for _, k := range lf.ReadOnly {
+		encoded, err := encodeKey(k)
+		if err != nil {
+			return nil, err
+		}
+		fp.ReadOnly = append(fp.ReadOnly, encoded)
+	}
+	for _, k := range lf.ReadWrite {
+		encoded, err := encodeKey(k)
+		if err != nil {
+			return nil, err
+		}
+		fp.ReadWrite = append(fp.ReadWrite, encoded)
+	}
+	return fp, nil
+}
+
+func encodeKey(key xdr.LedgerKey) (string, error) {
+	raw, err := key.MarshalBinary()
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func toSet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
+
+func union(a, b map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		out[k] = true
+	}
+	for k := range b {
+		out[k] = true
+	}
+	return out
+}
+
+func newKey(encoded string) Key {
+	return Key{XDR: encoded, Description: Describe(encoded)}
+}
+
+// Describe renders a base64 LedgerKey for people, e.g.
+// "contract_data CA...XYZ Symbol(balance) persistent".
+func Describe(encoded string) string {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(encoded, &key); err != nil {
+		return encoded
+	}
+
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return "account " + key.Account.AccountId.Address()
+	case xdr.LedgerEntryTypeTrustline:
+		return fmt.Sprintf("trustline %s %s", key.TrustLine.AccountId.Address(), trustLineAsset(key.TrustLine.Asset))
+	case xdr.LedgerEntryTypeContractData:
+		cd := key.ContractData
+		contract, _ := cd.Contract.String()
+		if cd.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+			return fmt.Sprintf("contract_instance %s", contract)
+		}
+		durability := "persistent"
+		if cd.Durability == xdr.ContractDataDurabilityTemporary {
+			durability = "temporary"
+		}
+		return fmt.Sprintf("contract_data %s %s %s", contract, decoder.FormatScVal(cd.Key), durability)
+	case xdr.LedgerEntryTypeContractCode:
+		return fmt.Sprintf("contract_code %x", key.ContractCode.Hash[:])
+	}
+	return key.Type.String()
+}
+
+func trustLineAsset(asset xdr.TrustLineAsset) string {
+	switch asset.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		return string(bytes.TrimRight(asset.AlphaNum4.AssetCode[:], "\x00")) + ":" + asset.AlphaNum4.Issuer.Address()
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		return string(bytes.TrimRight(asset.AlphaNum12.AssetCode[:], "\x00")) + ":" + asset.AlphaNum12.Issuer.Address()
+	case xdr.AssetTypeAssetTypePoolShare:
+		return fmt.Sprintf("pool:%x", asset.LiquidityPoolId[:])
+	}
+	return asset.Type.String()
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package footprint
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccountA = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	testAccountB = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+)
+
+func accountKey(address string) xdr.LedgerKey {
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(address)}}
+}
+
+func contractDataKey(sym string) xdr.LedgerKey {
+	s := xdr.ScSymbol(sym)
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.ContractId{1}},
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &s},
+		Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+}
+
+func mustEncode(t *testing.T, key xdr.LedgerKey) string {
+	t.Helper()
+	encoded, err := encodeKey(key)
+	require.NoError(t, err)
+	return encoded
+}
+
+func testEnvelope(t *testing.T, readOnly, readWrite []xdr.LedgerKey) string {
+	t.Helper()
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+		Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{},
+		}}},
+	}
+	if readOnly != nil || readWrite != nil {
+		tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: readOnly, ReadWrite: readWrite}},
+		}}
+	}
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestDeclared(t *testing.T) {
+	ro, rw := accountKey(testAccountA), contractDataKey("balance")
+	fp, ok, err := Declared(testEnvelope(t, []xdr.LedgerKey{ro}, []xdr.LedgerKey{rw}))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{mustEncode(t, ro)}, fp.ReadOnly)
+	assert.Equal(t, []string{mustEncode(t, rw)}, fp.ReadWrite)
+
+	_, ok, err = Declared(testEnvelope(t, nil, nil))
+	require.NoError(t, err)
+	assert.False(t, ok, "classic transactions have no footprint")
+
+	_, _, err = Declared("not-xdr")
+	assert.Error(t, err)
+}
+
+func TestAnalyze(t *testing.T) {
+	account := mustEncode(t, accountKey(testAccountA))
+	unusedRO := mustEncode(t, accountKey(testAccountB))
+	balance := mustEncode(t, contractDataKey("balance"))
+	admin := mustEncode(t, contractDataKey("admin"))
+	nonce := mustEncode(t, contractDataKey("nonce"))
+	config := mustEncode(t, contractDataKey("config"))
+
+	declared := &Footprint{
+		ReadOnly:  []string{account, unusedRO, config},
+		ReadWrite: []string{balance, admin, nonce},
+	}
+	access := &Access{
+		Read:       []string{account, admin, mustEncode(t, contractDataKey("allowance"))},
+		Written:    []string{balance, config},
+		ReadsKnown: true,
+		Sources:    []string{"test"},
+	}
+
+	r := Analyze(declared, access)
+	require.Len(t, r.Missing, 1)
+	assert.Contains(t, r.Missing[0].Description, "Symbol(allowance)")
+	require.Len(t, r.WrittenReadOnly, 1)
+	assert.Equal(t, config, r.WrittenReadOnly[0].XDR)
+	require.Len(t, r.UnusedReadOnly, 1)
+	assert.Equal(t, "account "+testAccountB, r.UnusedReadOnly[0].Description)
+	require.Len(t, r.UnusedReadWrite, 1)
+	assert.Equal(t, nonce, r.UnusedReadWrite[0].XDR)
+	require.Len(t, r.ReadWriteOnlyRead, 1)
+	assert.Equal(t, admin, r.ReadWriteOnlyRead[0].XDR)
+	assert.True(t, r.HasErrors())
+	assert.True(t, r.HasWaste())
+}
+
+func TestAnalyze_WritesOnly(t *testing.T) {
+	balance := mustEncode(t, contractDataKey("balance"))
+	declared := &Footprint{
+		ReadOnly:  []string{mustEncode(t, accountKey(testAccountA))},
+		ReadWrite: []string{balance, mustEncode(t, contractDataKey("nonce"))},
+	}
+
+	r := Analyze(declared, &Access{Written: []string{balance}})
+	assert.False(t, r.HasErrors())
+	assert.False(t, r.HasWaste(), "unaccessed keys are not reported when reads are unknown")
+}
+
+func TestAccessFromMeta(t *testing.T) {
+	created := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(testAccountA)},
+	}}
+	removed := contractDataKey("balance")
+	ttl := xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.TtlEntry{}}}
+
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		}}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &created},
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &created},
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &removed},
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &ttl},
+			}}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	access, err := AccessFromMeta(b64)
+	require.NoError(t, err)
+	assert.False(t, access.ReadsKnown)
+	assert.Equal(t, []string{mustEncode(t, accountKey(testAccountA)), mustEncode(t, removed)}, access.Written)
+}
+
+func TestAccessFromTransactionData(t *testing.T) {
+	ro, rw := accountKey(testAccountA), contractDataKey("balance")
+	data := xdr.SorobanTransactionData{Resources: xdr.SorobanResources{
+		Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{ro}, ReadWrite: []xdr.LedgerKey{rw}},
+	}}
+	b64, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+
+	access, err := AccessFromTransactionData(b64)
+	require.NoError(t, err)
+	assert.True(t, access.ReadsKnown)
+	assert.Equal(t, []string{mustEncode(t, ro)}, access.Read)
+	assert.Equal(t, []string{mustEncode(t, rw)}, access.Written)
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "account "+testAccountA, Describe(mustEncode(t, accountKey(testAccountA))))
+	assert.Contains(t, Describe(mustEncode(t, contractDataKey("balance"))), "Symbol(balance) persistent")
+	assert.Equal(t, "garbage", Describe("garbage"))
+
+	// Keys use the same value syntax as override files.
+	key := contractDataKey("balance")
+	sym := xdr.ScSymbol("Balance")
+	n := xdr.Uint32(7)
+	vec := &xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, {Type: xdr.ScValTypeScvU32, U32: &n}}
+	key.ContractData.Key = xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}
+	assert.Contains(t, Describe(mustEncode(t, key)), "Vec(Symbol(Balance), U32(7)) persistent")
+}
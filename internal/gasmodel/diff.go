@@ -0,0 +1,102 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package gasmodel
+
+import "sort"
+
+type CostChange struct {
+	Category string   `json:"category"` // "cpu_costs", "host_costs" or "ledger_costs"
+	Name     string   `json:"name"`
+	Old      *GasCost `json:"old,omitempty"` // nil when the cost was added
+	New      *GasCost `json:"new,omitempty"` // nil when the cost was removed
+}
+
+func (c CostChange) LinearDelta() int64 { return int64(costLinear(c.New)) - int64(costLinear(c.Old)) }
+func (c CostChange) ConstDelta() int64  { return int64(costConst(c.New)) - int64(costConst(c.Old)) }
+
+type LimitChange struct {
+	Name string `json:"name"`
+	Old  uint64 `json:"old"`
+	New  uint64 `json:"new"`
+}
+
+type ModelDiff struct {
+	Costs  []CostChange  `json:"costs,omitempty"`
+	Limits []LimitChange `json:"limits,omitempty"`
+}
+
+func (d *ModelDiff) Empty() bool {
+	return len(d.Costs) == 0 && len(d.Limits) == 0
+}
+
+// Diff lists the costs and resource limits that differ between two models.
+// Costs are matched by category and name and sorted the same way; a zero
+// limit means the limit is unset.
+func Diff(old, new *GasModel) *ModelDiff {
+	d := &ModelDiff{}
+
+	categories := []struct {
+		name     string
+		old, new []GasCost
+	}{
+		{"cpu_costs", old.CPUCosts, new.CPUCosts},
+		{"host_costs", old.HostCosts, new.HostCosts},
+		{"ledger_costs", old.LedgerCosts, new.LedgerCosts},
+	}
+	for _, cat := range categories {
+		oldByName := indexCosts(cat.old)
+		newByName := indexCosts(cat.new)
+		var changes []CostChange
+		for name, o := range oldByName {
+			n, ok := newByName[name]
+			if !ok {
+				changes = append(changes, CostChange{Category: cat.name, Name: name, Old: o})
+			} else if o.Linear != n.Linear || o.Const != n.Const {
+				changes = append(changes, CostChange{Category: cat.name, Name: name, Old: o, New: n})
+			}
+		}
+		for name, n := range newByName {
+			if _, ok := oldByName[name]; !ok {
+				changes = append(changes, CostChange{Category: cat.name, Name: name, New: n})
+			}
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+		d.Costs = append(d.Costs, changes...)
+	}
+
+	limits := []LimitChange{
+		{"max_txn_size", old.ResourceLimits.MaxTxnSize, new.ResourceLimits.MaxTxnSize},
+		{"max_cpu_insns", old.ResourceLimits.MaxCPUInsns, new.ResourceLimits.MaxCPUInsns},
+		{"max_memory", old.ResourceLimits.MaxMemory, new.ResourceLimits.MaxMemory},
+		{"max_ledger_entries", old.ResourceLimits.MaxLedgerEntries, new.ResourceLimits.MaxLedgerEntries},
+	}
+	for _, l := range limits {
+		if l.Old != l.New {
+			d.Limits = append(d.Limits, l)
+		}
+	}
+	return d
+}
+
+func indexCosts(costs []GasCost) map[string]*GasCost {
+	out := make(map[string]*GasCost, len(costs))
+	for i := range costs {
+		out[costs[i].Name] = &costs[i]
+	}
+	return out
+}
+
+func costLinear(c *GasCost) uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.Linear
+}
+
+func costConst(c *GasCost) uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.Const
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package gasmodel
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := &GasModel{
+		CPUCosts: []GasCost{
+			{Name: "wasm_inst", Linear: 100, Const: 10},
+			{Name: "sha256", Linear: 30, Const: 4000},
+		},
+		HostCosts:      []GasCost{{Name: "host_invoke", Linear: 500, Const: 50}},
+		ResourceLimits: ResourceLimits{MaxCPUInsns: 100000, MaxMemory: 4096},
+	}
+	b := &GasModel{
+		CPUCosts: []GasCost{
+			{Name: "wasm_inst", Linear: 120, Const: 10},
+			{Name: "keccak256", Linear: 40, Const: 3000},
+		},
+		HostCosts:      []GasCost{{Name: "host_invoke", Linear: 500, Const: 50}},
+		ResourceLimits: ResourceLimits{MaxCPUInsns: 200000, MaxMemory: 4096},
+	}
+
+	d := Diff(a, b)
+	if len(d.Costs) != 3 {
+		t.Fatalf("Diff() got %d cost changes, want 3: %+v", len(d.Costs), d.Costs)
+	}
+
+	keccak, sha, wasm := d.Costs[0], d.Costs[1], d.Costs[2]
+	if keccak.Name != "keccak256" || keccak.Old != nil || keccak.New == nil {
+		t.Errorf("keccak256 should be added, got %+v", keccak)
+	}
+	if sha.Name != "sha256" || sha.New != nil || sha.ConstDelta() != -4000 {
+		t.Errorf("sha256 should be removed, got %+v", sha)
+	}
+	if wasm.Name != "wasm_inst" || wasm.LinearDelta() != 20 || wasm.ConstDelta() != 0 {
+		t.Errorf("wasm_inst linear should change by 20, got %+v", wasm)
+	}
+
+	if len(d.Limits) != 1 || d.Limits[0].Name != "max_cpu_insns" || d.Limits[0].New != 200000 {
+		t.Errorf("Diff() limits = %+v, want only max_cpu_insns", d.Limits)
+	}
+
+	if !Diff(a, a).Empty() {
+		t.Error("Diff() of a model with itself should be empty")
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpauth checks the shared-secret tokens erst's HTTP services
+// accept, without leaking the token through comparison timing.
+package httpauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// TokenEqual reports whether got equals want in constant time. Both values
+// are hashed first so the comparison does not reveal the token length.
+func TokenEqual(got, want string) bool {
+	g := sha256.Sum256([]byte(got))
+	w := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(g[:], w[:]) == 1
+}
+
+// BearerTokenValid reports whether r carries "Authorization: Bearer <token>".
+// An empty token never matches.
+func BearerTokenValid(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix)
+	return ok && TokenEqual(got, token)
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package httpauth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearerTokenValid(t *testing.T) {
+	cases := map[string]bool{
+		"Bearer secret123":  true,
+		"Bearer secret1234": false,
+		"Bearer wrong":      false,
+		"secret123":         false,
+		"bearer secret123":  false,
+		"":                  false,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest("POST", "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		assert.Equal(t, want, BearerTokenValid(req, "secret123"), "header %q", header)
+	}
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	assert.False(t, BearerTokenValid(req, ""), "an empty token never matches")
+}
+
+func TestTokenEqual(t *testing.T) {
+	assert.True(t, TokenEqual("secret123", "secret123"))
+	assert.False(t, TokenEqual("secret12", "secret123"))
+	assert.False(t, TokenEqual("", "secret123"))
+}
@@ -0,0 +1,519 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package override
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// DefaultTTLExtension is how many ledgers past the simulated ledger a
+// contract entry created by an override stays live (about 180 days).
+const DefaultTTLExtension = 3_110_400
+
+// Options controls how statements are applied.
+type Options struct {
+	// LedgerSequence is the ledger being simulated. It dates new entries and
+	// sets the TTL of contract entries that an override creates.
+	LedgerSequence uint32
+	// Fetch loads entries that statements edit but the request does not hold
+	// yet, so an edit keeps the rest of the on-chain entry. Optional; without
+	// it missing entries are created from scratch.
+	Fetch func(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// Change describes the effect of one applied statement.
+type Change struct {
+	Statement   Statement
+	Description string
+}
+
+type edit interface {
+	ledgerKey() xdr.LedgerKey
+	// apply returns the new entry, or nil to delete it. entry is nil when
+	// the key is not in the ledger state.
+	apply(entry *xdr.LedgerEntry, opts Options) (*xdr.LedgerEntry, string, error)
+}
+
+// Apply compiles the statements into entries in order, editing the map of
+// base64 ledger keys to base64 ledger entries in place.
+func Apply(ctx context.Context, stmts []Statement, entries map[string]string, opts Options) ([]Change, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+	if opts.Fetch != nil {
+		if err := fetchMissing(ctx, stmts, entries, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make([]Change, 0, len(stmts))
+	for _, stmt := range stmts {
+		desc, err := applyOne(stmt.edit, entries, opts)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("%s: %v", stmt.Text, err))
+		}
+		changes = append(changes, Change{Statement: stmt, Description: desc})
+	}
+	return changes, nil
+}
+
+func applyOne(e edit, entries map[string]string, opts Options) (string, error) {
+	key := e.ledgerKey()
+	keyXDR, err := encodeKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	var current *xdr.LedgerEntry
+	if raw, ok := entries[keyXDR]; ok {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(raw, &entry); err != nil {
+			return "", fmt.Errorf("existing entry does not decode: %v", err)
+		}
+		current = &entry
+	}
+
+	updated, desc, err := e.apply(current, opts)
+	if err != nil {
+		return "", err
+	}
+
+	ttlKeyXDR := ""
+	if hasTTL(key) {
+		if ttlKeyXDR, err = encodeKey(ttlKey(key)); err != nil {
+			return "", err
+		}
+	}
+
+	if updated == nil {
+		delete(entries, keyXDR)
+		if ttlKeyXDR != "" {
+			delete(entries, ttlKeyXDR)
+		}
+		return desc, nil
+	}
+
+	raw, err := xdr.MarshalBase64(updated)
+	if err != nil {
+		return "", err
+	}
+	entries[keyXDR] = raw
+
+	if _, ok := entries[ttlKeyXDR]; ttlKeyXDR != "" && !ok {
+		ttl, err := xdr.MarshalBase64(newTTLEntry(key, defaultLiveUntil(opts), opts))
+		if err != nil {
+			return "", err
+		}
+		entries[ttlKeyXDR] = ttl
+	}
+	return desc, nil
+}
+
+// fetchMissing loads every entry the statements touch that is not in
+// entries yet, including TTL entries of contract data and code.
+func fetchMissing(ctx context.Context, stmts []Statement, entries map[string]string, opts Options) error {
+	seen := make(map[string]bool)
+	var missing []string
+	add := func(k xdr.LedgerKey) error {
+		keyXDR, err := encodeKey(k)
+		if err != nil {
+			return err
+		}
+		if _, ok := entries[keyXDR]; !ok && !seen[keyXDR] {
+			seen[keyXDR] = true
+			missing = append(missing, keyXDR)
+		}
+		return nil
+	}
+
+	for _, stmt := range stmts {
+		key := stmt.edit.ledgerKey()
+		if err := add(key); err != nil {
+			return err
+		}
+		if hasTTL(key) {
+			if err := add(ttlKey(key)); err != nil {
+				return err
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	fetched, err := opts.Fetch(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for k, v := range fetched {
+		entries[k] = v
+	}
+	return nil
+}
+
+type accountEdit struct {
+	id    xdr.AccountId
+	field string
+	value int64
+	del   bool
+}
+
+func (e *accountEdit) ledgerKey() xdr.LedgerKey {
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: e.id}}
+}
+
+func (e *accountEdit) apply(entry *xdr.LedgerEntry, opts Options) (*xdr.LedgerEntry, string, error) {
+	addr := e.id.Address()
+	if e.del {
+		if entry == nil {
+			return nil, "", fmt.Errorf("account %s is not in the ledger state", addr)
+		}
+		return nil, fmt.Sprintf("account %s deleted", addr), nil
+	}
+
+	created := entry == nil
+	if created {
+		entry = &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: xdr.Uint32(opts.LedgerSequence),
+			Data: xdr.LedgerEntryData{
+				Type:    xdr.LedgerEntryTypeAccount,
+				Account: &xdr.AccountEntry{AccountId: e.id, Thresholds: xdr.Thresholds{1, 0, 0, 0}},
+			},
+		}
+	}
+	acc := entry.Data.Account
+
+	var desc string
+	switch e.field {
+	case "balance":
+		desc = fmt.Sprintf("account %s balance %s -> %s XLM", addr, amount.String(acc.Balance), amount.StringFromInt64(e.value))
+		acc.Balance = xdr.Int64(e.value)
+	case "sequence":
+		desc = fmt.Sprintf("account %s sequence %d -> %d", addr, acc.SeqNum, e.value)
+		acc.SeqNum = xdr.SequenceNumber(e.value)
+	}
+	if created {
+		desc += " (new entry)"
+	}
+	return entry, desc, nil
+}
+
+type trustlineEdit struct {
+	id    xdr.AccountId
+	asset xdr.TrustLineAsset
+	field string
+	value int64
+	del   bool
+}
+
+func (e *trustlineEdit) ledgerKey() xdr.LedgerKey {
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeTrustline, TrustLine: &xdr.LedgerKeyTrustLine{AccountId: e.id, Asset: e.asset}}
+}
+
+func (e *trustlineEdit) apply(entry *xdr.LedgerEntry, opts Options) (*xdr.LedgerEntry, string, error) {
+	name := fmt.Sprintf("trustline %s %s", e.id.Address(), assetName(e.asset))
+	if e.del {
+		if entry == nil {
+			return nil, "", fmt.Errorf("%s is not in the ledger state", name)
+		}
+		return nil, name + " deleted", nil
+	}
+
+	created := entry == nil
+	if created {
+		entry = &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: xdr.Uint32(opts.LedgerSequence),
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeTrustline,
+				TrustLine: &xdr.TrustLineEntry{
+					AccountId: e.id,
+					Asset:     e.asset,
+					Limit:     math.MaxInt64,
+					Flags:     xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag),
+				},
+			},
+		}
+	}
+	tl := entry.Data.TrustLine
+
+	var desc string
+	switch e.field {
+	case "balance":
+		desc = fmt.Sprintf("%s balance %s -> %s", name, amount.String(tl.Balance), amount.StringFromInt64(e.value))
+		tl.Balance = xdr.Int64(e.value)
+	case "limit":
+		desc = fmt.Sprintf("%s limit %s -> %s", name, amount.String(tl.Limit), amount.StringFromInt64(e.value))
+		tl.Limit = xdr.Int64(e.value)
+	}
+	if created {
+		desc += " (new entry)"
+	}
+	return entry, desc, nil
+}
+
+type contractDataEdit struct {
+	contract   xdr.ScAddress
+	durability xdr.ContractDataDurability
+	instance   bool
+	key        xdr.ScVal
+	keyText    string
+	val        xdr.ScVal
+	valText    string
+	del        bool
+}
+
+func (e *contractDataEdit) ledgerKey() xdr.LedgerKey {
+	key := e.key
+	if e.instance {
+		key = xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+	}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   e.contract,
+			Key:        key,
+			Durability: e.durability,
+		},
+	}
+}
+
+func (e *contractDataEdit) apply(entry *xdr.LedgerEntry, opts Options) (*xdr.LedgerEntry, string, error) {
+	contract, _ := e.contract.String()
+	storage := "persistent"
+	if e.durability == xdr.ContractDataDurabilityTemporary {
+		storage = "temporary"
+	}
+	if e.instance {
+		return e.applyInstance(entry, contract)
+	}
+
+	name := fmt.Sprintf("contract %s %s %s", contract, storage, e.keyText)
+	if e.del {
+		if entry == nil {
+			return nil, "", fmt.Errorf("%s is not in the ledger state", name)
+		}
+		return nil, name + " deleted", nil
+	}
+
+	if entry == nil {
+		entry = &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: xdr.Uint32(opts.LedgerSequence),
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.ContractDataEntry{
+					Contract:   e.contract,
+					Key:        e.key,
+					Durability: e.durability,
+					Val:        e.val,
+				},
+			},
+		}
+		return entry, fmt.Sprintf("%s = %s (new entry)", name, e.valText), nil
+	}
+
+	entry.Data.ContractData.Val = e.val
+	return entry, fmt.Sprintf("%s = %s", name, e.valText), nil
+}
+
+// applyInstance edits one key of the contract's instance storage map.
+func (e *contractDataEdit) applyInstance(entry *xdr.LedgerEntry, contract string) (*xdr.LedgerEntry, string, error) {
+	if entry == nil || entry.Data.ContractData == nil || entry.Data.ContractData.Val.Instance == nil {
+		return nil, "", fmt.Errorf("contract instance of %s is not in the ledger state", contract)
+	}
+	inst := entry.Data.ContractData.Val.Instance
+	var storage xdr.ScMap
+	if inst.Storage != nil {
+		storage = *inst.Storage
+	}
+
+	name := fmt.Sprintf("contract %s instance %s", contract, e.keyText)
+	idx := -1
+	for i, kv := range storage {
+		if compareScVal(kv.Key, e.key) == 0 {
+			idx = i
+			break
+		}
+	}
+
+	var desc string
+	switch {
+	case e.del && idx < 0:
+		return nil, "", fmt.Errorf("%s is not set", name)
+	case e.del:
+		storage = append(storage[:idx], storage[idx+1:]...)
+		desc = name + " deleted"
+	case idx >= 0:
+		storage[idx].Val = e.val
+		desc = fmt.Sprintf("%s = %s", name, e.valText)
+	default:
+		storage = append(storage, xdr.ScMapEntry{Key: e.key, Val: e.val})
+		// The host rejects maps whose keys are out of order.
+		sort.SliceStable(storage, func(i, j int) bool { return compareScVal(storage[i].Key, storage[j].Key) < 0 })
+		desc = fmt.Sprintf("%s = %s (new key)", name, e.valText)
+	}
+	inst.Storage = &storage
+	return entry, desc, nil
+}
+
+type ttlEdit struct {
+	target    xdr.LedgerKey
+	liveUntil uint32
+	bump      bool
+}
+
+func (e *ttlEdit) ledgerKey() xdr.LedgerKey { return ttlKey(e.target) }
+
+func (e *ttlEdit) apply(entry *xdr.LedgerEntry, opts Options) (*xdr.LedgerEntry, string, error) {
+	if entry == nil {
+		entry = newTTLEntry(e.target, e.liveUntil, opts)
+		return entry, fmt.Sprintf("TTL of %s set to ledger %d (new entry)", describeKey(e.target), e.liveUntil), nil
+	}
+
+	ttl := entry.Data.Ttl
+	old := uint32(ttl.LiveUntilLedgerSeq)
+	if e.bump && old >= e.liveUntil {
+		return entry, fmt.Sprintf("TTL of %s already live until ledger %d", describeKey(e.target), old), nil
+	}
+	ttl.LiveUntilLedgerSeq = xdr.Uint32(e.liveUntil)
+	return entry, fmt.Sprintf("TTL of %s %d -> %d", describeKey(e.target), old, e.liveUntil), nil
+}
+
+func hasTTL(key xdr.LedgerKey) bool {
+	return key.Type == xdr.LedgerEntryTypeContractData || key.Type == xdr.LedgerEntryTypeContractCode
+}
+
+// ttlKey returns the key of the TTL entry belonging to a contract data or
+// code key: the SHA-256 of the key's XDR.
+func ttlKey(key xdr.LedgerKey) xdr.LedgerKey {
+	raw, _ := key.MarshalBinary()
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.LedgerKeyTtl{KeyHash: sha256.Sum256(raw)}}
+}
+
+func newTTLEntry(target xdr.LedgerKey, liveUntil uint32, opts Options) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(opts.LedgerSequence),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTtl,
+			Ttl:  &xdr.TtlEntry{KeyHash: ttlKey(target).Ttl.KeyHash, LiveUntilLedgerSeq: xdr.Uint32(liveUntil)},
+		},
+	}
+}
+
+func defaultLiveUntil(opts Options) uint32 {
+	if opts.LedgerSequence == 0 || uint64(opts.LedgerSequence)+DefaultTTLExtension > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return opts.LedgerSequence + DefaultTTLExtension
+}
+
+func describeKey(key xdr.LedgerKey) string {
+	switch key.Type {
+	case xdr.LedgerEntryTypeContractData:
+		contract, _ := key.ContractData.Contract.String()
+		if key.ContractData.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+			return fmt.Sprintf("contract %s instance", contract)
+		}
+		return fmt.Sprintf("contract %s data", contract)
+	case xdr.LedgerEntryTypeContractCode:
+		return fmt.Sprintf("code %x", key.ContractCode.Hash[:])
+	}
+	return key.Type.String()
+}
+
+func assetName(asset xdr.TrustLineAsset) string {
+	var code, issuer string
+	switch asset.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		code = string(bytes.TrimRight(asset.AlphaNum4.AssetCode[:], "\x00"))
+		issuer = asset.AlphaNum4.Issuer.Address()
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		code = string(bytes.TrimRight(asset.AlphaNum12.AssetCode[:], "\x00"))
+		issuer = asset.AlphaNum12.Issuer.Address()
+	default:
+		return asset.Type.String()
+	}
+	return code + ":" + issuer
+}
+
+func encodeKey(key xdr.LedgerKey) (string, error) {
+	raw, err := key.MarshalBinary()
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// compareScVal orders values the way the host orders map keys for the types
+// scripts can express: by type, then by value.
+func compareScVal(a, b xdr.ScVal) int {
+	if a.Type != b.Type {
+		if a.Type < b.Type {
+			return -1
+		}
+		return 1
+	}
+
+	switch a.Type {
+	case xdr.ScValTypeScvBool:
+		return cmp.Compare(boolInt(*a.B), boolInt(*b.B))
+	case xdr.ScValTypeScvU32:
+		return cmp.Compare(*a.U32, *b.U32)
+	case xdr.ScValTypeScvI32:
+		return cmp.Compare(*a.I32, *b.I32)
+	case xdr.ScValTypeScvU64:
+		return cmp.Compare(*a.U64, *b.U64)
+	case xdr.ScValTypeScvI64:
+		return cmp.Compare(*a.I64, *b.I64)
+	case xdr.ScValTypeScvTimepoint:
+		return cmp.Compare(*a.Timepoint, *b.Timepoint)
+	case xdr.ScValTypeScvDuration:
+		return cmp.Compare(*a.Duration, *b.Duration)
+	case xdr.ScValTypeScvU128:
+		if c := cmp.Compare(a.U128.Hi, b.U128.Hi); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.U128.Lo, b.U128.Lo)
+	case xdr.ScValTypeScvI128:
+		if c := cmp.Compare(a.I128.Hi, b.I128.Hi); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.I128.Lo, b.I128.Lo)
+	case xdr.ScValTypeScvSymbol:
+		return cmp.Compare(string(*a.Sym), string(*b.Sym))
+	case xdr.ScValTypeScvString:
+		return cmp.Compare(string(*a.Str), string(*b.Str))
+	case xdr.ScValTypeScvBytes:
+		return bytes.Compare(*a.Bytes, *b.Bytes)
+	case xdr.ScValTypeScvVec:
+		av, bv := **a.Vec, **b.Vec
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c := compareScVal(av[i], bv[i]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(av), len(bv))
+	}
+
+	ra, _ := a.MarshalBinary()
+	rb, _ := b.MarshalBinary()
+	return bytes.Compare(ra, rb)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package override
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func testContract(t *testing.T) string {
+	t.Helper()
+	id := make([]byte, 32)
+	id[0] = 7
+	c, err := strkey.Encode(strkey.VersionByteContract, id)
+	require.NoError(t, err)
+	return c
+}
+
+func putEntry(t *testing.T, entries map[string]string, entry xdr.LedgerEntry) string {
+	t.Helper()
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+	keyXDR, err := encodeKey(key)
+	require.NoError(t, err)
+	raw, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	entries[keyXDR] = raw
+	return keyXDR
+}
+
+func getEntry(t *testing.T, entries map[string]string, key xdr.LedgerKey) *xdr.LedgerEntry {
+	t.Helper()
+	keyXDR, err := encodeKey(key)
+	require.NoError(t, err)
+	raw, ok := entries[keyXDR]
+	if !ok {
+		return nil
+	}
+	var entry xdr.LedgerEntry
+	require.NoError(t, xdr.SafeUnmarshalBase64(raw, &entry))
+	return &entry
+}
+
+func applyText(t *testing.T, src string, entries map[string]string) []Change {
+	t.Helper()
+	stmts, err := Parse(src)
+	require.NoError(t, err)
+	changes, err := Apply(context.Background(), stmts, entries, Options{LedgerSequence: 1000})
+	require.NoError(t, err)
+	return changes
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unknown verb", "frobnicate account " + testAccount},
+		{"bad account", "set account GNOTANACCOUNT balance 1"},
+		{"unknown field", "set account " + testAccount + " flags 1"},
+		{"missing value", "set account " + testAccount + " balance"},
+		{"trailing tokens", "delete account " + testAccount + " now"},
+		{"bad scval", "set contract CAAA persistent Nope(1) = U32(1)"},
+		{"long symbol", "bump ttl contract CAAA persistent Symbol(" + fmt.Sprintf("%033d", 0) + ") to 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("# header\n\n" + tt.src)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 3")
+		})
+	}
+}
+
+func TestParseScVal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want xdr.ScValType
+	}{
+		{"Void", xdr.ScValTypeScvVoid},
+		{"Bool(true)", xdr.ScValTypeScvBool},
+		{"U32(7)", xdr.ScValTypeScvU32},
+		{"I64(-3)", xdr.ScValTypeScvI64},
+		{"U128(340282366920938463463374607431768211455)", xdr.ScValTypeScvU128},
+		{"I128(-1)", xdr.ScValTypeScvI128},
+		{"Symbol(admin)", xdr.ScValTypeScvSymbol},
+		{`String("hello world")`, xdr.ScValTypeScvString},
+		{"Bytes(deadbeef)", xdr.ScValTypeScvBytes},
+		{"Address(" + testAccount + ")", xdr.ScValTypeScvAddress},
+		{"Vec(Symbol(Balance), U64(1))", xdr.ScValTypeScvVec},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := ParseScVal(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.Type)
+		})
+	}
+
+	hi, err := ParseScVal("I128(-1)")
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Int64(-1), hi.I128.Hi)
+	assert.Equal(t, xdr.Uint64(1<<64-1), hi.I128.Lo)
+
+	_, err = ParseScVal("U32(-1)")
+	assert.Error(t, err)
+}
+
+func TestApply_AccountBalance(t *testing.T) {
+	entries := map[string]string{}
+	putEntry(t, entries, xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(testAccount), Balance: 5, SeqNum: 9},
+	}})
+
+	changes := applyText(t, "set account "+testAccount+" balance to 100 XLM\nset account "+testAccount+" sequence = 42", entries)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "account "+testAccount+" balance 0.0000005 -> 100.0000000 XLM", changes[0].Description)
+
+	acc := getEntry(t, entries, xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(testAccount)}})
+	require.NotNil(t, acc)
+	assert.Equal(t, xdr.Int64(1_000_000_000), acc.Data.Account.Balance)
+	assert.Equal(t, xdr.SequenceNumber(42), acc.Data.Account.SeqNum)
+
+	applyText(t, "delete account "+testAccount, entries)
+	assert.Empty(t, entries)
+}
+
+func TestApply_TrustlineCreated(t *testing.T) {
+	entries := map[string]string{}
+	changes := applyText(t, "set trustline "+testAccount+" USDC:"+testAccount+" balance 250.5", entries)
+	require.Len(t, changes, 1)
+	assert.Contains(t, changes[0].Description, "(new entry)")
+	require.Len(t, entries, 1)
+
+	for _, raw := range entries {
+		var entry xdr.LedgerEntry
+		require.NoError(t, xdr.SafeUnmarshalBase64(raw, &entry))
+		tl := entry.Data.TrustLine
+		require.NotNil(t, tl)
+		assert.Equal(t, xdr.Int64(2_505_000_000), tl.Balance)
+		assert.Equal(t, xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag), tl.Flags)
+	}
+}
+
+func TestApply_ContractDataCreatesTTL(t *testing.T) {
+	contract := testContract(t)
+	entries := map[string]string{}
+	changes := applyText(t, "set contract "+contract+" persistent Symbol(admin) = Address("+testAccount+")", entries)
+	require.Len(t, changes, 1)
+	assert.Contains(t, changes[0].Description, "(new entry)")
+	require.Len(t, entries, 2, "a new contract entry needs a TTL entry to be live")
+
+	stmts, err := Parse("set contract " + contract + " persistent Symbol(admin) = Void")
+	require.NoError(t, err)
+	key := stmts[0].edit.ledgerKey()
+
+	ttl := getEntry(t, entries, ttlKey(key))
+	require.NotNil(t, ttl)
+	assert.Equal(t, xdr.Uint32(1000+DefaultTTLExtension), ttl.Data.Ttl.LiveUntilLedgerSeq)
+
+	applyText(t, "bump ttl contract "+contract+" persistent Symbol(admin) to 500", entries)
+	ttl = getEntry(t, entries, ttlKey(key))
+	assert.Equal(t, xdr.Uint32(1000+DefaultTTLExtension), ttl.Data.Ttl.LiveUntilLedgerSeq, "bump never lowers the TTL")
+
+	applyText(t, "set ttl contract "+contract+" persistent Symbol(admin) to 500", entries)
+	ttl = getEntry(t, entries, ttlKey(key))
+	assert.Equal(t, xdr.Uint32(500), ttl.Data.Ttl.LiveUntilLedgerSeq)
+
+	applyText(t, "delete contract "+contract+" persistent Symbol(admin)", entries)
+	assert.Empty(t, entries, "deleting an entry also deletes its TTL")
+}
+
+func TestApply_InstanceStorage(t *testing.T) {
+	contract := testContract(t)
+	addr, err := parseAddress(contract)
+	require.NoError(t, err)
+
+	sym := func(s string) xdr.ScVal {
+		v := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+	}
+	storage := xdr.ScMap{{Key: sym("admin"), Val: sym("x")}, {Key: sym("paused"), Val: sym("y")}}
+	entries := map[string]string{}
+	putEntry(t, entries, xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   addr,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+				Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset},
+				Storage:    &storage,
+			}},
+		},
+	}})
+
+	changes := applyText(t, "set contract "+contract+" instance Symbol(paused) = Bool(true)\n"+
+		"set contract "+contract+" instance Symbol(fee) = U32(3)\n"+
+		"delete contract "+contract+" instance Symbol(admin)", entries)
+	require.Len(t, changes, 3)
+	assert.Contains(t, changes[1].Description, "(new key)")
+
+	stmts, err := Parse("bump ttl contract " + contract + " instance to 5")
+	require.NoError(t, err)
+	inst := getEntry(t, entries, stmts[0].edit.(*ttlEdit).target)
+	require.NotNil(t, inst)
+	got := *inst.Data.ContractData.Val.Instance.Storage
+	require.Len(t, got, 2)
+	assert.Equal(t, xdr.ScSymbol("fee"), *got[0].Key.Sym, "keys stay sorted")
+	assert.Equal(t, xdr.ScSymbol("paused"), *got[1].Key.Sym)
+	assert.True(t, *got[1].Val.B)
+}
+
+func TestApply_FetchesMissingEntries(t *testing.T) {
+	base := map[string]string{}
+	accKey := putEntry(t, base, xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(testAccount), Balance: 5, SeqNum: 77},
+	}})
+
+	var fetched []string
+	fetch := func(ctx context.Context, keys []string) (map[string]string, error) {
+		fetched = append(fetched, keys...)
+		return base, nil
+	}
+
+	stmts, err := Parse("set account " + testAccount + " balance 1 XLM")
+	require.NoError(t, err)
+	entries := map[string]string{}
+	_, err = Apply(context.Background(), stmts, entries, Options{Fetch: fetch})
+	require.NoError(t, err)
+	assert.Equal(t, []string{accKey}, fetched)
+
+	acc := getEntry(t, entries, xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(testAccount)}})
+	assert.Equal(t, xdr.SequenceNumber(77), acc.Data.Account.SeqNum, "fields the statement does not touch come from the network")
+	assert.Equal(t, xdr.Int64(10_000_000), acc.Data.Account.Balance)
+}
+
+func TestApply_DeleteMissingFails(t *testing.T) {
+	stmts, err := Parse("delete account " + testAccount)
+	require.NoError(t, err)
+	_, err = Apply(context.Background(), stmts, map[string]string{}, Options{})
+	assert.Error(t, err)
+}
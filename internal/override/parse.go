@@ -0,0 +1,587 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package override compiles human-readable ledger state edits into the
+// base64 ledger entries handed to the simulator.
+//
+// One statement per line; '#' starts a comment. The words "to" and "=" before
+// a value are optional.
+//
+//	set account G... balance to 100 XLM
+//	set account G... sequence 123456
+//	delete account G...
+//	set trustline G... USDC:G... balance 250.5
+//	set trustline G... USDC:G... limit 1000
+//	delete trustline G... USDC:G...
+//	set contract C... persistent Symbol(admin) = Address(G...)
+//	set contract C... instance Symbol(paused) = Bool(true)
+//	delete contract C... temporary Vec(Symbol(nonce), U64(7))
+//	bump ttl contract C... persistent Symbol(admin) to 60000000
+//	bump ttl contract C... instance to 60000000
+//	set ttl code <wasm-hash-hex> to 50000000
+package override
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Statement is one parsed edit.
+type Statement struct {
+	Line int
+	Text string
+	edit edit
+}
+
+// Parse reads an override script, one statement per line.
+func Parse(src string) ([]Statement, error) {
+	var stmts []Statement
+	for i, line := range strings.Split(src, "\n") {
+		if idx := commentIndex(line); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		stmt, err := ParseStatement(line)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("line %d: %v", i+1, err))
+		}
+		stmt.Line = i + 1
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// ParseStatement parses a single statement, as given to --set.
+func ParseStatement(text string) (Statement, error) {
+	toks, err := tokenize(text)
+	if err != nil {
+		return Statement{}, err
+	}
+	if len(toks) < 2 {
+		return Statement{}, fmt.Errorf("incomplete statement %q", text)
+	}
+
+	p := &parser{toks: toks}
+	var e edit
+	switch verb := strings.ToLower(p.next()); verb {
+	case "set", "delete":
+		e, err = p.parseEdit(verb == "delete")
+	case "bump":
+		if !strings.EqualFold(p.next(), "ttl") {
+			return Statement{}, fmt.Errorf("expected 'bump ttl'")
+		}
+		e, err = p.parseTTL(true)
+	default:
+		return Statement{}, fmt.Errorf("unknown verb %q (expected set, delete or bump)", verb)
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+	if p.more() {
+		return Statement{}, fmt.Errorf("unexpected %q", p.peek())
+	}
+	return Statement{Text: strings.TrimSpace(text), edit: e}, nil
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) more() bool { return p.pos < len(p.toks) }
+
+func (p *parser) peek() string {
+	if !p.more() {
+		return ""
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	if p.more() {
+		p.pos++
+	}
+	return t
+}
+
+// value returns the next token, skipping an optional "to" or "=".
+func (p *parser) value() (string, error) {
+	if t := strings.ToLower(p.peek()); t == "to" || t == "=" {
+		p.pos++
+	}
+	if !p.more() {
+		return "", fmt.Errorf("missing value")
+	}
+	return p.next(), nil
+}
+
+func (p *parser) parseEdit(del bool) (edit, error) {
+	switch target := strings.ToLower(p.next()); target {
+	case "account":
+		return p.parseAccount(del)
+	case "trustline":
+		return p.parseTrustline(del)
+	case "contract":
+		return p.parseContractData(del)
+	case "ttl":
+		if del {
+			return nil, fmt.Errorf("TTL entries cannot be deleted; delete the entry they belong to")
+		}
+		return p.parseTTL(false)
+	default:
+		return nil, fmt.Errorf("unknown target %q (expected account, trustline, contract or ttl)", target)
+	}
+}
+
+func (p *parser) parseAccount(del bool) (edit, error) {
+	id, err := parseAccountID(p.next())
+	if err != nil {
+		return nil, err
+	}
+	e := &accountEdit{id: id, del: del}
+	if del {
+		return e, nil
+	}
+
+	if strings.EqualFold(p.peek(), "native") {
+		p.pos++
+	}
+	switch e.field = strings.ToLower(p.next()); e.field {
+	case "balance":
+		e.value, err = p.amount()
+	case "sequence", "seq":
+		e.field = "sequence"
+		var tok string
+		if tok, err = p.value(); err == nil {
+			e.value, err = strconv.ParseInt(tok, 10, 64)
+		}
+	default:
+		return nil, fmt.Errorf("unknown account field %q (expected balance or sequence)", e.field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseTrustline(del bool) (edit, error) {
+	id, err := parseAccountID(p.next())
+	if err != nil {
+		return nil, err
+	}
+	asset, err := parseTrustLineAsset(p.next())
+	if err != nil {
+		return nil, err
+	}
+	e := &trustlineEdit{id: id, asset: asset, del: del}
+	if del {
+		return e, nil
+	}
+
+	switch e.field = strings.ToLower(p.next()); e.field {
+	case "balance", "limit":
+		e.value, err = p.amount()
+	default:
+		return nil, fmt.Errorf("unknown trustline field %q (expected balance or limit)", e.field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseContractData(del bool) (edit, error) {
+	contract, err := parseAddress(p.next())
+	if err != nil {
+		return nil, err
+	}
+	if contract.Type != xdr.ScAddressTypeScAddressTypeContract {
+		return nil, fmt.Errorf("contract storage needs a C... address")
+	}
+
+	e := &contractDataEdit{contract: contract, del: del}
+	switch storage := strings.ToLower(p.next()); storage {
+	case "persistent":
+		e.durability = xdr.ContractDataDurabilityPersistent
+	case "temporary":
+		e.durability = xdr.ContractDataDurabilityTemporary
+	case "instance":
+		e.instance = true
+		e.durability = xdr.ContractDataDurabilityPersistent
+	default:
+		return nil, fmt.Errorf("unknown storage %q (expected persistent, temporary or instance)", storage)
+	}
+
+	e.keyText = p.next()
+	if e.key, err = ParseScVal(e.keyText); err != nil {
+		return nil, err
+	}
+	if del {
+		return e, nil
+	}
+	if e.valText, err = p.value(); err != nil {
+		return nil, err
+	}
+	if e.val, err = ParseScVal(e.valText); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// parseTTL parses "contract C... <storage> <key> <ledger>" or
+// "code <hash> <ledger>" after the ttl keyword.
+func (p *parser) parseTTL(bump bool) (edit, error) {
+	e := &ttlEdit{bump: bump}
+	switch target := strings.ToLower(p.next()); target {
+	case "contract":
+		contract, err := parseAddress(p.next())
+		if err != nil {
+			return nil, err
+		}
+		durability := xdr.ContractDataDurabilityPersistent
+		key := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+		switch storage := strings.ToLower(p.next()); storage {
+		case "persistent", "temporary":
+			if storage == "temporary" {
+				durability = xdr.ContractDataDurabilityTemporary
+			}
+			if key, err = ParseScVal(p.next()); err != nil {
+				return nil, err
+			}
+		case "instance":
+		default:
+			return nil, fmt.Errorf("unknown storage %q (expected persistent, temporary or instance)", storage)
+		}
+		e.target = xdr.LedgerKey{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.LedgerKeyContractData{
+				Contract:   contract,
+				Key:        key,
+				Durability: durability,
+			},
+		}
+	case "code":
+		raw, err := hex.DecodeString(p.next())
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("code TTL needs a 32-byte hex WASM hash")
+		}
+		var hash xdr.Hash
+		copy(hash[:], raw)
+		e.target = xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: hash}}
+	default:
+		return nil, fmt.Errorf("unknown TTL target %q (expected contract or code)", target)
+	}
+
+	tok, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := strconv.ParseUint(tok, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ledger sequence %q", tok)
+	}
+	e.liveUntil = uint32(ledger)
+	return e, nil
+}
+
+// amount parses "<n>", "<n> XLM" or "<n> stroops". Values without a unit use
+// seven decimal places like XLM.
+func (p *parser) amount() (int64, error) {
+	tok, err := p.value()
+	if err != nil {
+		return 0, err
+	}
+	unit := strings.ToLower(p.peek())
+	switch unit {
+	case "stroops", "stroop":
+		p.pos++
+		return strconv.ParseInt(tok, 10, 64)
+	case "xlm":
+		p.pos++
+	}
+	v, err := amount.ParseInt64(tok)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", tok)
+	}
+	return v, nil
+}
+
+// ParseScVal parses a typed literal such as Symbol(admin), U64(10),
+// I128(-5), Address(G...), Bytes(00ff), String("hi"), Bool(true), Void or
+// Vec(Symbol(a), U32(1)).
+func ParseScVal(tok string) (xdr.ScVal, error) {
+	name, arg, hasArg := strings.Cut(tok, "(")
+	if hasArg {
+		if !strings.HasSuffix(arg, ")") {
+			return xdr.ScVal{}, fmt.Errorf("unbalanced parentheses in %q", tok)
+		}
+		arg = strings.TrimSpace(arg[:len(arg)-1])
+	}
+
+	switch strings.ToLower(name) {
+	case "void":
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case "bool":
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid Bool %q", arg)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case "u32":
+		v, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid U32 %q", arg)
+		}
+		u := xdr.Uint32(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case "i32":
+		v, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid I32 %q", arg)
+		}
+		i := xdr.Int32(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i}, nil
+	case "u64":
+		v, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid U64 %q", arg)
+		}
+		u := xdr.Uint64(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}, nil
+	case "i64":
+		v, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid I64 %q", arg)
+		}
+		i := xdr.Int64(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i}, nil
+	case "timepoint":
+		v, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid Timepoint %q", arg)
+		}
+		tp := xdr.TimePoint(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &tp}, nil
+	case "duration":
+		v, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid Duration %q", arg)
+		}
+		d := xdr.Duration(v)
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &d}, nil
+	case "u128", "i128":
+		return parseInt128(strings.ToLower(name) == "i128", arg)
+	case "symbol":
+		if len(arg) > 32 {
+			return xdr.ScVal{}, fmt.Errorf("symbol %q is longer than 32 characters", arg)
+		}
+		sym := xdr.ScSymbol(arg)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, nil
+	case "string":
+		s := xdr.ScString(unquote(arg))
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}, nil
+	case "bytes":
+		raw, err := hex.DecodeString(arg)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid hex in Bytes(%s)", arg)
+		}
+		b := xdr.ScBytes(raw)
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+	case "address":
+		addr, err := parseAddress(arg)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+	case "vec":
+		var items xdr.ScVec
+		if arg != "" {
+			for _, part := range splitTopLevel(arg) {
+				item, err := ParseScVal(strings.TrimSpace(part))
+				if err != nil {
+					return xdr.ScVal{}, err
+				}
+				items = append(items, item)
+			}
+		}
+		vec := &items
+		return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}, nil
+	default:
+		return xdr.ScVal{}, fmt.Errorf("unknown value %q (expected a typed literal such as Symbol(x), U64(1) or Address(G...))", tok)
+	}
+}
+
+func parseInt128(signed bool, arg string) (xdr.ScVal, error) {
+	n, ok := new(big.Int).SetString(arg, 10)
+	if !ok {
+		return xdr.ScVal{}, fmt.Errorf("invalid 128-bit integer %q", arg)
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	if signed {
+		half := new(big.Int).Rsh(limit, 1)
+		if n.Cmp(new(big.Int).Neg(half)) < 0 || n.Cmp(half) >= 0 {
+			return xdr.ScVal{}, fmt.Errorf("%s out of range for I128", arg)
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, limit) // two's complement
+		}
+	} else if n.Sign() < 0 || n.Cmp(limit) >= 0 {
+		return xdr.ScVal{}, fmt.Errorf("%s out of range for U128", arg)
+	}
+
+	mask := new(big.Int).SetUint64(^uint64(0))
+	lo := new(big.Int).And(n, mask).Uint64()
+	hi := new(big.Int).Rsh(n, 64).Uint64()
+	if signed {
+		parts := xdr.Int128Parts{Hi: xdr.Int64(hi), Lo: xdr.Uint64(lo)}
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}, nil
+	}
+	parts := xdr.UInt128Parts{Hi: xdr.Uint64(hi), Lo: xdr.Uint64(lo)}
+	return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &parts}, nil
+}
+
+func parseAccountID(s string) (xdr.AccountId, error) {
+	var id xdr.AccountId
+	if err := id.SetAddress(s); err != nil {
+		return id, fmt.Errorf("invalid account %q", s)
+	}
+	return id, nil
+}
+
+func parseAddress(s string) (xdr.ScAddress, error) {
+	switch {
+	case strings.HasPrefix(s, "G"):
+		id, err := parseAccountID(s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}, nil
+	case strings.HasPrefix(s, "C"):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, fmt.Errorf("invalid contract address %q", s)
+		}
+		var cid xdr.ContractId
+		copy(cid[:], raw)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &cid}, nil
+	default:
+		return xdr.ScAddress{}, fmt.Errorf("invalid address %q (expected G... or C...)", s)
+	}
+}
+
+// parseTrustLineAsset parses CODE:ISSUER.
+func parseTrustLineAsset(s string) (xdr.TrustLineAsset, error) {
+	code, issuer, ok := strings.Cut(s, ":")
+	if !ok || code == "" || len(code) > 12 {
+		return xdr.TrustLineAsset{}, fmt.Errorf("invalid asset %q (expected CODE:ISSUER)", s)
+	}
+	asset, err := xdr.BuildAsset(assetType(code), issuer, code)
+	if err != nil {
+		return xdr.TrustLineAsset{}, fmt.Errorf("invalid asset %q: %v", s, err)
+	}
+	return asset.ToTrustLineAsset(), nil
+}
+
+func assetType(code string) string {
+	if len(code) <= 4 {
+		return "credit_alphanum4"
+	}
+	return "credit_alphanum12"
+}
+
+// tokenize splits on whitespace outside parentheses and double quotes.
+func tokenize(s string) ([]string, error) {
+	var toks []string
+	var cur strings.Builder
+	depth := 0
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case (r == ' ' || r == '\t') && depth == 0:
+			if cur.Len() > 0 {
+				toks = append(toks, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if depth != 0 || inQuote {
+		return nil, fmt.Errorf("unbalanced parentheses or quotes")
+	}
+	if cur.Len() > 0 {
+		toks = append(toks, cur.String())
+	}
+	return toks, nil
+}
+
+// splitTopLevel splits on commas outside nested parentheses and quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inQuote := false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// commentIndex returns the index of a '#' that is not inside quotes.
+func commentIndex(line string) int {
+	inQuote := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inQuote = !inQuote
+		case '#':
+			if !inQuote {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
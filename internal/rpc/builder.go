@@ -24,12 +24,15 @@ type clientBuilder struct {
 	cacheEnabled bool
 	config       *NetworkConfig
 	httpClient   *http.Client
+	backend      Backend
+	cassette     *Cassette
 }
 
 func newBuilder() *clientBuilder {
 	return &clientBuilder{
 		network:      Mainnet,
 		cacheEnabled: true,
+		backend:      BackendAuto,
 	}
 }
 
@@ -117,12 +120,41 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithBackend selects where transactions and ledgers are read from. The
+// default is BackendAuto, or the ERST_RPC_BACKEND environment variable.
+func WithBackend(backend Backend) ClientOption {
+	return func(b *clientBuilder) error {
+		parsed, err := ParseBackend(string(backend))
+		if err != nil {
+			return err
+		}
+		b.backend = parsed
+		return nil
+	}
+}
+
+// WithCassette records every HTTP exchange of the client into c, or answers
+// them from c without network access, depending on the cassette's mode.
+func WithCassette(c *Cassette) ClientOption {
+	return func(b *clientBuilder) error {
+		b.cassette = c
+		return nil
+	}
+}
+
 func NewClient(opts ...ClientOption) (*Client, error) {
 	builder := newBuilder()
 
 	if builder.token == "" {
 		builder.token = os.Getenv("ERST_RPC_TOKEN")
 	}
+	if env := os.Getenv("ERST_RPC_BACKEND"); env != "" {
+		backend, err := ParseBackend(env)
+		if err != nil {
+			return nil, err
+		}
+		builder.backend = backend
+	}
 
 	for _, opt := range opts {
 		if err := opt(builder); err != nil {
@@ -196,6 +228,14 @@ func (b *clientBuilder) build() (*Client, error) {
 		b.httpClient = createHTTPClient(b.token)
 	}
 
+	if b.cassette != nil {
+		// The cassette sits above retries and authentication so it sees one
+		// exchange per logical request and never the raw token.
+		hc := *b.httpClient
+		hc.Transport = b.cassette.Transport(hc.Transport)
+		b.httpClient = &hc
+	}
+
 	if len(b.altURLs) == 0 && b.horizonURL != "" {
 		b.altURLs = []string{b.horizonURL}
 	}
@@ -223,5 +263,7 @@ func (b *clientBuilder) build() (*Client, error) {
 		CacheEnabled: b.cacheEnabled,
 		failures:     make(map[string]int),
 		lastFailure:  make(map[string]time.Time),
+		Backend:      b.backend,
+		rpcCapable:   make(map[string]bool),
 	}, nil
 }
@@ -0,0 +1,403 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// CassetteVersion is the cassette file format version.
+const CassetteVersion = 1
+
+// redactedValue replaces credentials in recorded headers and query strings.
+const redactedValue = "REDACTED"
+
+// sensitiveHeaders are never written to a cassette.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// sensitiveQueryParams are credential query parameters some providers
+// accept in place of an Authorization header.
+var sensitiveQueryParams = []string{"api_key", "apikey", "token", "access_token"}
+
+// CassetteMode says whether a cassette captures or serves traffic.
+type CassetteMode int
+
+const (
+	// CassetteRecord passes requests through and appends every exchange to
+	// the cassette file.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay answers requests from the cassette without touching the
+	// network.
+	CassetteReplay
+)
+
+// Cassette is a file of recorded HTTP exchanges. A recording cassette is
+// rewritten after every exchange, so a run that exits early still leaves a
+// usable file. A replaying cassette serves exchanges that match on method,
+// URL and body in the order they were recorded.
+type Cassette struct {
+	path string
+	mode CassetteMode
+
+	mu   sync.Mutex
+	file cassetteFile
+	used []bool
+}
+
+type cassetteFile struct {
+	Version    int       `json:"version"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Meta holds whatever the recording command needs to repeat the run,
+	// such as the network it resolved.
+	Meta         map[string]string `json:"meta,omitempty"`
+	Interactions []Interaction     `json:"interactions"`
+}
+
+// Interaction is one recorded request and its outcome. Error is set instead
+// of Response when the request failed without an HTTP response.
+type Interaction struct {
+	Request  RecordedRequest   `json:"request"`
+	Response *RecordedResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// RecordedRequest is a request with credentials redacted.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+	// BodyBase64 holds bodies that are not valid UTF-8.
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+// RecordedResponse is a response with credentials redacted.
+type RecordedResponse struct {
+	Status     int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// NewRecordingCassette starts an empty cassette at path. The file is
+// created, or truncated, on the first recorded exchange.
+func NewRecordingCassette(path string) *Cassette {
+	return &Cassette{
+		path: path,
+		mode: CassetteRecord,
+		file: cassetteFile{Version: CassetteVersion, RecordedAt: time.Now().UTC()},
+	}
+}
+
+// LoadCassette opens path for replay.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read cassette %s: %v", path, err))
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, path)
+	}
+	if file.Version != CassetteVersion {
+		return nil, errors.WrapValidationError(fmt.Sprintf("cassette %s has version %d, expected %d", path, file.Version, CassetteVersion))
+	}
+	return &Cassette{
+		path: path,
+		mode: CassetteReplay,
+		file: file,
+		used: make([]bool, len(file.Interactions)),
+	}, nil
+}
+
+// Path returns the cassette file path.
+func (c *Cassette) Path() string {
+	return c.path
+}
+
+// Mode returns whether c records or replays.
+func (c *Cassette) Mode() CassetteMode {
+	return c.mode
+}
+
+// Meta returns the metadata value stored under key.
+func (c *Cassette) Meta(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Meta[key]
+}
+
+// SetMeta stores a metadata value. It is written with the next exchange.
+func (c *Cassette) SetMeta(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file.Meta == nil {
+		c.file.Meta = make(map[string]string)
+	}
+	c.file.Meta[key] = value
+}
+
+// Interactions returns a copy of the exchanges in c.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.file.Interactions...)
+}
+
+// Transport wraps next. When recording, requests go through next and are
+// captured; when replaying, next is never called.
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	if c.mode == CassetteReplay {
+		return &replayTransport{cassette: c}
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordTransport{cassette: c, next: next}
+}
+
+type recordTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper interface
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	interaction := Interaction{Request: recordRequest(req, body)}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		interaction.Error = err.Error()
+		t.cassette.append(interaction)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recorded := &RecordedResponse{Status: resp.StatusCode, Headers: redactHeaders(resp.Header)}
+	recorded.Body, recorded.BodyBase64 = encodeBody(respBody)
+	interaction.Response = recorded
+	t.cassette.append(interaction)
+	return resp, nil
+}
+
+func (c *Cassette) append(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.file.Interactions = append(c.file.Interactions, interaction)
+	if err := c.saveLocked(); err != nil {
+		logger.Logger.Warn("Failed to write cassette", "path", c.path, "error", err)
+	}
+}
+
+// saveLocked writes the cassette through a temporary file so a crash never
+// leaves a truncated cassette behind.
+func (c *Cassette) saveLocked() error {
+	data, err := json.MarshalIndent(c.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+type replayTransport struct {
+	cassette *Cassette
+}
+
+// RoundTrip implements http.RoundTripper interface
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := recordRequest(req, body)
+
+	interaction, ok := t.cassette.match(recorded)
+	if !ok {
+		return nil, errors.WrapCassetteMiss(t.cassette.path, recorded.Method, recorded.URL)
+	}
+	if interaction.Response == nil {
+		return nil, fmt.Errorf("replayed from %s: %s", t.cassette.path, interaction.Error)
+	}
+
+	respBody, err := decodeBody(interaction.Response.Body, interaction.Response.BodyBase64)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "cassette response body")
+	}
+	status := interaction.Response.Status
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// match returns the first unserved interaction for req. Once every matching
+// interaction has been served the last one is repeated, so polling loops
+// replay the state they ended on.
+func (c *Cassette) match(req RecordedRequest) (Interaction, bool) {
+	key := interactionKey(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := -1
+	for i, interaction := range c.file.Interactions {
+		if interactionKey(interaction.Request) != key {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Interaction{}, false
+	}
+	return c.file.Interactions[last], true
+}
+
+// interactionKey identifies a request for matching. JSON bodies are
+// compared without their JSON-RPC id and with keys in canonical order.
+func interactionKey(req RecordedRequest) string {
+	body := req.Body
+	if req.BodyBase64 != "" {
+		body = req.BodyBase64
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &obj); err == nil {
+		delete(obj, "id")
+		if canonical, err := json.Marshal(obj); err == nil {
+			body = string(canonical)
+		}
+	}
+	return req.Method + " " + req.URL + "\n" + body
+}
+
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	recorded := RecordedRequest{
+		Method:  req.Method,
+		URL:     redactURL(req.URL),
+		Headers: redactHeaders(req.Header),
+	}
+	recorded.Body, recorded.BodyBase64 = encodeBody(body)
+	return recorded
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, redactedValue)
+		}
+	}
+	return out
+}
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	changed := false
+	for name := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(name, sensitive) {
+				query.Set(name, redactedValue)
+				changed = true
+			}
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	redacted.User = nil
+	return redacted.String()
+}
+
+func encodeBody(body []byte) (text, b64 string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(body)
+}
+
+func decodeBody(text, b64 string) ([]byte, error) {
+	if b64 != "" {
+		return base64.StdEncoding.DecodeString(b64)
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	var healthCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(string(body), "getLatestLedger"):
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"sequence":100}}`)
+		case strings.Contains(string(body), "getTransaction"):
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"status":"SUCCESS","txHash":"abc","envelopeXdr":"ENV","ledger":90}}`)
+		case strings.Contains(string(body), "getHealth"):
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"status":"healthy","latestLedger":%d}}`, healthCalls.Add(1))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "run.cassette.json")
+	newClient := func(c *Cassette) *Client {
+		client, err := NewClient(
+			WithNetwork(Testnet),
+			WithHorizonURL(server.URL),
+			WithSorobanURL(server.URL),
+			WithToken("s3cret-token"),
+			WithCassette(c),
+		)
+		require.NoError(t, err)
+		return client
+	}
+
+	ctx := context.Background()
+	recorder := NewRecordingCassette(path)
+	recorder.SetMeta("network", "testnet")
+	client := newClient(recorder)
+	tx, err := client.GetTransaction(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ENV", tx.EnvelopeXdr)
+	for want := uint32(1); want <= 2; want++ {
+		health, err := client.GetHealth(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, health.Result.LatestLedger)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cret-token")
+	assert.Len(t, recorder.Interactions(), 4)
+
+	player, err := LoadCassette(path)
+	require.NoError(t, err)
+	assert.Equal(t, "testnet", player.Meta("network"))
+	client = newClient(player)
+
+	tx, err = client.GetTransaction(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ENV", tx.EnvelopeXdr)
+	for _, want := range []uint32{1, 2, 2} {
+		health, err := client.GetHealth(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, health.Result.LatestLedger, "exchanges replay in order, then the last one repeats")
+	}
+
+	// Failover reports the miss inside an AllNodesFailedError.
+	_, err = client.GetTransaction(ctx, "def")
+	assert.ErrorContains(t, err, errors.ErrCassetteMiss.Error())
+}
+
+func TestCassette_RedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "cookie-secret"})
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewRecordingCassette(path)
+	httpClient := &http.Client{Transport: recorder.Transport(nil)}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/ledgers/1?api_key=key-secret&limit=1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer header-secret")
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body), "the caller still sees the real response")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"header-secret", "key-secret", "cookie-secret"} {
+		assert.NotContains(t, string(data), secret)
+	}
+
+	// Replay matches the redacted form, whatever key the replaying user has.
+	player, err := LoadCassette(path)
+	require.NoError(t, err)
+	httpClient = &http.Client{Transport: player.Transport(nil)}
+	resp, err = httpClient.Get(server.URL + "/ledgers/1?api_key=other-key&limit=1")
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestCassette_MatchIgnoresJSONRPCID(t *testing.T) {
+	c := &Cassette{
+		mode: CassetteReplay,
+		file: cassetteFile{Interactions: []Interaction{{
+			Request:  RecordedRequest{Method: http.MethodPost, URL: "http://rpc", Body: `{"id":1,"jsonrpc":"2.0","method":"getHealth"}`},
+			Response: &RecordedResponse{Status: http.StatusOK, Body: "recorded"},
+		}}},
+		used: make([]bool, 1),
+	}
+
+	_, ok := c.match(RecordedRequest{Method: http.MethodPost, URL: "http://rpc", Body: `{"method":"getHealth","jsonrpc":"2.0","id":7}`})
+	assert.True(t, ok)
+	_, ok = c.match(RecordedRequest{Method: http.MethodPost, URL: "http://rpc", Body: `{"method":"getEvents","jsonrpc":"2.0","id":1}`})
+	assert.False(t, ok)
+
+	_, err := (&http.Client{Transport: c.Transport(nil)}).Get("http://rpc/unknown")
+	assert.ErrorIs(t, err, errors.ErrCassetteMiss)
+}
+
+func TestLoadCassette_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadCassette(filepath.Join(dir, "missing.json"))
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+
+	path := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":99,"interactions":[]}`), 0o644))
+	_, err = LoadCassette(path)
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+}
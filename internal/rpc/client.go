@@ -109,6 +109,10 @@ type Client struct {
 	CacheEnabled bool
 	failures     map[string]int
 	lastFailure  map[string]time.Time
+	// Backend selects Soroban RPC or Horizon for transaction and ledger
+	// reads. The zero value reads from Horizon only.
+	Backend    Backend
+	rpcCapable map[string]bool
 }
 
 // NodeFailure records a failure for a specific RPC URL
@@ -354,6 +358,17 @@ func (c *Client) getTransactionAttempt(ctx context.Context, hash string) (*Trans
 	)
 	defer span.End()
 
+	if url := c.sorobanRPCURL(ctx); url != "" {
+		resp, err := c.getTransactionRPC(ctx, url, hash)
+		if err == nil || c.Backend == BackendRPC {
+			if err != nil {
+				span.RecordError(err)
+			}
+			return resp, err
+		}
+		logger.Logger.Warn("Soroban RPC could not serve transaction, falling back to Horizon", "hash", hash, "error", err)
+	}
+
 	logger.Logger.Debug("Fetching transaction details", "hash", hash, "url", c.HorizonURL)
 
 	tx, err := c.Horizon.TransactionDetail(hash)
@@ -472,6 +487,17 @@ func (c *Client) getLedgerHeaderAttempt(ctx context.Context, sequence uint32) (*
 
 	logger.Logger.Debug("Fetching ledger header", "sequence", sequence, "network", c.Network, "url", c.HorizonURL)
 
+	if url := c.sorobanRPCURL(ctx); url != "" {
+		resp, err := c.getLedgerHeaderRPC(ctx, url, sequence)
+		if err == nil || c.Backend == BackendRPC {
+			if err != nil {
+				span.RecordError(err)
+			}
+			return resp, err
+		}
+		logger.Logger.Warn("Soroban RPC could not serve ledger header, falling back to Horizon", "sequence", sequence, "error", err)
+	}
+
 	// Fetch ledger from Horizon
 	ledger, err := c.Horizon.LedgerDetail(sequence)
 	if err != nil {
@@ -605,7 +631,9 @@ func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[strin
 	return nil, &AllNodesFailedError{Failures: failures}
 }
 
-func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []string) (map[string]string, error) {
+// postGetLedgerEntries sends a single getLedgerEntries request to the current
+// Soroban RPC endpoint and returns the decoded response and the URL used.
+func (c *Client) postGetLedgerEntries(ctx context.Context, keysToFetch []string) (*GetLedgerEntriesResponse, string, error) {
 	logger.Logger.Debug("Fetching ledger entries", "count", len(keysToFetch), "url", c.HorizonURL)
 	reqBody := GetLedgerEntriesRequest{
 		Jsonrpc: "2.0",
@@ -616,7 +644,7 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, errors.WrapMarshalFailed(err)
+		return nil, "", errors.WrapMarshalFailed(err)
 	}
 
 	targetURL := c.HorizonURL
@@ -628,32 +656,41 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 
 	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
 	if err != nil {
-		return nil, errors.WrapRPCConnectionFailed(err)
+		return nil, targetURL, errors.WrapRPCConnectionFailed(err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.getHTTPClient().Do(req)
 	if err != nil {
-		return nil, errors.WrapRPCConnectionFailed(err)
+		return nil, targetURL, errors.WrapRPCConnectionFailed(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusRequestEntityTooLarge {
-		return nil, errors.WrapRPCResponseTooLarge(targetURL)
+		return nil, targetURL, errors.WrapRPCResponseTooLarge(targetURL)
 	}
 
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+		return nil, targetURL, errors.WrapUnmarshalFailed(err, "body read error")
 	}
 
 	var rpcResp GetLedgerEntriesResponse
 	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
-		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+		return nil, targetURL, errors.WrapUnmarshalFailed(err, string(respBytes))
 	}
 
 	if rpcResp.Error != nil {
-		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+		return nil, targetURL, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return &rpcResp, targetURL, nil
+}
+
+func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []string) (map[string]string, error) {
+	rpcResp, targetURL, err := c.postGetLedgerEntries(ctx, keysToFetch)
+	if err != nil {
+		return nil, err
 	}
 
 	entries := make(map[string]string)
@@ -701,8 +738,23 @@ func (c *Client) GetAccountTransactions(ctx context.Context, account string, lim
 		Order:      horizonclient.OrderDesc,
 	}
 
+	// Soroban RPC cannot filter by account, so Horizon is preferred and RPC
+	// is only scanned when Horizon is unavailable.
+	rpcURL := ""
+	if c.Backend == BackendRPC {
+		rpcURL = c.sorobanRPCURL(ctx)
+		if rpcURL == "" {
+			return nil, errors.WrapRPCConnectionFailed(fmt.Errorf("no Soroban RPC endpoint available for account transactions"))
+		}
+		return c.getAccountTransactionsRPC(ctx, rpcURL, account, limit)
+	}
+
 	page, err := c.Horizon.Transactions(req)
 	if err != nil {
+		if rpcURL = c.sorobanRPCURL(ctx); rpcURL != "" {
+			logger.Logger.Warn("Horizon could not serve account transactions, scanning recent ledgers via Soroban RPC", "account", account, "error", err)
+			return c.getAccountTransactionsRPC(ctx, rpcURL, account, limit)
+		}
 		logger.Logger.Error("Failed to fetch account transactions", "account", account, "error", err)
 		return nil, errors.WrapRPCConnectionFailed(err)
 	}
@@ -720,6 +772,55 @@ func (c *Client) GetAccountTransactions(ctx context.Context, account string, lim
 	return summaries, nil
 }
 
+// ledgerTransactionsPageSize is the largest page Horizon serves.
+const ledgerTransactionsPageSize = 200
+
+// GetLedgerTransactions fetches every transaction in a ledger, including
+// failed ones, in the order they were applied.
+func (c *Client) GetLedgerTransactions(ctx context.Context, sequence uint32) ([]*TransactionResponse, error) {
+	if url := c.sorobanRPCURL(ctx); url != "" {
+		txs, err := c.getLedgerTransactionsRPC(ctx, url, sequence)
+		if err == nil || c.Backend == BackendRPC {
+			return txs, err
+		}
+		logger.Logger.Warn("Soroban RPC could not serve ledger transactions, falling back to Horizon", "sequence", sequence, "error", err)
+	}
+
+	logger.Logger.Debug("Fetching ledger transactions", "sequence", sequence, "url", c.HorizonURL)
+
+	req := horizonclient.TransactionRequest{
+		ForLedger:     uint(sequence),
+		IncludeFailed: true,
+		Order:         horizonclient.OrderAsc,
+		Limit:         ledgerTransactionsPageSize,
+	}
+
+	var txs []*TransactionResponse
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := c.Horizon.Transactions(req)
+		if err != nil {
+			logger.Logger.Error("Failed to fetch ledger transactions", "sequence", sequence, "error", err)
+			return nil, c.handleLedgerError(err, sequence)
+		}
+
+		records := page.Embedded.Records
+		for _, tx := range records {
+			txs = append(txs, ParseTransactionResponse(tx))
+		}
+		if len(records) < ledgerTransactionsPageSize {
+			break
+		}
+		req.Cursor = records[len(records)-1].PagingToken()
+	}
+
+	logger.Logger.Debug("Ledger transactions retrieved", "sequence", sequence, "count", len(txs))
+	return txs, nil
+}
+
 func getTransactionStatus(tx hProtocol.Transaction) string {
 	if tx.Successful {
 		return "success"
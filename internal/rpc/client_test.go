@@ -24,6 +24,7 @@ import (
 type mockHorizonClient struct {
 	TransactionDetailFunc func(hash string) (hProtocol.Transaction, error)
 	LedgerDetailFunc      func(sequence uint32) (hProtocol.Ledger, error)
+	TransactionsFunc      func(request horizonclient.TransactionRequest) (hProtocol.TransactionsPage, error)
 }
 
 func (m *mockHorizonClient) TransactionDetail(hash string) (hProtocol.Transaction, error) {
@@ -101,6 +102,9 @@ func (m *mockHorizonClient) AsyncSubmitTransaction(transaction *txnbuild.Transac
 	return hProtocol.AsyncTransactionSubmissionResponse{}, nil
 }
 func (m *mockHorizonClient) Transactions(request horizonclient.TransactionRequest) (hProtocol.TransactionsPage, error) {
+	if m.TransactionsFunc != nil {
+		return m.TransactionsFunc(request)
+	}
 	return hProtocol.TransactionsPage{}, nil
 }
 func (m *mockHorizonClient) OrderBook(request horizonclient.OrderBookRequest) (hProtocol.OrderBookSummary, error) {
@@ -307,6 +311,8 @@ func TestGetLedgerEntries_WithVerification(t *testing.T) {
 		// The actual verification logic is tested separately in verification_test.go
 		assert.True(t, true, "Verification integration is documented")
 	})
+}
+
 func TestGetLedgerEntries_ResponseTooLarge(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusRequestEntityTooLarge)
@@ -0,0 +1,172 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// Limits Soroban RPC enforces on getEvents requests.
+const (
+	MaxEventFilters         = 5
+	MaxEventFilterContracts = 5
+	MaxEventTopicSegments   = 4
+	DefaultEventsLimit      = 100
+)
+
+// Topic pattern wildcards.
+const (
+	EventTopicWildcard      = "*"
+	EventTopicMultiWildcard = "**"
+)
+
+// Event types accepted in EventFilter.Type.
+const (
+	EventTypeContract   = "contract"
+	EventTypeSystem     = "system"
+	EventTypeDiagnostic = "diagnostic"
+)
+
+// EventFilter selects events by type, emitting contract and topics. An event
+// matches when it matches every non-empty field; Topics match when any one
+// pattern does.
+type EventFilter struct {
+	// Type is one of the EventType* constants, or empty for all types.
+	Type        string   `json:"type,omitempty"`
+	ContractIDs []string `json:"contractIds,omitempty"`
+	// Topics are patterns of base64 ScVal XDR segments. "*" matches any
+	// single topic and a trailing "**" any number of remaining topics.
+	Topics [][]string `json:"topics,omitempty"`
+}
+
+// EventsQuery is a page request for GetEvents. Either StartLedger or Cursor
+// must be set; a cursor resumes after the last event of a previous page.
+type EventsQuery struct {
+	StartLedger uint32
+	EndLedger   uint32
+	Filters     []EventFilter
+	Cursor      string
+	Limit       uint
+}
+
+// Event is a contract event as returned by getEvents. Topic and Value hold
+// base64 ScVal XDR.
+type Event struct {
+	Type                     string   `json:"type"`
+	Ledger                   uint32   `json:"ledger"`
+	LedgerClosedAt           string   `json:"ledgerClosedAt"`
+	ContractID               string   `json:"contractId"`
+	ID                       string   `json:"id"`
+	TxHash                   string   `json:"txHash"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+}
+
+// EventsPage is one page of GetEvents results. Cursor continues after the
+// last event returned, or after the scanned ledger range when the page is
+// empty.
+type EventsPage struct {
+	Events       []Event `json:"events"`
+	LatestLedger uint32  `json:"latestLedger"`
+	Cursor       string  `json:"cursor"`
+}
+
+type getEventsParams struct {
+	StartLedger uint32          `json:"startLedger,omitempty"`
+	EndLedger   uint32          `json:"endLedger,omitempty"`
+	Filters     []EventFilter   `json:"filters"`
+	Pagination  *eventsPaginate `json:"pagination,omitempty"`
+}
+
+type eventsPaginate struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+// Validate checks q against the limits Soroban RPC enforces, so a bad
+// filter fails before a round trip.
+func (q EventsQuery) Validate() error {
+	if q.StartLedger == 0 && q.Cursor == "" {
+		return errors.WrapValidationError("getEvents needs a start ledger or a cursor")
+	}
+	if q.Cursor == "" && q.EndLedger != 0 && q.EndLedger <= q.StartLedger {
+		return errors.WrapValidationError(fmt.Sprintf("end ledger %d must be after start ledger %d", q.EndLedger, q.StartLedger))
+	}
+	if len(q.Filters) > MaxEventFilters {
+		return errors.WrapValidationError(fmt.Sprintf("at most %d event filters are allowed", MaxEventFilters))
+	}
+	for _, f := range q.Filters {
+		switch f.Type {
+		case "", EventTypeContract, EventTypeSystem, EventTypeDiagnostic:
+		default:
+			return errors.WrapValidationError(fmt.Sprintf("unknown event type %q", f.Type))
+		}
+		if len(f.ContractIDs) > MaxEventFilterContracts {
+			return errors.WrapValidationError(fmt.Sprintf("at most %d contract IDs are allowed per filter", MaxEventFilterContracts))
+		}
+		for _, id := range f.ContractIDs {
+			if _, err := strkey.Decode(strkey.VersionByteContract, id); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid contract ID %q", id))
+			}
+		}
+		for _, topic := range f.Topics {
+			if err := validateTopicPattern(topic); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateTopicPattern(topic []string) error {
+	fixed := len(topic)
+	for i, segment := range topic {
+		if segment != EventTopicMultiWildcard {
+			continue
+		}
+		if i != len(topic)-1 {
+			return errors.WrapValidationError(`"**" is only allowed as the last topic segment`)
+		}
+		fixed--
+	}
+	if len(topic) == 0 || fixed > MaxEventTopicSegments {
+		return errors.WrapValidationError(fmt.Sprintf("a topic pattern needs 1 to %d segments", MaxEventTopicSegments))
+	}
+	return nil
+}
+
+// GetEvents fetches one page of contract events matching q from Soroban RPC.
+func (c *Client) GetEvents(ctx context.Context, q EventsQuery) (*EventsPage, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	params := getEventsParams{Filters: q.Filters}
+	if params.Filters == nil {
+		params.Filters = []EventFilter{}
+	}
+	// Soroban RPC rejects a start ledger alongside a cursor.
+	if q.Cursor == "" {
+		params.StartLedger = q.StartLedger
+	}
+	params.EndLedger = q.EndLedger
+	if q.Cursor != "" || q.Limit > 0 {
+		params.Pagination = &eventsPaginate{Cursor: q.Cursor, Limit: q.Limit}
+	}
+
+	logger.Logger.Debug("Fetching events", "start_ledger", params.StartLedger, "cursor", q.Cursor)
+
+	var page EventsPage
+	if err := c.callSoroban(ctx, "getEvents", params, &page); err != nil {
+		return nil, err
+	}
+	logger.Logger.Debug("Events fetched", "count", len(page.Events), "latest_ledger", page.LatestLedger)
+	return &page, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContractID = "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"
+
+func TestEventsQuery_Validate(t *testing.T) {
+	assert.ErrorIs(t, EventsQuery{}.Validate(), errors.ErrValidationFailed, "start ledger or cursor required")
+	assert.NoError(t, EventsQuery{Cursor: "0000000001-0000000001"}.Validate())
+	assert.ErrorIs(t, EventsQuery{StartLedger: 10, EndLedger: 5}.Validate(), errors.ErrValidationFailed)
+
+	valid := EventsQuery{StartLedger: 1, Filters: []EventFilter{{
+		Type:        EventTypeContract,
+		ContractIDs: []string{testContractID},
+		Topics:      [][]string{{"AAAADwAAAAh0cmFuc2Zlcg==", "*", "**"}},
+	}}}
+	assert.NoError(t, valid.Validate())
+
+	tests := map[string]EventFilter{
+		"bad type":        {Type: "bogus"},
+		"bad contract":    {ContractIDs: []string{"GABC"}},
+		"inner **":        {Topics: [][]string{{"**", "*"}}},
+		"empty topic":     {Topics: [][]string{{}}},
+		"too many topics": {Topics: [][]string{{"*", "*", "*", "*", "*"}}},
+	}
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := EventsQuery{StartLedger: 1, Filters: []EventFilter{filter}}
+			assert.ErrorIs(t, q.Validate(), errors.ErrValidationFailed)
+		})
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	var got []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "getLatestLedger" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":20}}`))
+			return
+		}
+		assert.Equal(t, "getEvents", req.Method)
+		got = append(got, req.Params)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{
+			"events":[{"type":"contract","ledger":12,"ledgerClosedAt":"2025-01-01T00:00:00Z","contractId":"` + testContractID + `",
+				"id":"0000000012-0000000001","txHash":"abc","inSuccessfulContractCall":true,
+				"topic":["AAAADwAAAAh0cmFuc2Zlcg=="],"value":"AAAAAQ=="}],
+			"latestLedger":20,"cursor":"0000000012-0000000001"}}`))
+	}))
+	defer server.Close()
+
+	client := newRPCTestClient(server.URL, BackendAuto, nil)
+	page, err := client.GetEvents(context.Background(), EventsQuery{
+		StartLedger: 10,
+		Filters:     []EventFilter{{ContractIDs: []string{testContractID}}},
+		Limit:       50,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, uint32(12), page.Events[0].Ledger)
+	assert.Equal(t, []string{"AAAADwAAAAh0cmFuc2Zlcg=="}, page.Events[0].Topic)
+	assert.Equal(t, uint32(20), page.LatestLedger)
+	assert.Equal(t, float64(10), got[0]["startLedger"])
+
+	_, err = client.GetEvents(context.Background(), EventsQuery{Cursor: page.Cursor})
+	require.NoError(t, err)
+	assert.NotContains(t, got[1], "startLedger", "a cursor replaces the start ledger")
+	assert.Equal(t, map[string]interface{}{"cursor": page.Cursor}, got[1]["pagination"])
+}
+
+func TestGetEvents_RPCError(t *testing.T) {
+	server, _ := newSorobanRPCServer(t, map[string]string{"getLatestLedger": `{"sequence":20}`})
+
+	_, err := newRPCTestClient(server.URL, BackendAuto, nil).GetEvents(context.Background(), EventsQuery{StartLedger: 1})
+	assert.ErrorIs(t, err, errors.ErrRPCError)
+
+	_, err = newRPCTestClient(server.URL, BackendHorizon, nil).GetEvents(context.Background(), EventsQuery{StartLedger: 1})
+	assert.ErrorIs(t, err, errors.ErrValidationFailed, "events need Soroban RPC")
+}
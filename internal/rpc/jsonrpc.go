@@ -0,0 +1,85 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// jsonRPCRequest is a JSON-RPC 2.0 request with object params, as taken by
+// the newer Soroban RPC methods.
+type jsonRPCRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// callSoroban invokes method on the Soroban RPC endpoint requireSorobanRPC
+// selects and decodes its result into result. Methods that need the endpoint
+// for several calls select it once and use callSorobanAt.
+func (c *Client) callSoroban(ctx context.Context, method string, params interface{}, result interface{}) error {
+	url, err := c.requireSorobanRPC(ctx, method)
+	if err != nil {
+		return err
+	}
+	return c.callSorobanAt(ctx, url, method, params, result)
+}
+
+// callSorobanAt is callSoroban against an explicit endpoint.
+func (c *Client) callSorobanAt(ctx context.Context, targetURL, method string, params interface{}, result interface{}) error {
+	bodyBytes, err := json.Marshal(jsonRPCRequest{Jsonrpc: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return errors.WrapRPCResponseTooLarge(targetURL)
+	}
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp jsonRPCResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+	if rpcResp.Error != nil {
+		return errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return errors.WrapUnmarshalFailed(err, string(rpcResp.Result))
+	}
+	return nil
+}
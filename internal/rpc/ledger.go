@@ -5,6 +5,7 @@ package rpc
 
 import (
 	"encoding/base64"
+	"sort"
 	"time"
 
 	"github.com/dotandev/hintents/internal/errors"
@@ -144,6 +145,142 @@ func ExtractLedgerEntriesFromMeta(resultMetaXDR string) (map[string]string, erro
 	return entries, nil
 }
 
+// LedgerEntryChanges splits the ledger entry changes recorded in a
+// transaction's result meta into the state the transaction observed and the
+// state it left behind. All keys and entries are base64 XDR.
+type LedgerEntryChanges struct {
+	Before  map[string]string // entry values before the transaction touched them
+	After   map[string]string // entries created or updated by the transaction
+	Removed []string          // keys deleted by the transaction
+}
+
+// ExtractLedgerEntryChanges decodes a TransactionResultMeta and collects its
+// changes in apply order: changes before operations, each operation, then
+// changes after operations.
+func ExtractLedgerEntryChanges(resultMetaXDR string) (*LedgerEntryChanges, error) {
+	metaBytes, err := base64.StdEncoding.DecodeString(resultMetaXDR)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "result meta")
+	}
+
+	var resultMeta xdr.TransactionResultMeta
+	if err := resultMeta.UnmarshalBinary(metaBytes); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "result meta binary")
+	}
+
+	var sets []xdr.LedgerEntryChanges
+	meta := resultMeta.TxApplyProcessing
+	switch meta.V {
+	case 0:
+		if meta.Operations != nil {
+			for _, op := range *meta.Operations {
+				sets = append(sets, op.Changes)
+			}
+		}
+	case 1:
+		if meta.V1 != nil {
+			sets = append(sets, meta.V1.TxChanges)
+			for _, op := range meta.V1.Operations {
+				sets = append(sets, op.Changes)
+			}
+		}
+	case 2:
+		if v2 := meta.V2; v2 != nil {
+			sets = append(sets, v2.TxChangesBefore)
+			for _, op := range v2.Operations {
+				sets = append(sets, op.Changes)
+			}
+			sets = append(sets, v2.TxChangesAfter)
+		}
+	case 3:
+		if v3 := meta.V3; v3 != nil {
+			sets = append(sets, v3.TxChangesBefore)
+			for _, op := range v3.Operations {
+				sets = append(sets, op.Changes)
+			}
+			sets = append(sets, v3.TxChangesAfter)
+		}
+	case 4:
+		if v4 := meta.V4; v4 != nil {
+			sets = append(sets, v4.TxChangesBefore)
+			for _, op := range v4.Operations {
+				sets = append(sets, op.Changes)
+			}
+			sets = append(sets, v4.TxChangesAfter)
+		}
+	}
+
+	changes := &LedgerEntryChanges{
+		Before: make(map[string]string),
+		After:  make(map[string]string),
+	}
+	removed := make(map[string]bool)
+	for _, set := range sets {
+		for _, change := range set {
+			switch change.Type {
+			case xdr.LedgerEntryChangeTypeLedgerEntryState:
+				if change.State == nil {
+					continue
+				}
+				keyXDR, entryXDR, ok := encodeEntryPair(*change.State)
+				if !ok {
+					continue
+				}
+				if _, seen := changes.Before[keyXDR]; !seen {
+					changes.Before[keyXDR] = entryXDR
+				}
+			case xdr.LedgerEntryChangeTypeLedgerEntryCreated, xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+				entry := change.Created
+				if entry == nil {
+					entry = change.Updated
+				}
+				if entry == nil {
+					continue
+				}
+				keyXDR, entryXDR, ok := encodeEntryPair(*entry)
+				if !ok {
+					continue
+				}
+				changes.After[keyXDR] = entryXDR
+				delete(removed, keyXDR)
+			case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+				if change.Removed == nil {
+					continue
+				}
+				keyXDR, err := EncodeLedgerKey(*change.Removed)
+				if err != nil {
+					continue
+				}
+				delete(changes.After, keyXDR)
+				removed[keyXDR] = true
+			}
+		}
+	}
+	for k := range removed {
+		changes.Removed = append(changes.Removed, k)
+	}
+	sort.Strings(changes.Removed)
+
+	return changes, nil
+}
+
+// encodeEntryPair returns the base64 XDR of an entry and its key.
+func encodeEntryPair(entry xdr.LedgerEntry) (string, string, bool) {
+	key := ledgerKeyFromEntry(entry)
+	if key == nil {
+		return "", "", false
+	}
+	keyXDR, err := EncodeLedgerKey(*key)
+	if err != nil {
+		return "", "", false
+	}
+	entryXDR, err := EncodeLedgerEntry(entry)
+	if err != nil {
+		return "", "", false
+	}
+	return keyXDR, entryXDR, true
+}
+
 // extractFromLedgerEntryChanges processes operation-level changes
 func extractFromLedgerEntryChanges(operations []xdr.OperationMeta, entries map[string]string) {
 	for _, op := range operations {
@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountEntry(address string, balance int64) xdr.LedgerEntry {
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: xdr.MustAddress(address),
+				Balance:   xdr.Int64(balance),
+			},
+		},
+	}
+}
+
+func encodeResultMeta(t *testing.T, before, ops, after xdr.LedgerEntryChanges) string {
+	t.Helper()
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{},
+				},
+			},
+		},
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 3,
+			V3: &xdr.TransactionMetaV3{
+				TxChangesBefore: before,
+				Operations:      []xdr.OperationMeta{{Changes: ops}},
+				TxChangesAfter:  after,
+			},
+		},
+	}
+	raw, err := meta.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+const (
+	testAccountA = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	testAccountB = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+)
+
+func TestExtractLedgerEntryChanges(t *testing.T) {
+	a0 := accountEntry(testAccountA, 100)
+	a1 := accountEntry(testAccountA, 90)
+	a2 := accountEntry(testAccountA, 80)
+	b := accountEntry(testAccountB, 5)
+	bKey := ledgerKeyFromEntry(b)
+
+	metaXDR := encodeResultMeta(t,
+		xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &a0},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &a1},
+		},
+		xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &a1},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &a2},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &b},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: bKey},
+		},
+		nil,
+	)
+
+	changes, err := ExtractLedgerEntryChanges(metaXDR)
+	require.NoError(t, err)
+
+	aKey, aBefore, ok := encodeEntryPair(a0)
+	require.True(t, ok)
+	_, aAfter, _ := encodeEntryPair(a2)
+	bKeyXDR, bBefore, _ := encodeEntryPair(b)
+
+	assert.Equal(t, aBefore, changes.Before[aKey], "the first observed state is kept")
+	assert.Equal(t, bBefore, changes.Before[bKeyXDR])
+	assert.Equal(t, map[string]string{aKey: aAfter}, changes.After)
+	assert.Equal(t, []string{bKeyXDR}, changes.Removed)
+
+	_, err = ExtractLedgerEntryChanges("not base64!")
+	assert.Error(t, err)
+}
+
+func TestGetLedgerTransactions_Paginates(t *testing.T) {
+	var requests []horizonclient.TransactionRequest
+	mock := &mockHorizonClient{
+		TransactionsFunc: func(req horizonclient.TransactionRequest) (hProtocol.TransactionsPage, error) {
+			requests = append(requests, req)
+			n := ledgerTransactionsPageSize
+			if req.Cursor != "" {
+				n = 3
+			}
+			var page hProtocol.TransactionsPage
+			for i := 0; i < n; i++ {
+				idx := len(requests)*1000 + i
+				page.Embedded.Records = append(page.Embedded.Records, hProtocol.Transaction{
+					Hash:       fmt.Sprintf("tx%d", idx),
+					PT:         fmt.Sprintf("%d", idx),
+					Successful: i%2 == 0,
+					Ledger:     42,
+				})
+			}
+			return page, nil
+		},
+	}
+	client := &Client{Horizon: mock, Network: Testnet}
+
+	txs, err := client.GetLedgerTransactions(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, txs, ledgerTransactionsPageSize+3)
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, uint(42), requests[0].ForLedger)
+	assert.True(t, requests[0].IncludeFailed)
+	assert.Equal(t, horizonclient.OrderAsc, requests[0].Order)
+	assert.Equal(t, fmt.Sprintf("%d", 1000+ledgerTransactionsPageSize-1), requests[1].Cursor)
+
+	assert.Equal(t, "tx1000", txs[0].Hash)
+	assert.True(t, txs[0].Successful)
+	assert.False(t, txs[1].Successful)
+	assert.Equal(t, uint32(42), txs[0].LedgerSequence)
+}
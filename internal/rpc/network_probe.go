@@ -18,7 +18,8 @@ func ResolveNetwork(ctx context.Context, hash string, token string) (Network, er
 }
 
 // resolveNetwork is the testable core. overrideURLs maps each Network to a
-// custom Horizon URL; when nil or a network is absent, the default URL is used.
+// custom URL that replaces both its Horizon and Soroban RPC endpoints; when
+// nil or a network is absent, the default URLs are used.
 func resolveNetwork(ctx context.Context, hash string, token string, overrideURLs map[Network]string) (Network, error) {
 	candidates := []Network{Mainnet, Testnet, Futurenet}
 
@@ -35,7 +36,7 @@ func resolveNetwork(ctx context.Context, hash string, token string, overrideURLs
 
 			opts := []ClientOption{WithNetwork(n), WithToken(token)}
 			if url, ok := overrideURLs[n]; ok {
-				opts = append(opts, WithHorizonURL(url))
+				opts = append(opts, WithHorizonURL(url), WithSorobanURL(url))
 			}
 
 			client, err := NewClient(opts...)
@@ -0,0 +1,307 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// networkSettingIDs are the CONFIG_SETTING entries that affect budget
+// metering and fees. All of them exist since protocol 20, so every network
+// that runs Soroban returns the full set.
+var networkSettingIDs = []xdr.ConfigSettingId{
+	xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+	xdr.ConfigSettingIdConfigSettingContractComputeV0,
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+	xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+	xdr.ConfigSettingIdConfigSettingContractEventsV0,
+	xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+	xdr.ConfigSettingIdConfigSettingContractCostParamsCpuInstructions,
+	xdr.ConfigSettingIdConfigSettingContractCostParamsMemoryBytes,
+	xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes,
+	xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes,
+}
+
+// optionalNetworkSettingIDs are requested alongside networkSettingIDs but
+// were introduced by later protocols, so older networks may not return them.
+var optionalNetworkSettingIDs = []xdr.ConfigSettingId{
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0,
+}
+
+// CostParam is one entry of a host cost model, indexed by xdr.ContractCostType.
+// LinearTerm is stored exactly as on-chain, scaled by 2^CostModelLinearScaleBits.
+type CostParam struct {
+	ConstTerm  int64 `json:"const_term"`
+	LinearTerm int64 `json:"linear_term"`
+}
+
+// CostModelLinearScaleBits is the fixed-point shift applied to on-chain linear
+// cost terms (COST_MODEL_LIN_TERM_SCALE_BITS in soroban-env-host).
+const CostModelLinearScaleBits = 7
+
+// NetworkSettings holds the Soroban network settings validators used to meter
+// and charge transactions, read from CONFIG_SETTING ledger entries.
+type NetworkSettings struct {
+	// LedgerSequence is the ledger the config was requested for.
+	LedgerSequence uint32 `json:"ledger_sequence"`
+	// LatestLedger is the ledger the RPC node served the entries from.
+	LatestLedger uint32 `json:"latest_ledger"`
+
+	ContractMaxSizeBytes       uint32 `json:"contract_max_size_bytes"`
+	ContractDataKeySizeBytes   uint32 `json:"contract_data_key_size_bytes"`
+	ContractDataEntrySizeBytes uint32 `json:"contract_data_entry_size_bytes"`
+
+	// Compute limits and fees
+	LedgerMaxInstructions           int64  `json:"ledger_max_instructions"`
+	TxMaxInstructions               int64  `json:"tx_max_instructions"`
+	TxMemoryLimit                   uint32 `json:"tx_memory_limit"`
+	FeeRatePerInstructionsIncrement int64  `json:"fee_rate_per_instructions_increment"`
+
+	// Ledger access limits and fees
+	TxMaxDiskReadEntries    uint32 `json:"tx_max_disk_read_entries"`
+	TxMaxDiskReadBytes      uint32 `json:"tx_max_disk_read_bytes"`
+	TxMaxWriteLedgerEntries uint32 `json:"tx_max_write_ledger_entries"`
+	TxMaxWriteBytes         uint32 `json:"tx_max_write_bytes"`
+	FeeDiskReadLedgerEntry  int64  `json:"fee_disk_read_ledger_entry"`
+	FeeWriteLedgerEntry     int64  `json:"fee_write_ledger_entry"`
+	FeeDiskRead1Kb          int64  `json:"fee_disk_read_1kb"`
+	// FeeWrite1Kb and TxMaxFootprintEntries come from the protocol 23
+	// ledger cost extension and are zero on older networks.
+	FeeWrite1Kb           int64  `json:"fee_write_1kb,omitempty"`
+	TxMaxFootprintEntries uint32 `json:"tx_max_footprint_entries,omitempty"`
+
+	// Other per-transaction fees and limits
+	FeeHistorical1Kb             int64  `json:"fee_historical_1kb"`
+	TxMaxContractEventsSizeBytes uint32 `json:"tx_max_contract_events_size_bytes"`
+	FeeContractEvents1Kb         int64  `json:"fee_contract_events_1kb"`
+	TxMaxSizeBytes               uint32 `json:"tx_max_size_bytes"`
+	FeeTxSize1Kb                 int64  `json:"fee_tx_size_1kb"`
+
+	CPUCostParams []CostParam `json:"cpu_cost_params,omitempty"`
+	MemCostParams []CostParam `json:"mem_cost_params,omitempty"`
+
+	// UpgradedAfter lists settings whose entries were last modified after
+	// LedgerSequence. RPC only serves current state, so for these the values
+	// are newer than what the transaction was actually charged with.
+	UpgradedAfter []string `json:"upgraded_after,omitempty"`
+}
+
+// LookupCostParam returns the cost model entry for typ, if the network defines it.
+func LookupCostParam(params []CostParam, typ xdr.ContractCostType) (CostParam, bool) {
+	idx := int(typ)
+	if idx < 0 || idx >= len(params) {
+		return CostParam{}, false
+	}
+	return params[idx], true
+}
+
+// NetworkSettingsKeys returns the base64 LedgerKeys of the CONFIG_SETTING
+// entries read by GetNetworkSettings.
+func NetworkSettingsKeys() ([]string, error) {
+	keys := make([]string, 0, len(networkSettingIDs)+len(optionalNetworkSettingIDs))
+	for _, id := range append(slices.Clone(networkSettingIDs), optionalNetworkSettingIDs...) {
+		key, err := EncodeLedgerKey(xdr.LedgerKey{
+			Type:          xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id},
+		})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func networkSettingsCacheKey(passphrase string, ledgerSeq uint32) string {
+	return fmt.Sprintf("network_settings:%s:%d", passphrase, ledgerSeq)
+}
+
+// GetNetworkSettings fetches the network's CONFIG_SETTING ledger entries and
+// returns them as NetworkSettings for ledgerSeq. Results are cached per
+// ledger when the client cache is enabled, so repeated replays of
+// transactions from the same ledger only hit RPC once.
+func (c *Client) GetNetworkSettings(ctx context.Context, ledgerSeq uint32) (*NetworkSettings, error) {
+	cacheKey := networkSettingsCacheKey(c.Config.NetworkPassphrase, ledgerSeq)
+	if c.CacheEnabled {
+		if raw, hit, err := Get(cacheKey); err != nil {
+			logger.Logger.Warn("Cache read failed", "error", err)
+		} else if hit {
+			var cfg NetworkSettings
+			if err := json.Unmarshal([]byte(raw), &cfg); err == nil {
+				logger.Logger.Debug("Network settings cache hit", "ledger", ledgerSeq)
+				return &cfg, nil
+			}
+		}
+	}
+
+	keys, err := NetworkSettingsKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []NodeFailure
+	for attempt := 0; attempt < len(c.AltURLs); attempt++ {
+		rpcResp, targetURL, err := c.postGetLedgerEntries(ctx, keys)
+		if err == nil {
+			var cfg *NetworkSettings
+			cfg, err = parseNetworkSettingsResponse(rpcResp, ledgerSeq)
+			if err == nil {
+				c.markSuccess(c.HorizonURL)
+				logger.Logger.Info("Network settings fetched", "ledger", ledgerSeq, "url", targetURL)
+				if c.CacheEnabled {
+					if raw, mErr := json.Marshal(cfg); mErr == nil {
+						if err := Set(cacheKey, string(raw)); err != nil {
+							logger.Logger.Warn("Failed to cache network settings", "error", err)
+						}
+					}
+				}
+				return cfg, nil
+			}
+		}
+
+		c.markFailure(c.HorizonURL)
+		failures = append(failures, NodeFailure{URL: c.HorizonURL, Reason: err})
+
+		if attempt < len(c.AltURLs)-1 {
+			logger.Logger.Warn("Retrying with fallback Soroban RPC...", "error", err)
+			if !c.rotateURL() {
+				break
+			}
+		}
+	}
+	return nil, &AllNodesFailedError{Failures: failures}
+}
+
+func parseNetworkSettingsResponse(resp *GetLedgerEntriesResponse, ledgerSeq uint32) (*NetworkSettings, error) {
+	cfg := &NetworkSettings{
+		LedgerSequence: ledgerSeq,
+		LatestLedger:   uint32(resp.Result.LatestLedger),
+	}
+
+	seen := make(map[xdr.ConfigSettingId]bool, len(networkSettingIDs))
+	for _, entry := range resp.Result.Entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(entry.Xdr, &data); err != nil {
+			return nil, errors.WrapUnmarshalFailed(err, "config setting entry")
+		}
+		setting, ok := data.GetConfigSetting()
+		if !ok {
+			return nil, errors.WrapValidationError(fmt.Sprintf("ledger entry %s is not a config setting", entry.Key))
+		}
+
+		applyConfigSetting(cfg, setting)
+		seen[setting.ConfigSettingId] = true
+
+		if ledgerSeq > 0 && uint32(entry.LastModifiedLedger) > ledgerSeq {
+			cfg.UpgradedAfter = append(cfg.UpgradedAfter, setting.ConfigSettingId.String())
+		}
+	}
+
+	for _, id := range networkSettingIDs {
+		if !seen[id] {
+			return nil, errors.WrapValidationError(fmt.Sprintf("network did not return config setting %s", id))
+		}
+	}
+	return cfg, nil
+}
+
+// ParseNetworkSettings builds NetworkSettings from CONFIG_SETTING entries keyed
+// by ledger key, each value being a base64 LedgerEntry or LedgerEntryData.
+// It is used for snapshots and metadata that already carry the entries.
+func ParseNetworkSettings(entries map[string]string, ledgerSeq uint32) (*NetworkSettings, bool) {
+	cfg := &NetworkSettings{LedgerSequence: ledgerSeq}
+	found := false
+	for _, raw := range entries {
+		var setting xdr.ConfigSettingEntry
+		var entry xdr.LedgerEntry
+		var data xdr.LedgerEntryData
+		switch {
+		case xdr.SafeUnmarshalBase64(raw, &entry) == nil && entry.Data.Type == xdr.LedgerEntryTypeConfigSetting:
+			setting = *entry.Data.ConfigSetting
+		case xdr.SafeUnmarshalBase64(raw, &data) == nil && data.Type == xdr.LedgerEntryTypeConfigSetting:
+			setting = *data.ConfigSetting
+		default:
+			continue
+		}
+		applyConfigSetting(cfg, setting)
+		found = true
+	}
+	return cfg, found
+}
+
+func applyConfigSetting(cfg *NetworkSettings, setting xdr.ConfigSettingEntry) {
+	switch setting.ConfigSettingId {
+	case xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes:
+		if v, ok := setting.GetContractMaxSizeBytes(); ok {
+			cfg.ContractMaxSizeBytes = uint32(v)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractComputeV0:
+		if v, ok := setting.GetContractCompute(); ok {
+			cfg.LedgerMaxInstructions = int64(v.LedgerMaxInstructions)
+			cfg.TxMaxInstructions = int64(v.TxMaxInstructions)
+			cfg.FeeRatePerInstructionsIncrement = int64(v.FeeRatePerInstructionsIncrement)
+			cfg.TxMemoryLimit = uint32(v.TxMemoryLimit)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractLedgerCostV0:
+		if v, ok := setting.GetContractLedgerCost(); ok {
+			cfg.TxMaxDiskReadEntries = uint32(v.TxMaxDiskReadEntries)
+			cfg.TxMaxDiskReadBytes = uint32(v.TxMaxDiskReadBytes)
+			cfg.TxMaxWriteLedgerEntries = uint32(v.TxMaxWriteLedgerEntries)
+			cfg.TxMaxWriteBytes = uint32(v.TxMaxWriteBytes)
+			cfg.FeeDiskReadLedgerEntry = int64(v.FeeDiskReadLedgerEntry)
+			cfg.FeeWriteLedgerEntry = int64(v.FeeWriteLedgerEntry)
+			cfg.FeeDiskRead1Kb = int64(v.FeeDiskRead1Kb)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0:
+		if v, ok := setting.GetContractLedgerCostExt(); ok {
+			cfg.TxMaxFootprintEntries = uint32(v.TxMaxFootprintEntries)
+			cfg.FeeWrite1Kb = int64(v.FeeWrite1Kb)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0:
+		if v, ok := setting.GetContractHistoricalData(); ok {
+			cfg.FeeHistorical1Kb = int64(v.FeeHistorical1Kb)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractEventsV0:
+		if v, ok := setting.GetContractEvents(); ok {
+			cfg.TxMaxContractEventsSizeBytes = uint32(v.TxMaxContractEventsSizeBytes)
+			cfg.FeeContractEvents1Kb = int64(v.FeeContractEvents1Kb)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractBandwidthV0:
+		if v, ok := setting.GetContractBandwidth(); ok {
+			cfg.TxMaxSizeBytes = uint32(v.TxMaxSizeBytes)
+			cfg.FeeTxSize1Kb = int64(v.FeeTxSize1Kb)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractCostParamsCpuInstructions:
+		if v, ok := setting.GetContractCostParamsCpuInsns(); ok {
+			cfg.CPUCostParams = convertCostParams(v)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractCostParamsMemoryBytes:
+		if v, ok := setting.GetContractCostParamsMemBytes(); ok {
+			cfg.MemCostParams = convertCostParams(v)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes:
+		if v, ok := setting.GetContractDataKeySizeBytes(); ok {
+			cfg.ContractDataKeySizeBytes = uint32(v)
+		}
+	case xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes:
+		if v, ok := setting.GetContractDataEntrySizeBytes(); ok {
+			cfg.ContractDataEntrySizeBytes = uint32(v)
+		}
+	}
+}
+
+func convertCostParams(params xdr.ContractCostParams) []CostParam {
+	out := make([]CostParam, len(params))
+	for i, p := range params {
+		out[i] = CostParam{ConstTerm: int64(p.ConstTerm), LinearTerm: int64(p.LinearTerm)}
+	}
+	return out
+}
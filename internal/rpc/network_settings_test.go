@@ -0,0 +1,186 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfigSetting(id xdr.ConfigSettingId) xdr.ConfigSettingEntry {
+	u32 := func(v uint32) *xdr.Uint32 { x := xdr.Uint32(v); return &x }
+	entry := xdr.ConfigSettingEntry{ConfigSettingId: id}
+	switch id {
+	case xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes:
+		entry.ContractMaxSizeBytes = u32(131072)
+	case xdr.ConfigSettingIdConfigSettingContractComputeV0:
+		entry.ContractCompute = &xdr.ConfigSettingContractComputeV0{
+			LedgerMaxInstructions:           500000000,
+			TxMaxInstructions:               400000000,
+			FeeRatePerInstructionsIncrement: 25,
+			TxMemoryLimit:                   41943040,
+		}
+	case xdr.ConfigSettingIdConfigSettingContractLedgerCostV0:
+		entry.ContractLedgerCost = &xdr.ConfigSettingContractLedgerCostV0{
+			TxMaxDiskReadEntries:   100,
+			FeeDiskReadLedgerEntry: 6250,
+			FeeWriteLedgerEntry:    10000,
+		}
+	case xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0:
+		entry.ContractLedgerCostExt = &xdr.ConfigSettingContractLedgerCostExtV0{TxMaxFootprintEntries: 100, FeeWrite1Kb: 3500}
+	case xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0:
+		entry.ContractHistoricalData = &xdr.ConfigSettingContractHistoricalDataV0{FeeHistorical1Kb: 16235}
+	case xdr.ConfigSettingIdConfigSettingContractEventsV0:
+		entry.ContractEvents = &xdr.ConfigSettingContractEventsV0{TxMaxContractEventsSizeBytes: 16384, FeeContractEvents1Kb: 10000}
+	case xdr.ConfigSettingIdConfigSettingContractBandwidthV0:
+		entry.ContractBandwidth = &xdr.ConfigSettingContractBandwidthV0{TxMaxSizeBytes: 132096, FeeTxSize1Kb: 1624}
+	case xdr.ConfigSettingIdConfigSettingContractCostParamsCpuInstructions:
+		params := make(xdr.ContractCostParams, int(xdr.ContractCostTypeVerifyEd25519Sig)+1)
+		params[xdr.ContractCostTypeComputeSha256Hash] = xdr.ContractCostParamEntry{ConstTerm: 3738, LinearTerm: 7012}
+		params[xdr.ContractCostTypeVerifyEd25519Sig] = xdr.ContractCostParamEntry{ConstTerm: 377524, LinearTerm: 4068}
+		entry.ContractCostParamsCpuInsns = &params
+	case xdr.ConfigSettingIdConfigSettingContractCostParamsMemoryBytes:
+		params := xdr.ContractCostParams{{ConstTerm: 0, LinearTerm: 0}}
+		entry.ContractCostParamsMemBytes = &params
+	case xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes:
+		entry.ContractDataKeySizeBytes = u32(250)
+	case xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes:
+		entry.ContractDataEntrySizeBytes = u32(131072)
+	}
+	return entry
+}
+
+// configSettingsServer serves getLedgerEntries for every requested key with
+// lastModifiedLedgerSeq set to modified.
+func configSettingsServer(t *testing.T, modified int, skip xdr.ConfigSettingId) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params [][]string `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var resp GetLedgerEntriesResponse
+		resp.Jsonrpc = "2.0"
+		resp.Result.LatestLedger = 2000
+		for _, key := range req.Params[0] {
+			var lk xdr.LedgerKey
+			require.NoError(t, xdr.SafeUnmarshalBase64(key, &lk))
+			id := lk.ConfigSetting.ConfigSettingId
+			if id == skip {
+				continue
+			}
+			setting := testConfigSetting(id)
+			data := xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &setting}
+			b64, err := xdr.MarshalBase64(data)
+			require.NoError(t, err)
+			resp.Result.Entries = append(resp.Result.Entries, struct {
+				Key                string `json:"key"`
+				Xdr                string `json:"xdr"`
+				LastModifiedLedger int    `json:"lastModifiedLedgerSeq"`
+				LiveUntilLedger    int    `json:"liveUntilLedgerSeq"`
+			}{Key: key, Xdr: b64, LastModifiedLedger: modified})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newSettingsTestClient(url string) *Client {
+	return &Client{
+		Horizon:    &mockHorizonClient{},
+		HorizonURL: url,
+		SorobanURL: url,
+		Network:    "custom",
+		AltURLs:    []string{url},
+	}
+}
+
+func TestNetworkSettingsKeys(t *testing.T) {
+	keys, err := NetworkSettingsKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, len(networkSettingIDs)+len(optionalNetworkSettingIDs))
+
+	var lk xdr.LedgerKey
+	require.NoError(t, xdr.SafeUnmarshalBase64(keys[0], &lk))
+	assert.Equal(t, xdr.LedgerEntryTypeConfigSetting, lk.Type)
+	assert.Equal(t, xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes, lk.ConfigSetting.ConfigSettingId)
+}
+
+func TestGetNetworkSettings(t *testing.T) {
+	server := configSettingsServer(t, 900, -1)
+	defer server.Close()
+
+	settings, err := newSettingsTestClient(server.URL).GetNetworkSettings(context.Background(), 1000)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(1000), settings.LedgerSequence)
+	assert.Equal(t, uint32(2000), settings.LatestLedger)
+	assert.Equal(t, uint32(131072), settings.ContractMaxSizeBytes)
+	assert.Equal(t, int64(400000000), settings.TxMaxInstructions)
+	assert.Equal(t, uint32(41943040), settings.TxMemoryLimit)
+	assert.Equal(t, int64(6250), settings.FeeDiskReadLedgerEntry)
+	assert.Equal(t, int64(1624), settings.FeeTxSize1Kb)
+	assert.Equal(t, int64(3500), settings.FeeWrite1Kb)
+	assert.Equal(t, uint32(100), settings.TxMaxFootprintEntries)
+	assert.Empty(t, settings.UpgradedAfter)
+
+	p, ok := LookupCostParam(settings.CPUCostParams, xdr.ContractCostTypeComputeSha256Hash)
+	require.True(t, ok)
+	assert.Equal(t, CostParam{ConstTerm: 3738, LinearTerm: 7012}, p)
+
+	_, ok = LookupCostParam(settings.CPUCostParams, xdr.ContractCostTypeVmInstantiation)
+	assert.False(t, ok)
+}
+
+func TestGetNetworkSettings_FlagsLaterUpgrades(t *testing.T) {
+	server := configSettingsServer(t, 1500, -1)
+	defer server.Close()
+
+	settings, err := newSettingsTestClient(server.URL).GetNetworkSettings(context.Background(), 1000)
+	require.NoError(t, err)
+	assert.Len(t, settings.UpgradedAfter, len(networkSettingIDs)+len(optionalNetworkSettingIDs))
+}
+
+func TestGetNetworkSettings_MissingEntry(t *testing.T) {
+	server := configSettingsServer(t, 900, xdr.ConfigSettingIdConfigSettingContractComputeV0)
+	defer server.Close()
+
+	_, err := newSettingsTestClient(server.URL).GetNetworkSettings(context.Background(), 1000)
+	assert.Error(t, err)
+}
+
+func TestGetNetworkSettings_OptionalEntryMissing(t *testing.T) {
+	server := configSettingsServer(t, 900, xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0)
+	defer server.Close()
+
+	settings, err := newSettingsTestClient(server.URL).GetNetworkSettings(context.Background(), 1000)
+	require.NoError(t, err)
+	assert.Zero(t, settings.FeeWrite1Kb)
+	assert.Zero(t, settings.TxMaxFootprintEntries)
+}
+
+func TestParseNetworkSettings(t *testing.T) {
+	setting := testConfigSetting(xdr.ConfigSettingIdConfigSettingContractComputeV0)
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 10,
+		Data:                  xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &setting},
+	}
+	b64, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+
+	settings, found := ParseNetworkSettings(map[string]string{"k": b64, "other": "AAAA"}, 42)
+	require.True(t, found)
+	assert.Equal(t, uint32(42), settings.LedgerSequence)
+	assert.Equal(t, int64(400000000), settings.TxMaxInstructions)
+
+	_, found = ParseNetworkSettings(map[string]string{"other": "AAAA"}, 42)
+	assert.False(t, found)
+}
@@ -7,17 +7,23 @@ import hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
 
 // TransactionResponse holds the XDR data for a transaction
 type TransactionResponse struct {
-	EnvelopeXdr   string
-	ResultXdr     string
-	ResultMetaXdr string
+	Hash           string
+	Successful     bool
+	EnvelopeXdr    string
+	ResultXdr      string
+	ResultMetaXdr  string
+	LedgerSequence uint32
 }
 
 // ParseTransactionResponse converts a Horizon transaction into a TransactionResponse
 func ParseTransactionResponse(tx hProtocol.Transaction) *TransactionResponse {
 	return &TransactionResponse{
-		EnvelopeXdr:   tx.EnvelopeXdr,
-		ResultXdr:     tx.ResultXdr,
-		ResultMetaXdr: tx.ResultMetaXdr,
+		Hash:           tx.Hash,
+		Successful:     tx.Successful,
+		EnvelopeXdr:    tx.EnvelopeXdr,
+		ResultXdr:      tx.ResultXdr,
+		ResultMetaXdr:  tx.ResultMetaXdr,
+		LedgerSequence: uint32(tx.Ledger),
 	}
 }
 
@@ -0,0 +1,470 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Backend selects where transactions and ledgers are read from.
+type Backend string
+
+const (
+	// BackendAuto uses Soroban RPC when the provider speaks it and falls
+	// back to Horizon for anything RPC cannot answer, such as transactions
+	// older than the RPC retention window.
+	BackendAuto Backend = "auto"
+	// BackendRPC uses Soroban RPC only.
+	BackendRPC Backend = "rpc"
+	// BackendHorizon uses Horizon only.
+	BackendHorizon Backend = "horizon"
+)
+
+// ParseBackend validates a backend name; an empty name is BackendAuto.
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(strings.ToLower(strings.TrimSpace(s))); b {
+	case "":
+		return BackendAuto, nil
+	case BackendAuto, BackendRPC, BackendHorizon:
+		return b, nil
+	}
+	return "", errors.WrapValidationError(fmt.Sprintf("unknown RPC backend %q (expected auto, rpc or horizon)", s))
+}
+
+// Soroban RPC getTransaction statuses.
+const (
+	TxStatusSuccess  = "SUCCESS"
+	TxStatusFailed   = "FAILED"
+	TxStatusNotFound = "NOT_FOUND"
+)
+
+// unixTime is a Unix timestamp that Soroban RPC encodes either as a number or
+// as a decimal string depending on the method and server version.
+type unixTime int64
+
+func (t *unixTime) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*t = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*t = unixTime(v)
+	return nil
+}
+
+func (t unixTime) Time() time.Time {
+	return time.Unix(int64(t), 0).UTC()
+}
+
+// LatestLedger is the result of getLatestLedger.
+type LatestLedger struct {
+	ID              string `json:"id"`
+	ProtocolVersion uint32 `json:"protocolVersion"`
+	Sequence        uint32 `json:"sequence"`
+}
+
+// LedgerInfo is one ledger as returned by getLedgers. HeaderXdr is a base64
+// LedgerHeaderHistoryEntry.
+type LedgerInfo struct {
+	Hash            string   `json:"hash"`
+	Sequence        uint32   `json:"sequence"`
+	LedgerCloseTime unixTime `json:"ledgerCloseTime"`
+	HeaderXdr       string   `json:"headerXdr"`
+	MetadataXdr     string   `json:"metadataXdr,omitempty"`
+}
+
+// LedgersPage is one page of GetLedgers results.
+type LedgersPage struct {
+	Ledgers      []LedgerInfo `json:"ledgers"`
+	LatestLedger uint32       `json:"latestLedger"`
+	OldestLedger uint32       `json:"oldestLedger"`
+	Cursor       string       `json:"cursor"`
+}
+
+// RPCTransaction is one transaction as returned by getTransaction and
+// getTransactions.
+type RPCTransaction struct {
+	Status           string   `json:"status"`
+	TxHash           string   `json:"txHash"`
+	ApplicationOrder int      `json:"applicationOrder"`
+	FeeBump          bool     `json:"feeBump"`
+	EnvelopeXdr      string   `json:"envelopeXdr"`
+	ResultXdr        string   `json:"resultXdr"`
+	ResultMetaXdr    string   `json:"resultMetaXdr"`
+	Ledger           uint32   `json:"ledger"`
+	CreatedAt        unixTime `json:"createdAt"`
+}
+
+// TransactionResponse converts t to the form the rest of erst consumes.
+func (t *RPCTransaction) TransactionResponse() *TransactionResponse {
+	return &TransactionResponse{
+		Hash:           t.TxHash,
+		Successful:     t.Status == TxStatusSuccess,
+		EnvelopeXdr:    t.EnvelopeXdr,
+		ResultXdr:      t.ResultXdr,
+		ResultMetaXdr:  t.ResultMetaXdr,
+		LedgerSequence: t.Ledger,
+	}
+}
+
+// TransactionsPage is one page of GetTransactions results.
+type TransactionsPage struct {
+	Transactions []RPCTransaction `json:"transactions"`
+	LatestLedger uint32           `json:"latestLedger"`
+	OldestLedger uint32           `json:"oldestLedger"`
+	Cursor       string           `json:"cursor"`
+}
+
+// PageQuery is a ledger-ranged page request for GetLedgers and
+// GetTransactions. Either StartLedger or Cursor must be set.
+type PageQuery struct {
+	StartLedger uint32
+	Cursor      string
+	Limit       uint
+}
+
+type pageParams struct {
+	StartLedger uint32          `json:"startLedger,omitempty"`
+	Pagination  *eventsPaginate `json:"pagination,omitempty"`
+}
+
+func (q PageQuery) params() (pageParams, error) {
+	if q.StartLedger == 0 && q.Cursor == "" {
+		return pageParams{}, errors.WrapValidationError("a start ledger or a cursor is required")
+	}
+	var p pageParams
+	if q.Cursor == "" {
+		p.StartLedger = q.StartLedger
+	}
+	if q.Cursor != "" || q.Limit > 0 {
+		p.Pagination = &eventsPaginate{Cursor: q.Cursor, Limit: q.Limit}
+	}
+	return p, nil
+}
+
+// sorobanRPCURL returns the endpoint transaction and ledger reads go to via
+// Soroban RPC: the current provider URL when it speaks JSON-RPC and the
+// network's Soroban RPC URL otherwise. It returns "" when neither does or the
+// client is restricted to Horizon.
+func (c *Client) sorobanRPCURL(ctx context.Context) string {
+	if c.Backend == "" || c.Backend == BackendHorizon {
+		return ""
+	}
+	c.mu.RLock()
+	candidates := []string{c.HorizonURL}
+	if c.SorobanURL != "" && c.SorobanURL != c.HorizonURL {
+		candidates = append(candidates, c.SorobanURL)
+	}
+	c.mu.RUnlock()
+
+	for _, url := range candidates {
+		if url != "" && c.speaksSorobanRPC(ctx, url) {
+			return url
+		}
+	}
+	return ""
+}
+
+// speaksSorobanRPC reports whether url answers getLatestLedger. The answer
+// is remembered for the lifetime of the client.
+func (c *Client) speaksSorobanRPC(ctx context.Context, url string) bool {
+	c.mu.RLock()
+	capable, known := c.rpcCapable[url]
+	c.mu.RUnlock()
+	if known {
+		return capable
+	}
+
+	var latest LatestLedger
+	err := c.callSorobanAt(ctx, url, "getLatestLedger", nil, &latest)
+	if ctx.Err() != nil {
+		return false
+	}
+	capable = err == nil && latest.Sequence > 0
+	logger.Logger.Debug("Probed Soroban RPC support", "url", url, "supported", capable)
+
+	c.mu.Lock()
+	if c.rpcCapable == nil {
+		c.rpcCapable = make(map[string]bool)
+	}
+	c.rpcCapable[url] = capable
+	c.mu.Unlock()
+	return capable
+}
+
+// requireSorobanRPC returns the Soroban RPC endpoint or an error when the
+// provider does not offer one.
+func (c *Client) requireSorobanRPC(ctx context.Context, method string) (string, error) {
+	if c.Backend == "" || c.Backend == BackendHorizon {
+		return "", errors.WrapValidationError(fmt.Sprintf("%s needs Soroban RPC but the client is restricted to Horizon", method))
+	}
+	url := c.sorobanRPCURL(ctx)
+	if url == "" {
+		return "", errors.WrapRPCConnectionFailed(fmt.Errorf("no Soroban RPC endpoint available for %s", method))
+	}
+	return url, nil
+}
+
+// GetLatestLedger returns the newest ledger known to the Soroban RPC node.
+func (c *Client) GetLatestLedger(ctx context.Context) (*LatestLedger, error) {
+	var latest LatestLedger
+	if err := c.callSoroban(ctx, "getLatestLedger", nil, &latest); err != nil {
+		return nil, err
+	}
+	return &latest, nil
+}
+
+// GetLedgers fetches one page of ledgers from Soroban RPC.
+func (c *Client) GetLedgers(ctx context.Context, q PageQuery) (*LedgersPage, error) {
+	params, err := q.params()
+	if err != nil {
+		return nil, err
+	}
+	var page LedgersPage
+	if err := c.callSoroban(ctx, "getLedgers", params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetTransactions fetches one page of transactions, in application order,
+// from Soroban RPC.
+func (c *Client) GetTransactions(ctx context.Context, q PageQuery) (*TransactionsPage, error) {
+	params, err := q.params()
+	if err != nil {
+		return nil, err
+	}
+	var page TransactionsPage
+	if err := c.callSoroban(ctx, "getTransactions", params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) getTransactionsAt(ctx context.Context, url string, params pageParams) (*TransactionsPage, error) {
+	var page TransactionsPage
+	if err := c.callSorobanAt(ctx, url, "getTransactions", params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// getTransactionRPC fetches hash with getTransaction. A transaction outside
+// the node's retention window yields ErrTransactionNotFound.
+func (c *Client) getTransactionRPC(ctx context.Context, url, hash string) (*TransactionResponse, error) {
+	var tx RPCTransaction
+	if err := c.callSorobanAt(ctx, url, "getTransaction", map[string]string{"hash": hash}, &tx); err != nil {
+		return nil, err
+	}
+	if tx.Status == TxStatusNotFound || tx.Status == "" {
+		return nil, errors.WrapTransactionNotFound(fmt.Errorf("%s not found on %s", hash, url))
+	}
+	if tx.TxHash == "" {
+		tx.TxHash = hash
+	}
+	logger.Logger.Info("Transaction fetched", "hash", hash, "envelope_size", len(tx.EnvelopeXdr), "url", url, "backend", BackendRPC)
+	return tx.TransactionResponse(), nil
+}
+
+// getLedgerHeaderRPC fetches the header of sequence with getLedgers.
+func (c *Client) getLedgerHeaderRPC(ctx context.Context, url string, sequence uint32) (*LedgerHeaderResponse, error) {
+	params, _ := PageQuery{StartLedger: sequence, Limit: 1}.params()
+	var page LedgersPage
+	if err := c.callSorobanAt(ctx, url, "getLedgers", params, &page); err != nil {
+		return nil, err
+	}
+	if len(page.Ledgers) == 0 || page.Ledgers[0].Sequence != sequence {
+		return nil, errors.WrapLedgerNotFound(sequence)
+	}
+	return FromSorobanLedger(page.Ledgers[0])
+}
+
+// rpcTransactionsPageSize is the largest page Soroban RPC serves.
+const rpcTransactionsPageSize = 200
+
+// getLedgerTransactionsRPC pages through getTransactions from sequence until
+// the first transaction of a later ledger.
+func (c *Client) getLedgerTransactionsRPC(ctx context.Context, url string, sequence uint32) ([]*TransactionResponse, error) {
+	params, _ := PageQuery{StartLedger: sequence, Limit: rpcTransactionsPageSize}.params()
+
+	var txs []*TransactionResponse
+	for {
+		page, err := c.getTransactionsAt(ctx, url, params)
+		if err != nil {
+			return nil, err
+		}
+		if sequence < page.OldestLedger {
+			return nil, errors.WrapLedgerArchived(sequence)
+		}
+		for i := range page.Transactions {
+			tx := &page.Transactions[i]
+			if tx.Ledger > sequence {
+				return txs, nil
+			}
+			if tx.Ledger == sequence {
+				txs = append(txs, tx.TransactionResponse())
+			}
+		}
+		if len(page.Transactions) < rpcTransactionsPageSize || page.Cursor == "" {
+			return txs, nil
+		}
+		params = pageParams{Pagination: &eventsPaginate{Cursor: page.Cursor, Limit: rpcTransactionsPageSize}}
+	}
+}
+
+// accountScanLedgers bounds how far back GetAccountTransactions scans when
+// only Soroban RPC is available, which cannot filter by account: about one
+// hour of ledgers.
+const accountScanLedgers = 720
+
+// accountScanWindow is how many ledgers each backwards step of the account
+// scan covers, and accountScanMaxPages caps the getTransactions pages one
+// scan may fetch, so a busy network cannot turn a lookup into thousands of
+// requests.
+const (
+	accountScanWindow   = 60
+	accountScanMaxPages = 25
+)
+
+// getAccountTransactionsRPC scans recent ledgers for transactions whose
+// source or fee source is account, newest first. It walks back from the
+// latest ledger one window at a time and stops once limit matches are found.
+func (c *Client) getAccountTransactionsRPC(ctx context.Context, url, account string, limit int) ([]TransactionSummary, error) {
+	var health struct {
+		LatestLedger uint32 `json:"latestLedger"`
+		OldestLedger uint32 `json:"oldestLedger"`
+	}
+	if err := c.callSorobanAt(ctx, url, "getHealth", nil, &health); err != nil {
+		return nil, err
+	}
+	floor := max(health.OldestLedger, 1)
+	if health.LatestLedger > accountScanLedgers && health.LatestLedger-accountScanLedgers > floor {
+		floor = health.LatestLedger - accountScanLedgers
+	}
+
+	var matches []TransactionSummary
+	pages := 0
+	for end := health.LatestLedger + 1; end > floor && (limit <= 0 || len(matches) < limit); {
+		from := floor
+		if end-floor > accountScanWindow {
+			from = end - accountScanWindow
+		}
+		window, n, err := c.scanAccountWindow(ctx, url, account, from, end, accountScanMaxPages-pages)
+		if err != nil {
+			return nil, err
+		}
+		pages += n
+		// Newest first, as Horizon returns them.
+		for i := len(window) - 1; i >= 0; i-- {
+			matches = append(matches, window[i])
+		}
+		if pages >= accountScanMaxPages {
+			logger.Logger.Warn("Account scan stopped at page limit", "account", account, "pages", pages, "oldest_ledger", from)
+			break
+		}
+		end = from
+	}
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// scanAccountWindow returns, oldest first, the transactions of ledgers
+// [from, end) that involve account, fetching at most maxPages pages. It also
+// returns the number of pages fetched.
+func (c *Client) scanAccountWindow(ctx context.Context, url, account string, from, end uint32, maxPages int) ([]TransactionSummary, int, error) {
+	params, err := PageQuery{StartLedger: from, Limit: rpcTransactionsPageSize}.params()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches []TransactionSummary
+	pages := 0
+	for pages < maxPages {
+		page, err := c.getTransactionsAt(ctx, url, params)
+		if err != nil {
+			return nil, pages, err
+		}
+		pages++
+		for _, tx := range page.Transactions {
+			if tx.Ledger >= end {
+				return matches, pages, nil
+			}
+			if tx.Ledger < from || !transactionInvolves(tx.EnvelopeXdr, account) {
+				continue
+			}
+			status := "success"
+			if tx.Status != TxStatusSuccess {
+				status = "failed"
+			}
+			matches = append(matches, TransactionSummary{
+				Hash:      tx.TxHash,
+				Status:    status,
+				CreatedAt: tx.CreatedAt.Time().Format("2006-01-02 15:04:05"),
+			})
+		}
+		if len(page.Transactions) < rpcTransactionsPageSize || page.Cursor == "" {
+			break
+		}
+		params = pageParams{Pagination: &eventsPaginate{Cursor: page.Cursor, Limit: rpcTransactionsPageSize}}
+	}
+	return matches, pages, nil
+}
+
+// transactionInvolves reports whether account is the source or fee source
+// of the base64 envelope.
+func transactionInvolves(envelopeXdr, account string) bool {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return false
+	}
+	source := env.SourceAccount().ToAccountId()
+	if source.Address() == account {
+		return true
+	}
+	if env.IsFeeBump() {
+		fee := env.FeeBumpAccount().ToAccountId()
+		return fee.Address() == account
+	}
+	return false
+}
+
+// FromSorobanLedger converts a getLedgers entry to our internal structure.
+// Transaction counts are not part of the header and stay zero.
+func FromSorobanLedger(info LedgerInfo) (*LedgerHeaderResponse, error) {
+	var entry xdr.LedgerHeaderHistoryEntry
+	if err := xdr.SafeUnmarshalBase64(info.HeaderXdr, &entry); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "ledger header")
+	}
+	h := entry.Header
+	return &LedgerHeaderResponse{
+		Sequence:        uint32(h.LedgerSeq),
+		Hash:            info.Hash,
+		PrevHash:        hex.EncodeToString(h.PreviousLedgerHash[:]),
+		CloseTime:       time.Unix(int64(h.ScpValue.CloseTime), 0).UTC(),
+		ProtocolVersion: uint32(h.LedgerVersion),
+		BaseFee:         int32(h.BaseFee),
+		BaseReserve:     int32(h.BaseReserve),
+		MaxTxSetSize:    int32(h.MaxTxSetSize),
+		TotalCoins:      amount.String(h.TotalCoins),
+		FeePool:         amount.String(h.FeePool),
+		HeaderXDR:       info.HeaderXdr,
+	}, nil
+}
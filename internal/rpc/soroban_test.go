@@ -0,0 +1,292 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/support/render/problem"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSorobanRPCServer answers JSON-RPC methods from results, a map of method
+// name to raw JSON result. Unknown methods get a method-not-found error.
+func newSorobanRPCServer(t *testing.T, results map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls = append(calls, req.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		result, ok := results[req.Method]
+		if !ok {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newRPCTestClient(url string, backend Backend, horizon horizonclient.ClientInterface) *Client {
+	return &Client{
+		Horizon:    horizon,
+		HorizonURL: url,
+		AltURLs:    []string{url},
+		Backend:    backend,
+	}
+}
+
+func testEnvelope(t *testing.T, source string) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{SourceAccount: xdr.MustMuxedAddress(source)},
+		},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestParseBackend(t *testing.T) {
+	for in, want := range map[string]Backend{"": BackendAuto, "auto": BackendAuto, " RPC ": BackendRPC, "horizon": BackendHorizon} {
+		got, err := ParseBackend(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseBackend("graphql")
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+}
+
+func TestGetTransaction_SorobanRPC(t *testing.T) {
+	server, calls := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"id":"x","protocolVersion":22,"sequence":100}`,
+		"getTransaction": `{"status":"FAILED","txHash":"abc","envelopeXdr":"ENV","resultXdr":"RES",
+			"resultMetaXdr":"META","ledger":95,"createdAt":"1700000000"}`,
+	})
+	horizon := &mockHorizonClient{TransactionDetailFunc: func(string) (hProtocol.Transaction, error) {
+		t.Fatal("Horizon must not be queried when Soroban RPC has the transaction")
+		return hProtocol.Transaction{}, nil
+	}}
+
+	client := newRPCTestClient(server.URL, BackendAuto, horizon)
+	resp, err := client.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ENV", resp.EnvelopeXdr)
+	assert.Equal(t, "META", resp.ResultMetaXdr)
+	assert.Equal(t, uint32(95), resp.LedgerSequence)
+	assert.False(t, resp.Successful)
+
+	_, err = client.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"getLatestLedger", "getTransaction", "getTransaction"}, *calls, "capability probe is cached")
+}
+
+func TestGetTransaction_FallsBackToHorizon(t *testing.T) {
+	server, _ := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"sequence":100}`,
+		"getTransaction":  `{"status":"NOT_FOUND","latestLedger":100,"oldestLedger":50}`,
+	})
+	horizon := &mockHorizonClient{TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+		return hProtocol.Transaction{Hash: hash, EnvelopeXdr: "OLD", Successful: true}, nil
+	}}
+
+	resp, err := newRPCTestClient(server.URL, BackendAuto, horizon).GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "OLD", resp.EnvelopeXdr)
+
+	_, err = newRPCTestClient(server.URL, BackendRPC, horizon).GetTransaction(context.Background(), "abc")
+	assert.Error(t, err, "rpc backend never falls back")
+}
+
+func TestGetTransaction_HorizonOnlyProvider(t *testing.T) {
+	server, calls := newSorobanRPCServer(t, nil)
+	horizon := &mockHorizonClient{TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+		return hProtocol.Transaction{Hash: hash, EnvelopeXdr: "H"}, nil
+	}}
+
+	client := newRPCTestClient(server.URL, BackendAuto, horizon)
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetTransaction(context.Background(), "abc")
+		require.NoError(t, err)
+		assert.Equal(t, "H", resp.EnvelopeXdr)
+	}
+	assert.Equal(t, []string{"getLatestLedger"}, *calls)
+}
+
+func TestGetLedgerHeader_SorobanRPC(t *testing.T) {
+	entry := xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{
+		LedgerVersion: 22,
+		LedgerSeq:     42,
+		BaseFee:       100,
+		BaseReserve:   5_000_000,
+		MaxTxSetSize:  1000,
+		TotalCoins:    1_000_000_000,
+		ScpValue:      xdr.StellarValue{CloseTime: 1_700_000_000},
+	}}
+	headerXdr, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+
+	server, _ := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"sequence":100}`,
+		"getLedgers":      `{"ledgers":[{"hash":"h42","sequence":42,"ledgerCloseTime":"1700000000","headerXdr":"` + headerXdr + `"}],"latestLedger":100,"oldestLedger":1}`,
+	})
+
+	header, err := newRPCTestClient(server.URL, BackendRPC, &mockHorizonClient{}).GetLedgerHeader(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(42), header.Sequence)
+	assert.Equal(t, "h42", header.Hash)
+	assert.Equal(t, uint32(22), header.ProtocolVersion)
+	assert.Equal(t, int32(100), header.BaseFee)
+	assert.Equal(t, int32(5_000_000), header.BaseReserve)
+	assert.Equal(t, "100.0000000", header.TotalCoins)
+	assert.Equal(t, int64(1_700_000_000), header.CloseTime.Unix())
+
+	_, err = newRPCTestClient(server.URL, BackendRPC, &mockHorizonClient{}).GetLedgerHeader(context.Background(), 43)
+	assert.Error(t, err)
+}
+
+func TestGetLedgerTransactions_SorobanRPC(t *testing.T) {
+	server, _ := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"sequence":100}`,
+		"getTransactions": `{"transactions":[
+			{"status":"SUCCESS","txHash":"a","ledger":10,"envelopeXdr":"A"},
+			{"status":"FAILED","txHash":"b","ledger":10,"envelopeXdr":"B"},
+			{"status":"SUCCESS","txHash":"c","ledger":11,"envelopeXdr":"C"}],
+			"latestLedger":100,"oldestLedger":1,"cursor":"x"}`,
+	})
+
+	txs, err := newRPCTestClient(server.URL, BackendRPC, &mockHorizonClient{}).GetLedgerTransactions(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "a", txs[0].Hash)
+	assert.False(t, txs[1].Successful)
+}
+
+func TestGetAccountTransactions_SorobanRPC(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	other := keypair.MustRandom().Address()
+	server, _ := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"sequence":100}`,
+		"getHealth":       `{"status":"healthy","latestLedger":100,"oldestLedger":1}`,
+		"getTransactions": `{"transactions":[
+			{"status":"SUCCESS","txHash":"first","ledger":5,"createdAt":"1700000000","envelopeXdr":"` + testEnvelope(t, account) + `"},
+			{"status":"SUCCESS","txHash":"skip","ledger":6,"createdAt":"1700000005","envelopeXdr":"` + testEnvelope(t, other) + `"},
+			{"status":"FAILED","txHash":"second","ledger":7,"createdAt":"1700000010","envelopeXdr":"` + testEnvelope(t, account) + `"}],
+			"latestLedger":100,"oldestLedger":1}`,
+	})
+
+	horizon := &mockHorizonClient{TransactionsFunc: func(horizonclient.TransactionRequest) (hProtocol.TransactionsPage, error) {
+		return hProtocol.TransactionsPage{}, &horizonclient.Error{Problem: problem.P{Status: http.StatusNotFound}}
+	}}
+
+	txs, err := newRPCTestClient(server.URL, BackendAuto, horizon).GetAccountTransactions(context.Background(), account, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "second", txs[0].Hash, "newest first")
+	assert.Equal(t, "failed", txs[0].Status)
+	assert.Equal(t, "first", txs[1].Hash)
+
+	txs, err = newRPCTestClient(server.URL, BackendRPC, horizon).GetAccountTransactions(context.Background(), account, 1)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, "second", txs[0].Hash)
+}
+
+func TestGetTransaction_AutoProbesSorobanURL(t *testing.T) {
+	horizonOnly, horizonCalls := newSorobanRPCServer(t, nil)
+	server, _ := newSorobanRPCServer(t, map[string]string{
+		"getLatestLedger": `{"sequence":100}`,
+		"getTransaction":  `{"status":"SUCCESS","txHash":"abc","envelopeXdr":"ENV","ledger":95}`,
+	})
+	horizon := &mockHorizonClient{TransactionDetailFunc: func(string) (hProtocol.Transaction, error) {
+		t.Fatal("Horizon must not be queried when the Soroban RPC URL has the transaction")
+		return hProtocol.Transaction{}, nil
+	}}
+
+	client := newRPCTestClient(horizonOnly.URL, BackendAuto, horizon)
+	client.SorobanURL = server.URL
+	resp, err := client.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ENV", resp.EnvelopeXdr)
+	assert.Equal(t, []string{"getLatestLedger"}, *horizonCalls)
+}
+
+func TestGetAccountTransactions_SorobanRPCBounded(t *testing.T) {
+	account := keypair.MustRandom().Address()
+	other := keypair.MustRandom().Address()
+	match := testEnvelope(t, account)
+	miss := testEnvelope(t, other)
+
+	var getTransactions int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+
+		var result string
+		switch req.Method {
+		case "getLatestLedger":
+			result = `{"sequence":1000}`
+		case "getHealth":
+			result = `{"status":"healthy","latestLedger":1000,"oldestLedger":1}`
+		case "getTransactions":
+			getTransactions++
+			var p pageParams
+			require.NoError(t, json.Unmarshal(req.Params, &p))
+			// Every page is full. A page opened by startLedger holds one
+			// match followed by filler from that ledger; a cursor page is
+			// filler from past the window, which ends it after two pages.
+			ledger := p.StartLedger
+			if ledger == 0 {
+				ledger = 1_000_000
+			}
+			txs := make([]string, rpcTransactionsPageSize)
+			for i := range txs {
+				env := miss
+				if i == 0 && p.StartLedger != 0 {
+					env = match
+				}
+				txs[i] = fmt.Sprintf(`{"status":"SUCCESS","txHash":"t%d-%d","ledger":%d,"createdAt":"1700000000","envelopeXdr":"%s"}`, ledger, i, ledger, env)
+			}
+			result = `{"transactions":[` + strings.Join(txs, ",") + `],"latestLedger":1000,"oldestLedger":1,"cursor":"next"}`
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newRPCTestClient(server.URL, BackendRPC, &mockHorizonClient{})
+	txs, err := client.GetAccountTransactions(context.Background(), account, 2)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "t941-0", txs[0].Hash, "newest window first")
+	assert.Equal(t, "t881-0", txs[1].Hash)
+
+	getTransactions = 0
+	_, err = client.GetAccountTransactions(context.Background(), account, 0)
+	require.NoError(t, err)
+	assert.Equal(t, accountScanMaxPages, getTransactions)
+}
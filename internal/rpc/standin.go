@@ -0,0 +1,252 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// JSON-RPC 2.0 error codes returned by StandIn.
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+)
+
+// StandInMethods are the Soroban RPC methods StandIn answers.
+var StandInMethods = []string{"getHealth", "getNetwork", "getLatestLedger", "getLedgerEntries", "getTransaction"}
+
+// maxStandInBody bounds a request body; getLedgerEntries with the maximum
+// key count is far below it.
+const maxStandInBody = 10 << 20
+
+// StandInConfig is the data a StandIn serves.
+type StandInConfig struct {
+	NetworkPassphrase string
+	ProtocolVersion   uint32
+	// LatestLedger is reported as the newest ledger. Zero uses the highest
+	// ledger among Transactions, or 1.
+	LatestLedger uint32
+	// Entries maps base64 LedgerKey XDR to base64 entry XDR, as snapshots
+	// store them.
+	Entries map[string]string
+	// Transactions are keyed by transaction hash.
+	Transactions map[string]*TransactionResponse
+}
+
+// StandIn is an http.Handler exposing a read-only Soroban JSON-RPC surface
+// over local data, so tools that speak Soroban RPC can run without a
+// network. The data is fixed at construction, and every entry is reported
+// as last modified at the latest ledger.
+type StandIn struct {
+	cfg    StandInConfig
+	closed time.Time
+}
+
+// NewStandIn builds a StandIn over cfg. Hashes are matched case-insensitively.
+func NewStandIn(cfg StandInConfig) *StandIn {
+	txs := make(map[string]*TransactionResponse, len(cfg.Transactions))
+	var highest uint32
+	for hash, tx := range cfg.Transactions {
+		if tx == nil {
+			continue
+		}
+		txs[strings.ToLower(hash)] = tx
+		highest = max(highest, tx.LedgerSequence)
+	}
+	cfg.Transactions = txs
+	if cfg.LatestLedger == 0 {
+		cfg.LatestLedger = max(highest, 1)
+	}
+	if cfg.Entries == nil {
+		cfg.Entries = make(map[string]string)
+	}
+	return &StandIn{cfg: cfg, closed: time.Now().UTC()}
+}
+
+// Stats reports how many ledger entries and transactions s serves.
+func (s *StandIn) Stats() (entries, transactions int) {
+	return len(s.cfg.Entries), len(s.cfg.Transactions)
+}
+
+type standInRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type standInError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type standInResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *standInError   `json:"error,omitempty"`
+}
+
+// ServeHTTP implements http.Handler.
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Soroban RPC requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := standInResponse{Jsonrpc: "2.0", ID: json.RawMessage("null")}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxStandInBody))
+	if err != nil {
+		resp.Error = &standInError{Code: jsonRPCParseError, Message: err.Error()}
+		writeStandInResponse(w, resp)
+		return
+	}
+
+	var req standInRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.Error = &standInError{Code: jsonRPCParseError, Message: fmt.Sprintf("parse error: %v", err)}
+		writeStandInResponse(w, resp)
+		return
+	}
+	if len(req.ID) > 0 {
+		resp.ID = req.ID
+	}
+	if req.Jsonrpc != "2.0" || req.Method == "" {
+		resp.Error = &standInError{Code: jsonRPCInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}
+		writeStandInResponse(w, resp)
+		return
+	}
+
+	logger.Logger.Debug("Stand-in RPC request", "method", req.Method)
+	resp.Result, resp.Error = s.dispatch(req.Method, req.Params)
+	writeStandInResponse(w, resp)
+}
+
+func writeStandInResponse(w http.ResponseWriter, resp standInResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Logger.Warn("Failed to write stand-in RPC response", "error", err)
+	}
+}
+
+func (s *StandIn) dispatch(method string, params json.RawMessage) (interface{}, *standInError) {
+	switch method {
+	case "getHealth":
+		return map[string]interface{}{
+			"status":                "healthy",
+			"latestLedger":          s.cfg.LatestLedger,
+			"oldestLedger":          1,
+			"ledgerRetentionWindow": s.cfg.LatestLedger,
+		}, nil
+	case "getNetwork":
+		return map[string]interface{}{
+			"passphrase":      s.cfg.NetworkPassphrase,
+			"protocolVersion": s.cfg.ProtocolVersion,
+		}, nil
+	case "getLatestLedger":
+		return map[string]interface{}{
+			"id":              s.ledgerID(s.cfg.LatestLedger),
+			"protocolVersion": s.cfg.ProtocolVersion,
+			"sequence":        s.cfg.LatestLedger,
+		}, nil
+	case "getLedgerEntries":
+		return s.getLedgerEntries(params)
+	case "getTransaction":
+		return s.getTransaction(params)
+	}
+	return nil, &standInError{Code: jsonRPCMethodNotFound, Message: fmt.Sprintf("method %q is not served by the erst stand-in (supported: %s)", method, strings.Join(StandInMethods, ", "))}
+}
+
+// ledgerID is a stable stand-in ledger hash derived from the sequence.
+func (s *StandIn) ledgerID(sequence uint32) string {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], sequence)
+	sum := sha256.Sum256(append([]byte(s.cfg.NetworkPassphrase), buf[:]...))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *StandIn) getLedgerEntries(params json.RawMessage) (interface{}, *standInError) {
+	// Accept both {"keys": [...]} and the positional [[...]] form.
+	var named struct {
+		Keys []string `json:"keys"`
+	}
+	var keys []string
+	if err := json.Unmarshal(params, &named); err == nil {
+		keys = named.Keys
+	} else {
+		var positional [][]string
+		if err := json.Unmarshal(params, &positional); err != nil || len(positional) != 1 {
+			return nil, &standInError{Code: jsonRPCInvalidParams, Message: "getLedgerEntries expects {\"keys\": [...]}"}
+		}
+		keys = positional[0]
+	}
+	if len(keys) == 0 {
+		return nil, &standInError{Code: jsonRPCInvalidParams, Message: "at least one key is required"}
+	}
+
+	type entry struct {
+		Key                string `json:"key"`
+		Xdr                string `json:"xdr"`
+		LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
+	}
+	entries := make([]entry, 0, len(keys))
+	for _, key := range keys {
+		if xdr, ok := s.cfg.Entries[key]; ok {
+			entries = append(entries, entry{Key: key, Xdr: xdr, LastModifiedLedger: s.cfg.LatestLedger})
+		}
+	}
+	return map[string]interface{}{
+		"entries":      entries,
+		"latestLedger": s.cfg.LatestLedger,
+	}, nil
+}
+
+func (s *StandIn) getTransaction(params json.RawMessage) (interface{}, *standInError) {
+	var p struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.Hash == "" {
+		return nil, &standInError{Code: jsonRPCInvalidParams, Message: "getTransaction expects {\"hash\": \"...\"}"}
+	}
+
+	closeTime := s.closed.Unix()
+	result := map[string]interface{}{
+		"latestLedger":          s.cfg.LatestLedger,
+		"latestLedgerCloseTime": fmt.Sprint(closeTime),
+		"oldestLedger":          1,
+		"oldestLedgerCloseTime": fmt.Sprint(closeTime),
+	}
+	tx, ok := s.cfg.Transactions[strings.ToLower(p.Hash)]
+	if !ok {
+		result["status"] = TxStatusNotFound
+		return result, nil
+	}
+
+	status := TxStatusFailed
+	if tx.Successful {
+		status = TxStatusSuccess
+	}
+	result["status"] = status
+	result["txHash"] = tx.Hash
+	result["applicationOrder"] = 1
+	result["envelopeXdr"] = tx.EnvelopeXdr
+	result["resultXdr"] = tx.ResultXdr
+	result["resultMetaXdr"] = tx.ResultMetaXdr
+	result["ledger"] = tx.LedgerSequence
+	result["createdAt"] = fmt.Sprint(closeTime)
+	return result, nil
+}
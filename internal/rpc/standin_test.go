@@ -0,0 +1,120 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountLedgerKey(t *testing.T) string {
+	t.Helper()
+	key := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(keypair.MustRandom().Address())},
+	}
+	b64, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	return b64
+}
+
+func newStandInServer(t *testing.T, key1, key2 string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(NewStandIn(StandInConfig{
+		NetworkPassphrase: TestnetConfig.NetworkPassphrase,
+		ProtocolVersion:   22,
+		Entries:           map[string]string{key1: "ENTRY1", key2: "ENTRY2"},
+		Transactions: map[string]*TransactionResponse{
+			"ABCDEF": {Hash: "abcdef", Successful: true, EnvelopeXdr: "ENV", ResultXdr: "RES", ResultMetaXdr: "META", LedgerSequence: 77},
+		},
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func callStandIn(t *testing.T, url, body string) map[string]interface{} {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var out map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func TestStandIn_ServesErstClient(t *testing.T) {
+	key1, key2 := accountLedgerKey(t), accountLedgerKey(t)
+	server := newStandInServer(t, key1, key2)
+	client, err := NewClient(WithNetwork(Testnet), WithHorizonURL(server.URL), WithSorobanURL(server.URL), WithCacheEnabled(false))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tx, err := client.GetTransaction(ctx, "abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, "ENV", tx.EnvelopeXdr)
+	assert.Equal(t, "META", tx.ResultMetaXdr)
+	assert.Equal(t, uint32(77), tx.LedgerSequence)
+	assert.True(t, tx.Successful)
+
+	latest, err := client.GetLatestLedger(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(77), latest.Sequence, "defaults to the highest served ledger")
+	assert.Equal(t, uint32(22), latest.ProtocolVersion)
+
+	health, err := client.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "healthy", health.Result.Status)
+
+	entries, err := client.GetLedgerEntries(ctx, []string{key1, key2})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{key1: "ENTRY1", key2: "ENTRY2"}, entries)
+}
+
+func TestStandIn_Methods(t *testing.T) {
+	server := newStandInServer(t, "KEY1", "KEY2")
+
+	out := callStandIn(t, server.URL, `{"jsonrpc":"2.0","id":"a","method":"getNetwork"}`)
+	assert.Equal(t, "a", out["id"])
+	assert.Equal(t, TestnetConfig.NetworkPassphrase, out["result"].(map[string]interface{})["passphrase"])
+
+	out = callStandIn(t, server.URL, `{"jsonrpc":"2.0","id":2,"method":"getLedgerEntries","params":{"keys":["KEY2","MISSING"]}}`)
+	entries := out["result"].(map[string]interface{})["entries"].([]interface{})
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ENTRY2", entries[0].(map[string]interface{})["xdr"])
+
+	out = callStandIn(t, server.URL, `{"jsonrpc":"2.0","id":3,"method":"getTransaction","params":{"hash":"ffff"}}`)
+	assert.Equal(t, TxStatusNotFound, out["result"].(map[string]interface{})["status"])
+
+	tests := map[string]struct {
+		body string
+		code float64
+	}{
+		"unknown method": {`{"jsonrpc":"2.0","id":4,"method":"sendTransaction"}`, jsonRPCMethodNotFound},
+		"bad params":     {`{"jsonrpc":"2.0","id":5,"method":"getTransaction","params":{}}`, jsonRPCInvalidParams},
+		"no keys":        {`{"jsonrpc":"2.0","id":6,"method":"getLedgerEntries","params":{"keys":[]}}`, jsonRPCInvalidParams},
+		"not json":       {`{`, jsonRPCParseError},
+		"not 2.0":        {`{"id":7,"method":"getHealth"}`, jsonRPCInvalidRequest},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := callStandIn(t, server.URL, tc.body)
+			require.Contains(t, out, "error")
+			assert.Equal(t, tc.code, out["error"].(map[string]interface{})["code"])
+		})
+	}
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -4,6 +4,7 @@
 package rpc
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -40,7 +41,7 @@ func VerifyLedgerEntryHash(requestedKeyB64, returnedKeyB64 string) error {
 
 	// Unmarshal into LedgerKey to validate structure
 	var ledgerKey xdr.LedgerKey
-	if _, err := xdr.Unmarshal(keyBytes, &ledgerKey); err != nil {
+	if _, err := xdr.Unmarshal(bytes.NewReader(keyBytes), &ledgerKey); err != nil {
 		return errors.WrapValidationError(fmt.Sprintf("failed to unmarshal ledger key: %v", err))
 	}
 
@@ -0,0 +1,202 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Runner executes the simulations. When nil RunBatch creates a Runner
+	// with the default binary lookup. A Pool lets concurrent items reuse
+	// warm erst-sim processes.
+	Runner RunnerInterface
+	// Concurrency bounds how many simulations run at once. Defaults to the
+	// number of CPUs.
+	Concurrency int
+	// StopOnError cancels the items that have not started once any item
+	// fails. Items that return a response, even one with status "error",
+	// do not count as failures.
+	StopOnError bool
+	// OnProgress, when set, is called after every item finishes. Calls are
+	// serialised, so the callback needs no locking of its own.
+	OnProgress func(BatchProgress)
+}
+
+// BatchProgress reports the state of a batch after one item finished.
+type BatchProgress struct {
+	Completed int
+	Total     int
+	// Failed counts finished items with an error, cancelled ones included.
+	Failed int
+	// Item is the result that just finished.
+	Item *BatchItem
+}
+
+// BatchItem is the outcome of one request of a batch.
+type BatchItem struct {
+	// Index is the position of the request in the batch.
+	Index    int
+	Response *SimulationResponse
+	// Err is set when the runner failed or the item was cancelled before it
+	// started.
+	Err error
+	// Cancelled is true when the item never started.
+	Cancelled bool
+	Duration  time.Duration
+}
+
+// BatchStats aggregates the timing of a batch. Latency figures cover the
+// items that ran; cancelled items are only counted in Cancelled.
+type BatchStats struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+
+	WallTime time.Duration `json:"wall_time"`
+	// BusyTime is the sum of item durations; BusyTime/WallTime is the
+	// effective parallelism.
+	BusyTime time.Duration `json:"busy_time"`
+	Min      time.Duration `json:"min"`
+	Max      time.Duration `json:"max"`
+	Mean     time.Duration `json:"mean"`
+	P50      time.Duration `json:"p50"`
+	P95      time.Duration `json:"p95"`
+	// Throughput is finished simulations per second of wall time.
+	Throughput float64 `json:"throughput"`
+}
+
+// BatchResult holds the per-item results of RunBatch, in request order.
+type BatchResult struct {
+	Items []BatchItem
+	Stats BatchStats
+}
+
+// Responses returns the response of every item in request order, nil where
+// the item failed.
+func (b *BatchResult) Responses() []*SimulationResponse {
+	out := make([]*SimulationResponse, len(b.Items))
+	for i := range b.Items {
+		out[i] = b.Items[i].Response
+	}
+	return out
+}
+
+// RunBatch simulates reqs on at most opts.Concurrency goroutines. Results
+// keep the order of reqs and every item carries its own error, so one bad
+// request does not fail the batch. The returned error is only set when the
+// default runner cannot be created. Cancelling ctx stops items that have not
+// started and cancels the running ones.
+func RunBatch(ctx context.Context, reqs []*SimulationRequest, opts BatchOptions) (*BatchResult, error) {
+	runner := opts.Runner
+	if runner == nil {
+		r, err := NewRunner("", false)
+		if err != nil {
+			return nil, err
+		}
+		runner = r
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, max(1, len(reqs)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &BatchResult{Items: make([]BatchItem, len(reqs))}
+	var (
+		mu        sync.Mutex
+		completed int
+		failed    int
+	)
+	finish := func(item BatchItem) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Items[item.Index] = item
+		completed++
+		if item.Err != nil {
+			failed++
+			if opts.StopOnError {
+				cancel()
+			}
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(BatchProgress{Completed: completed, Total: len(reqs), Failed: failed, Item: &result.Items[item.Index]})
+		}
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if err := ctx.Err(); err != nil {
+					finish(BatchItem{Index: i, Err: err, Cancelled: true})
+					continue
+				}
+				itemStart := time.Now()
+				resp, err := runner.RunContext(ctx, reqs[i])
+				finish(BatchItem{Index: i, Response: resp, Err: err, Duration: time.Since(itemStart)})
+			}
+		}()
+	}
+	for i := range reqs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	result.Stats = batchStats(result.Items, time.Since(start))
+	return result, nil
+}
+
+func batchStats(items []BatchItem, wall time.Duration) BatchStats {
+	s := BatchStats{Total: len(items), WallTime: wall}
+
+	durations := make([]time.Duration, 0, len(items))
+	for _, item := range items {
+		switch {
+		case item.Err == nil:
+			s.Succeeded++
+		case item.Cancelled:
+			s.Cancelled++
+			continue
+		default:
+			s.Failed++
+		}
+		durations = append(durations, item.Duration)
+		s.BusyTime += item.Duration
+	}
+	if len(durations) == 0 {
+		return s
+	}
+
+	slices.Sort(durations)
+	s.Min = durations[0]
+	s.Max = durations[len(durations)-1]
+	s.Mean = s.BusyTime / time.Duration(len(durations))
+	s.P50 = percentile(durations, 50)
+	s.P95 = percentile(durations, 95)
+	if wall > 0 {
+		s.Throughput = float64(len(durations)) / wall.Seconds()
+	}
+	return s
+}
+
+// percentile returns the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(0, rank-1)]
+}
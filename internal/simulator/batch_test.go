@@ -0,0 +1,108 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batchRequests(n int) []*SimulationRequest {
+	reqs := make([]*SimulationRequest, n)
+	for i := range reqs {
+		reqs[i] = &SimulationRequest{LedgerSequence: uint32(i)}
+	}
+	return reqs
+}
+
+func TestRunBatch_OrderedResultsAndPerItemErrors(t *testing.T) {
+	var running, peak atomic.Int32
+	runner := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		// Later requests finish first so ordering cannot come from timing.
+		time.Sleep(time.Duration(20-req.LedgerSequence) * time.Millisecond)
+		if req.LedgerSequence == 3 {
+			return nil, errors.New("boom")
+		}
+		return &SimulationResponse{Status: "success", Logs: []string{string(rune('a' + req.LedgerSequence))}}, nil
+	})
+
+	var progress []int
+	batch, err := RunBatch(context.Background(), batchRequests(10), BatchOptions{
+		Runner:      runner,
+		Concurrency: 3,
+		OnProgress:  func(p BatchProgress) { progress = append(progress, p.Completed) },
+	})
+	require.NoError(t, err)
+	require.Len(t, batch.Items, 10)
+
+	for i, item := range batch.Items {
+		assert.Equal(t, i, item.Index)
+		if i == 3 {
+			assert.EqualError(t, item.Err, "boom")
+			assert.Nil(t, item.Response)
+			continue
+		}
+		require.NoError(t, item.Err)
+		assert.Equal(t, []string{string(rune('a' + i))}, item.Response.Logs)
+		assert.Positive(t, item.Duration)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(3), "concurrency is bounded")
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, progress)
+
+	s := batch.Stats
+	assert.Equal(t, 10, s.Total)
+	assert.Equal(t, 9, s.Succeeded)
+	assert.Equal(t, 1, s.Failed)
+	assert.Zero(t, s.Cancelled)
+	assert.LessOrEqual(t, s.Min, s.P50)
+	assert.LessOrEqual(t, s.P50, s.P95)
+	assert.LessOrEqual(t, s.P95, s.Max)
+	assert.Greater(t, s.BusyTime, s.WallTime, "items overlapped")
+	assert.Positive(t, s.Throughput)
+	assert.Nil(t, batch.Responses()[3])
+}
+
+func TestRunBatch_StopOnError(t *testing.T) {
+	var calls atomic.Int32
+	runner := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		calls.Add(1)
+		return nil, errors.New("fail")
+	})
+
+	batch, err := RunBatch(context.Background(), batchRequests(50), BatchOptions{Runner: runner, Concurrency: 1, StopOnError: true})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 1, batch.Stats.Failed)
+	assert.Equal(t, 49, batch.Stats.Cancelled)
+	assert.True(t, batch.Items[49].Cancelled)
+	assert.ErrorIs(t, batch.Items[49].Err, context.Canceled)
+}
+
+func TestRunBatch_Empty(t *testing.T) {
+	batch, err := RunBatch(context.Background(), nil, BatchOptions{Runner: NewDefaultMockRunner()})
+	require.NoError(t, err)
+	assert.Empty(t, batch.Items)
+	assert.Zero(t, batch.Stats.Total)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(5), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 95))
+	assert.Equal(t, time.Duration(7), percentile([]time.Duration{7}, 50))
+}
@@ -0,0 +1,318 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// SimSchemaVersion is the request/response schema this build of erst speaks
+// with erst-sim. Binaries reporting a different schema are refused.
+const SimSchemaVersion = 1
+
+// capabilitiesFlag asks erst-sim to describe itself instead of simulating.
+const capabilitiesFlag = "--capabilities"
+
+// CapabilitiesCacheFileName is the file in ~/.erst that remembers what each
+// simulator binary reported, so it is only queried once per build.
+const CapabilitiesCacheFileName = "sim_capabilities.json"
+
+// Optional simulator features reported in Capabilities.Features.
+const (
+	FeatureAuthTrace           = "auth_trace"
+	FeatureFlamegraph          = "flamegraph"
+	FeatureStackTrace          = "stack_trace"
+	FeatureOptimizationAdvisor = "optimization_advisor"
+	FeatureMockFees            = "mock_fees"
+	FeatureLedgerAccess        = "ledger_access"
+	FeatureServe               = "serve"
+	FeatureStream              = "stream"
+	FeatureLedgerInfo          = "ledger_info"
+	FeatureBudgetLimits        = "budget_limits"
+	FeatureCostParams          = "cost_params"
+)
+
+// legacyFeatures are assumed for binaries that predate --capabilities.
+var legacyFeatures = []string{FeatureFlamegraph, FeatureStackTrace, FeatureOptimizationAdvisor}
+
+// Capabilities is what an erst-sim binary reports for --capabilities.
+type Capabilities struct {
+	SchemaVersion int    `json:"schema_version"`
+	Version       string `json:"version,omitempty"`
+	HostVersion   string `json:"soroban_env_host,omitempty"`
+	// Protocols lists the supported protocol versions. Binaries may report
+	// only MaxProtocol; see SupportedProtocols.
+	Protocols   []uint32 `json:"protocols,omitempty"`
+	MaxProtocol uint32   `json:"max_protocol,omitempty"`
+	Features    []string `json:"features,omitempty"`
+	// Legacy is set for binaries that do not understand --capabilities; the
+	// schema and features of the last such release are assumed.
+	Legacy bool `json:"legacy,omitempty"`
+}
+
+// Has reports whether the binary supports feature.
+func (c *Capabilities) Has(feature string) bool {
+	return slices.Contains(c.Features, feature)
+}
+
+// SupportedProtocols returns Protocols, or when the binary only reported
+// MaxProtocol, the known protocols up to it. The list is derived on every call
+// rather than cached, so protocols added to the registry after the binary was
+// probed are picked up.
+func (c *Capabilities) SupportedProtocols() []uint32 {
+	if len(c.Protocols) == 0 && c.MaxProtocol > 0 {
+		return protocolsUpTo(c.MaxProtocol)
+	}
+	return c.Protocols
+}
+
+// SupportsProtocol reports whether the binary can simulate version. An
+// unknown protocol list supports every version.
+func (c *Capabilities) SupportsProtocol(version uint32) bool {
+	protocols := c.SupportedProtocols()
+	return len(protocols) == 0 || slices.Contains(protocols, version)
+}
+
+// Compatible returns an ErrSimIncompatible error when the binary's schema
+// differs from SimSchemaVersion.
+func (c *Capabilities) Compatible() error {
+	if c.SchemaVersion != SimSchemaVersion {
+		return errors.WrapSimIncompatible(fmt.Sprintf(
+			"simulator speaks schema version %d but this erst requires %d; install a matching erst-sim or upgrade erst",
+			c.SchemaVersion, SimSchemaVersion))
+	}
+	return nil
+}
+
+var (
+	capabilitiesMu  sync.Mutex
+	capabilitiesMem = make(map[string]*Capabilities)
+)
+
+// QueryCapabilities returns the capabilities of the binary at path. Results
+// are cached in memory and in ~/.erst/sim_capabilities.json, keyed by the
+// binary's path, size and modification time, so a rebuilt binary is asked
+// again.
+func QueryCapabilities(ctx context.Context, path string) (*Capabilities, error) {
+	key, err := capabilitiesKey(path)
+	if err != nil {
+		return nil, errors.WrapSimulatorNotFound(path)
+	}
+
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	if caps, ok := capabilitiesMem[key]; ok {
+		return caps, nil
+	}
+	cache := loadCapabilitiesCache()
+	if caps, ok := cache[key]; ok {
+		capabilitiesMem[key] = caps
+		return caps, nil
+	}
+
+	caps, err := probeCapabilities(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	capabilitiesMem[key] = caps
+	cache[key] = caps
+	saveCapabilitiesCache(cache)
+	return caps, nil
+}
+
+func capabilitiesKey(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s|%d|%d", abs(path), info.Size(), info.ModTime().UnixNano()), nil
+}
+
+func probeCapabilities(ctx context.Context, path string) (*Capabilities, error) {
+	ctx, cancel := context.WithTimeout(ctx, simProbeTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, capabilitiesFlag)
+	cmd.Stdout = &stdout
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, errors.WrapSimTimeout(simProbeTimeout)
+	}
+	if caps, ok := parseCapabilities(stdout.Bytes()); ok {
+		return caps, nil
+	}
+	if runErr != nil && cmd.ProcessState == nil {
+		return nil, errors.WrapSimCrash(runErr, "")
+	}
+
+	// The binary ran but did not understand the flag: it predates the
+	// handshake.
+	return &Capabilities{SchemaVersion: SimSchemaVersion, Features: legacyFeatures, Legacy: true}, nil
+}
+
+func parseCapabilities(output []byte) (*Capabilities, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bytes.TrimSpace(output), &raw); err != nil {
+		return nil, false
+	}
+	if _, ok := raw["schema_version"]; !ok {
+		return nil, false
+	}
+	var caps Capabilities
+	if err := json.Unmarshal(output, &caps); err != nil {
+		return nil, false
+	}
+	return &caps, true
+}
+
+func capabilitiesCachePath() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".erst", CapabilitiesCacheFileName), true
+}
+
+func loadCapabilitiesCache() map[string]*Capabilities {
+	cache := make(map[string]*Capabilities)
+	path, ok := capabilitiesCachePath()
+	if !ok {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		logger.Logger.Warn("Ignoring corrupt simulator capabilities cache", "path", path, "error", err)
+		return make(map[string]*Capabilities)
+	}
+	return cache
+}
+
+func saveCapabilitiesCache(cache map[string]*Capabilities) {
+	path, ok := capabilitiesCachePath()
+	if !ok {
+		return
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0600)
+	}
+	if err != nil {
+		logger.Logger.Warn("Failed to save simulator capabilities", "path", path, "error", err)
+	}
+}
+
+// negotiate checks a prepared request against the runner's capabilities.
+// Schema and protocol mismatches are refused; optional features the binary
+// lacks are switched off with a warning so the rest of the run still works.
+func (r *Runner) negotiate(req *SimulationRequest, proto *Protocol) error {
+	caps := r.Capabilities
+	if caps == nil {
+		return nil
+	}
+	if err := caps.Compatible(); err != nil {
+		return err
+	}
+	if !caps.SupportsProtocol(proto.Version) {
+		return errors.WrapSimIncompatible(fmt.Sprintf(
+			"%s does not support protocol %d (supports %v); register a matching build with erst sim register",
+			r.BinaryPath, proto.Version, caps.SupportedProtocols()))
+	}
+
+	if req.Profile && !caps.Has(FeatureFlamegraph) {
+		logger.Logger.Warn("Simulator cannot produce flamegraphs; profiling disabled", "path", r.BinaryPath)
+		req.Profile = false
+	}
+	if req.AuthTraceOpts != nil && req.AuthTraceOpts.Enabled && !caps.Has(FeatureAuthTrace) {
+		logger.Logger.Warn("Simulator does not support auth traces; auth tracing disabled", "path", r.BinaryPath)
+		req.AuthTraceOpts = nil
+	}
+	if (req.MockBaseFee != nil || req.MockGasPrice != nil) && !caps.Has(FeatureMockFees) {
+		logger.Logger.Warn("Simulator does not support fee mocks; ignoring them", "path", r.BinaryPath)
+		req.MockBaseFee, req.MockGasPrice = nil, nil
+	}
+	if len(req.CostParams) > 0 && !caps.Has(FeatureCostParams) {
+		logger.Logger.Warn("Simulator does not support cost models; only the gas model's hash and signature costs apply", "path", r.BinaryPath)
+		req.CostParams = nil
+	}
+	return nil
+}
+
+var (
+	responseFieldsOnce sync.Once
+	responseFields     map[string]bool
+)
+
+// unknownResponseFields returns the top-level fields of a raw simulator
+// response that SimulationResponse does not declare, sorted.
+func unknownResponseFields(data []byte) []string {
+	responseFieldsOnce.Do(func() {
+		responseFields = make(map[string]bool)
+		t := reflect.TypeOf(SimulationResponse{})
+		for i := range t.NumField() {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				responseFields[name] = true
+			}
+		}
+	})
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	var unknown []string
+	for name := range raw {
+		if !responseFields[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// warnUnknownFields logs, once per runner, response fields this erst does
+// not understand and therefore drops.
+func (r *Runner) warnUnknownFields(data []byte) {
+	r.unknownFieldsOnce.Do(func() {
+		if unknown := unknownResponseFields(data); len(unknown) > 0 {
+			logger.Logger.Warn("Simulator returned fields this erst does not understand; upgrade erst to use them",
+				"path", r.BinaryPath, "fields", strings.Join(unknown, ","))
+		}
+	})
+}
+
+// loadCapabilities queries the capabilities of r's binary, logging instead
+// of failing when the binary cannot be asked.
+func (r *Runner) loadCapabilities() {
+	ctx, cancel := context.WithTimeout(context.Background(), simProbeTimeout+time.Second)
+	defer cancel()
+	caps, err := QueryCapabilities(ctx, r.BinaryPath)
+	if err != nil {
+		logger.Logger.Warn("Could not query simulator capabilities", "path", r.BinaryPath, "error", err)
+		return
+	}
+	r.Capabilities = caps
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	caps, ok := parseCapabilities([]byte(`{"schema_version":1,"version":"0.2.0","soroban_env_host":"22.1.0","max_protocol":22,"features":["flamegraph"]}`))
+	require.True(t, ok)
+	assert.Equal(t, "22.1.0", caps.HostVersion)
+	assert.Empty(t, caps.Protocols, "protocols are derived from max_protocol when used, not stored")
+	assert.Contains(t, caps.SupportedProtocols(), uint32(22))
+	assert.True(t, caps.SupportsProtocol(22))
+	assert.False(t, caps.SupportsProtocol(23))
+	assert.True(t, caps.Has(FeatureFlamegraph))
+	assert.False(t, caps.Has(FeatureAuthTrace))
+
+	_, ok = parseCapabilities([]byte(`{"status":"error","error":"Invalid JSON: EOF"}`))
+	assert.False(t, ok, "a simulation error is not a capabilities report")
+}
+
+func TestQueryCapabilities_CachedPerBinary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	counter := filepath.Join(t.TempDir(), "calls")
+	runner := fakeSim(t, `echo x >> `+counter+`; echo '{"schema_version":1,"version":"0.3.0","protocols":[22,23],"features":["auth_trace"]}'`)
+
+	caps, err := QueryCapabilities(context.Background(), runner.BinaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, "0.3.0", caps.Version)
+	assert.Equal(t, []uint32{22, 23}, caps.Protocols)
+
+	// Forget the in-memory copy so the disk cache is exercised.
+	capabilitiesMu.Lock()
+	clear(capabilitiesMem)
+	capabilitiesMu.Unlock()
+
+	again, err := QueryCapabilities(context.Background(), runner.BinaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, caps, again)
+
+	calls, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(calls), "x"), "the binary is asked once")
+}
+
+func TestQueryCapabilities_Legacy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	runner := fakeSim(t, `cat >/dev/null; echo '{"status":"error","error":"Invalid JSON"}'; exit 1`)
+
+	caps, err := QueryCapabilities(context.Background(), runner.BinaryPath)
+	require.NoError(t, err)
+	assert.True(t, caps.Legacy)
+	assert.NoError(t, caps.Compatible())
+	assert.True(t, caps.SupportsProtocol(LatestVersion()))
+}
+
+func TestNegotiate(t *testing.T) {
+	proto := GetOrDefault(nil)
+
+	r := &Runner{BinaryPath: "erst-sim", Capabilities: &Capabilities{SchemaVersion: SimSchemaVersion + 1}}
+	err := r.negotiate(&SimulationRequest{}, proto)
+	assert.ErrorIs(t, err, errors.ErrSimIncompatible)
+
+	r.Capabilities = &Capabilities{SchemaVersion: SimSchemaVersion, Protocols: []uint32{proto.Version + 1}}
+	err = r.negotiate(&SimulationRequest{}, proto)
+	assert.ErrorIs(t, err, errors.ErrSimIncompatible)
+	assert.Contains(t, err.Error(), "erst sim register")
+
+	r.Capabilities = &Capabilities{SchemaVersion: SimSchemaVersion, Features: []string{FeatureAuthTrace}}
+	fee := uint32(100)
+	req := &SimulationRequest{
+		Profile:       true,
+		AuthTraceOpts: &AuthTraceOptions{Enabled: true},
+		MockBaseFee:   &fee,
+	}
+	require.NoError(t, r.negotiate(req, proto))
+	assert.False(t, req.Profile, "profiling is downgraded without flamegraph support")
+	assert.NotNil(t, req.AuthTraceOpts, "supported features are kept")
+	assert.Nil(t, req.MockBaseFee)
+
+	r.Capabilities = nil
+	assert.NoError(t, r.negotiate(&SimulationRequest{Profile: true}, proto), "unknown capabilities are not checked")
+}
+
+func TestPrepareRequest_RefusesUnsupportedProtocol(t *testing.T) {
+	r := &Runner{Capabilities: &Capabilities{SchemaVersion: SimSchemaVersion, Protocols: []uint32{1}}}
+	_, err := r.prepareRequest(&SimulationRequest{})
+	assert.ErrorIs(t, err, errors.ErrSimIncompatible)
+}
+
+func TestUnknownResponseFields(t *testing.T) {
+	fields := unknownResponseFields([]byte(`{"status":"success","logs":[],"zeta":1,"alpha":{}}`))
+	assert.Equal(t, []string{"alpha", "zeta"}, fields)
+	assert.Empty(t, unknownResponseFields([]byte(`{"status":"success","budget_usage":null}`)))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/dwarf"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ContractSpecSection is the WASM custom section soroban-sdk writes the
+// contract interface to, as a stream of XDR ScSpecEntry values.
+const ContractSpecSection = "contractspecv0"
+
+// ContractSpec is the typed interface of a contract: its functions and the
+// user-defined types their arguments refer to.
+type ContractSpec struct {
+	Functions  map[string]xdr.ScSpecFunctionV0
+	Structs    map[string]xdr.ScSpecUdtStructV0
+	Unions     map[string]xdr.ScSpecUdtUnionV0
+	Enums      map[string]xdr.ScSpecUdtEnumV0
+	ErrorEnums map[string]xdr.ScSpecUdtErrorEnumV0
+}
+
+// LoadContractSpec reads the contract spec from the WASM file at path.
+func LoadContractSpec(path string) (*ContractSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read WASM file: %v", err))
+	}
+	return ParseContractSpec(data)
+}
+
+// ParseContractSpec decodes the contractspecv0 section of a WASM module.
+func ParseContractSpec(wasm []byte) (*ContractSpec, error) {
+	section, ok := dwarf.WASMCustomSections(wasm)[ContractSpecSection]
+	if !ok {
+		return nil, errors.WrapValidationError("WASM module has no " + ContractSpecSection + " section")
+	}
+
+	spec := &ContractSpec{
+		Functions:  make(map[string]xdr.ScSpecFunctionV0),
+		Structs:    make(map[string]xdr.ScSpecUdtStructV0),
+		Unions:     make(map[string]xdr.ScSpecUdtUnionV0),
+		Enums:      make(map[string]xdr.ScSpecUdtEnumV0),
+		ErrorEnums: make(map[string]xdr.ScSpecUdtErrorEnumV0),
+	}
+
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, errors.WrapUnmarshalFailed(err, ContractSpecSection)
+		}
+		switch entry.Kind {
+		case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+			spec.Functions[string(entry.FunctionV0.Name)] = *entry.FunctionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+			spec.Structs[entry.UdtStructV0.Name] = *entry.UdtStructV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+			spec.Unions[entry.UdtUnionV0.Name] = *entry.UdtUnionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+			spec.Enums[entry.UdtEnumV0.Name] = *entry.UdtEnumV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+			spec.ErrorEnums[entry.UdtErrorEnumV0.Name] = *entry.UdtErrorEnumV0
+		}
+	}
+	return spec, nil
+}
@@ -0,0 +1,314 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Fee increments used by the Soroban fee model (soroban-env-host fees.rs).
+const (
+	instructionsIncrement = 10000
+	dataSizeIncrement     = 1024
+	// txBaseResultSize is added to the transaction size when charging for
+	// history, to account for the stored result.
+	txBaseResultSize = 300
+)
+
+// DefaultBaseFee is the network minimum inclusion fee per operation, in stroops.
+const DefaultBaseFee = 100
+
+// inMemorySorobanStateProtocol is the protocol from which contract data and
+// code are kept in memory and no longer charged as disk reads.
+const inMemorySorobanStateProtocol = 23
+
+// FeeResources are the resources a Soroban transaction is charged for.
+type FeeResources struct {
+	Instructions uint64 `json:"instructions"`
+	// DiskReadEntries counts footprint entries read from disk. Before
+	// protocol 23 that is every entry; since then only classic entries are,
+	// as contract data and code live in memory.
+	DiskReadEntries     uint32 `json:"disk_read_entries"`
+	WriteEntries        uint32 `json:"write_entries"`
+	DiskReadBytes       uint32 `json:"disk_read_bytes"`
+	WriteBytes          uint32 `json:"write_bytes"`
+	TxSizeBytes         uint32 `json:"tx_size_bytes"`
+	ContractEventsBytes uint32 `json:"contract_events_bytes"`
+}
+
+// ResourceFee is a resource fee broken down by component, in stroops.
+type ResourceFee struct {
+	Compute         int64 `json:"compute"`
+	DiskReadEntries int64 `json:"disk_read_entries"`
+	WriteEntries    int64 `json:"write_entries"`
+	DiskReadBytes   int64 `json:"disk_read_bytes"`
+	WriteBytes      int64 `json:"write_bytes"`
+	Historical      int64 `json:"historical"`
+	Bandwidth       int64 `json:"bandwidth"`
+	Events          int64 `json:"events"`
+}
+
+// NonRefundable is the part of the fee charged regardless of execution.
+func (f ResourceFee) NonRefundable() int64 {
+	return f.Compute + f.DiskReadEntries + f.WriteEntries + f.DiskReadBytes + f.WriteBytes + f.Historical + f.Bandwidth
+}
+
+// Refundable is the part of the fee charged for what execution produced.
+// Rent is refundable too but is not estimated.
+func (f ResourceFee) Refundable() int64 {
+	return f.Events
+}
+
+func (f ResourceFee) Total() int64 {
+	return f.NonRefundable() + f.Refundable()
+}
+
+// feePerIncrement charges fee for every started increment of resource.
+func feePerIncrement(resource uint64, fee int64, increment uint64) int64 {
+	if fee <= 0 || resource == 0 {
+		return 0
+	}
+	return int64((resource*uint64(fee) + increment - 1) / increment)
+}
+
+// ComputeResourceFee prices res with the network's fee configuration. Write
+// bytes are priced at the flat protocol 23 rate; older networks have no such
+// setting and those bytes come out free.
+func ComputeResourceFee(res FeeResources, ns *rpc.NetworkSettings) ResourceFee {
+	if ns == nil {
+		return ResourceFee{}
+	}
+	return ResourceFee{
+		Compute:         feePerIncrement(res.Instructions, ns.FeeRatePerInstructionsIncrement, instructionsIncrement),
+		DiskReadEntries: ns.FeeDiskReadLedgerEntry * int64(res.DiskReadEntries+res.WriteEntries),
+		WriteEntries:    ns.FeeWriteLedgerEntry * int64(res.WriteEntries),
+		DiskReadBytes:   feePerIncrement(uint64(res.DiskReadBytes), ns.FeeDiskRead1Kb, dataSizeIncrement),
+		WriteBytes:      feePerIncrement(uint64(res.WriteBytes), ns.FeeWrite1Kb, dataSizeIncrement),
+		Historical:      feePerIncrement(uint64(res.TxSizeBytes)+txBaseResultSize, ns.FeeHistorical1Kb, dataSizeIncrement),
+		Bandwidth:       feePerIncrement(uint64(res.TxSizeBytes), ns.FeeTxSize1Kb, dataSizeIncrement),
+		Events:          feePerIncrement(uint64(res.ContractEventsBytes), ns.FeeContractEvents1Kb, dataSizeIncrement),
+	}
+}
+
+// FeeEstimate compares the fee a transaction needed with the fee it declared.
+type FeeEstimate struct {
+	Resources FeeResources `json:"resources"`
+	Fee       ResourceFee  `json:"fee"`
+	// InclusionFee is the base fee times the number of operations.
+	InclusionFee int64 `json:"inclusion_fee"`
+
+	DeclaredResourceFee int64 `json:"declared_resource_fee"`
+	DeclaredFee         int64 `json:"declared_fee"`
+
+	// Notes lists inputs that could not be measured and were left out.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// RecommendedResourceFee is the minimum resourceFee the transaction needs.
+func (e *FeeEstimate) RecommendedResourceFee() int64 {
+	return e.Fee.Total()
+}
+
+// RecommendedFee is the minimum total fee: resource fee plus inclusion fee.
+func (e *FeeEstimate) RecommendedFee() int64 {
+	return e.Fee.Total() + e.InclusionFee
+}
+
+// ResourceFeeShortfall is how many stroops the declared resourceFee is short,
+// or zero when it covers the estimate.
+func (e *FeeEstimate) ResourceFeeShortfall() int64 {
+	return max(0, e.RecommendedResourceFee()-e.DeclaredResourceFee)
+}
+
+// FeeShortfall is how many stroops the declared total fee is short, or zero
+// when it covers the estimate.
+func (e *FeeEstimate) FeeShortfall() int64 {
+	return max(0, e.RecommendedFee()-e.DeclaredFee)
+}
+
+func (e *FeeEstimate) Sufficient() bool {
+	return e.ResourceFeeShortfall() == 0 && e.FeeShortfall() == 0
+}
+
+// FeeInputs are the artifacts EstimateFees measures resources from.
+type FeeInputs struct {
+	EnvelopeXdr string
+	// ResultMetaXdr is the on-chain TransactionResultMeta. It supplies event
+	// sizes and the entries the transaction wrote; entries it did not change,
+	// or all of them without meta, are sized from LedgerEntries. Without meta
+	// events are not charged.
+	ResultMetaXdr string
+	// LedgerEntries are the entries the simulation ran against, keyed by
+	// base64 LedgerKey.
+	LedgerEntries map[string]string
+	Response      *SimulationResponse
+	Settings      *rpc.NetworkSettings
+	// BaseFee is the inclusion fee per operation; zero means DefaultBaseFee.
+	BaseFee int64
+	// ProtocolVersion is the protocol of the ledger the transaction was
+	// applied in, which decides what counts as a disk read. Zero means the
+	// current rules.
+	ProtocolVersion uint32
+}
+
+// EstimateFees measures the resources of a Soroban transaction and prices
+// them with the network's fee configuration. Instructions come from the
+// simulation's budget usage, falling back to the declared instructions.
+func EstimateFees(in FeeInputs) (*FeeEstimate, error) {
+	if in.Settings == nil {
+		return nil, errors.WrapValidationError("network fee settings are required to estimate fees")
+	}
+
+	envBytes, err := base64.StdEncoding.DecodeString(in.EnvelopeXdr)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "envelope base64")
+	}
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshal(envBytes, &env); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+	}
+	sorobanData, ok := sorobanTransactionData(env)
+	if !ok {
+		return nil, errors.WrapValidationError("transaction has no Soroban resources to estimate")
+	}
+
+	est := &FeeEstimate{
+		DeclaredResourceFee: int64(sorobanData.ResourceFee),
+		DeclaredFee:         int64(env.Fee()),
+	}
+	ops := len(env.Operations())
+	if env.IsFeeBump() {
+		// The outer transaction pays the inclusion fee for one more operation.
+		est.DeclaredFee = env.FeeBumpFee()
+		ops++
+	}
+	declared := sorobanData.Resources
+	res := &est.Resources
+	res.TxSizeBytes = uint32(len(envBytes))
+
+	res.Instructions = uint64(declared.Instructions)
+	if in.Response != nil && in.Response.BudgetUsage != nil && in.Response.BudgetUsage.CPUInstructions > 0 {
+		res.Instructions = in.Response.BudgetUsage.CPUInstructions
+	}
+
+	var written map[string]string
+	if changes, err := rpc.ExtractLedgerEntryChanges(in.ResultMetaXdr); err == nil {
+		written = changes.After
+	} else {
+		est.Notes = append(est.Notes, "no result meta: write sizes use pre-execution entries")
+	}
+
+	if in.ProtocolVersion == 0 {
+		est.Notes = append(est.Notes, fmt.Sprintf("ledger protocol unknown: contract data and code reads are not charged (protocol %d+)", inMemorySorobanStateProtocol))
+	}
+	sorobanInMemory := in.ProtocolVersion == 0 || in.ProtocolVersion >= inMemorySorobanStateProtocol
+
+	footprint := declared.Footprint
+	for _, key := range append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...) {
+		if sorobanInMemory && isSorobanEntry(key.Type) {
+			continue
+		}
+		res.DiskReadEntries++
+		res.DiskReadBytes += entrySize(in.LedgerEntries, key)
+	}
+	res.WriteEntries = uint32(len(footprint.ReadWrite))
+	for _, key := range footprint.ReadWrite {
+		size := entrySize(written, key)
+		if size == 0 {
+			size = entrySize(in.LedgerEntries, key)
+		}
+		res.WriteBytes += size
+	}
+
+	if size, ok := contractEventsSize(in.ResultMetaXdr); ok {
+		res.ContractEventsBytes = size
+	} else {
+		est.Notes = append(est.Notes, "no result meta: contract event sizes not charged")
+	}
+	if in.Settings.FeeWrite1Kb == 0 {
+		est.Notes = append(est.Notes, "network has no flat write fee (pre-protocol 23): write bytes not charged")
+	}
+	est.Notes = append(est.Notes, "rent for new or extended entries is not included")
+
+	est.Fee = ComputeResourceFee(*res, in.Settings)
+
+	baseFee := in.BaseFee
+	if baseFee <= 0 {
+		baseFee = DefaultBaseFee
+	}
+	est.InclusionFee = baseFee * int64(max(1, ops))
+	return est, nil
+}
+
+func isSorobanEntry(t xdr.LedgerEntryType) bool {
+	return t == xdr.LedgerEntryTypeContractData || t == xdr.LedgerEntryTypeContractCode || t == xdr.LedgerEntryTypeTtl
+}
+
+// entrySize returns the XDR size of the entry stored under key, or zero when
+// entries does not hold it.
+func entrySize(entries map[string]string, key xdr.LedgerKey) uint32 {
+	k, err := rpc.EncodeLedgerKey(key)
+	if err != nil {
+		return 0
+	}
+	raw, ok := entries[k]
+	if !ok {
+		return 0
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return 0
+	}
+	return uint32(len(b))
+}
+
+// contractEventsSize sums the XDR size of the contract events and the return
+// value recorded in a TransactionResultMeta, which is what the events fee is
+// charged on.
+func contractEventsSize(resultMetaXdr string) (uint32, bool) {
+	var resultMeta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &resultMeta); err != nil {
+		return 0, false
+	}
+
+	var events []xdr.ContractEvent
+	var returnValue *xdr.ScVal
+	meta := resultMeta.TxApplyProcessing
+	switch meta.V {
+	case 3:
+		if meta.V3 == nil || meta.V3.SorobanMeta == nil {
+			return 0, false
+		}
+		events = meta.V3.SorobanMeta.Events
+		returnValue = &meta.V3.SorobanMeta.ReturnValue
+	case 4:
+		if meta.V4 == nil || meta.V4.SorobanMeta == nil {
+			return 0, false
+		}
+		for _, op := range meta.V4.Operations {
+			events = append(events, op.Events...)
+		}
+		returnValue = meta.V4.SorobanMeta.ReturnValue
+	default:
+		return 0, false
+	}
+
+	var size uint32
+	for _, ev := range events {
+		if b, err := ev.MarshalBinary(); err == nil {
+			size += uint32(len(b))
+		}
+	}
+	if returnValue != nil {
+		if b, err := returnValue.MarshalBinary(); err == nil {
+			size += uint32(len(b))
+		}
+	}
+	return size, true
+}
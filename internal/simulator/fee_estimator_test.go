@@ -0,0 +1,186 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeeSettings() *rpc.NetworkSettings {
+	return &rpc.NetworkSettings{
+		FeeRatePerInstructionsIncrement: 25,
+		FeeDiskReadLedgerEntry:          6250,
+		FeeWriteLedgerEntry:             10000,
+		FeeDiskRead1Kb:                  1786,
+		FeeWrite1Kb:                     3500,
+		FeeHistorical1Kb:                16235,
+		FeeContractEvents1Kb:            10000,
+		FeeTxSize1Kb:                    1624,
+	}
+}
+
+func TestComputeResourceFee(t *testing.T) {
+	fee := ComputeResourceFee(FeeResources{
+		Instructions:        1_000_001,
+		DiskReadEntries:     1,
+		WriteEntries:        2,
+		DiskReadBytes:       1024,
+		WriteBytes:          1025,
+		TxSizeBytes:         724,
+		ContractEventsBytes: 512,
+	}, testFeeSettings())
+
+	assert.Equal(t, int64(2501), fee.Compute, "partial increments round up")
+	assert.Equal(t, int64(3*6250), fee.DiskReadEntries, "write entries are read first")
+	assert.Equal(t, int64(2*10000), fee.WriteEntries)
+	assert.Equal(t, int64(1786), fee.DiskReadBytes)
+	assert.Equal(t, int64(3504), fee.WriteBytes)
+	assert.Equal(t, int64(16235), fee.Historical, "history includes the 300 byte result")
+	assert.Equal(t, int64(1149), fee.Bandwidth)
+	assert.Equal(t, int64(5000), fee.Events)
+	assert.Equal(t, int64(5000), fee.Refundable())
+	assert.Equal(t, fee.NonRefundable()+5000, fee.Total())
+
+	assert.Zero(t, ComputeResourceFee(FeeResources{Instructions: 1}, nil).Total())
+}
+
+func feeTestEnvelope(t *testing.T, readOnly, readWrite []xdr.LedgerKey, resourceFee int64, fee uint32) string {
+	t.Helper()
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+		Fee:           xdr.Uint32(fee),
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+		Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{},
+		}}},
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{
+				Footprint:    xdr.LedgerFootprint{ReadOnly: readOnly, ReadWrite: readWrite},
+				Instructions: 500000,
+			},
+			ResourceFee: xdr.Int64(resourceFee),
+		}},
+	}
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}}
+	raw, err := env.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func feeTestMeta(t *testing.T, event xdr.ContractEvent, ret xdr.ScVal, changes ...xdr.LedgerEntryChange) string {
+	t.Helper()
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		}}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations:  []xdr.OperationMeta{{Changes: changes}},
+			SorobanMeta: &xdr.SorobanTransactionMeta{Events: []xdr.ContractEvent{event}, ReturnValue: ret},
+		}},
+	}
+	raw, err := meta.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestEstimateFees(t *testing.T) {
+	_, roKey, roKeyXDR, roEntryXDR := replayAccount(t, "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", 100)
+	_, rwKey, rwKeyXDR, rwEntryXDR := replayAccount(t, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 50)
+	rwAfter, _, _, _ := replayAccount(t, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 40)
+	rwAfter.Data.Account.HomeDomain = "example.com"
+	rwAfterXDR, err := rpc.EncodeLedgerEntry(rwAfter)
+	require.NoError(t, err)
+
+	ret := xdr.ScVal{Type: xdr.ScValTypeScvBool, B: new(bool)}
+	event := xdr.ContractEvent{Type: xdr.ContractEventTypeContract, Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{Data: ret}}}
+
+	in := FeeInputs{
+		EnvelopeXdr:   feeTestEnvelope(t, []xdr.LedgerKey{roKey}, []xdr.LedgerKey{rwKey}, 1000, 1100),
+		ResultMetaXdr: feeTestMeta(t, event, ret, xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &rwAfter}),
+		LedgerEntries: map[string]string{roKeyXDR: roEntryXDR, rwKeyXDR: rwEntryXDR},
+		Response:      &SimulationResponse{BudgetUsage: &BudgetUsage{CPUInstructions: 2_000_000}},
+		Settings:      testFeeSettings(),
+	}
+	est, err := EstimateFees(in)
+	require.NoError(t, err)
+
+	size := func(b64 string) uint32 {
+		raw, err := base64.StdEncoding.DecodeString(b64)
+		require.NoError(t, err)
+		return uint32(len(raw))
+	}
+	eventBytes, err := event.MarshalBinary()
+	require.NoError(t, err)
+	retBytes, err := ret.MarshalBinary()
+	require.NoError(t, err)
+
+	res := est.Resources
+	assert.Equal(t, uint64(2_000_000), res.Instructions, "measured instructions win over declared")
+	assert.Equal(t, uint32(2), res.DiskReadEntries)
+	assert.Equal(t, uint32(1), res.WriteEntries)
+	assert.Equal(t, size(roEntryXDR)+size(rwEntryXDR), res.DiskReadBytes)
+	assert.Equal(t, size(rwAfterXDR), res.WriteBytes, "writes are sized after execution")
+	assert.Equal(t, size(in.EnvelopeXdr), res.TxSizeBytes)
+	assert.Equal(t, uint32(len(eventBytes)+len(retBytes)), res.ContractEventsBytes)
+
+	assert.Equal(t, ComputeResourceFee(res, in.Settings), est.Fee)
+	assert.Equal(t, int64(DefaultBaseFee), est.InclusionFee)
+	assert.Equal(t, int64(1000), est.DeclaredResourceFee)
+	assert.Equal(t, int64(1100), est.DeclaredFee)
+	assert.False(t, est.Sufficient())
+	assert.Equal(t, est.Fee.Total()-1000, est.ResourceFeeShortfall())
+	assert.Equal(t, est.RecommendedFee()-1100, est.FeeShortfall())
+}
+
+func TestEstimateFees_SufficientWithoutMeta(t *testing.T) {
+	_, key, keyXDR, entryXDR := replayAccount(t, "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", 100)
+
+	est, err := EstimateFees(FeeInputs{
+		EnvelopeXdr:   feeTestEnvelope(t, nil, []xdr.LedgerKey{key}, 1_000_000, 1_000_500),
+		LedgerEntries: map[string]string{keyXDR: entryXDR},
+		Settings:      testFeeSettings(),
+		BaseFee:       500,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(500000), est.Resources.Instructions, "declared instructions are used without a simulation")
+	assert.NotZero(t, est.Resources.WriteBytes, "writes fall back to pre-execution entries")
+	assert.Zero(t, est.Resources.ContractEventsBytes)
+	assert.Equal(t, int64(500), est.InclusionFee)
+	assert.True(t, est.Sufficient())
+	assert.Zero(t, est.ResourceFeeShortfall())
+	assert.NotEmpty(t, est.Notes)
+}
+
+func TestEstimateFees_SorobanReadsByProtocol(t *testing.T) {
+	_, account, _, _ := replayAccount(t, "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", 100)
+	code := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}}}
+	envelope := feeTestEnvelope(t, []xdr.LedgerKey{account, code}, nil, 1_000_000, 1_000_500)
+
+	reads := func(protocol uint32) uint32 {
+		est, err := EstimateFees(FeeInputs{EnvelopeXdr: envelope, Settings: testFeeSettings(), ProtocolVersion: protocol})
+		require.NoError(t, err)
+		return est.Resources.DiskReadEntries
+	}
+	assert.Equal(t, uint32(2), reads(22), "contract code is read from disk before protocol 23")
+	assert.Equal(t, uint32(1), reads(23))
+	assert.Equal(t, uint32(1), reads(0), "an unknown protocol uses the current rules")
+}
+
+func TestEstimateFees_Errors(t *testing.T) {
+	_, err := EstimateFees(FeeInputs{EnvelopeXdr: replayEnvelope(t, nil), Settings: testFeeSettings()})
+	assert.Error(t, err, "classic transactions have no resource fee")
+
+	_, err = EstimateFees(FeeInputs{EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{})})
+	assert.Error(t, err, "settings are required")
+}
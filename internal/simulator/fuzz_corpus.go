@@ -0,0 +1,427 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+const (
+	corpusStateFile   = "state.json"
+	corpusQueueDir    = "queue"
+	corpusCrashesDir  = "crashes"
+	corpusStateFormat = 1
+
+	// crashStackDepth is how many frames from the trap site contribute to a
+	// crash signature. Deeper frames mostly reflect the caller, not the bug.
+	crashStackDepth = 3
+)
+
+// CorpusEntry is an input kept because it reached behaviour no earlier input
+// did. NewFeatures drives how often it is picked for further mutation.
+type CorpusEntry struct {
+	ID          string      `json:"id"`
+	Input       FuzzerInput `json:"input"`
+	NewFeatures int         `json:"new_features"`
+	AddedAt     time.Time   `json:"added_at"`
+
+	picks int
+}
+
+// CrashRecord is a unique crash, identified by its signature, together with
+// the smallest input found that still reproduces it.
+type CrashRecord struct {
+	Signature    string      `json:"signature"`
+	Description  string      `json:"description"`
+	Status       string      `json:"status"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+	Input        FuzzerInput `json:"input"`
+	Minimized    bool        `json:"minimized"`
+	Hits         int         `json:"hits"`
+	FirstSeen    time.Time   `json:"first_seen"`
+}
+
+type corpusState struct {
+	Format     int      `json:"format"`
+	NextSeed   uint64   `json:"next_seed"`
+	Iterations uint64   `json:"iterations"`
+	Features   []string `json:"features"`
+}
+
+// FuzzCorpus holds the inputs and crashes of a fuzzing campaign. When created
+// with a directory it persists them there, so a campaign can be stopped and
+// resumed; with an empty directory it lives in memory only.
+type FuzzCorpus struct {
+	Dir string
+
+	entries    []*CorpusEntry
+	crashes    map[string]*CrashRecord
+	features   map[string]struct{}
+	nextSeed   uint64
+	iterations uint64
+}
+
+// NewFuzzCorpus returns an empty in-memory corpus.
+func NewFuzzCorpus() *FuzzCorpus {
+	return &FuzzCorpus{
+		crashes:  make(map[string]*CrashRecord),
+		features: make(map[string]struct{}),
+	}
+}
+
+// OpenFuzzCorpus loads the corpus in dir, creating the directory layout if it
+// does not exist yet.
+func OpenFuzzCorpus(dir string) (*FuzzCorpus, error) {
+	c := NewFuzzCorpus()
+	c.Dir = dir
+
+	for _, sub := range []string{corpusQueueDir, corpusCrashesDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to create corpus directory: %v", err))
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, corpusStateFile))
+	switch {
+	case err == nil:
+		var state corpusState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, errors.WrapUnmarshalFailed(err, corpusStateFile)
+		}
+		if state.Format > corpusStateFormat {
+			return nil, errors.WrapValidationError(fmt.Sprintf("corpus format %d is newer than supported format %d", state.Format, corpusStateFormat))
+		}
+		c.nextSeed = state.NextSeed
+		c.iterations = state.Iterations
+		for _, f := range state.Features {
+			c.features[f] = struct{}{}
+		}
+	case !os.IsNotExist(err):
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read corpus state: %v", err))
+	}
+
+	if err := loadJSONDir(filepath.Join(dir, corpusQueueDir), func(data []byte) error {
+		var entry CorpusEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		c.entries = append(c.entries, &entry)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(c.entries, func(i, j int) bool { return c.entries[i].AddedAt.Before(c.entries[j].AddedAt) })
+
+	if err := loadJSONDir(filepath.Join(dir, corpusCrashesDir), func(data []byte) error {
+		var rec CrashRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return err
+		}
+		c.crashes[rec.Signature] = &rec
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func loadJSONDir(dir string, fn func([]byte) error) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to read %s: %v", path, err))
+		}
+		if err := fn(data); err != nil {
+			return errors.WrapUnmarshalFailed(err, path)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of inputs in the corpus.
+func (c *FuzzCorpus) Len() int { return len(c.entries) }
+
+// Entries returns the corpus inputs in the order they were added.
+func (c *FuzzCorpus) Entries() []*CorpusEntry { return c.entries }
+
+// FeatureCount returns the number of distinct behaviours reached so far.
+func (c *FuzzCorpus) FeatureCount() int { return len(c.features) }
+
+// Iterations returns the total number of executions across all runs.
+func (c *FuzzCorpus) Iterations() uint64 { return c.iterations }
+
+// Crashes returns the unique crashes, sorted by signature.
+func (c *FuzzCorpus) Crashes() []*CrashRecord {
+	out := make([]*CrashRecord, 0, len(c.crashes))
+	for _, rec := range c.crashes {
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Signature < out[j].Signature })
+	return out
+}
+
+// CrashPath returns the file a crash record is stored in.
+func (c *FuzzCorpus) CrashPath(signature string) string {
+	if c.Dir == "" {
+		return ""
+	}
+	return filepath.Join(c.Dir, corpusCrashesDir, signature+".json")
+}
+
+// nextIterationSeed hands out seeds that never repeat across resumed runs.
+func (c *FuzzCorpus) nextIterationSeed() uint64 {
+	seed := c.nextSeed
+	c.nextSeed++
+	c.iterations++
+	return seed
+}
+
+// pick chooses an input to mutate. Inputs that uncovered more behaviour are
+// favoured, and each pick lowers an input's weight so the queue keeps cycling.
+func (c *FuzzCorpus) pick(rng *rand.Rand) *CorpusEntry {
+	if len(c.entries) == 0 {
+		return nil
+	}
+
+	weights := make([]float64, len(c.entries))
+	var total float64
+	for i, e := range c.entries {
+		w := float64(1+e.NewFeatures) / float64(1+e.picks/4)
+		weights[i] = w
+		total += w
+	}
+
+	target := rng.Float64() * total
+	for i, w := range weights {
+		target -= w
+		if target <= 0 {
+			c.entries[i].picks++
+			return c.entries[i]
+		}
+	}
+	last := c.entries[len(c.entries)-1]
+	last.picks++
+	return last
+}
+
+// observe records features and returns how many of them were new.
+func (c *FuzzCorpus) observe(features []string) int {
+	added := 0
+	for _, f := range features {
+		if _, ok := c.features[f]; !ok {
+			c.features[f] = struct{}{}
+			added++
+		}
+	}
+	return added
+}
+
+// add stores input in the corpus unless an identical input is already there.
+func (c *FuzzCorpus) add(input FuzzerInput, newFeatures int) (*CorpusEntry, error) {
+	id := inputID(input)
+	for _, e := range c.entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+
+	entry := &CorpusEntry{ID: id, Input: input, NewFeatures: newFeatures, AddedAt: time.Now()}
+	c.entries = append(c.entries, entry)
+	return entry, c.writeJSON(filepath.Join(corpusQueueDir, id+".json"), entry)
+}
+
+// recordCrash deduplicates a crash by signature. It returns the record and
+// whether the signature had not been seen before.
+func (c *FuzzCorpus) recordCrash(rec *CrashRecord) (*CrashRecord, bool, error) {
+	if existing, ok := c.crashes[rec.Signature]; ok {
+		existing.Hits++
+		return existing, false, nil
+	}
+	rec.Hits = 1
+	rec.FirstSeen = time.Now()
+	c.crashes[rec.Signature] = rec
+	return rec, true, c.writeJSON(filepath.Join(corpusCrashesDir, rec.Signature+".json"), rec)
+}
+
+// Save writes the campaign state so the next run resumes where this one
+// stopped. Crash hit counts are refreshed as well.
+func (c *FuzzCorpus) Save() error {
+	if c.Dir == "" {
+		return nil
+	}
+
+	state := corpusState{
+		Format:     corpusStateFormat,
+		NextSeed:   c.nextSeed,
+		Iterations: c.iterations,
+		Features:   make([]string, 0, len(c.features)),
+	}
+	for f := range c.features {
+		state.Features = append(state.Features, f)
+	}
+	sort.Strings(state.Features)
+
+	for _, rec := range c.crashes {
+		if err := c.writeJSON(filepath.Join(corpusCrashesDir, rec.Signature+".json"), rec); err != nil {
+			return err
+		}
+	}
+	return c.writeJSON(corpusStateFile, state)
+}
+
+func (c *FuzzCorpus) writeJSON(rel string, v interface{}) error {
+	if c.Dir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+
+	path := filepath.Join(c.Dir, rel)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to write %s: %v", path, err))
+	}
+	return os.Rename(tmp, path)
+}
+
+func inputID(input FuzzerInput) string {
+	input.Seed = 0
+	data, _ := json.Marshal(input)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+var (
+	digitsPattern = regexp.MustCompile(`[0-9]+`)
+	hexPattern    = regexp.MustCompile(`\b[0-9a-fA-F]{16,}\b`)
+)
+
+// errorCategory strips values from an error message so that failures which
+// differ only in numbers or hashes fall into the same category.
+func errorCategory(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = hexPattern.ReplaceAllString(msg, "<hex>")
+	msg = digitsPattern.ReplaceAllString(msg, "N")
+	if len(msg) > 96 {
+		msg = msg[:96]
+	}
+	return strings.TrimSpace(msg)
+}
+
+// coverageFeatures describes the behaviour an execution reached. erst-sim
+// does not expose edge coverage, so the fuzzer is guided by what it does
+// report: outcome and error category, emitted events, trap sites and the
+// order of magnitude of the resources consumed.
+func coverageFeatures(result FuzzingResult, resp *SimulationResponse) []string {
+	features := []string{"status:" + result.Status}
+	if result.ErrorMessage != "" {
+		features = append(features, "error:"+errorCategory(result.ErrorMessage))
+	}
+	if resp == nil {
+		return features
+	}
+
+	for _, ev := range resp.DiagnosticEvents {
+		contract := ""
+		if ev.ContractID != nil {
+			contract = *ev.ContractID
+		}
+		topic := ""
+		if len(ev.Topics) > 0 {
+			topic = ev.Topics[0]
+		}
+		features = append(features, fmt.Sprintf("event:%s:%s:%s", ev.EventType, contract, topic))
+	}
+
+	if resp.StackTrace != nil {
+		features = append(features, "trap:"+trapKindString(resp.StackTrace.TrapKind))
+		for _, frame := range resp.StackTrace.Frames {
+			features = append(features, "frame:"+frameLabel(frame))
+		}
+	}
+
+	if b := resp.BudgetUsage; b != nil {
+		features = append(features,
+			fmt.Sprintf("cpu:%d", bits.Len64(b.CPUInstructions)),
+			fmt.Sprintf("mem:%d", bits.Len64(b.MemoryBytes)),
+		)
+	}
+	return features
+}
+
+// CrashSignature identifies a crash by trap kind and the innermost stack
+// frames, so the same bug reached through different inputs is reported once.
+// Failures without a stack trace are grouped by status and error category.
+// It returns a short stable hash and the readable form it was derived from.
+func CrashSignature(result FuzzingResult, resp *SimulationResponse) (string, string) {
+	var desc string
+	if resp != nil && resp.StackTrace != nil {
+		parts := []string{"trap " + trapKindString(resp.StackTrace.TrapKind)}
+		for i, frame := range resp.StackTrace.Frames {
+			if i >= crashStackDepth {
+				break
+			}
+			parts = append(parts, frameLabel(frame))
+		}
+		desc = strings.Join(parts, " <- ")
+	} else {
+		desc = result.Status
+		if result.ErrorMessage != "" {
+			desc += ": " + errorCategory(result.ErrorMessage)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(desc))
+	return hex.EncodeToString(sum[:8]), desc
+}
+
+func trapKindString(kind interface{}) string {
+	switch k := kind.(type) {
+	case nil:
+		return "unknown"
+	case string:
+		return k
+	default:
+		data, err := json.Marshal(k)
+		if err != nil {
+			return fmt.Sprint(k)
+		}
+		return string(data)
+	}
+}
+
+func frameLabel(frame StackFrame) string {
+	switch {
+	case frame.FuncName != nil:
+		return *frame.FuncName
+	case frame.FuncIndex != nil:
+		return fmt.Sprintf("func[%d]", *frame.FuncIndex)
+	case frame.WasmOffset != nil:
+		return fmt.Sprintf("@0x%x", *frame.WasmOffset)
+	default:
+		return "?"
+	}
+}
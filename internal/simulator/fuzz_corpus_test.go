@@ -0,0 +1,150 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trapResponse(funcs ...string) *SimulationResponse {
+	trace := &WasmStackTrace{TrapKind: "UnreachableCodeReached"}
+	for i, name := range funcs {
+		name := name
+		trace.Frames = append(trace.Frames, StackFrame{Index: i, FuncName: &name})
+	}
+	return &SimulationResponse{Status: "error", Error: "wasm trap", StackTrace: trace}
+}
+
+func TestFuzzCorpus_PersistsAndResumes(t *testing.T) {
+	dir := t.TempDir()
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		return &SimulationResponse{Status: "success", BudgetUsage: &BudgetUsage{CPUInstructions: uint64(req.Timestamp)}}, nil
+	}}
+
+	corpus, err := OpenFuzzCorpus(dir)
+	require.NoError(t, err)
+	harness := NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 20})
+	harness.Corpus = corpus
+
+	_, _, err = harness.Fuzz(&FuzzerInput{EnvelopeXdr: "AAAA", Timestamp: 1})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "state.json"))
+
+	resumed, err := OpenFuzzCorpus(dir)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), resumed.Iterations())
+	assert.Equal(t, corpus.Len(), resumed.Len())
+	assert.Equal(t, corpus.FeatureCount(), resumed.FeatureCount())
+
+	harness = NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 5})
+	harness.Corpus = resumed
+	results, _, err := harness.Fuzz(&FuzzerInput{EnvelopeXdr: "AAAA", Timestamp: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.Equal(t, uint64(20), results[0].Seed, "seeds continue from the previous run")
+}
+
+func TestFuzzCorpus_KeepsInputsReachingNewBehaviour(t *testing.T) {
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		contract := "C1"
+		resp := &SimulationResponse{Status: "success"}
+		if req.Timestamp != 1 {
+			resp.DiagnosticEvents = []DiagnosticEvent{{EventType: "contract", ContractID: &contract, Topics: []string{"mutated"}}}
+		}
+		return resp, nil
+	}}
+
+	harness := NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 200})
+	results, _, err := harness.Fuzz(&FuzzerInput{EnvelopeXdr: "AAAA", Timestamp: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, harness.Corpus.Len(), "only the base and the first input reaching the event are kept")
+	newCount := 0
+	for _, r := range results {
+		if r.NewFeatures > 0 {
+			newCount++
+		}
+	}
+	assert.Equal(t, 1, newCount)
+}
+
+func TestFuzzingHarness_DeduplicatesCrashes(t *testing.T) {
+	dir := t.TempDir()
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		return trapResponse("transfer", "checked_sub", "main"), nil
+	}}
+
+	corpus, err := OpenFuzzCorpus(dir)
+	require.NoError(t, err)
+	harness := NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 25})
+	harness.Corpus = corpus
+
+	results, crashes, err := harness.Fuzz(&FuzzerInput{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	require.Len(t, crashes, 1)
+	assert.Equal(t, "crash", results[0].Status)
+	assert.NotEmpty(t, results[0].CrashSignature)
+
+	records := corpus.Crashes()
+	require.Len(t, records, 1)
+	assert.Equal(t, 25, records[0].Hits)
+	assert.Contains(t, records[0].Description, "checked_sub")
+
+	_, err = os.Stat(corpus.CrashPath(records[0].Signature))
+	assert.NoError(t, err)
+}
+
+func TestFuzzingHarness_MinimizesCrashes(t *testing.T) {
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		if _, ok := req.LedgerEntries["bad"]; ok && req.MockArgs != nil && len(*req.MockArgs) > 0 {
+			return trapResponse("overflow"), nil
+		}
+		return &SimulationResponse{Status: "success"}, nil
+	}}
+	harness := NewFuzzingHarness(runner, FuzzingConfig{})
+	harness.Corpus = NewFuzzCorpus()
+
+	input := FuzzerInput{
+		EnvelopeXdr:   "AAAA",
+		LedgerEntries: map[string]string{"a": "00", "bad": "01", "c": "02"},
+		Args:          []string{"aabbccddeeff0011", "ff"},
+	}
+	result, resp := harness.runFuzzerInput(context.Background(), &input)
+	require.Equal(t, "crash", result.Status)
+
+	rec, isNew, err := harness.recordCrash(context.Background(), input, result, resp)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.True(t, rec.Minimized)
+	assert.Equal(t, map[string]string{"bad": "01"}, rec.Input.LedgerEntries)
+	assert.Equal(t, []string{"aa"}, rec.Input.Args)
+}
+
+func TestCrashSignature(t *testing.T) {
+	a, descA := CrashSignature(FuzzingResult{Status: "crash"}, trapResponse("f1", "f2", "f3", "caller_a"))
+	b, _ := CrashSignature(FuzzingResult{Status: "crash"}, trapResponse("f1", "f2", "f3", "caller_b"))
+	c, _ := CrashSignature(FuzzingResult{Status: "crash"}, trapResponse("f1", "other"))
+
+	assert.Equal(t, a, b, "frames beyond the signature depth are ignored")
+	assert.NotEqual(t, a, c)
+	assert.Equal(t, "trap UnreachableCodeReached <- f1 <- f2 <- f3", descA)
+
+	t1, _ := CrashSignature(FuzzingResult{Status: "oom", ErrorMessage: "limit 1048576 exceeded"}, nil)
+	t2, _ := CrashSignature(FuzzingResult{Status: "oom", ErrorMessage: "limit 2097152 exceeded"}, nil)
+	assert.Equal(t, t1, t2)
+}
+
+func TestErrorCategory(t *testing.T) {
+	assert.Equal(t, "HostError: Error(Contract, #N)", errorCategory("HostError: Error(Contract, #12)\nbacktrace"))
+	assert.Equal(t,
+		errorCategory("missing entry 3f2a9b8c7d6e5f4a3b2c1d0e"),
+		errorCategory("missing entry 00112233445566778899aabb"))
+}
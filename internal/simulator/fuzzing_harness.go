@@ -4,18 +4,26 @@
 package simulator
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/dotandev/hintents/internal/gasmodel"
 )
 
-// FuzzerInput represents a single fuzz test input
+// FuzzerInput represents a single fuzz test input. EnvelopeXdr is base64,
+// as sent to the simulator.
 type FuzzerInput struct {
-	EnvelopeXdr   string
-	LedgerEntries map[string]string
-	Timestamp     int64
-	Args          []string
-	Seed          uint64
+	EnvelopeXdr   string            `json:"envelope_xdr"`
+	LedgerEntries map[string]string `json:"ledger_entries,omitempty"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	Args          []string          `json:"args,omitempty"`
+	Seed          uint64            `json:"seed,omitempty"`
 }
 
 // FuzzingConfig contains configuration for fuzzing operations
@@ -25,15 +33,25 @@ type FuzzingConfig struct {
 	TimeoutMs        uint64
 	EnableCoverage   bool
 	TargetContractID string
+	// MaxMinimizeRuns bounds the executions spent shrinking each new crash.
+	MaxMinimizeRuns int
+	// GasModel, when set, is applied to every fuzzed simulation.
+	GasModel *gasmodel.GasModel
+	// SlowThresholdMs marks a run that completes but takes longer than this
+	// as "slow". It must stay below TimeoutMs, where runs are killed and
+	// reported as "timeout"; it defaults to half of TimeoutMs.
+	SlowThresholdMs uint64
 }
 
 // FuzzingResult represents the outcome of a fuzz test
 type FuzzingResult struct {
 	Seed            uint64
-	Status          string // "pass", "crash", "slow", "error"
+	Status          string // "pass", "crash", "slow", "timeout", "oom", "error"
 	ErrorMessage    string
 	ExecutionTimeMs uint64
 	CodeCoverage    uint32
+	NewFeatures     int    // behaviours this input reached for the first time
+	CrashSignature  string // set for crashes, timeouts and out-of-memory failures
 }
 
 // FuzzingHarness manages fuzzing operations for XDR inputs
@@ -42,6 +60,13 @@ type FuzzingHarness struct {
 	Config         FuzzingConfig
 	Results        []FuzzingResult
 	CrashingInputs []FuzzerInput
+	// Corpus guides mutation and collects unique crashes. A persistent corpus
+	// from OpenFuzzCorpus makes the campaign resumable; if nil, an in-memory
+	// corpus is created on the first run.
+	Corpus *FuzzCorpus
+	// ArgMutator, when set, mutates the envelope's InvokeContract arguments
+	// as typed ScVals instead of leaving the envelope untouched.
+	ArgMutator *ScValMutator
 }
 
 // NewFuzzingHarness creates a new fuzzing harness for contract testing
@@ -52,9 +77,15 @@ func NewFuzzingHarness(runner RunnerInterface, config FuzzingConfig) *FuzzingHar
 	if config.TimeoutMs == 0 {
 		config.TimeoutMs = 5000 // 5 second default timeout
 	}
+	if config.SlowThresholdMs == 0 || config.SlowThresholdMs >= config.TimeoutMs {
+		config.SlowThresholdMs = config.TimeoutMs / 2
+	}
 	if config.MaxInputSize == 0 {
 		config.MaxInputSize = 256 * 1024 // 256KB default
 	}
+	if config.MaxMinimizeRuns == 0 {
+		config.MaxMinimizeRuns = 64
+	}
 
 	return &FuzzingHarness{
 		Runner:         runner,
@@ -66,40 +97,187 @@ func NewFuzzingHarness(runner RunnerInterface, config FuzzingConfig) *FuzzingHar
 
 // Fuzz runs fuzzing against randomly generated XDR inputs
 func (h *FuzzingHarness) Fuzz(baseInput *FuzzerInput) ([]FuzzingResult, []FuzzerInput, error) {
+	return h.FuzzContext(context.Background(), baseInput)
+}
+
+// FuzzContext runs a coverage-guided campaign. Each iteration mutates an input
+// picked from the corpus, favouring inputs that reached new behaviour; inputs
+// that reach new behaviour themselves are added back. Crashes are deduplicated
+// by CrashSignature and minimized, and only new unique crashes are returned.
+// Cancelling ctx stops the campaign early with the corpus saved.
+func (h *FuzzingHarness) FuzzContext(ctx context.Context, baseInput *FuzzerInput) ([]FuzzingResult, []FuzzerInput, error) {
 	if baseInput == nil {
 		return nil, nil, fmt.Errorf("base input required for fuzzing")
 	}
+	if h.Corpus == nil {
+		h.Corpus = NewFuzzCorpus()
+	}
+	corpus := h.Corpus
 
 	results := make([]FuzzingResult, 0)
 	crashingInputs := make([]FuzzerInput, 0)
 
+	if corpus.Len() == 0 {
+		result, resp := h.runFuzzerInput(ctx, baseInput)
+		if _, err := corpus.add(*baseInput, corpus.observe(coverageFeatures(result, resp))); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rng := rand.New(rand.NewSource(int64(corpus.nextSeed)))
+
 	for i := uint64(0); i < h.Config.MaxIterations; i++ {
-		// Generate mutated input based on base
-		mutated := h.mutateInput(baseInput, i)
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Generate mutated input based on a corpus entry
+		parent := corpus.pick(rng)
+		mutated := h.mutateInput(&parent.Input, corpus.nextIterationSeed())
 
 		// Run simulation with timeout
-		result := h.testFuzzerInput(&mutated)
+		result, resp := h.runFuzzerInput(ctx, &mutated)
+		if ctx.Err() != nil {
+			break
+		}
 
-		results = append(results, result)
+		features := coverageFeatures(result, resp)
+		result.CodeCoverage = uint32(len(features))
+		if n := corpus.observe(features); n > 0 {
+			result.NewFeatures = n
+			if _, err := corpus.add(mutated, n); err != nil {
+				return results, crashingInputs, err
+			}
+		}
 
-		// Track crashing inputs
-		if result.Status == "crash" {
-			crashingInputs = append(crashingInputs, mutated)
+		// Track unique crashing inputs
+		if isCrashStatus(result.Status) {
+			rec, isNew, err := h.recordCrash(ctx, mutated, result, resp)
+			if err != nil {
+				return results, crashingInputs, err
+			}
+			result.CrashSignature = rec.Signature
+			if isNew {
+				crashingInputs = append(crashingInputs, rec.Input)
+			}
 		}
 
+		results = append(results, result)
+
 		// Report progress every 100 iterations
 		if (i+1)%100 == 0 {
-			fmt.Printf("Fuzz progress: %d/%d iterations\n", i+1, h.Config.MaxIterations)
+			fmt.Printf("Fuzz progress: %d/%d iterations, corpus %d, features %d, unique crashes %d\n",
+				i+1, h.Config.MaxIterations, corpus.Len(), corpus.FeatureCount(), len(corpus.crashes))
+			if err := corpus.Save(); err != nil {
+				return results, crashingInputs, err
+			}
 		}
 	}
 
 	h.Results = results
 	h.CrashingInputs = crashingInputs
 
-	return results, crashingInputs, nil
+	return results, crashingInputs, corpus.Save()
+}
+
+// isCrashStatus reports whether a result is a finding worth keeping.
+func isCrashStatus(status string) bool {
+	switch status {
+	case "crash", "timeout", "oom":
+		return true
+	}
+	return false
+}
+
+// recordCrash deduplicates a crash and, the first time its signature is seen,
+// minimizes the input before storing it.
+func (h *FuzzingHarness) recordCrash(ctx context.Context, input FuzzerInput, result FuzzingResult, resp *SimulationResponse) (*CrashRecord, bool, error) {
+	signature, desc := CrashSignature(result, resp)
+	if existing, ok := h.Corpus.crashes[signature]; ok {
+		return h.Corpus.recordCrash(existing)
+	}
+
+	rec := &CrashRecord{
+		Signature:    signature,
+		Description:  desc,
+		Status:       result.Status,
+		ErrorMessage: result.ErrorMessage,
+		Input:        input,
+	}
+	// Timeouts are not minimized: every attempt would cost a full timeout.
+	if result.Status != "timeout" {
+		rec.Input = h.minimize(ctx, input, signature)
+		rec.Minimized = true
+	}
+	return h.Corpus.recordCrash(rec)
+}
+
+// minimize shrinks a crashing input while it still produces the same crash
+// signature: it drops ledger entries and arguments, then truncates arguments.
+func (h *FuzzingHarness) minimize(ctx context.Context, input FuzzerInput, signature string) FuzzerInput {
+	budget := h.Config.MaxMinimizeRuns
+	reproduces := func(candidate FuzzerInput) bool {
+		if budget <= 0 || ctx.Err() != nil {
+			return false
+		}
+		budget--
+		result, resp := h.runFuzzerInput(ctx, &candidate)
+		if !isCrashStatus(result.Status) {
+			return false
+		}
+		sig, _ := CrashSignature(result, resp)
+		return sig == signature
+	}
+
+	best := cloneFuzzerInput(input)
+
+	keys := make([]string, 0, len(best.LedgerEntries))
+	for k := range best.LedgerEntries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		candidate := cloneFuzzerInput(best)
+		delete(candidate.LedgerEntries, k)
+		if reproduces(candidate) {
+			best = candidate
+		}
+	}
+
+	for i := len(best.Args) - 1; i >= 0; i-- {
+		candidate := cloneFuzzerInput(best)
+		candidate.Args = append(candidate.Args[:i], candidate.Args[i+1:]...)
+		if reproduces(candidate) {
+			best = candidate
+		}
+	}
+
+	for i := range best.Args {
+		for len(best.Args[i]) >= 4 {
+			candidate := cloneFuzzerInput(best)
+			half := len(best.Args[i]) / 4 * 2
+			candidate.Args[i] = best.Args[i][:half]
+			if !reproduces(candidate) {
+				break
+			}
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+func cloneFuzzerInput(in FuzzerInput) FuzzerInput {
+	out := in
+	out.LedgerEntries = make(map[string]string, len(in.LedgerEntries))
+	for k, v := range in.LedgerEntries {
+		out.LedgerEntries[k] = v
+	}
+	out.Args = append([]string{}, in.Args...)
+	return out
 }
 
-// FuzzXDR fuzzes a specific XDR input against the harness
+// FuzzXDR fuzzes a specific hex-encoded XDR input against the harness
 func (h *FuzzingHarness) FuzzXDR(envelopeXdr string) (*FuzzingResult, error) {
 	if envelopeXdr == "" {
 		return nil, fmt.Errorf("envelope XDR cannot be empty")
@@ -116,7 +294,7 @@ func (h *FuzzingHarness) FuzzXDR(envelopeXdr string) (*FuzzingResult, error) {
 	}
 
 	input := &FuzzerInput{
-		EnvelopeXdr:   envelopeXdr,
+		EnvelopeXdr:   base64.StdEncoding.EncodeToString(data),
 		LedgerEntries: make(map[string]string),
 		Timestamp:     0,
 	}
@@ -147,6 +325,21 @@ func (h *FuzzingHarness) mutateInput(base *FuzzerInput, seed uint64) FuzzerInput
 		}
 	}
 
+	// Mutate contract call arguments structurally when possible, otherwise
+	// mutate the envelope bytes themselves.
+	if base.EnvelopeXdr != "" {
+		structured := false
+		if h.ArgMutator != nil {
+			if env, err := h.ArgMutator.MutateEnvelope(base.EnvelopeXdr, rng); err == nil {
+				mutated.EnvelopeXdr = env
+				structured = true
+			}
+		}
+		if !structured && rng.Float64() < 0.5 {
+			mutated.EnvelopeXdr = h.mutateEnvelope(base.EnvelopeXdr, rng)
+		}
+	}
+
 	// Optionally mutate timestamp
 	if rng.Float64() < 0.2 {
 		mutated.Timestamp = base.Timestamp + int64(rng.Intn(1000000))
@@ -164,6 +357,32 @@ func (h *FuzzingHarness) mutateInput(base *FuzzerInput, seed uint64) FuzzerInput
 	return mutated
 }
 
+// interestingWords are boundary values written over XDR-aligned words, where
+// they land on lengths, discriminants and integer fields.
+var interestingWords = []uint32{0, 1, 0x7fffffff, 0x80000000, 0xffffffff}
+
+// mutateEnvelope mutates a base64 envelope at the byte level, either by
+// overwriting a 4-byte aligned word with a boundary value or by flipping a
+// few bits. An envelope that does not decode is returned unchanged.
+func (h *FuzzingHarness) mutateEnvelope(envelope string, rng *rand.Rand) string {
+	data, err := base64.StdEncoding.DecodeString(envelope)
+	if err != nil || len(data) == 0 {
+		return envelope
+	}
+
+	if len(data) >= 4 && rng.Float64() < 0.5 {
+		pos := rng.Intn(len(data)/4) * 4
+		binary.BigEndian.PutUint32(data[pos:], interestingWords[rng.Intn(len(interestingWords))])
+	} else {
+		flipCount := 1 + rng.Intn(3)
+		for i := 0; i < flipCount; i++ {
+			data[rng.Intn(len(data))] ^= uint8(1 << rng.Intn(8))
+		}
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
+
 // mutateHexString applies random bit flips to a hex-encoded string
 func (h *FuzzingHarness) mutateHexString(hexStr string, rng *rand.Rand) string {
 	data, err := hex.DecodeString(hexStr)
@@ -184,6 +403,14 @@ func (h *FuzzingHarness) mutateHexString(hexStr string, rng *rand.Rand) string {
 
 // testFuzzerInput runs a single fuzz input through the simulator
 func (h *FuzzingHarness) testFuzzerInput(input *FuzzerInput) FuzzingResult {
+	result, _ := h.runFuzzerInput(context.Background(), input)
+	return result
+}
+
+// runFuzzerInput runs input and returns the classified result together with
+// the simulator response, which is nil when the simulator itself failed.
+// A contract trap that produced a WASM stack trace is reported as a crash.
+func (h *FuzzingHarness) runFuzzerInput(parent context.Context, input *FuzzerInput) (FuzzingResult, *SimulationResponse) {
 	result := FuzzingResult{
 		Seed:   input.Seed,
 		Status: "pass",
@@ -195,29 +422,45 @@ func (h *FuzzingHarness) testFuzzerInput(input *FuzzerInput) FuzzingResult {
 		LedgerEntries: input.LedgerEntries,
 		Timestamp:     input.Timestamp,
 		MockArgs:      &input.Args,
+		GasModel:      h.Config.GasModel,
 	}
 
 	// Run simulation with timeout context
-	simResp, err := h.Runner.Run(simReq)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(h.Config.TimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	simResp, err := h.Runner.RunContext(ctx, simReq)
+	result.ExecutionTimeMs = uint64(time.Since(start).Milliseconds())
 	if err != nil {
-		result.Status = "crash"
+		switch ClassifyRunError(err) {
+		case FailureTimeout:
+			result.Status = "timeout"
+		case FailureOOM:
+			result.Status = "oom"
+		default:
+			result.Status = "crash"
+		}
 		result.ErrorMessage = fmt.Sprintf("execution error: %v", err)
-		return result
+		return result, nil
 	}
 
 	// Analyze response
 	if simResp.Status == "error" {
 		result.Status = "error"
 		result.ErrorMessage = simResp.Error
+		if simResp.StackTrace != nil {
+			result.Status = "crash"
+		}
 	}
 
-	// Check for slow execution (would need actual timing in production)
-	if result.ExecutionTimeMs > h.Config.TimeoutMs {
+	// Check for slow execution; runs past TimeoutMs were reported above.
+	if result.Status == "pass" && result.ExecutionTimeMs > h.Config.SlowThresholdMs {
 		result.Status = "slow"
-		result.ErrorMessage = fmt.Sprintf("execution time exceeded %dms", h.Config.TimeoutMs)
+		result.ErrorMessage = fmt.Sprintf("execution time exceeded %dms", h.Config.SlowThresholdMs)
 	}
 
-	return result
+	return result, simResp
 }
 
 // CorpusCoverage returns statistics about code coverage across all fuzz runs
@@ -4,10 +4,16 @@
 package simulator
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
+	"github.com/dotandev/hintents/internal/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,6 +50,7 @@ func TestFuzzingConfig(t *testing.T) {
 
 		assert.Equal(t, 1000, int(harness.Config.MaxIterations))
 		assert.Equal(t, uint64(5000), harness.Config.TimeoutMs)
+		assert.Equal(t, uint64(2500), harness.Config.SlowThresholdMs)
 		assert.Equal(t, 256*1024, harness.Config.MaxInputSize)
 	})
 
@@ -148,6 +155,9 @@ func TestFuzzingHarness_FuzzXDR(t *testing.T) {
 	t.Run("accepts valid XDR", func(t *testing.T) {
 		mockRunner := &MockRunner{
 			RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+				raw, err := base64.StdEncoding.DecodeString(req.EnvelopeXdr)
+				require.NoError(t, err, "the simulator expects a base64 envelope")
+				assert.Equal(t, []byte("test data"), raw)
 				return &SimulationResponse{Status: "success"}, nil
 			},
 		}
@@ -195,6 +205,28 @@ func TestFuzzingHarness_MutateInput(t *testing.T) {
 	})
 }
 
+func TestFuzzingHarness_MutatesEnvelopeWithoutStructuredMode(t *testing.T) {
+	base := make([]byte, 64)
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		raw, err := base64.StdEncoding.DecodeString(req.EnvelopeXdr)
+		require.NoError(t, err)
+		// Each changed word reaches a different "code path".
+		resp := &SimulationResponse{Status: "success"}
+		for i := 0; i+4 <= len(raw); i += 4 {
+			if !bytes.Equal(raw[i:i+4], base[i:i+4]) {
+				resp.DiagnosticEvents = append(resp.DiagnosticEvents, DiagnosticEvent{EventType: "contract", Topics: []string{fmt.Sprintf("word%d", i/4)}})
+			}
+		}
+		return resp, nil
+	}}
+
+	harness := NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 200})
+	_, _, err := harness.Fuzz(&FuzzerInput{EnvelopeXdr: base64.StdEncoding.EncodeToString(base)})
+	require.NoError(t, err)
+
+	assert.Greater(t, harness.Corpus.Len(), 2, "mutated envelopes reaching new behaviour are kept")
+}
+
 func TestFuzzingHarness_MutateHexString(t *testing.T) {
 	mockRunner := &MockRunner{}
 	harness := NewFuzzingHarness(mockRunner, FuzzingConfig{})
@@ -263,6 +295,38 @@ func TestFuzzingHarness_TestFuzzerInput(t *testing.T) {
 		assert.Equal(t, "error", result.Status)
 		assert.NotEmpty(t, result.ErrorMessage)
 	})
+
+	t.Run("reports slow runs below the timeout", func(t *testing.T) {
+		harness := NewFuzzingHarness(&MockRunner{
+			RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+				time.Sleep(30 * time.Millisecond)
+				return &SimulationResponse{Status: "success"}, nil
+			},
+		}, FuzzingConfig{TimeoutMs: 1000, SlowThresholdMs: 10})
+
+		result := harness.testFuzzerInput(&FuzzerInput{EnvelopeXdr: "xyz"})
+		assert.Equal(t, "slow", result.Status)
+		assert.Contains(t, result.ErrorMessage, "10ms")
+	})
+
+	t.Run("classifies resource limit failures", func(t *testing.T) {
+		cases := map[string]error{
+			"timeout": errors.WrapSimTimeout(time.Second),
+			"oom":     errors.WrapSimOutOfMemory(1<<20, ""),
+			"crash":   errors.WrapSimKilled(context.Canceled, ""),
+		}
+		for want, runErr := range cases {
+			runErr := runErr
+			harness := NewFuzzingHarness(&MockRunner{
+				RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+					return nil, runErr
+				},
+			}, FuzzingConfig{})
+
+			result := harness.testFuzzerInput(&FuzzerInput{EnvelopeXdr: "xyz"})
+			assert.Equal(t, want, result.Status)
+		}
+	})
 }
 
 func TestFuzzingHarness_CorpusCoverage(t *testing.T) {
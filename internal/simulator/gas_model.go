@@ -0,0 +1,150 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"strings"
+
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// gasCostAliases maps normalised gas model cost names to the host cost types
+// erst-sim can recalibrate. Names are matched case-insensitively with '_'
+// and '-' ignored, so "ComputeSha256Hash", "compute_sha256_hash" and
+// "sha256" all select the same cost.
+var gasCostAliases = map[string]string{
+	"sha256":               "sha256",
+	"computesha256hash":    "sha256",
+	"keccak256":            "keccak256",
+	"computekeccak256hash": "keccak256",
+	"ed25519":              "ed25519",
+	"verifyed25519sig":     "ed25519",
+}
+
+func normaliseCostName(name string) string {
+	n := strings.ToLower(name)
+	n = strings.NewReplacer("_", "", "-", "", " ", "").Replace(n)
+	return strings.TrimPrefix(n, "contractcosttype")
+}
+
+// CalibrationFromGasModel derives host function calibration from the CPU and
+// host costs of a gas model: Const is the fixed cost and Linear the per-byte
+// cost. Cost types the model does not define keep the value from base.
+func CalibrationFromGasModel(base *ResourceCalibration, model *gasmodel.GasModel) *ResourceCalibration {
+	calib := ResourceCalibration{}
+	if base != nil {
+		calib = *base
+	}
+	if model == nil {
+		return &calib
+	}
+
+	for _, costs := range [][]gasmodel.GasCost{model.CPUCosts, model.HostCosts} {
+		for _, c := range costs {
+			switch gasCostAliases[normaliseCostName(c.Name)] {
+			case "sha256":
+				calib.SHA256Fixed, calib.SHA256PerByte = c.Const, c.Linear
+			case "keccak256":
+				calib.Keccak256Fixed, calib.Keccak256PerByte = c.Const, c.Linear
+			case "ed25519":
+				calib.Ed25519Fixed = c.Const
+			}
+		}
+	}
+	return &calib
+}
+
+// contractCostType resolves a gas model cost name to the host cost type it
+// sets, matching either the aliases above or a ContractCostType name such as
+// "WasmInsnExec" or "wasm_insn_exec".
+func contractCostType(name string) (xdr.ContractCostType, bool) {
+	n := normaliseCostName(name)
+	switch gasCostAliases[n] {
+	case "sha256":
+		return xdr.ContractCostTypeComputeSha256Hash, true
+	case "keccak256":
+		return xdr.ContractCostTypeComputeKeccak256Hash, true
+	case "ed25519":
+		return xdr.ContractCostTypeVerifyEd25519Sig, true
+	}
+	var ct xdr.ContractCostType
+	for v := int32(0); ct.ValidEnum(v); v++ {
+		if normaliseCostName(xdr.ContractCostType(v).String()) == n {
+			return xdr.ContractCostType(v), true
+		}
+	}
+	return 0, false
+}
+
+// IgnoredGasModelEntries lists the parts of a gas model a replay does not
+// apply: ledger costs, CPU and host costs that name no host cost type, and
+// the transaction size and ledger entry limits.
+func IgnoredGasModelEntries(model *gasmodel.GasModel) []string {
+	if model == nil {
+		return nil
+	}
+	var ignored []string
+	for _, cat := range []struct {
+		name  string
+		costs []gasmodel.GasCost
+	}{{"cpu_costs", model.CPUCosts}, {"host_costs", model.HostCosts}} {
+		for _, c := range cat.costs {
+			if _, ok := contractCostType(c.Name); !ok {
+				ignored = append(ignored, cat.name+"."+c.Name)
+			}
+		}
+	}
+	for _, c := range model.LedgerCosts {
+		ignored = append(ignored, "ledger_costs."+c.Name)
+	}
+	if model.ResourceLimits.MaxTxnSize > 0 {
+		ignored = append(ignored, "resource_limits.max_txn_size")
+	}
+	if model.ResourceLimits.MaxLedgerEntries > 0 {
+		ignored = append(ignored, "resource_limits.max_ledger_entries")
+	}
+	return ignored
+}
+
+// applyGasModel overrides the budget limits, cost models and calibration set
+// so far with the values of the request's gas model. It runs after
+// applyNetworkSettings: a model the user supplied explicitly wins over both
+// the protocol defaults and the network's settings. CPU and host costs that
+// name a host cost type replace its CPU cost model in erst-sim; see
+// IgnoredGasModelEntries for what is left out.
+func applyGasModel(req *SimulationRequest) {
+	model := req.GasModel
+	if model == nil {
+		return
+	}
+
+	budget := BudgetLimits{}
+	if req.BudgetLimits != nil {
+		budget = *req.BudgetLimits
+	}
+	if rl := model.ResourceLimits; rl.MaxCPUInsns > 0 {
+		budget.CPUInstructions = rl.MaxCPUInsns
+	}
+	if rl := model.ResourceLimits; rl.MaxMemory > 0 {
+		budget.MemoryBytes = rl.MaxMemory
+	}
+	req.BudgetLimits = &budget
+
+	var params []CostParam
+	for _, costs := range [][]gasmodel.GasCost{model.CPUCosts, model.HostCosts} {
+		for _, c := range costs {
+			if ct, ok := contractCostType(c.Name); ok {
+				params = append(params, CostParam{
+					CostType:   strings.TrimPrefix(ct.String(), "ContractCostType"),
+					ConstTerm:  c.Const,
+					LinearTerm: c.Linear,
+				})
+			}
+		}
+	}
+	req.CostParams = params
+
+	req.ResourceCalibration = CalibrationFromGasModel(req.ResourceCalibration, model)
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGasModel() *gasmodel.GasModel {
+	return &gasmodel.GasModel{
+		Version:   "1.0",
+		NetworkID: "private-1",
+		CPUCosts: []gasmodel.GasCost{
+			{Name: "ComputeSha256Hash", Linear: 30, Const: 4000},
+			{Name: "wasm_insn_exec", Linear: 4, Const: 0},
+		},
+		HostCosts: []gasmodel.GasCost{
+			{Name: "verify_ed25519_sig", Const: 400000},
+		},
+		ResourceLimits: gasmodel.ResourceLimits{MaxCPUInsns: 50_000_000, MaxLedgerEntries: 20},
+	}
+}
+
+func TestCalibrationFromGasModel(t *testing.T) {
+	base := &ResourceCalibration{SHA256Fixed: 1, SHA256PerByte: 1, Keccak256Fixed: 9, Keccak256PerByte: 9, Ed25519Fixed: 1}
+
+	calib := CalibrationFromGasModel(base, testGasModel())
+	assert.Equal(t, uint64(4000), calib.SHA256Fixed)
+	assert.Equal(t, uint64(30), calib.SHA256PerByte)
+	assert.Equal(t, uint64(400000), calib.Ed25519Fixed)
+	assert.Equal(t, uint64(9), calib.Keccak256Fixed, "costs missing from the model keep the base value")
+	assert.Equal(t, uint64(1), base.SHA256Fixed, "base must not be modified")
+}
+
+func TestPrepareRequest_GasModelOverridesNetworkSettings(t *testing.T) {
+	r := &Runner{}
+	req := &SimulationRequest{EnvelopeXdr: "AAAA", NetworkSettings: testNetworkSettings(), GasModel: testGasModel()}
+
+	_, err := r.prepareRequest(req)
+	require.NoError(t, err)
+
+	require.NotNil(t, req.BudgetLimits)
+	assert.Equal(t, uint64(50_000_000), req.BudgetLimits.CPUInstructions, "the gas model wins over the network")
+	assert.Equal(t, uint64(41943040), req.BudgetLimits.MemoryBytes, "limits the model leaves unset keep the network value")
+	assert.NotContains(t, req.CustomAuthCfg, "gas_model")
+
+	assert.ElementsMatch(t, []CostParam{
+		{CostType: "ComputeSha256Hash", ConstTerm: 4000, LinearTerm: 30},
+		{CostType: "WasmInsnExec", ConstTerm: 0, LinearTerm: 4},
+		{CostType: "VerifyEd25519Sig", ConstTerm: 400000},
+	}, req.CostParams)
+
+	assert.Equal(t, uint64(4000), req.ResourceCalibration.SHA256Fixed)
+}
+
+func TestIgnoredGasModelEntries(t *testing.T) {
+	model := testGasModel()
+	model.HostCosts = append(model.HostCosts, gasmodel.GasCost{Name: "host_invoke", Linear: 400})
+	model.LedgerCosts = []gasmodel.GasCost{{Name: "ledger_read", Linear: 250}}
+
+	assert.Equal(t, []string{
+		"host_costs.host_invoke",
+		"ledger_costs.ledger_read",
+		"resource_limits.max_ledger_entries",
+	}, IgnoredGasModelEntries(model))
+	assert.Nil(t, IgnoredGasModelEntries(nil))
+}
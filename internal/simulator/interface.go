@@ -3,9 +3,13 @@
 
 package simulator
 
+import "context"
+
 // RunnerInterface defines the contract for simulator execution
 type RunnerInterface interface {
 	Run(req *SimulationRequest) (*SimulationResponse, error)
+	// RunContext is like Run but stops the simulation when ctx is done.
+	RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error)
 }
 
 // NewRunnerInterface creates a RunnerInterface implementation
@@ -4,6 +4,7 @@
 package simulator
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,3 +59,7 @@ func (m *mockRunnerForTest) Run(req *SimulationRequest) (*SimulationResponse, er
 		Events: []string{"mock-event"},
 	}, nil
 }
+
+func (m *mockRunnerForTest) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	return m.Run(req)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// DefaultBaseReserve is the base reserve, in stroops, assumed when the
+// original ledger header is not available. DefaultBaseFee serves the same
+// purpose for the base fee.
+const DefaultBaseReserve = 5_000_000
+
+// Sources of a pinned ledger field, reported in ReplayField.Source.
+const (
+	SourceLedgerHeader = "ledger header"
+	SourceTransaction  = "transaction"
+	SourceNetwork      = "network config"
+	SourceOverride     = "override"
+	SourceDefault      = "default"
+)
+
+// ReplayField describes one ledger value a contract can observe through
+// env.ledger() and where the replay took it from.
+type ReplayField struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Source  string `json:"source"`
+	Guessed bool   `json:"guessed"`
+}
+
+// ReplayFidelity summarises how faithfully a replay reproduces the ledger
+// context of the original execution.
+type ReplayFidelity struct {
+	Fields []ReplayField `json:"fields"`
+}
+
+// Guessed returns the names of the fields that had to be guessed.
+func (f *ReplayFidelity) Guessed() []string {
+	var names []string
+	for _, field := range f.Fields {
+		if field.Guessed {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+// Exact reports whether every field was pinned from the original ledger or
+// set explicitly by the caller.
+func (f *ReplayFidelity) Exact() bool {
+	return len(f.Guessed()) == 0
+}
+
+func (f *ReplayFidelity) add(name, value, source string, guessed bool) {
+	f.Fields = append(f.Fields, ReplayField{Name: name, Value: value, Source: source, Guessed: guessed})
+}
+
+// PinLedgerContext fills the ledger fields of req from header, the header of
+// the ledger the transaction was applied in, so env.ledger() returns what it
+// returned on chain. Timestamp and ProtocolVersion already set on req are
+// caller overrides and are kept. header may be nil when it could not be
+// fetched; the missing fields then get defaults and are reported as guessed.
+func PinLedgerContext(req *SimulationRequest, header *rpc.LedgerHeaderResponse, passphrase string) *ReplayFidelity {
+	fidelity := &ReplayFidelity{}
+
+	switch {
+	case header != nil && header.Sequence > 0:
+		req.LedgerSequence = header.Sequence
+		fidelity.add("sequence", strconv.FormatUint(uint64(req.LedgerSequence), 10), SourceLedgerHeader, false)
+	case req.LedgerSequence > 0:
+		fidelity.add("sequence", strconv.FormatUint(uint64(req.LedgerSequence), 10), SourceTransaction, false)
+	default:
+		fidelity.add("sequence", "unknown", SourceDefault, true)
+	}
+
+	switch {
+	case req.Timestamp != 0:
+		fidelity.add("close time", formatCloseTime(req.Timestamp), SourceOverride, false)
+	case header != nil && !header.CloseTime.IsZero():
+		req.Timestamp = header.CloseTime.Unix()
+		fidelity.add("close time", formatCloseTime(req.Timestamp), SourceLedgerHeader, false)
+	default:
+		fidelity.add("close time", "simulation time", SourceDefault, true)
+	}
+
+	if header != nil && header.BaseFee > 0 {
+		req.BaseFee = uint32(header.BaseFee)
+		fidelity.add("base fee", fmt.Sprintf("%d stroops", req.BaseFee), SourceLedgerHeader, false)
+	} else {
+		req.BaseFee = DefaultBaseFee
+		fidelity.add("base fee", fmt.Sprintf("%d stroops", req.BaseFee), SourceDefault, true)
+	}
+
+	if header != nil && header.BaseReserve > 0 {
+		req.BaseReserve = uint32(header.BaseReserve)
+		fidelity.add("base reserve", fmt.Sprintf("%d stroops", req.BaseReserve), SourceLedgerHeader, false)
+	} else {
+		req.BaseReserve = DefaultBaseReserve
+		fidelity.add("base reserve", fmt.Sprintf("%d stroops", req.BaseReserve), SourceDefault, true)
+	}
+
+	switch {
+	case req.ProtocolVersion != nil:
+		fidelity.add("protocol version", strconv.FormatUint(uint64(*req.ProtocolVersion), 10), SourceOverride, false)
+	case header != nil && header.ProtocolVersion > 0 && Validate(header.ProtocolVersion) == nil:
+		v := header.ProtocolVersion
+		req.ProtocolVersion = &v
+		fidelity.add("protocol version", strconv.FormatUint(uint64(v), 10), SourceLedgerHeader, false)
+	case header != nil && header.ProtocolVersion > 0:
+		// The ledger ran a protocol this erst does not know; the default is
+		// the closest it can get.
+		fidelity.add("protocol version",
+			fmt.Sprintf("%d (ledger ran %d)", GetOrDefault(nil).Version, header.ProtocolVersion), SourceDefault, true)
+	default:
+		fidelity.add("protocol version", strconv.FormatUint(uint64(GetOrDefault(nil).Version), 10), SourceDefault, true)
+	}
+
+	if passphrase != "" {
+		req.NetworkPassphrase = passphrase
+		fidelity.add("network passphrase", passphrase, SourceNetwork, false)
+	} else {
+		fidelity.add("network passphrase", "unknown", SourceDefault, true)
+	}
+
+	return fidelity
+}
+
+func formatCloseTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinLedgerContext_FromHeader(t *testing.T) {
+	closeTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	header := &rpc.LedgerHeaderResponse{
+		Sequence:        5000,
+		CloseTime:       closeTime,
+		ProtocolVersion: GetOrDefault(nil).Version,
+		BaseFee:         200,
+		BaseReserve:     10_000_000,
+	}
+	req := &SimulationRequest{LedgerSequence: 5000}
+
+	fidelity := PinLedgerContext(req, header, "Test SDF Network ; September 2015")
+
+	assert.True(t, fidelity.Exact(), "guessed: %v", fidelity.Guessed())
+	assert.Equal(t, closeTime.Unix(), req.Timestamp)
+	assert.Equal(t, uint32(200), req.BaseFee)
+	assert.Equal(t, uint32(10_000_000), req.BaseReserve)
+	require.NotNil(t, req.ProtocolVersion)
+	assert.Equal(t, header.ProtocolVersion, *req.ProtocolVersion)
+	assert.Equal(t, "Test SDF Network ; September 2015", req.NetworkPassphrase)
+}
+
+func TestPinLedgerContext_KeepsOverrides(t *testing.T) {
+	override := GetOrDefault(nil).Version
+	req := &SimulationRequest{Timestamp: 1700000000, ProtocolVersion: &override}
+	header := &rpc.LedgerHeaderResponse{Sequence: 10, CloseTime: time.Unix(1600000000, 0), ProtocolVersion: 1}
+
+	fidelity := PinLedgerContext(req, header, "passphrase")
+
+	assert.Equal(t, int64(1700000000), req.Timestamp)
+	assert.Equal(t, override, *req.ProtocolVersion)
+	for _, f := range fidelity.Fields {
+		if f.Name == "close time" || f.Name == "protocol version" {
+			assert.Equal(t, SourceOverride, f.Source)
+		}
+	}
+}
+
+func TestPinLedgerContext_WithoutHeader(t *testing.T) {
+	req := &SimulationRequest{LedgerSequence: 42}
+
+	fidelity := PinLedgerContext(req, nil, "")
+
+	assert.False(t, fidelity.Exact())
+	assert.Equal(t, []string{"close time", "base fee", "base reserve", "protocol version", "network passphrase"}, fidelity.Guessed())
+	assert.Equal(t, uint32(42), req.LedgerSequence)
+	assert.Equal(t, uint32(DefaultBaseFee), req.BaseFee)
+	assert.Equal(t, uint32(DefaultBaseReserve), req.BaseReserve)
+	assert.Nil(t, req.ProtocolVersion)
+	assert.Zero(t, req.Timestamp)
+}
+
+func TestPinLedgerContext_UnknownProtocol(t *testing.T) {
+	req := &SimulationRequest{}
+	header := &rpc.LedgerHeaderResponse{Sequence: 1, CloseTime: time.Unix(1, 0), ProtocolVersion: 999, BaseFee: 100, BaseReserve: 1}
+
+	fidelity := PinLedgerContext(req, header, "passphrase")
+
+	assert.Nil(t, req.ProtocolVersion, "an unknown protocol is not pinned")
+	assert.Equal(t, []string{"protocol version"}, fidelity.Guessed())
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// LedgerSource provides the transactions and state a ledger replay needs.
+// *rpc.Client implements it.
+type LedgerSource interface {
+	GetLedgerHeader(ctx context.Context, sequence uint32) (*rpc.LedgerHeaderResponse, error)
+	GetLedgerTransactions(ctx context.Context, sequence uint32) ([]*rpc.TransactionResponse, error)
+	GetLedgerEntries(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// LedgerReplayResult is the outcome of replaying one transaction of a ledger.
+type LedgerReplayResult struct {
+	Index           int    `json:"index"`
+	TransactionHash string `json:"tx_hash"`
+	Status          string `json:"status"` // "match", "diverged", "skipped", "error"
+	OnChainSuccess  bool   `json:"on_chain_success"`
+	LocalStatus     string `json:"local_status,omitempty"`
+	Detail          string `json:"detail,omitempty"`
+	// ThreadedEntries counts ledger entries whose value came from an earlier
+	// transaction in the same ledger rather than from the network.
+	ThreadedEntries int `json:"threaded_entries"`
+}
+
+// LedgerReplayReport summarises a full-ledger replay.
+type LedgerReplayReport struct {
+	Sequence        uint32               `json:"sequence"`
+	Matched         int                  `json:"matched"`
+	Diverged        int                  `json:"diverged"`
+	Skipped         int                  `json:"skipped"`
+	Errors          int                  `json:"errors"`
+	FirstDivergence int                  `json:"first_divergence"` // index into Results, -1 if none
+	Results         []LedgerReplayResult `json:"results"`
+}
+
+// LedgerReplayer replays every transaction of a ledger in apply order.
+//
+// erst-sim does not report the entries a transaction writes, so the state
+// handed from one transaction to the next is taken from the on-chain result
+// meta of the earlier transactions. A divergence therefore pinpoints the
+// transaction whose local execution disagrees with the network given the
+// exact state it saw, instead of cascading into every later transaction.
+type LedgerReplayer struct {
+	Runner          RunnerInterface
+	Source          LedgerSource
+	ProtocolVersion *uint32
+	// StopOnDivergence ends the replay at the first diverging transaction.
+	StopOnDivergence bool
+}
+
+// NewLedgerReplayer creates a replayer reading ledger data from source.
+func NewLedgerReplayer(runner RunnerInterface, source LedgerSource) *LedgerReplayer {
+	return &LedgerReplayer{Runner: runner, Source: source}
+}
+
+// ledgerState is the ledger entry state threaded between transactions.
+type ledgerState struct {
+	entries map[string]string
+	removed map[string]bool
+}
+
+func (s *ledgerState) apply(changes *rpc.LedgerEntryChanges) {
+	for k, v := range changes.After {
+		s.entries[k] = v
+		delete(s.removed, k)
+	}
+	for _, k := range changes.Removed {
+		delete(s.entries, k)
+		s.removed[k] = true
+	}
+}
+
+// Replay fetches every transaction in the ledger and simulates them in order.
+func (r *LedgerReplayer) Replay(ctx context.Context, sequence uint32) (*LedgerReplayReport, error) {
+	txs, err := r.Source.GetLedgerTransactions(ctx, sequence)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, errors.WrapValidationError(fmt.Sprintf("ledger %d has no transactions", sequence))
+	}
+
+	var timestamp int64
+	protocolVersion := r.ProtocolVersion
+	if header, err := r.Source.GetLedgerHeader(ctx, sequence); err != nil {
+		logger.Logger.Warn("Ledger header unavailable, replaying without close time", "sequence", sequence, "error", err)
+	} else {
+		timestamp = header.CloseTime.Unix()
+		if protocolVersion == nil && header.ProtocolVersion > 0 {
+			v := header.ProtocolVersion
+			protocolVersion = &v
+		}
+	}
+
+	report := &LedgerReplayReport{Sequence: sequence, FirstDivergence: -1}
+	state := &ledgerState{entries: make(map[string]string), removed: make(map[string]bool)}
+
+	for i, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		changes, metaErr := rpc.ExtractLedgerEntryChanges(tx.ResultMetaXdr)
+		result := r.replayTransaction(ctx, i, tx, changes, metaErr, state, timestamp, protocolVersion)
+		report.Results = append(report.Results, result)
+
+		switch result.Status {
+		case "match":
+			report.Matched++
+		case "diverged":
+			report.Diverged++
+			if report.FirstDivergence < 0 {
+				report.FirstDivergence = len(report.Results) - 1
+			}
+		case "skipped":
+			report.Skipped++
+		default:
+			report.Errors++
+		}
+
+		if metaErr == nil {
+			state.apply(changes)
+		}
+		if result.Status == "diverged" && r.StopOnDivergence {
+			break
+		}
+	}
+	return report, nil
+}
+
+func (r *LedgerReplayer) replayTransaction(
+	ctx context.Context,
+	index int,
+	tx *rpc.TransactionResponse,
+	changes *rpc.LedgerEntryChanges,
+	metaErr error,
+	state *ledgerState,
+	timestamp int64,
+	protocolVersion *uint32,
+) LedgerReplayResult {
+	result := LedgerReplayResult{
+		Index:           index,
+		TransactionHash: tx.Hash,
+		Status:          "error",
+		OnChainSuccess:  tx.Successful,
+	}
+
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &env); err != nil {
+		result.Detail = fmt.Sprintf("failed to decode envelope: %v", err)
+		return result
+	}
+	footprint, ok := sorobanFootprintKeys(env)
+	if !ok {
+		result.Status = "skipped"
+		result.Detail = "not a Soroban transaction"
+		return result
+	}
+	if metaErr != nil {
+		result.Detail = fmt.Sprintf("failed to decode result meta: %v", metaErr)
+		return result
+	}
+
+	entries, threaded, err := r.assembleEntries(ctx, footprint, changes, state)
+	if err != nil {
+		result.Detail = fmt.Sprintf("failed to fetch ledger entries: %v", err)
+		return result
+	}
+	result.ThreadedEntries = threaded
+
+	resp, err := r.Runner.RunContext(ctx, &SimulationRequest{
+		EnvelopeXdr:     tx.EnvelopeXdr,
+		ResultMetaXdr:   tx.ResultMetaXdr,
+		LedgerEntries:   entries,
+		Timestamp:       timestamp,
+		LedgerSequence:  tx.LedgerSequence,
+		ProtocolVersion: protocolVersion,
+	})
+	if err != nil {
+		result.Detail = fmt.Sprintf("simulation failed (%s): %v", ClassifyRunError(err), err)
+		return result
+	}
+
+	result.LocalStatus = resp.Status
+	localSuccess := resp.Status == "success"
+	switch {
+	case localSuccess == tx.Successful:
+		result.Status = "match"
+	case tx.Successful:
+		result.Status = "diverged"
+		result.Detail = "succeeded on-chain but failed locally: " + resp.Error
+	default:
+		result.Status = "diverged"
+		result.Detail = "failed on-chain but succeeded locally"
+	}
+	return result
+}
+
+// assembleEntries builds the ledger entries for one transaction. The state
+// recorded in its own meta wins, then entries written earlier in the ledger,
+// then the network's current state for anything still missing. Keys removed
+// earlier in the ledger are left out. It returns how many entries came from
+// the threaded state.
+func (r *LedgerReplayer) assembleEntries(
+	ctx context.Context,
+	footprint []string,
+	changes *rpc.LedgerEntryChanges,
+	state *ledgerState,
+) (map[string]string, int, error) {
+	entries := make(map[string]string, len(footprint)+len(changes.Before))
+	threaded := 0
+	var missing []string
+
+	for _, key := range footprint {
+		if _, ok := changes.Before[key]; ok {
+			continue
+		}
+		if v, ok := state.entries[key]; ok {
+			entries[key] = v
+			threaded++
+			continue
+		}
+		if state.removed[key] {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	for k, v := range changes.Before {
+		entries[k] = v
+	}
+
+	if len(missing) > 0 {
+		fetched, err := r.Source.GetLedgerEntries(ctx, missing)
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, v := range fetched {
+			entries[k] = v
+		}
+	}
+	return entries, threaded, nil
+}
+
+// sorobanTransactionData returns the Soroban resources declared by the
+// transaction. ok is false for classic transactions.
+func sorobanTransactionData(env xdr.TransactionEnvelope) (xdr.SorobanTransactionData, bool) {
+	var ext xdr.TransactionExt
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		if env.V1 == nil {
+			return xdr.SorobanTransactionData{}, false
+		}
+		ext = env.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		if env.FeeBump == nil || env.FeeBump.Tx.InnerTx.V1 == nil {
+			return xdr.SorobanTransactionData{}, false
+		}
+		ext = env.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	default:
+		return xdr.SorobanTransactionData{}, false
+	}
+	return ext.GetSorobanData()
+}
+
+// sorobanFootprintKeys returns the base64 ledger keys in the transaction's
+// Soroban footprint. ok is false for classic transactions.
+func sorobanFootprintKeys(env xdr.TransactionEnvelope) ([]string, bool) {
+	data, ok := sorobanTransactionData(env)
+	if !ok {
+		return nil, false
+	}
+
+	fp := data.Resources.Footprint
+	keys := make([]string, 0, len(fp.ReadOnly)+len(fp.ReadWrite))
+	for _, set := range [][]xdr.LedgerKey{fp.ReadOnly, fp.ReadWrite} {
+		for _, k := range set {
+			encoded, err := rpc.EncodeLedgerKey(k)
+			if err != nil {
+				continue
+			}
+			keys = append(keys, encoded)
+		}
+	}
+	return keys, true
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedgerSource struct {
+	txs     []*rpc.TransactionResponse
+	entries map[string]string
+	fetched [][]string
+}
+
+func (f *fakeLedgerSource) GetLedgerHeader(ctx context.Context, sequence uint32) (*rpc.LedgerHeaderResponse, error) {
+	return &rpc.LedgerHeaderResponse{Sequence: sequence, CloseTime: time.Unix(1700000000, 0), ProtocolVersion: 22}, nil
+}
+
+func (f *fakeLedgerSource) GetLedgerTransactions(ctx context.Context, sequence uint32) ([]*rpc.TransactionResponse, error) {
+	return f.txs, nil
+}
+
+func (f *fakeLedgerSource) GetLedgerEntries(ctx context.Context, keys []string) (map[string]string, error) {
+	f.fetched = append(f.fetched, keys)
+	out := make(map[string]string)
+	for _, k := range keys {
+		if v, ok := f.entries[k]; ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func replayAccount(t *testing.T, address string, balance int64) (xdr.LedgerEntry, xdr.LedgerKey, string, string) {
+	t.Helper()
+	entry := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(address), Balance: xdr.Int64(balance)},
+	}}
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+	keyXDR, err := rpc.EncodeLedgerKey(key)
+	require.NoError(t, err)
+	entryXDR, err := rpc.EncodeLedgerEntry(entry)
+	require.NoError(t, err)
+	return entry, key, keyXDR, entryXDR
+}
+
+func replayMeta(t *testing.T, changes ...xdr.LedgerEntryChange) string {
+	t.Helper()
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		}}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{Changes: changes}},
+		}},
+	}
+	raw, err := meta.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// replayEnvelope builds a transaction envelope; a nil footprint produces a
+// classic transaction.
+func replayEnvelope(t *testing.T, readOnly []xdr.LedgerKey) string {
+	t.Helper()
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+		Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{},
+		}}},
+	}
+	if readOnly != nil {
+		tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: readOnly}},
+		}}
+	}
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}}
+	raw, err := env.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestLedgerReplayer_ThreadsStateBetweenTransactions(t *testing.T) {
+	x0, xKey, xKeyXDR, _ := replayAccount(t, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 100)
+	x1, _, _, x1XDR := replayAccount(t, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 50)
+	y, yKey, yKeyXDR, yXDR := replayAccount(t, "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", 7)
+
+	source := &fakeLedgerSource{
+		entries: map[string]string{xKeyXDR: "current-x", yKeyXDR: yXDR},
+		txs: []*rpc.TransactionResponse{
+			{Hash: "classic", Successful: true, EnvelopeXdr: replayEnvelope(t, nil), ResultMetaXdr: replayMeta(t,
+				xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &x0},
+				xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &x1},
+			)},
+			{Hash: "reads-x", Successful: true, EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{xKey, yKey}), ResultMetaXdr: replayMeta(t)},
+			{Hash: "removes-y", Successful: false, EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{yKey}), ResultMetaXdr: replayMeta(t,
+				xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &y},
+				xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &yKey},
+			)},
+			{Hash: "reads-y", Successful: true, EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{yKey}), ResultMetaXdr: replayMeta(t)},
+		},
+	}
+
+	var requests []*SimulationRequest
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		requests = append(requests, req)
+		return &SimulationResponse{Status: "success"}, nil
+	}}
+
+	report, err := NewLedgerReplayer(runner, source).Replay(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 4)
+	require.Len(t, requests, 3)
+
+	assert.Equal(t, "skipped", report.Results[0].Status)
+
+	assert.Equal(t, "match", report.Results[1].Status)
+	assert.Equal(t, 1, report.Results[1].ThreadedEntries)
+	assert.Equal(t, x1XDR, requests[0].LedgerEntries[xKeyXDR], "value written earlier in the ledger wins over the network")
+	assert.Equal(t, yXDR, requests[0].LedgerEntries[yKeyXDR])
+	assert.Equal(t, int64(1700000000), requests[0].Timestamp)
+	require.NotNil(t, requests[0].ProtocolVersion)
+	assert.Equal(t, uint32(22), *requests[0].ProtocolVersion)
+
+	assert.Equal(t, "diverged", report.Results[2].Status)
+	assert.Equal(t, 2, report.FirstDivergence)
+
+	assert.Equal(t, "match", report.Results[3].Status)
+	assert.NotContains(t, requests[2].LedgerEntries, yKeyXDR, "entries removed earlier in the ledger stay absent")
+	assert.Equal(t, [][]string{{yKeyXDR}}, source.fetched, "only entries never seen in the ledger are fetched")
+
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.Diverged)
+	assert.Equal(t, 1, report.Skipped)
+}
+
+func TestLedgerReplayer_StopOnDivergence(t *testing.T) {
+	source := &fakeLedgerSource{txs: []*rpc.TransactionResponse{
+		{Hash: "a", Successful: true, EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{}), ResultMetaXdr: replayMeta(t)},
+		{Hash: "b", Successful: true, EnvelopeXdr: replayEnvelope(t, []xdr.LedgerKey{}), ResultMetaXdr: replayMeta(t)},
+	}}
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		return &SimulationResponse{Status: "error", Error: "HostError: Error(Contract, #3)"}, nil
+	}}
+
+	replayer := NewLedgerReplayer(runner, source)
+	replayer.StopOnDivergence = true
+	report, err := replayer.Replay(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Contains(t, report.Results[0].Detail, "Error(Contract, #3)")
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package simulator
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// setMemoryLimit caps the address space of the process pid at limit bytes
+// using prlimit(2). It must be called before the request is written so the
+// limit is in place before the contract starts executing.
+func setMemoryLimit(pid int, limit uint64) error {
+	rlim := syscall.Rlimit{Cur: limit, Max: limit}
+	_, _, errno := syscall.RawSyscall6(
+		syscall.SYS_PRLIMIT64,
+		uintptr(pid),
+		uintptr(syscall.RLIMIT_AS),
+		uintptr(unsafe.Pointer(&rlim)),
+		0, 0, 0,
+	)
+	if errno != 0 {
+		return os.NewSyscallError("prlimit", errno)
+	}
+	return nil
+}
+
+// killedBySignal reports whether the process was terminated by SIGKILL, which
+// is how both context cancellation and the kernel OOM killer end a child.
+func killedBySignal(state *os.ProcessState) bool {
+	if state == nil {
+		return false
+	}
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled() && ws.Signal() == syscall.SIGKILL
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package simulator
+
+import (
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// setMemoryLimit is only implemented on Linux; elsewhere the memory limit is
+// ignored and callers fall back to the wall-clock timeout.
+func setMemoryLimit(pid int, limit uint64) error {
+	return errors.New("memory limits are only supported on linux")
+}
+
+// killedBySignal reports whether the process did not exit normally.
+func killedBySignal(state *os.ProcessState) bool {
+	return state != nil && state.ExitCode() == -1
+}
@@ -3,6 +3,8 @@
 
 package simulator
 
+import "context"
+
 type MockRunner struct {
 	RunFunc func(req *SimulationRequest) (*SimulationResponse, error)
 }
@@ -14,6 +16,14 @@ func (m *MockRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	return &SimulationResponse{Status: "success"}, nil
 }
 
+// RunContext honours cancellation of ctx before delegating to Run.
+func (m *MockRunner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Run(req)
+}
+
 func NewMockRunner(fn func(req *SimulationRequest) (*SimulationResponse, error)) *MockRunner {
 	return &MockRunner{RunFunc: fn}
 }
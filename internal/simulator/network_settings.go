@@ -0,0 +1,71 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// CalibrationFromNetwork derives host function calibration from the network's
+// CPU cost model. ResourceCalibration only covers the SHA-256, Keccak-256 and
+// Ed25519 costs; the rest of the cost model is not carried over. Cost types the
+// network does not define keep the value from base, which is usually the
+// protocol's static calibration.
+func CalibrationFromNetwork(base *ResourceCalibration, settings *rpc.NetworkSettings) *ResourceCalibration {
+	calib := ResourceCalibration{}
+	if base != nil {
+		calib = *base
+	}
+	if settings == nil {
+		return &calib
+	}
+
+	params := settings.CPUCostParams
+	if p, ok := rpc.LookupCostParam(params, xdr.ContractCostTypeComputeSha256Hash); ok {
+		calib.SHA256Fixed = nonNegative(p.ConstTerm)
+		calib.SHA256PerByte = nonNegative(p.LinearTerm >> rpc.CostModelLinearScaleBits)
+	}
+	if p, ok := rpc.LookupCostParam(params, xdr.ContractCostTypeComputeKeccak256Hash); ok {
+		calib.Keccak256Fixed = nonNegative(p.ConstTerm)
+		calib.Keccak256PerByte = nonNegative(p.LinearTerm >> rpc.CostModelLinearScaleBits)
+	}
+	if p, ok := rpc.LookupCostParam(params, xdr.ContractCostTypeVerifyEd25519Sig); ok {
+		calib.Ed25519Fixed = nonNegative(p.ConstTerm)
+	}
+	return &calib
+}
+
+// applyNetworkSettings overrides the budget limits and calibration that
+// applyProtocolConfig set with the on-chain values carried by req. Only the
+// transaction's instruction and memory limits are enforced by the host budget;
+// ledger read, write and size limits are not. Fee rates are not sent to
+// erst-sim; they only feed EstimateFees.
+func applyNetworkSettings(req *SimulationRequest) {
+	ns := req.NetworkSettings
+	if ns == nil {
+		return
+	}
+
+	limits := BudgetLimits{}
+	if req.BudgetLimits != nil {
+		limits = *req.BudgetLimits
+	}
+	if ns.TxMaxInstructions > 0 {
+		limits.CPUInstructions = uint64(ns.TxMaxInstructions)
+	}
+	if ns.TxMemoryLimit > 0 {
+		limits.MemoryBytes = uint64(ns.TxMemoryLimit)
+	}
+	req.BudgetLimits = &limits
+
+	req.ResourceCalibration = CalibrationFromNetwork(req.ResourceCalibration, ns)
+}
+
+func nonNegative(v int64) uint64 {
+	if v < 0 {
+		return 0
+	}
+	return uint64(v)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNetworkSettings() *rpc.NetworkSettings {
+	cpu := make([]rpc.CostParam, int(xdr.ContractCostTypeVerifyEd25519Sig)+1)
+	cpu[xdr.ContractCostTypeComputeSha256Hash] = rpc.CostParam{ConstTerm: 3738, LinearTerm: 7012}
+	cpu[xdr.ContractCostTypeVerifyEd25519Sig] = rpc.CostParam{ConstTerm: 377524, LinearTerm: 4068}
+
+	return &rpc.NetworkSettings{
+		LedgerSequence:                  1000,
+		ContractMaxSizeBytes:            131072,
+		ContractDataEntrySizeBytes:      131072,
+		TxMaxInstructions:               100000000,
+		TxMemoryLimit:                   41943040,
+		FeeRatePerInstructionsIncrement: 25,
+		CPUCostParams:                   cpu,
+	}
+}
+
+func TestCalibrationFromNetwork(t *testing.T) {
+	base := &ResourceCalibration{SHA256Fixed: 1, SHA256PerByte: 1, Keccak256Fixed: 9, Keccak256PerByte: 9, Ed25519Fixed: 1}
+
+	calib := CalibrationFromNetwork(base, testNetworkSettings())
+	assert.Equal(t, uint64(3738), calib.SHA256Fixed)
+	assert.Equal(t, uint64(7012>>rpc.CostModelLinearScaleBits), calib.SHA256PerByte)
+	assert.Equal(t, uint64(377524), calib.Ed25519Fixed)
+	assert.Equal(t, uint64(9), calib.Keccak256Fixed, "cost types missing on-chain keep the base value")
+	assert.Equal(t, uint64(1), base.SHA256Fixed, "base must not be modified")
+}
+
+func TestPrepareRequest_AppliesNetworkSettings(t *testing.T) {
+	r := &Runner{}
+	req := &SimulationRequest{EnvelopeXdr: "AAAA", NetworkSettings: testNetworkSettings()}
+
+	_, err := r.prepareRequest(req)
+	require.NoError(t, err)
+
+	require.NotNil(t, req.BudgetLimits)
+	assert.Equal(t, uint64(100000000), req.BudgetLimits.CPUInstructions)
+	assert.Equal(t, uint64(41943040), req.BudgetLimits.MemoryBytes)
+
+	assert.NotContains(t, req.CustomAuthCfg, "network_fees", "fee rates are only used by EstimateFees")
+
+	require.NotNil(t, req.ResourceCalibration)
+	assert.Equal(t, uint64(3738), req.ResourceCalibration.SHA256Fixed)
+
+	// The protocol table's shared calibration must be left untouched.
+	proto := GetOrDefault(nil)
+	static := proto.Features["resource_calibration"].(*ResourceCalibration)
+	assert.NotSame(t, static, req.ResourceCalibration)
+}
+
+func TestPrepareRequest_WithoutNetworkSettingsUsesProtocol(t *testing.T) {
+	r := &Runner{}
+	req := &SimulationRequest{EnvelopeXdr: "AAAA"}
+
+	proto, err := r.prepareRequest(req)
+	require.NoError(t, err)
+
+	limits := req.CustomAuthCfg["protocol_limits"].(map[string]interface{})
+	assert.Equal(t, proto.Features["max_instruction_limit"], limits["max_instruction_limit"])
+	assert.NotContains(t, req.CustomAuthCfg, "network_fees")
+}
@@ -0,0 +1,473 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// Pool wire protocol
+//
+// Workers are started as `erst-sim --serve` and exchange length-prefixed JSON
+// frames over stdin/stdout: a 4-byte big-endian payload length followed by
+// the payload. Each request frame is a poolMessage and is answered by exactly
+// one poolReply carrying the same ID. Closing stdin asks the worker to exit.
+const (
+	serveFlag = "--serve"
+
+	msgSimulate = "simulate"
+	msgPing     = "ping"
+
+	replyResult = "result"
+	replyPong   = "pong"
+
+	// maxFrameSize bounds a single frame so a corrupted length prefix cannot
+	// make us allocate arbitrary amounts of memory.
+	maxFrameSize = 256 << 20
+
+	// stderrTailSize is how much of a worker's stderr is kept for crash reports.
+	stderrTailSize = 8 << 10
+
+	workerStopTimeout = 2 * time.Second
+)
+
+// DefaultPoolHealthCheckInterval is how long a worker may sit idle before it
+// is pinged again prior to being handed a new request.
+const DefaultPoolHealthCheckInterval = 30 * time.Second
+
+var errPoolClosed = errors.New("simulator pool is closed")
+
+// PoolConfig configures a Pool.
+type PoolConfig struct {
+	// Size is the number of erst-sim processes kept alive. Defaults to the
+	// number of CPUs.
+	Size int
+	// HealthCheckInterval is the idle time after which a worker is pinged
+	// before reuse. Defaults to DefaultPoolHealthCheckInterval.
+	HealthCheckInterval time.Duration
+	// MockTime overrides Timestamp in every request when non-zero.
+	MockTime int64
+	// MemoryLimit, when set, replaces the workers' address-space limit in
+	// bytes; zero disables it.
+	MemoryLimit *uint64
+}
+
+type poolMessage struct {
+	ID      uint64             `json:"id"`
+	Type    string             `json:"type"`
+	Request *SimulationRequest `json:"request,omitempty"`
+}
+
+type poolReply struct {
+	ID       uint64              `json:"id"`
+	Type     string              `json:"type"`
+	Response *SimulationResponse `json:"response,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// Pool runs simulations on a fixed set of long-lived erst-sim processes so
+// that callers issuing many requests (fuzzing, regression runs) do not pay
+// process spawn and host initialisation cost on every call. Crashed workers
+// are respawned transparently and idle workers are health-checked before
+// reuse.
+type Pool struct {
+	runner              *Runner
+	args                []string
+	size                int
+	healthCheckInterval time.Duration
+
+	idle      chan *poolWorker
+	closed    chan struct{}
+	closeOnce sync.Once
+	nextID    atomic.Uint64
+}
+
+// Compile-time check to ensure Pool implements RunnerInterface
+var _ RunnerInterface = (*Pool)(nil)
+
+// NewPool resolves the erst-sim binary the same way NewRunner does and starts
+// cfg.Size workers in serve mode. Binaries whose capabilities do not list
+// FeatureServe are refused. The caller must Close the pool when done.
+func NewPool(simPathOverride string, debug bool, cfg PoolConfig) (*Pool, error) {
+	r, err := NewRunner(simPathOverride, debug)
+	if err != nil {
+		return nil, err
+	}
+	if r.Capabilities != nil && !r.Capabilities.Has(FeatureServe) {
+		return nil, errors.WrapSimIncompatible(fmt.Sprintf("%s has no --serve mode; rebuild erst-sim or run without a pool", r.BinaryPath))
+	}
+	r.MockTime = cfg.MockTime
+	if cfg.MemoryLimit != nil {
+		r.MemoryLimit = *cfg.MemoryLimit
+	}
+	return newPool(r, cfg, []string{serveFlag})
+}
+
+func newPool(r *Runner, cfg PoolConfig, args []string) (*Pool, error) {
+	if cfg.Size <= 0 {
+		cfg.Size = runtime.NumCPU()
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = DefaultPoolHealthCheckInterval
+	}
+
+	p := &Pool{
+		runner:              r,
+		args:                args,
+		size:                cfg.Size,
+		healthCheckInterval: cfg.HealthCheckInterval,
+		idle:                make(chan *poolWorker, cfg.Size),
+		closed:              make(chan struct{}),
+	}
+
+	workers := make([]*poolWorker, 0, cfg.Size)
+	for i := 0; i < cfg.Size; i++ {
+		w := &poolWorker{id: i, pool: p}
+		if err := w.start(); err != nil {
+			for _, started := range workers {
+				started.stop()
+			}
+			return nil, errors.WrapSimCrash(err, "")
+		}
+		workers = append(workers, w)
+	}
+	for _, w := range workers {
+		p.idle <- w
+	}
+
+	if r.Debug {
+		logger.Logger.Debug("Simulator pool started", "size", cfg.Size, "path", r.BinaryPath)
+	}
+
+	return p, nil
+}
+
+// Size returns the number of workers managed by the pool.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Run sends req to an idle worker and waits for its response.
+func (p *Pool) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return p.RunContext(context.Background(), req)
+}
+
+// RunContext sends req to an idle worker and waits for its response. If ctx
+// is done or the runner's Timeout elapses first, the worker is killed and
+// respawned. A worker that dies mid-request is likewise respawned and the
+// failure is classified the same way Runner.RunContext does.
+func (p *Pool) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	proto, err := p.runner.prepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel, timeout := withRunTimeout(ctx, p.runner.Timeout)
+	defer cancel()
+
+	w, err := p.acquire(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	type callResult struct {
+		reply *poolReply
+		err   error
+	}
+	done := make(chan callResult, 1)
+	go func() {
+		reply, err := w.call(poolMessage{ID: p.nextID.Add(1), Type: msgSimulate, Request: req})
+		done <- callResult{reply, err}
+	}()
+
+	var res callResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		w.kill()
+		res = <-done
+	}
+
+	if res.err != nil {
+		w.stop()
+		stderr := w.stderr.String()
+		failure := p.runner.classifyFailure(ctx, timeout, res.err, w.state, stderr)
+		logger.Logger.Error("Simulator worker failed", "worker", w.id, "error", failure, "stderr", stderr)
+		if err := w.start(); err != nil {
+			logger.Logger.Warn("Failed to respawn simulator worker", "worker", w.id, "error", err)
+		}
+		p.release(w)
+		return nil, failure
+	}
+	p.release(w)
+
+	reply := res.reply
+	if reply.Error != "" {
+		return nil, errors.WrapSimulationLogicError(reply.Error)
+	}
+	if reply.Response == nil {
+		return nil, errors.WrapSimulationLogicError("simulator worker returned an empty response")
+	}
+
+	reply.Response.ProtocolVersion = &proto.Version
+	return reply.Response, nil
+}
+
+// Close stops every worker. It waits for in-flight requests to finish.
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		for i := 0; i < p.size; i++ {
+			w := <-p.idle
+			w.stop()
+		}
+	})
+	return nil
+}
+
+// acquire hands out an idle, healthy worker, respawning it if necessary.
+// timeout is reported if ctx expires while waiting for one.
+func (p *Pool) acquire(ctx context.Context, timeout time.Duration) (*poolWorker, error) {
+	select {
+	case <-p.closed:
+		return nil, errPoolClosed
+	default:
+	}
+
+	var w *poolWorker
+	select {
+	case w = <-p.idle:
+	case <-p.closed:
+		return nil, errPoolClosed
+	case <-ctx.Done():
+		return nil, p.runner.classifyFailure(ctx, timeout, ctx.Err(), nil, "")
+	}
+
+	if !w.alive() {
+		logger.Logger.Warn("Respawning dead simulator worker", "worker", w.id)
+		if err := w.start(); err != nil {
+			p.release(w)
+			return nil, errors.WrapSimCrash(err, "")
+		}
+		return w, nil
+	}
+
+	if time.Since(w.lastUsed) >= p.healthCheckInterval {
+		if err := w.ping(p.nextID.Add(1)); err != nil {
+			logger.Logger.Warn("Simulator worker failed health check", "worker", w.id, "error", err)
+			if err := w.restart(); err != nil {
+				p.release(w)
+				return nil, errors.WrapSimCrash(err, "")
+			}
+		}
+	}
+
+	return w, nil
+}
+
+func (p *Pool) release(w *poolWorker) {
+	p.idle <- w
+}
+
+// poolWorker owns a single erst-sim process running in serve mode.
+type poolWorker struct {
+	id   int
+	pool *Pool
+
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stdout   *bufio.Reader
+	stderr   *tailBuffer
+	exited   chan struct{}
+	state    *os.ProcessState // exit state of the last stopped process
+	lastUsed time.Time
+}
+
+func (w *poolWorker) start() error {
+	cmd := exec.Command(w.pool.runner.BinaryPath, w.pool.args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr := &tailBuffer{max: stderrTailSize}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if limit := w.pool.runner.MemoryLimit; limit > 0 {
+		if err := setMemoryLimit(cmd.Process.Pid, limit); err != nil {
+			logger.Logger.Warn("Failed to apply simulator memory limit", "worker", w.id, "limit", limit, "error", err)
+		}
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	w.cmd = cmd
+	w.stdin = stdin
+	w.stdout = bufio.NewReader(stdout)
+	w.stderr = stderr
+	w.exited = exited
+	w.lastUsed = time.Now()
+
+	if w.pool.runner.Debug {
+		logger.Logger.Debug("Simulator worker started", "worker", w.id, "pid", cmd.Process.Pid)
+	}
+	return nil
+}
+
+func (w *poolWorker) alive() bool {
+	if w.cmd == nil {
+		return false
+	}
+	select {
+	case <-w.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (w *poolWorker) stop() {
+	if w.cmd == nil {
+		return
+	}
+	_ = w.stdin.Close()
+	select {
+	case <-w.exited:
+	case <-time.After(workerStopTimeout):
+		_ = w.cmd.Process.Kill()
+		<-w.exited
+	}
+	w.state = w.cmd.ProcessState
+	w.cmd = nil
+}
+
+// kill terminates the worker process immediately, unblocking any in-flight call.
+func (w *poolWorker) kill() {
+	if w.cmd != nil {
+		_ = w.cmd.Process.Kill()
+	}
+}
+
+func (w *poolWorker) restart() error {
+	w.stop()
+	return w.start()
+}
+
+func (w *poolWorker) ping(id uint64) error {
+	reply, err := w.call(poolMessage{ID: id, Type: msgPing})
+	if err != nil {
+		return err
+	}
+	if reply.Type != replyPong {
+		return fmt.Errorf("unexpected health check reply %q", reply.Type)
+	}
+	return nil
+}
+
+func (w *poolWorker) call(msg poolMessage) (*poolReply, error) {
+	if err := writeFrame(w.stdin, msg); err != nil {
+		return nil, err
+	}
+
+	var reply poolReply
+	if err := readFrame(w.stdout, &reply); err != nil {
+		return nil, err
+	}
+	if reply.ID != msg.ID {
+		return nil, fmt.Errorf("reply id %d does not match request id %d", reply.ID, msg.ID)
+	}
+
+	w.lastUsed = time.Now()
+	return &reply, nil
+}
+
+// -------------------- Framing --------------------
+
+func writeFrame(w io.Writer, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	if len(payload) > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes exceeds limit of %d bytes", len(payload), maxFrameSize)
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(payload)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader, v interface{}) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes exceeds limit of %d bytes", size, maxFrameSize)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errors.WrapUnmarshalFailed(err, string(payload))
+	}
+	return nil
+}
+
+// tailBuffer is an io.Writer that keeps only the last max bytes written.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.max; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPoolHelperProcess is not a real test. It is re-executed by the pool
+// tests as a stand-in for `erst-sim --serve`.
+func TestPoolHelperProcess(t *testing.T) {
+	if os.Getenv("ERST_POOL_HELPER") != "1" {
+		return
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		var msg poolMessage
+		if err := readFrame(in, &msg); err != nil {
+			os.Exit(0)
+		}
+
+		reply := poolReply{ID: msg.ID}
+		switch msg.Type {
+		case msgPing:
+			reply.Type = replyPong
+		case msgSimulate:
+			if msg.Request.EnvelopeXdr == "crash" {
+				fmt.Fprintln(os.Stderr, "helper: simulated crash")
+				os.Exit(3)
+			}
+			reply.Type = replyResult
+			reply.Response = &SimulationResponse{
+				Status: "success",
+				Logs:   []string{fmt.Sprintf("pid=%d", os.Getpid())},
+			}
+		default:
+			reply.Error = "unknown message type " + msg.Type
+		}
+
+		if err := writeFrame(os.Stdout, reply); err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+func newTestPool(t *testing.T, cfg PoolConfig) *Pool {
+	t.Helper()
+	t.Setenv("ERST_POOL_HELPER", "1")
+
+	runner := &Runner{BinaryPath: os.Args[0]}
+	pool, err := newPool(runner, cfg, []string{"-test.run=^TestPoolHelperProcess$", "--", serveFlag})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
+
+func TestPool_ReusesWorkerProcess(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 1})
+
+	first, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+	second, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "success", first.Status)
+	assert.Equal(t, first.Logs, second.Logs, "both requests should be served by the same process")
+	require.NotNil(t, first.ProtocolVersion)
+	assert.Equal(t, LatestVersion(), *first.ProtocolVersion)
+}
+
+func TestPool_RespawnsCrashedWorker(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 1})
+
+	before, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	_, err = pool.Run(&SimulationRequest{EnvelopeXdr: "crash"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimCrash))
+	assert.Contains(t, err.Error(), "simulated crash")
+
+	after, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Logs, after.Logs, "crashed worker should have been replaced")
+}
+
+func TestPool_HealthCheckBeforeReuse(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 1, HealthCheckInterval: time.Nanosecond})
+
+	for i := 0; i < 3; i++ {
+		resp, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+		require.NoError(t, err)
+		assert.Equal(t, "success", resp.Status)
+	}
+}
+
+func TestPool_ConcurrentRuns(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 4})
+	assert.Equal(t, 4, pool.Size())
+
+	const requests = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent Run returned error: %v", err)
+	}
+}
+
+func TestPool_RunAfterClose(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 1})
+	require.NoError(t, pool.Close())
+
+	_, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	assert.ErrorIs(t, err, errPoolClosed)
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	msg := poolMessage{ID: 7, Type: msgSimulate, Request: &SimulationRequest{EnvelopeXdr: "AAAA"}}
+	require.NoError(t, writeFrame(&buf, msg))
+
+	var decoded poolMessage
+	require.NoError(t, readFrame(&buf, &decoded))
+	assert.Equal(t, uint64(7), decoded.ID)
+	assert.Equal(t, "AAAA", decoded.Request.EnvelopeXdr)
+}
+
+func TestReadFrame_RejectsOversizedFrame(t *testing.T) {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], maxFrameSize+1)
+
+	var msg poolMessage
+	err := readFrame(bytes.NewReader(header[:]), &msg)
+	assert.Error(t, err)
+}
+
+func TestTailBuffer_KeepsLastBytes(t *testing.T) {
+	buf := &tailBuffer{max: 4}
+	_, _ = buf.Write([]byte("abcdef"))
+	assert.Equal(t, "cdef", buf.String())
+}
+
+func TestNewPool_RefusesBinaryWithoutServe(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sim := fakeSim(t, `echo '{"schema_version":1,"max_protocol":23,"features":["flamegraph"]}'`)
+
+	_, err := NewPool(sim.BinaryPath, false, PoolConfig{Size: 1})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimIncompatible))
+}
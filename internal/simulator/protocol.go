@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"sort"
+	"sync"
 
 	"github.com/dotandev/hintents/internal/errors"
 )
@@ -17,70 +18,24 @@ type Protocol struct {
 	Features map[string]interface{}
 }
 
-var protocols = map[uint32]*Protocol{
-	20: {
-		Version: 20,
-		Name:    "Soroban Protocol 20",
-		Features: map[string]interface{}{
-			"max_contract_size":      65536,
-			"max_contract_data_size": 1024000,
-			"max_instruction_limit":  100000000,
-			"supported_opcodes":      []string{"invoke_contract", "create_contract"},
-			"resource_calibration": &ResourceCalibration{
-				SHA256Fixed:      3738,
-				SHA256PerByte:    37,
-				Keccak256Fixed:   3766,
-				Keccak256PerByte: 63,
-				Ed25519Fixed:     377524,
-			},
-		},
-	},
-	21: {
-		Version: 21,
-		Name:    "Soroban Protocol 21",
-		Features: map[string]interface{}{
-			"max_contract_size":      65536,
-			"max_contract_data_size": 2048000,
-			"max_instruction_limit":  150000000,
-			"supported_opcodes":      []string{"invoke_contract", "create_contract", "extend_contract"},
-			"enhanced_metering":      true,
-			"resource_calibration": &ResourceCalibration{
-				SHA256Fixed:      3738,
-				SHA256PerByte:    37,
-				Keccak256Fixed:   3766,
-				Keccak256PerByte: 63,
-				Ed25519Fixed:     377524,
-			},
-		},
-	},
-	22: {
-		Version: 22,
-		Name:    "Soroban Protocol 22",
-		Features: map[string]interface{}{
-			"max_contract_size":      131072,
-			"max_contract_data_size": 4096000,
-			"max_instruction_limit":  200000000,
-			"supported_opcodes":      []string{"invoke_contract", "create_contract", "extend_contract", "upgrade_contract"},
-			"enhanced_metering":      true,
-			"optimized_storage":      true,
-			"resource_calibration": &ResourceCalibration{
-				SHA256Fixed:      3738,
-				SHA256PerByte:    37,
-				Keccak256Fixed:   3766,
-				Keccak256PerByte: 63,
-				Ed25519Fixed:     377524,
-			},
-		},
-	},
-}
-
-var defaultVersion uint32 = 22
+// protocols and defaultVersion are populated from the embedded protocol
+// registry (see protocol_registry.go) at package initialisation and may be
+// replaced by LoadRegistryOverride. registryMu guards both.
+var (
+	registryMu     sync.RWMutex
+	protocols      map[uint32]*Protocol
+	defaultVersion uint32
+)
 
 func LatestVersion() uint32 {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	return defaultVersion
 }
 
 func Get(version uint32) (*Protocol, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	if p, exists := protocols[version]; exists {
 		return p, nil
 	}
@@ -88,14 +43,14 @@ func Get(version uint32) (*Protocol, error) {
 }
 
 func GetOrDefault(version *uint32) *Protocol {
-	if version == nil || *version == 0 {
-		return protocols[defaultVersion]
-	}
-	p, _ := Get(*version)
-	if p == nil {
-		return protocols[defaultVersion]
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if version != nil && *version != 0 {
+		if p, exists := protocols[*version]; exists {
+			return p
+		}
 	}
-	return p
+	return protocols[defaultVersion]
 }
 
 func Feature(version uint32, key string) (interface{}, error) {
@@ -119,6 +74,8 @@ func FeatureOrDefault(version uint32, key string, defVal interface{}) interface{
 }
 
 func Validate(version uint32) error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	if _, ok := protocols[version]; !ok {
 		return errors.WrapProtocolUnsupported(version)
 	}
@@ -126,6 +83,8 @@ func Validate(version uint32) error {
 }
 
 func Supported() []uint32 {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	versions := make([]uint32, 0, len(protocols))
 	for v := range protocols {
 		versions = append(versions, v)
@@ -0,0 +1,267 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// RegistrySchemaVersion is the protocol registry file format understood by
+// this build. Files declaring a newer schema are rejected.
+const RegistrySchemaVersion = 1
+
+// UserRegistryFileName is looked up in ~/.erst; see UserRegistryPath.
+const UserRegistryFileName = "protocols.json"
+
+//go:embed protocols.json
+var embeddedRegistry []byte
+
+// registrySource describes where the active protocol table came from. It is
+// guarded by registryMu.
+var registrySource = "embedded"
+
+// registryFile is the on-disk format of a protocol registry.
+type registryFile struct {
+	SchemaVersion  int             `json:"schema_version"`
+	DefaultVersion uint32          `json:"default_version,omitempty"`
+	Protocols      []registryEntry `json:"protocols"`
+}
+
+type registryEntry struct {
+	Version  uint32                     `json:"version"`
+	Name     string                     `json:"name"`
+	Features map[string]json.RawMessage `json:"features"`
+}
+
+func init() {
+	table, def, err := parseRegistry(embeddedRegistry)
+	if err == nil && table[def] == nil {
+		err = fmt.Errorf("default_version %d is not defined", def)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("embedded protocol registry is invalid: %v", err))
+	}
+	protocols, defaultVersion = table, def
+}
+
+// UserRegistryPath returns ~/.erst/protocols.json if it exists. Commands pass
+// it to LoadRegistryOverride so new protocols can be simulated without a new
+// erst release.
+func UserRegistryPath() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	path := filepath.Join(home, ".erst", UserRegistryFileName)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// LoadRegistryOverride overlays the registry file at path on the active
+// protocol table. Protocols it defines replace built-in ones with the same
+// version; a default_version in the file becomes the new default.
+func LoadRegistryOverride(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.WrapConfigError("failed to read protocol registry", err)
+	}
+
+	table, def, err := parseRegistry(data)
+	if err != nil {
+		return err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	merged := make(map[uint32]*Protocol, len(protocols)+len(table))
+	for v, p := range protocols {
+		merged[v] = p
+	}
+	for v, p := range table {
+		merged[v] = p
+	}
+
+	if def == 0 {
+		def = defaultVersion
+	}
+	if _, ok := merged[def]; !ok {
+		return errors.WrapConfigError(fmt.Sprintf("default_version %d is not defined", def), nil)
+	}
+
+	protocols, defaultVersion = merged, def
+	registrySource = path
+	return nil
+}
+
+// RegistrySource reports whether the protocol table is the embedded default
+// or which override file was applied.
+func RegistrySource() string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registrySource
+}
+
+func parseRegistry(data []byte) (map[uint32]*Protocol, uint32, error) {
+	var file registryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, errors.WrapConfigError("failed to parse protocol registry", err)
+	}
+	if file.SchemaVersion == 0 || file.SchemaVersion > RegistrySchemaVersion {
+		return nil, 0, errors.WrapConfigError(fmt.Sprintf("unsupported protocol registry schema_version %d", file.SchemaVersion), nil)
+	}
+
+	table := make(map[uint32]*Protocol, len(file.Protocols))
+	for _, entry := range file.Protocols {
+		if entry.Version == 0 {
+			return nil, 0, errors.WrapConfigError("protocol entry is missing a version", nil)
+		}
+		if _, dup := table[entry.Version]; dup {
+			return nil, 0, errors.WrapConfigError(fmt.Sprintf("protocol %d is defined twice", entry.Version), nil)
+		}
+
+		features := make(map[string]interface{}, len(entry.Features))
+		for key, raw := range entry.Features {
+			val, err := decodeFeature(key, raw)
+			if err != nil {
+				return nil, 0, errors.WrapConfigError(fmt.Sprintf("protocol %d feature %q", entry.Version, key), err)
+			}
+			features[key] = val
+		}
+
+		name := entry.Name
+		if name == "" {
+			name = fmt.Sprintf("Soroban Protocol %d", entry.Version)
+		}
+		table[entry.Version] = &Protocol{Version: entry.Version, Name: name, Features: features}
+	}
+
+	return table, file.DefaultVersion, nil
+}
+
+// decodeFeature converts a raw feature value into the Go type the rest of the
+// simulator expects: int for integral numbers, []string for string lists and
+// *ResourceCalibration for resource_calibration.
+func decodeFeature(key string, raw json.RawMessage) (interface{}, error) {
+	if key == "resource_calibration" {
+		var calib ResourceCalibration
+		if err := json.Unmarshal(raw, &calib); err != nil {
+			return nil, err
+		}
+		return &calib, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return nil, err
+	}
+	return normalizeFeature(val), nil
+}
+
+func normalizeFeature(val interface{}) interface{} {
+	switch v := val.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		strs := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				for i := range v {
+					v[i] = normalizeFeature(v[i])
+				}
+				return v
+			}
+			strs = append(strs, s)
+		}
+		return strs
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = normalizeFeature(v[k])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// FeatureChange describes how a single feature differs between two protocols.
+type FeatureChange struct {
+	Key  string
+	Kind string // "added", "removed" or "changed"
+	Old  interface{}
+	New  interface{}
+}
+
+// DiffProtocols lists feature and limit changes going from one protocol
+// version to another, sorted by key. Calibration values are compared field
+// by field.
+func DiffProtocols(from, to uint32) ([]FeatureChange, error) {
+	a, err := Get(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Get(to)
+	if err != nil {
+		return nil, err
+	}
+
+	oldFeatures := FlattenFeatures(a.Features)
+	newFeatures := FlattenFeatures(b.Features)
+
+	var changes []FeatureChange
+	for key, oldVal := range oldFeatures {
+		newVal, ok := newFeatures[key]
+		switch {
+		case !ok:
+			changes = append(changes, FeatureChange{Key: key, Kind: "removed", Old: oldVal})
+		case !reflect.DeepEqual(oldVal, newVal):
+			changes = append(changes, FeatureChange{Key: key, Kind: "changed", Old: oldVal, New: newVal})
+		}
+	}
+	for key, newVal := range newFeatures {
+		if _, ok := oldFeatures[key]; !ok {
+			changes = append(changes, FeatureChange{Key: key, Kind: "added", New: newVal})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+// FlattenFeatures expands resource_calibration into dotted per-field keys so
+// features can be listed and compared as scalars.
+func FlattenFeatures(features map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(features))
+	for key, val := range features {
+		calib, ok := val.(*ResourceCalibration)
+		if !ok || calib == nil {
+			flat[key] = val
+			continue
+		}
+		v := reflect.ValueOf(*calib)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			flat[key+"."+t.Field(i).Tag.Get("json")] = v.Field(i).Interface()
+		}
+	}
+	return flat
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withRegistry restores the active protocol table after a test that loads an
+// override.
+func withRegistry(t *testing.T) {
+	t.Helper()
+	saved, savedDefault, savedSource := protocols, defaultVersion, registrySource
+	t.Cleanup(func() {
+		protocols, defaultVersion, registrySource = saved, savedDefault, savedSource
+	})
+}
+
+func writeRegistry(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), UserRegistryFileName)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestEmbeddedRegistry_FeatureTypes(t *testing.T) {
+	p, err := Get(23)
+	require.NoError(t, err)
+
+	assert.Equal(t, 131072, p.Features["max_contract_size"])
+	assert.Equal(t, true, p.Features["parallel_execution"])
+	assert.IsType(t, []string{}, p.Features["supported_opcodes"])
+	assert.IsType(t, &ResourceCalibration{}, p.Features["resource_calibration"])
+}
+
+func TestLoadRegistryOverride_AddsProtocolAndDefault(t *testing.T) {
+	withRegistry(t)
+
+	path := writeRegistry(t, `{
+  "schema_version": 1,
+  "default_version": 24,
+  "protocols": [
+    {"version": 24, "features": {"max_contract_size": 262144, "max_instruction_limit": 500000000}},
+    {"version": 22, "name": "Patched 22", "features": {"max_contract_size": 1}}
+  ]
+}`)
+	require.NoError(t, LoadRegistryOverride(path))
+
+	assert.Equal(t, uint32(24), LatestVersion())
+	assert.Equal(t, path, RegistrySource())
+
+	p24, err := Get(24)
+	require.NoError(t, err)
+	assert.Equal(t, "Soroban Protocol 24", p24.Name)
+	assert.Equal(t, 262144, p24.Features["max_contract_size"])
+
+	p22, err := Get(22)
+	require.NoError(t, err)
+	assert.Equal(t, "Patched 22", p22.Name)
+
+	_, err = Get(21)
+	assert.NoError(t, err, "protocols not in the override are kept")
+}
+
+func TestLoadRegistryOverride_Rejects(t *testing.T) {
+	withRegistry(t)
+
+	cases := map[string]string{
+		"newer schema":      `{"schema_version": 99, "protocols": []}`,
+		"missing schema":    `{"protocols": []}`,
+		"duplicate version": `{"schema_version": 1, "protocols": [{"version": 30}, {"version": 30}]}`,
+		"unknown default":   `{"schema_version": 1, "default_version": 40, "protocols": []}`,
+		"bad calibration":   `{"schema_version": 1, "protocols": [{"version": 30, "features": {"resource_calibration": 5}}]}`,
+		"malformed":         `{`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, LoadRegistryOverride(writeRegistry(t, content)))
+			assert.Equal(t, uint32(22), LatestVersion())
+		})
+	}
+}
+
+func TestDiffProtocols(t *testing.T) {
+	changes, err := DiffProtocols(21, 22)
+	require.NoError(t, err)
+
+	byKey := make(map[string]FeatureChange)
+	for _, c := range changes {
+		byKey[c.Key] = c
+	}
+
+	assert.Equal(t, "changed", byKey["max_contract_size"].Kind)
+	assert.Equal(t, 65536, byKey["max_contract_size"].Old)
+	assert.Equal(t, 131072, byKey["max_contract_size"].New)
+	assert.Equal(t, "added", byKey["optimized_storage"].Kind)
+	assert.NotContains(t, byKey, "enhanced_metering")
+	assert.NotContains(t, byKey, "resource_calibration.sha256_fixed")
+
+	reverse, err := DiffProtocols(22, 21)
+	require.NoError(t, err)
+	for _, c := range reverse {
+		if c.Key == "optimized_storage" {
+			assert.Equal(t, "removed", c.Kind)
+		}
+	}
+
+	_, err = DiffProtocols(21, 99)
+	assert.Error(t, err)
+}
+
+func TestFlattenFeatures_ExpandsCalibration(t *testing.T) {
+	flat := FlattenFeatures(map[string]interface{}{
+		"resource_calibration": &ResourceCalibration{SHA256Fixed: 7},
+		"enhanced_metering":    true,
+	})
+	assert.Equal(t, uint64(7), flat["resource_calibration.sha256_fixed"])
+	assert.Equal(t, true, flat["enhanced_metering"])
+	assert.NotContains(t, flat, "resource_calibration")
+}
@@ -23,6 +23,7 @@ func TestGet(t *testing.T) {
 		{"protocol 20", 20, false},
 		{"protocol 21", 21, false},
 		{"protocol 22", 22, false},
+		{"protocol 23", 23, false},
 		{"unsupported", 99, true},
 	}
 
@@ -0,0 +1,355 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// BaselineFormatVersion is the schema version written to baseline files.
+// Version 2 added the recorded inputs.
+const BaselineFormatVersion = 2
+
+// GoldenRecord is the recorded simulator input and output for one
+// transaction.
+type GoldenRecord struct {
+	TransactionHash string       `json:"tx_hash"`
+	Input           *GoldenInput `json:"input"`
+	Status          string       `json:"status"`
+	Error           string       `json:"error,omitempty"`
+	Events          []string     `json:"events"`
+	CPUInstructions uint64       `json:"cpu_instructions"`
+	MemoryBytes     uint64       `json:"memory_bytes"`
+}
+
+// GoldenInput is what a transaction was simulated with when it was recorded,
+// so verify can re-simulate it without the network.
+type GoldenInput struct {
+	EnvelopeXdr       string            `json:"envelope_xdr"`
+	ResultMetaXdr     string            `json:"result_meta_xdr,omitempty"`
+	LedgerEntries     map[string]string `json:"ledger_entries,omitempty"`
+	Timestamp         int64             `json:"timestamp,omitempty"`
+	LedgerSequence    uint32            `json:"ledger_sequence,omitempty"`
+	ProtocolVersion   *uint32           `json:"protocol_version,omitempty"`
+	BaseFee           uint32            `json:"base_fee,omitempty"`
+	BaseReserve       uint32            `json:"base_reserve,omitempty"`
+	NetworkPassphrase string            `json:"network_passphrase,omitempty"`
+}
+
+// GoldenInputFromRequest captures the inputs of req.
+func GoldenInputFromRequest(req *SimulationRequest) *GoldenInput {
+	in := &GoldenInput{
+		EnvelopeXdr:       req.EnvelopeXdr,
+		ResultMetaXdr:     req.ResultMetaXdr,
+		LedgerEntries:     make(map[string]string, len(req.LedgerEntries)),
+		Timestamp:         req.Timestamp,
+		LedgerSequence:    req.LedgerSequence,
+		BaseFee:           req.BaseFee,
+		BaseReserve:       req.BaseReserve,
+		NetworkPassphrase: req.NetworkPassphrase,
+	}
+	for k, v := range req.LedgerEntries {
+		in.LedgerEntries[k] = v
+	}
+	if req.ProtocolVersion != nil {
+		v := *req.ProtocolVersion
+		in.ProtocolVersion = &v
+	}
+	return in
+}
+
+// Request returns a fresh simulation request for the recorded inputs.
+func (in *GoldenInput) Request() *SimulationRequest {
+	req := &SimulationRequest{
+		EnvelopeXdr:       in.EnvelopeXdr,
+		ResultMetaXdr:     in.ResultMetaXdr,
+		LedgerEntries:     make(map[string]string, len(in.LedgerEntries)),
+		Timestamp:         in.Timestamp,
+		LedgerSequence:    in.LedgerSequence,
+		BaseFee:           in.BaseFee,
+		BaseReserve:       in.BaseReserve,
+		NetworkPassphrase: in.NetworkPassphrase,
+	}
+	for k, v := range in.LedgerEntries {
+		req.LedgerEntries[k] = v
+	}
+	if in.ProtocolVersion != nil {
+		v := *in.ProtocolVersion
+		req.ProtocolVersion = &v
+	}
+	return req
+}
+
+// RegressionBaseline is a set of golden records that later simulator builds
+// are verified against.
+type RegressionBaseline struct {
+	Version         int            `json:"version"`
+	Network         string         `json:"network,omitempty"`
+	ProtocolVersion *uint32        `json:"protocol_version,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Records         []GoldenRecord `json:"records"`
+}
+
+// BudgetTolerance bounds how far budget usage may drift from the baseline,
+// as a percentage of the recorded value.
+type BudgetTolerance struct {
+	CPUPercent    float64
+	MemoryPercent float64
+}
+
+// DefaultBudgetTolerance allows 5% drift in CPU and memory usage.
+var DefaultBudgetTolerance = BudgetTolerance{CPUPercent: 5, MemoryPercent: 5}
+
+// GoldenFromResponse captures the parts of a simulation response that are
+// compared across simulator versions. Diagnostic events are left out because
+// they carry host debug output that legitimately changes between releases;
+// contract and system events are kept in emission order.
+func GoldenFromResponse(txHash string, resp *SimulationResponse) GoldenRecord {
+	rec := GoldenRecord{
+		TransactionHash: txHash,
+		Status:          resp.Status,
+		Error:           resp.Error,
+		Events:          []string{},
+	}
+	if resp.BudgetUsage != nil {
+		rec.CPUInstructions = resp.BudgetUsage.CPUInstructions
+		rec.MemoryBytes = resp.BudgetUsage.MemoryBytes
+	}
+
+	if len(resp.DiagnosticEvents) > 0 {
+		for _, ev := range resp.DiagnosticEvents {
+			if ev.EventType == "diagnostic" {
+				continue
+			}
+			contract := ""
+			if ev.ContractID != nil {
+				contract = *ev.ContractID
+			}
+			rec.Events = append(rec.Events, fmt.Sprintf("%s|%s|%s|%s",
+				ev.EventType, contract, strings.Join(ev.Topics, ","), ev.Data))
+		}
+	} else {
+		rec.Events = append(rec.Events, resp.Events...)
+	}
+	return rec
+}
+
+// CompareGolden returns a description of every difference between the
+// recorded and the current output. Budget usage is compared within tol.
+func CompareGolden(want, got GoldenRecord, tol BudgetTolerance) []string {
+	var diffs []string
+	if want.Status != got.Status {
+		diffs = append(diffs, fmt.Sprintf("status: %s -> %s", want.Status, got.Status))
+	}
+	if want.Error != got.Error {
+		diffs = append(diffs, fmt.Sprintf("error: %q -> %q", want.Error, got.Error))
+	}
+
+	if len(want.Events) != len(got.Events) {
+		diffs = append(diffs, fmt.Sprintf("events: count %d -> %d", len(want.Events), len(got.Events)))
+	}
+	for i := 0; i < len(want.Events) && i < len(got.Events); i++ {
+		if want.Events[i] != got.Events[i] {
+			diffs = append(diffs, fmt.Sprintf("events[%d]: %s -> %s", i, want.Events[i], got.Events[i]))
+			break
+		}
+	}
+
+	if d, ok := budgetDrift(want.CPUInstructions, got.CPUInstructions, tol.CPUPercent); !ok {
+		diffs = append(diffs, fmt.Sprintf("cpu_instructions: %d -> %d (%+.2f%%, tolerance %.2f%%)",
+			want.CPUInstructions, got.CPUInstructions, d, tol.CPUPercent))
+	}
+	if d, ok := budgetDrift(want.MemoryBytes, got.MemoryBytes, tol.MemoryPercent); !ok {
+		diffs = append(diffs, fmt.Sprintf("memory_bytes: %d -> %d (%+.2f%%, tolerance %.2f%%)",
+			want.MemoryBytes, got.MemoryBytes, d, tol.MemoryPercent))
+	}
+	return diffs
+}
+
+// budgetDrift returns the relative change from want to got in percent and
+// whether it is within tolerance.
+func budgetDrift(want, got uint64, tolerance float64) (float64, bool) {
+	if want == got {
+		return 0, true
+	}
+	if want == 0 {
+		return 100, false
+	}
+	drift := (float64(got) - float64(want)) / float64(want) * 100
+	if drift < 0 {
+		return drift, -drift <= tolerance
+	}
+	return drift, drift <= tolerance
+}
+
+// LoadRegressionBaseline reads a baseline file written by Save.
+func LoadRegressionBaseline(path string) (*RegressionBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read baseline: %v", err))
+	}
+
+	var baseline RegressionBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, path)
+	}
+	if baseline.Version < BaselineFormatVersion {
+		return nil, errors.WrapValidationError(fmt.Sprintf(
+			"baseline version %d does not record transaction inputs; re-record it with erst regress record", baseline.Version))
+	}
+	if baseline.Version != BaselineFormatVersion {
+		return nil, errors.WrapValidationError(fmt.Sprintf(
+			"unsupported baseline version %d (expected %d)", baseline.Version, BaselineFormatVersion))
+	}
+	return &baseline, nil
+}
+
+// Save writes the baseline to path with records sorted by transaction hash
+// so that re-recording produces reviewable diffs.
+func (b *RegressionBaseline) Save(path string) error {
+	sort.Slice(b.Records, func(i, j int) bool {
+		return b.Records[i].TransactionHash < b.Records[j].TransactionHash
+	})
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create baseline directory: %v", err))
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to write baseline: %v", err))
+	}
+	return nil
+}
+
+// RecordBaseline simulates each transaction and captures its inputs and
+// output as a golden record. Transactions that cannot be simulated are reported as
+// errors in the suite and left out of the baseline.
+func (h *RegressionHarness) RecordBaseline(
+	ctx context.Context,
+	txHashes []string,
+	protocolVersion *uint32,
+) (*RegressionBaseline, *RegressionTestSuite, error) {
+	if len(txHashes) == 0 {
+		return nil, nil, errors.WrapValidationError("no transactions to record")
+	}
+
+	records := make(map[string]GoldenRecord, len(txHashes))
+	var mu sync.Mutex
+	suite := h.runEach(ctx, txHashes, func(ctx context.Context, hash string) RegressionTestResult {
+		result := RegressionTestResult{TransactionHash: hash, Status: "error"}
+		simReq, err := h.buildRequest(ctx, hash, protocolVersion)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			return result
+		}
+		// Capture the inputs before the runner fills in its defaults.
+		input := GoldenInputFromRequest(simReq)
+		resp, kind, err := h.runRequest(ctx, simReq)
+		if err != nil {
+			result.FailureKind = kind
+			result.ErrorMessage = err.Error()
+			return result
+		}
+
+		rec := GoldenFromResponse(hash, resp)
+		rec.Input = input
+		mu.Lock()
+		records[hash] = rec
+		mu.Unlock()
+
+		result.Status = "pass"
+		result.EventCount = len(rec.Events)
+		result.ExpectedCount = len(rec.Events)
+		result.EventCountMatch = true
+		result.TrapsMatch = true
+		return result
+	})
+
+	baseline := &RegressionBaseline{
+		Version:         BaselineFormatVersion,
+		ProtocolVersion: protocolVersion,
+		CreatedAt:       time.Now().UTC(),
+		Records:         make([]GoldenRecord, 0, len(records)),
+	}
+	for _, hash := range txHashes {
+		if rec, ok := records[hash]; ok {
+			baseline.Records = append(baseline.Records, rec)
+		}
+	}
+	return baseline, suite, nil
+}
+
+// VerifyBaseline re-simulates every transaction in the baseline from its
+// recorded inputs, without the network, and compares the output with the
+// golden record. Results with differences are marked "fail" and list them in
+// Diffs. protocolVersion, when set, replaces the recorded protocol version.
+func (h *RegressionHarness) VerifyBaseline(
+	ctx context.Context,
+	baseline *RegressionBaseline,
+	tol BudgetTolerance,
+	protocolVersion *uint32,
+) (*RegressionTestSuite, error) {
+	if baseline == nil || len(baseline.Records) == 0 {
+		return nil, errors.WrapValidationError("baseline has no records")
+	}
+
+	golden := make(map[string]GoldenRecord, len(baseline.Records))
+	hashes := make([]string, 0, len(baseline.Records))
+	for _, rec := range baseline.Records {
+		golden[rec.TransactionHash] = rec
+		hashes = append(hashes, rec.TransactionHash)
+	}
+
+	suite := h.runEach(ctx, hashes, func(ctx context.Context, hash string) RegressionTestResult {
+		want := golden[hash]
+		result := RegressionTestResult{
+			TransactionHash: hash,
+			Status:          "error",
+			ExpectedCount:   len(want.Events),
+		}
+		if want.Input == nil {
+			result.ErrorMessage = "baseline record has no inputs; re-record it"
+			return result
+		}
+		simReq := want.Input.Request()
+		if protocolVersion != nil {
+			simReq.ProtocolVersion = protocolVersion
+		}
+		resp, kind, err := h.runRequest(ctx, simReq)
+		if err != nil {
+			result.FailureKind = kind
+			result.ErrorMessage = err.Error()
+			return result
+		}
+
+		got := GoldenFromResponse(hash, resp)
+		result.EventCount = len(got.Events)
+		result.EventCountMatch = len(got.Events) == len(want.Events)
+		result.TrapsMatch = got.Status == want.Status && got.Error == want.Error
+		result.Diffs = CompareGolden(want, got, tol)
+		if len(result.Diffs) > 0 {
+			result.Status = "fail"
+			result.ErrorMessage = fmt.Sprintf("%d difference(s) from baseline", len(result.Diffs))
+		} else {
+			result.Status = "pass"
+		}
+		return result
+	})
+	return suite, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRegressionHarness returns a harness whose requests carry the tx hash
+// in EnvelopeXdr so the runner can answer per transaction.
+func stubRegressionHarness(run func(hash string) (*SimulationResponse, error)) *RegressionHarness {
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		return run(req.EnvelopeXdr)
+	}}
+	h := NewRegressionHarness(runner, nil, 2)
+	h.FetchRequest = func(ctx context.Context, txHash string) (*SimulationRequest, error) {
+		if txHash == "missing" {
+			return nil, fmt.Errorf("failed to fetch transaction: not found")
+		}
+		return &SimulationRequest{EnvelopeXdr: txHash}, nil
+	}
+	return h
+}
+
+func budgetResponse(cpu, mem uint64, events ...string) *SimulationResponse {
+	contract := "CABC"
+	resp := &SimulationResponse{
+		Status:      "success",
+		BudgetUsage: &BudgetUsage{CPUInstructions: cpu, MemoryBytes: mem},
+		DiagnosticEvents: []DiagnosticEvent{
+			{EventType: "diagnostic", Topics: []string{"fn_call"}, Data: "noise"},
+		},
+	}
+	for _, e := range events {
+		resp.DiagnosticEvents = append(resp.DiagnosticEvents, DiagnosticEvent{
+			EventType: "contract", ContractID: &contract, Topics: []string{e}, Data: "1",
+		})
+	}
+	return resp
+}
+
+func TestGoldenFromResponse_SkipsDiagnosticEvents(t *testing.T) {
+	rec := GoldenFromResponse("tx1", budgetResponse(100, 200, "transfer"))
+
+	assert.Equal(t, "success", rec.Status)
+	assert.Equal(t, []string{"contract|CABC|transfer|1"}, rec.Events)
+	assert.Equal(t, uint64(100), rec.CPUInstructions)
+	assert.Equal(t, uint64(200), rec.MemoryBytes)
+}
+
+func TestCompareGolden(t *testing.T) {
+	want := GoldenFromResponse("tx1", budgetResponse(1000, 1000, "transfer"))
+	tol := BudgetTolerance{CPUPercent: 5, MemoryPercent: 5}
+
+	t.Run("budget drift within tolerance passes", func(t *testing.T) {
+		got := GoldenFromResponse("tx1", budgetResponse(1040, 960, "transfer"))
+		assert.Empty(t, CompareGolden(want, got, tol))
+	})
+
+	t.Run("budget drift beyond tolerance fails", func(t *testing.T) {
+		got := GoldenFromResponse("tx1", budgetResponse(1100, 1000, "transfer"))
+		diffs := CompareGolden(want, got, tol)
+		require.Len(t, diffs, 1)
+		assert.Contains(t, diffs[0], "cpu_instructions: 1000 -> 1100 (+10.00%")
+	})
+
+	t.Run("status and events are compared exactly", func(t *testing.T) {
+		got := GoldenFromResponse("tx1", budgetResponse(1000, 1000, "mint", "burn"))
+		got.Status = "error"
+		diffs := CompareGolden(want, got, tol)
+		assert.Contains(t, diffs, "status: success -> error")
+		assert.Contains(t, diffs, "events: count 1 -> 2")
+		assert.Contains(t, diffs, "events[0]: contract|CABC|transfer|1 -> contract|CABC|mint|1")
+	})
+}
+
+func TestRegressionBaseline_RecordSaveVerify(t *testing.T) {
+	cpu := map[string]uint64{"tx1": 1000, "tx2": 5000}
+	h := stubRegressionHarness(func(hash string) (*SimulationResponse, error) {
+		return budgetResponse(cpu[hash], 100, "evt-"+hash), nil
+	})
+
+	baseline, suite, err := h.RecordBaseline(context.Background(), []string{"tx2", "tx1", "missing"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, suite.ErrorTests)
+	require.Len(t, baseline.Records, 2)
+	assert.Equal(t, "tx2", baseline.Records[0].TransactionHash, "records keep input order until saved")
+
+	require.NotNil(t, baseline.Records[0].Input)
+	assert.Equal(t, "tx2", baseline.Records[0].Input.EnvelopeXdr)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, baseline.Save(path))
+	loaded, err := LoadRegressionBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", loaded.Records[0].TransactionHash)
+
+	// Verify re-simulates the recorded inputs and never fetches.
+	h.FetchRequest = func(ctx context.Context, txHash string) (*SimulationRequest, error) {
+		t.Fatalf("verify fetched %s", txHash)
+		return nil, nil
+	}
+
+	suite, err = h.VerifyBaseline(context.Background(), loaded, DefaultBudgetTolerance, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, suite.PassedTests)
+
+	cpu["tx2"] = 6000
+	suite, err = h.VerifyBaseline(context.Background(), loaded, DefaultBudgetTolerance, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, suite.PassedTests)
+	assert.Equal(t, 1, suite.FailedTests)
+	failed := suite.FailedResults()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "tx2", failed[0].TransactionHash)
+	assert.Len(t, failed[0].Diffs, 1)
+}
+
+func TestLoadRegressionBaseline_RejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	b := &RegressionBaseline{Version: BaselineFormatVersion + 1}
+	require.NoError(t, b.Save(path))
+
+	_, err := LoadRegressionBaseline(path)
+	assert.Error(t, err)
+
+	b.Version = 1
+	require.NoError(t, b.Save(path))
+	_, err = LoadRegressionBaseline(path)
+	assert.ErrorContains(t, err, "re-record")
+}
+
+func TestRegressionTestSuite_Reports(t *testing.T) {
+	suite := &RegressionTestSuite{TotalTests: 3, PassedTests: 1, FailedTests: 1, ErrorTests: 1}
+	suite.Results = []RegressionTestResult{
+		{TransactionHash: "tx1", Status: "pass"},
+		{TransactionHash: "tx2", Status: "fail", ErrorMessage: "1 difference(s) from baseline", Diffs: []string{"status: success -> error"}},
+		{TransactionHash: "tx3", Status: "error", ErrorMessage: "simulation failed", FailureKind: "crash"},
+	}
+
+	var junit bytes.Buffer
+	require.NoError(t, suite.WriteJUnit(&junit, "erst.regress"))
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(junit.Bytes(), &doc))
+	assert.Equal(t, 3, doc.Tests)
+	require.Len(t, doc.Suites, 1)
+	cases := doc.Suites[0].Cases
+	require.Len(t, cases, 3)
+	assert.Nil(t, cases[0].Failure)
+	require.NotNil(t, cases[1].Failure)
+	assert.Equal(t, "status: success -> error", cases[1].Failure.Body)
+	require.NotNil(t, cases[2].Error)
+	assert.Equal(t, "crash", cases[2].Error.Type)
+
+	var js bytes.Buffer
+	require.NoError(t, suite.WriteJSON(&js))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.EqualValues(t, 1, decoded["failed"])
+	assert.Len(t, decoded["results"], 3)
+}
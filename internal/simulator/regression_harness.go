@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/rpc"
@@ -15,22 +16,25 @@ import (
 
 // RegressionTestResult represents the outcome of a single transaction test
 type RegressionTestResult struct {
-	TransactionHash string
-	Status          string // "pass", "fail", "error"
-	ErrorMessage    string
-	EventCountMatch bool
-	EventCount      int
-	ExpectedCount   int
-	TrapsMatch      bool
+	TransactionHash string        `json:"tx_hash"`
+	Status          string        `json:"status"` // "pass", "fail", "error"
+	ErrorMessage    string        `json:"error,omitempty"`
+	FailureKind     string        `json:"failure_kind,omitempty"` // set when the simulator itself failed; see ClassifyRunError
+	EventCountMatch bool          `json:"event_count_match"`
+	EventCount      int           `json:"event_count"`
+	ExpectedCount   int           `json:"expected_count"`
+	TrapsMatch      bool          `json:"traps_match"`
+	Diffs           []string      `json:"diffs,omitempty"` // golden baseline mismatches; see CompareGolden
+	Duration        time.Duration `json:"duration_ns"`
 }
 
 // RegressionTestSuite holds results from a batch of regression tests
 type RegressionTestSuite struct {
-	TotalTests  int
-	PassedTests int
-	FailedTests int
-	ErrorTests  int
-	Results     []RegressionTestResult
+	TotalTests  int                    `json:"total"`
+	PassedTests int                    `json:"passed"`
+	FailedTests int                    `json:"failed"`
+	ErrorTests  int                    `json:"errors"`
+	Results     []RegressionTestResult `json:"results"`
 	mu          sync.Mutex
 }
 
@@ -40,6 +44,10 @@ type RegressionHarness struct {
 	RPCClient  *rpc.Client
 	MaxWorkers int
 	Verbose    bool
+
+	// FetchRequest builds the simulation request for a transaction. When nil
+	// the envelope and ledger state are fetched through RPCClient.
+	FetchRequest func(ctx context.Context, txHash string) (*SimulationRequest, error)
 }
 
 // NewRegressionHarness creates a new regression test harness
@@ -82,25 +90,41 @@ func (h *RegressionHarness) RunRegressionTests(
 
 	logger.Logger.Info("Found transactions to test", "count", len(txHashes))
 
-	// Run tests in parallel
-	suite := &RegressionTestSuite{
-		TotalTests: len(txHashes),
-		Results:    make([]RegressionTestResult, 0, len(txHashes)),
-	}
+	suite := h.runEach(ctx, txHashes, func(ctx context.Context, hash string) RegressionTestResult {
+		return h.testTransaction(ctx, hash, protocolVersion)
+	})
+	return suite, nil
+}
 
-	sem := make(chan struct{}, h.MaxWorkers)
+// runEach runs fn for every transaction on up to MaxWorkers goroutines and
+// tallies the results. Results keep the order of txHashes.
+func (h *RegressionHarness) runEach(
+	ctx context.Context,
+	txHashes []string,
+	fn func(ctx context.Context, hash string) RegressionTestResult,
+) *RegressionTestSuite {
+	suite := &RegressionTestSuite{TotalTests: len(txHashes)}
+	results := make([]RegressionTestResult, len(txHashes))
+
+	workers := h.MaxWorkers
+	if workers <= 0 {
+		workers = 4
+	}
+	sem := make(chan struct{}, workers)
 	var wg sync.WaitGroup
 	var processedCount atomic.Int64
 
-	for _, txHash := range txHashes {
+	for i, txHash := range txHashes {
 		wg.Add(1)
-		go func(hash string) {
+		go func(i int, hash string) {
 			defer wg.Done()
 			sem <- struct{}{}        // Acquire semaphore
 			defer func() { <-sem }() // Release semaphore
 
-			result := h.testTransaction(ctx, hash, protocolVersion)
-			suite.addResult(result)
+			start := time.Now()
+			result := fn(ctx, hash)
+			result.Duration = time.Since(start)
+			results[i] = result
 
 			current := processedCount.Add(1)
 			if h.Verbose || current%10 == 0 {
@@ -111,13 +135,13 @@ func (h *RegressionHarness) RunRegressionTests(
 					"status", result.Status,
 				)
 			}
-		}(txHash)
+		}(i, txHash)
 	}
 
 	wg.Wait()
 
-	// Calculate statistics
-	for _, result := range suite.Results {
+	for _, result := range results {
+		suite.addResult(result)
 		switch result.Status {
 		case "pass":
 			suite.PassedTests++
@@ -128,7 +152,7 @@ func (h *RegressionHarness) RunRegressionTests(
 		}
 	}
 
-	return suite, nil
+	return suite
 }
 
 // testTransaction runs a single transaction through the simulator and verifies results
@@ -142,45 +166,10 @@ func (h *RegressionHarness) testTransaction(
 		Status:          "error",
 	}
 
-	// Check if RPCClient is available
-	if h.RPCClient == nil {
-		result.ErrorMessage = "RPC client not configured"
-		return result
-	}
-
-	// Fetch transaction details
-	resp, err := h.RPCClient.GetTransaction(ctx, txHash)
-	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("failed to fetch transaction: %v", err)
-		return result
-	}
-
-	// Extract ledger entries
-	keys, err := extractLedgerKeysFromXDR(resp.ResultMetaXdr)
-	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("failed to extract ledger keys: %v", err)
-		return result
-	}
-
-	// Fetch ledger entries from network
-	ledgerEntries, err := h.RPCClient.GetLedgerEntries(ctx, keys)
-	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("failed to fetch ledger entries: %v", err)
-		return result
-	}
-
-	// Build simulation request
-	simReq := &SimulationRequest{
-		EnvelopeXdr:     resp.EnvelopeXdr,
-		ResultMetaXdr:   resp.ResultMetaXdr,
-		LedgerEntries:   ledgerEntries,
-		ProtocolVersion: protocolVersionOverride,
-	}
-
-	// Run simulation
-	simResp, err := h.Runner.Run(simReq)
+	simResp, failureKind, err := h.simulateTransaction(ctx, txHash, protocolVersionOverride)
 	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("simulation failed: %v", err)
+		result.FailureKind = failureKind
+		result.ErrorMessage = err.Error()
 		return result
 	}
 
@@ -215,6 +204,94 @@ func (h *RegressionHarness) testTransaction(
 	return result
 }
 
+// simulateTransaction fetches a transaction and runs it through the simulator.
+// When the simulator itself fails, the returned failure kind classifies the
+// error; it is empty when the request could not be built.
+func (h *RegressionHarness) simulateTransaction(
+	ctx context.Context,
+	txHash string,
+	protocolVersionOverride *uint32,
+) (*SimulationResponse, string, error) {
+	simReq, err := h.buildRequest(ctx, txHash, protocolVersionOverride)
+	if err != nil {
+		return nil, "", err
+	}
+	return h.runRequest(ctx, simReq)
+}
+
+// buildRequest fetches the simulation request for txHash and applies the
+// protocol version override, if any.
+func (h *RegressionHarness) buildRequest(
+	ctx context.Context,
+	txHash string,
+	protocolVersionOverride *uint32,
+) (*SimulationRequest, error) {
+	fetch := h.FetchRequest
+	if fetch == nil {
+		fetch = h.fetchRequest
+	}
+	simReq, err := fetch(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if protocolVersionOverride != nil {
+		simReq.ProtocolVersion = protocolVersionOverride
+	}
+	return simReq, nil
+}
+
+// runRequest runs simReq and classifies simulator failures.
+func (h *RegressionHarness) runRequest(ctx context.Context, simReq *SimulationRequest) (*SimulationResponse, string, error) {
+	simResp, err := h.Runner.RunContext(ctx, simReq)
+	if err != nil {
+		kind := ClassifyRunError(err)
+		return nil, kind, fmt.Errorf("simulation failed (%s): %w", kind, err)
+	}
+	return simResp, "", nil
+}
+
+// fetchRequest builds a simulation request from the transaction and ledger
+// state served by RPCClient.
+func (h *RegressionHarness) fetchRequest(ctx context.Context, txHash string) (*SimulationRequest, error) {
+	if h.RPCClient == nil {
+		return nil, fmt.Errorf("RPC client not configured")
+	}
+
+	resp, err := h.RPCClient.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+
+	keys, err := extractLedgerKeysFromXDR(resp.ResultMetaXdr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract ledger keys: %w", err)
+	}
+
+	ledgerEntries, err := h.RPCClient.GetLedgerEntries(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+
+	simReq := &SimulationRequest{
+		EnvelopeXdr:    resp.EnvelopeXdr,
+		ResultMetaXdr:  resp.ResultMetaXdr,
+		LedgerEntries:  ledgerEntries,
+		LedgerSequence: resp.LedgerSequence,
+	}
+
+	var header *rpc.LedgerHeaderResponse
+	if resp.LedgerSequence > 0 {
+		header, err = h.RPCClient.GetLedgerHeader(ctx, resp.LedgerSequence)
+		if err != nil {
+			logger.Logger.Warn("Failed to fetch ledger header, ledger context will be guessed",
+				"tx_hash", txHash, "sequence", resp.LedgerSequence, "error", err)
+			header = nil
+		}
+	}
+	PinLedgerContext(simReq, header, h.RPCClient.GetNetworkPassphrase())
+	return simReq, nil
+}
+
 // fetchFailedTransactions retrieves hashes of failed transactions from mainnet
 // Uses ledger sequence as a starting point for the search
 func (h *RegressionHarness) fetchFailedTransactions(
@@ -0,0 +1,118 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the suite as JUnit XML with one test case per
+// transaction. Baseline mismatches become failures and simulator or fetch
+// problems become errors, so CI systems can tell the two apart.
+func (suite *RegressionTestSuite) WriteJUnit(w io.Writer, name string) error {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	ts := junitTestSuite{
+		Name:     name,
+		Tests:    len(suite.Results),
+		Failures: suite.FailedTests,
+		Errors:   suite.ErrorTests,
+	}
+	var total float64
+	for _, r := range suite.Results {
+		secs := r.Duration.Seconds()
+		total += secs
+		tc := junitTestCase{
+			Name:      r.TransactionHash,
+			ClassName: name,
+			Time:      fmt.Sprintf("%.3f", secs),
+		}
+		switch r.Status {
+		case "fail":
+			tc.Failure = &junitProblem{
+				Message: r.ErrorMessage,
+				Type:    "BaselineMismatch",
+				Body:    strings.Join(r.Diffs, "\n"),
+			}
+		case "error":
+			tc.Error = &junitProblem{
+				Message: r.ErrorMessage,
+				Type:    r.FailureKind,
+			}
+		}
+		ts.Cases = append(ts.Cases, tc)
+	}
+	ts.Time = fmt.Sprintf("%.3f", total)
+
+	doc := junitTestSuites{
+		Tests:    ts.Tests,
+		Failures: ts.Failures,
+		Errors:   ts.Errors,
+		Time:     ts.Time,
+		Suites:   []junitTestSuite{ts},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJSON writes the suite totals and per-transaction results as JSON.
+func (suite *RegressionTestSuite) WriteJSON(w io.Writer) error {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/dotandev/hintents/internal/httpauth"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// Defaults for RemoteRunner.
+const (
+	DefaultRemoteRetries      = 3
+	DefaultRemoteRetryBackoff = 500 * time.Millisecond
+)
+
+// Additional failure kinds a remote simulator reports for requests it
+// rejected before running them.
+const (
+	FailureUnsupportedProtocol = "unsupported_protocol"
+	FailureInvalidRequest      = "invalid_request"
+	FailureIncompatible        = "incompatible"
+)
+
+// maxRemoteBodyBytes bounds request and response bodies exchanged with a
+// remote simulator.
+const maxRemoteBodyBytes = 64 << 20
+
+// remoteRequest is the wire format of POST /simulate. NetworkSettings and
+// GasModel are not part of the request's JSON encoding, so they travel next
+// to it and the service applies them exactly as a local runner would.
+type remoteRequest struct {
+	Request         *SimulationRequest   `json:"request"`
+	NetworkSettings *rpc.NetworkSettings `json:"network_settings,omitempty"`
+	GasModel        *gasmodel.GasModel   `json:"gas_model,omitempty"`
+}
+
+// remoteResponse is the wire format returned by POST /simulate. Exactly one
+// of Response and Error is set.
+type remoteResponse struct {
+	Response *SimulationResponse `json:"response,omitempty"`
+	Error    *RemoteError        `json:"error,omitempty"`
+}
+
+// RemoteError is a failure reported by a remote simulator. Kind is one of
+// the Failure* constants and makes the error match the same sentinel errors
+// a local Runner returns, so errors.Is and ClassifyRunError behave the same
+// for both backends.
+type RemoteError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (e *RemoteError) Error() string {
+	return "remote simulator: " + e.Message
+}
+
+func (e *RemoteError) Is(target error) bool {
+	switch e.Kind {
+	case FailureTimeout:
+		return target == errors.ErrSimTimeout
+	case FailureOOM:
+		return target == errors.ErrSimOutOfMemory
+	case FailureKilled:
+		return target == errors.ErrSimKilled
+	case FailureCrash:
+		return target == errors.ErrSimCrash
+	case FailureUnsupportedProtocol:
+		return target == errors.ErrProtocolUnsupported
+	case FailureInvalidRequest:
+		return target == errors.ErrValidationFailed
+	case FailureIncompatible:
+		return target == errors.ErrSimIncompatible
+	}
+	return false
+}
+
+// RemoteRunner executes simulations on an erst simulator service, as served
+// by `erst daemon` or `erst sim-serve`, instead of a local erst-sim binary.
+type RemoteRunner struct {
+	// URL is the service base URL; requests go to URL/simulate.
+	URL   string
+	Token string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// MaxRetries is how many times a request is retried after a connection
+	// error or a 429, 502, 503 or 504 response.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles for every
+	// further attempt.
+	RetryBackoff time.Duration
+	// Timeout bounds a whole run, retries included; zero disables it.
+	Timeout  time.Duration
+	MockTime int64 // non-zero overrides Timestamp in every SimulationRequest
+}
+
+// Compile-time check to ensure RemoteRunner implements RunnerInterface
+var _ RunnerInterface = (*RemoteRunner)(nil)
+
+// NewRemoteRunner creates a RemoteRunner for the service at url with the
+// default retry policy and run timeout.
+func NewRemoteRunner(url, token string) (*RemoteRunner, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, errors.WrapValidationError(fmt.Sprintf("simulator URL must start with http:// or https://: %q", url))
+	}
+	return &RemoteRunner{
+		URL:          url,
+		Token:        token,
+		MaxRetries:   DefaultRemoteRetries,
+		RetryBackoff: DefaultRemoteRetryBackoff,
+		Timeout:      DefaultRunTimeout,
+	}, nil
+}
+
+// Run executes req with no caller-supplied context.
+func (r *RemoteRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return r.RunContext(context.Background(), req)
+}
+
+// RunContext posts req to the remote service. Failures are reported with the
+// same typed errors as Runner.RunContext; a service that cannot be reached
+// yields ErrRPCConnectionFailed.
+func (r *RemoteRunner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.WrapSimKilled(err, "")
+	}
+	if r.MockTime != 0 {
+		req.Timestamp = r.MockTime
+	}
+
+	sent, err := inlineWasm(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(remoteRequest{Request: sent, NetworkSettings: req.NetworkSettings, GasModel: req.GasModel})
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	ctx, cancel, timeout := withRunTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	backoff := r.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, retry, err := r.post(ctx, body)
+		if err == nil {
+			return resp, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, r.contextFailure(ctxErr, timeout)
+		}
+		if !retry || attempt >= r.MaxRetries {
+			return nil, err
+		}
+
+		logger.Logger.Warn("Remote simulator request failed, retrying", "url", r.URL, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, r.contextFailure(ctx.Err(), timeout)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// inlineWasm returns req with the file at WasmPath sent inline as
+// ContractWasm, since the path means nothing on the remote machine. req
+// itself is left unchanged.
+func inlineWasm(req *SimulationRequest) (*SimulationRequest, error) {
+	if req.WasmPath == nil || *req.WasmPath == "" {
+		return req, nil
+	}
+	data, err := os.ReadFile(*req.WasmPath)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read WASM file: %v", err))
+	}
+	wasm := base64.StdEncoding.EncodeToString(data)
+	sent := *req
+	sent.WasmPath = nil
+	sent.ContractWasm = &wasm
+	return &sent, nil
+}
+
+// post performs one request. retry reports whether the failure is transient.
+func (r *RemoteRunner) post(ctx context.Context, body []byte) (*SimulationResponse, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, false, errors.WrapValidationError(err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if r.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, true, errors.WrapRPCConnectionFailed(err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, maxRemoteBodyBytes))
+	if err != nil {
+		return nil, true, errors.WrapRPCConnectionFailed(err)
+	}
+
+	switch httpResp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, false, errors.WrapUnauthorized("remote simulator rejected the token")
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, true, errors.WrapRPCConnectionFailed(fmt.Errorf("%s returned %s", r.URL, httpResp.Status))
+	}
+
+	var out remoteResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, false, errors.WrapUnmarshalFailed(err, string(data))
+	}
+	if out.Error != nil {
+		return nil, false, out.Error
+	}
+	if out.Response == nil {
+		return nil, false, errors.WrapUnmarshalFailed(fmt.Errorf("%s returned %s without a response", r.URL, httpResp.Status), string(data))
+	}
+	return out.Response, false, nil
+}
+
+func (r *RemoteRunner) endpoint() string {
+	url := strings.TrimRight(r.URL, "/")
+	if strings.HasSuffix(url, "/simulate") {
+		return url
+	}
+	return url + "/simulate"
+}
+
+// contextFailure mirrors Runner.classifyFailure for a run cut short on the
+// client side; timeout is the run's deadline as returned by withRunTimeout.
+func (r *RemoteRunner) contextFailure(err error, timeout time.Duration) error {
+	if err == context.DeadlineExceeded {
+		return errors.WrapSimTimeout(timeout)
+	}
+	return errors.WrapSimKilled(err, "")
+}
+
+// NewRemoteHandler returns the handler behind POST /simulate. It runs the
+// posted request on runner and reports failures with their Failure* kind.
+// When token is non-empty requests must carry it as a bearer token.
+func NewRemoteHandler(runner RunnerInterface, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" && !httpauth.BearerTokenValid(r, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var in remoteRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxRemoteBodyBytes)).Decode(&in); err != nil || in.Request == nil {
+			writeRemoteResponse(w, http.StatusBadRequest, remoteResponse{Error: &RemoteError{
+				Kind:    FailureInvalidRequest,
+				Message: "malformed simulation request",
+			}})
+			return
+		}
+		// A path would name a file on this machine; clients send the WASM
+		// inline as contract_wasm instead.
+		if in.Request.WasmPath != nil {
+			writeRemoteResponse(w, http.StatusBadRequest, remoteResponse{Error: &RemoteError{
+				Kind:    FailureInvalidRequest,
+				Message: "wasm_path is not accepted by a remote simulator; send the WASM inline as contract_wasm",
+			}})
+			return
+		}
+		// Drain the body so the server notices a client that hangs up and
+		// cancels r.Context, which stops the simulation.
+		_, _ = io.Copy(io.Discard, r.Body)
+		in.Request.NetworkSettings = in.NetworkSettings
+		in.Request.GasModel = in.GasModel
+
+		resp, err := runner.RunContext(r.Context(), in.Request)
+		if err != nil {
+			status, kind := http.StatusInternalServerError, ClassifyRunError(err)
+			switch {
+			case errors.Is(err, errors.ErrProtocolUnsupported):
+				status, kind = http.StatusBadRequest, FailureUnsupportedProtocol
+			case errors.Is(err, errors.ErrValidationFailed):
+				status, kind = http.StatusBadRequest, FailureInvalidRequest
+			case errors.Is(err, errors.ErrSimIncompatible):
+				status, kind = http.StatusBadRequest, FailureIncompatible
+			}
+			writeRemoteResponse(w, status, remoteResponse{Error: &RemoteError{Kind: kind, Message: err.Error()}})
+			return
+		}
+		writeRemoteResponse(w, http.StatusOK, remoteResponse{Response: resp})
+	})
+}
+
+func writeRemoteResponse(w http.ResponseWriter, status int, body remoteResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Logger.Warn("Failed to write simulation response", "error", err)
+	}
+}
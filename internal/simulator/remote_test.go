@@ -0,0 +1,186 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRemoteRunner(t *testing.T, url, token string) *RemoteRunner {
+	t.Helper()
+	r, err := NewRemoteRunner(url, token)
+	require.NoError(t, err)
+	r.RetryBackoff = time.Millisecond
+	return r
+}
+
+func TestRemoteRunner_RoundTrip(t *testing.T) {
+	var got *SimulationRequest
+	srv := httptest.NewServer(NewRemoteHandler(NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		got = req
+		return &SimulationResponse{Status: "success", Logs: []string{"ok"}}, nil
+	}), "secret"))
+	defer srv.Close()
+
+	r := newTestRemoteRunner(t, srv.URL, "secret")
+	r.MockTime = 1700000000
+	resp, err := r.Run(&SimulationRequest{
+		EnvelopeXdr:     "env",
+		NetworkSettings: &rpc.NetworkSettings{LedgerMaxInstructions: 42},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []string{"ok"}, resp.Logs)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "env", got.EnvelopeXdr)
+	assert.Equal(t, int64(1700000000), got.Timestamp)
+	require.NotNil(t, got.NetworkSettings, "network settings travel outside the request encoding")
+	assert.Equal(t, int64(42), got.NetworkSettings.LedgerMaxInstructions)
+}
+
+func TestRemoteRunner_InlinesWasm(t *testing.T) {
+	var got *SimulationRequest
+	srv := httptest.NewServer(NewRemoteHandler(NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		got = req
+		return &SimulationResponse{Status: "success"}, nil
+	}), ""))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "contract.wasm")
+	require.NoError(t, os.WriteFile(path, []byte("\x00asm\x01\x00\x00\x00"), 0644))
+
+	req := &SimulationRequest{EnvelopeXdr: "env", WasmPath: &path}
+	_, err := newTestRemoteRunner(t, srv.URL, "").Run(req)
+	require.NoError(t, err)
+
+	require.NotNil(t, got)
+	assert.Nil(t, got.WasmPath, "the local path is not sent")
+	require.NotNil(t, got.ContractWasm)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("\x00asm\x01\x00\x00\x00")), *got.ContractWasm)
+	assert.Equal(t, &path, req.WasmPath, "the caller's request is left unchanged")
+}
+
+func TestRemoteHandler_RejectsWasmPath(t *testing.T) {
+	ran := false
+	handler := NewRemoteHandler(NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		ran = true
+		return &SimulationResponse{Status: "success"}, nil
+	}), "")
+
+	req := httptest.NewRequest(http.MethodPost, "/simulate",
+		strings.NewReader(`{"request":{"envelope_xdr":"env","wasm_path":"/etc/passwd"}}`))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), FailureInvalidRequest)
+	assert.False(t, ran)
+}
+
+func TestRemoteRunner_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(NewRemoteHandler(NewDefaultMockRunner(), "secret"))
+	defer srv.Close()
+
+	_, err := newTestRemoteRunner(t, srv.URL, "wrong").Run(&SimulationRequest{})
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+}
+
+func TestRemoteHandler_RequiresBearerScheme(t *testing.T) {
+	handler := NewRemoteHandler(NewDefaultMockRunner(), "secret")
+
+	req := httptest.NewRequest(http.MethodPost, "/simulate", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "a bare token is rejected")
+}
+
+func TestRemoteRunner_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	handler := NewRemoteHandler(NewDefaultMockRunner(), "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	resp, err := newTestRemoteRunner(t, srv.URL+"/simulate", "").Run(&SimulationRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	r := newTestRemoteRunner(t, srv.URL, "")
+	r.MaxRetries = 1
+	_, err = r.Run(&SimulationRequest{})
+	assert.ErrorIs(t, err, errors.ErrRPCConnectionFailed)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRemoteRunner_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		kind     string
+	}{
+		{"timeout", errors.WrapSimTimeout(time.Second), errors.ErrSimTimeout, FailureTimeout},
+		{"oom", errors.WrapSimOutOfMemory(1<<20, "memory allocation of 8 bytes failed"), errors.ErrSimOutOfMemory, FailureOOM},
+		{"killed", errors.WrapSimKilled(context.Canceled, ""), errors.ErrSimKilled, FailureKilled},
+		{"crash", errors.WrapSimCrash(assert.AnError, "panic"), errors.ErrSimCrash, FailureCrash},
+		{"protocol", errors.WrapProtocolUnsupported(99), errors.ErrProtocolUnsupported, FailureUnsupportedProtocol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(NewRemoteHandler(NewMockRunner(func(*SimulationRequest) (*SimulationResponse, error) {
+				return nil, tt.err
+			}), ""))
+			defer srv.Close()
+
+			_, err := newTestRemoteRunner(t, srv.URL, "").Run(&SimulationRequest{})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.sentinel)
+			if tt.kind != FailureUnsupportedProtocol {
+				assert.Equal(t, tt.kind, ClassifyRunError(err))
+			}
+			assert.Contains(t, err.Error(), tt.err.Error())
+		})
+	}
+}
+
+func TestRemoteRunner_ContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	r := newTestRemoteRunner(t, srv.URL, "")
+	r.Timeout = 50 * time.Millisecond
+	_, err := r.Run(&SimulationRequest{})
+	assert.ErrorIs(t, err, errors.ErrSimTimeout)
+}
+
+func TestNewRemoteRunner_RejectsBadURL(t *testing.T) {
+	_, err := NewRemoteRunner("localhost:8090", "")
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+}
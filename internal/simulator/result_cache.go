@@ -0,0 +1,267 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// ResultCacheDBName is the SQLite file, stored in ~/.erst next to cache.db.
+	ResultCacheDBName = "sim_cache.db"
+
+	// DefaultResultCacheMaxBytes bounds the total size of cached responses.
+	DefaultResultCacheMaxBytes int64 = 256 << 20
+
+	// resultCacheKeyVersion is mixed into every key so a change to the key
+	// derivation invalidates old entries instead of misreading them.
+	resultCacheKeyVersion = "v1"
+)
+
+const resultCacheSchema = `
+CREATE TABLE IF NOT EXISTS sim_results (
+	key_hash      TEXT PRIMARY KEY,
+	response      TEXT NOT NULL,
+	size          INTEGER NOT NULL,
+	created_at    INTEGER NOT NULL,
+	last_accessed INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_sim_results_accessed ON sim_results(last_accessed);
+`
+
+// ResultCache stores simulator responses keyed by a hash of the fully prepared
+// request and the simulator binary, so identical replays skip erst-sim.
+type ResultCache struct {
+	db       *sql.DB
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// ResultCacheStats summarises the contents of a ResultCache.
+type ResultCacheStats struct {
+	Entries  int
+	Bytes    int64
+	MaxBytes int64
+}
+
+// DefaultResultCachePath returns ~/.erst/sim_cache.db.
+func DefaultResultCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WrapValidationError(fmt.Sprintf("failed to get user home directory: %v", err))
+	}
+	return filepath.Join(home, ".erst", ResultCacheDBName), nil
+}
+
+// OpenResultCache opens (creating if needed) the cache database at path.
+// maxBytes <= 0 selects DefaultResultCacheMaxBytes.
+func OpenResultCache(path string, maxBytes int64) (*ResultCache, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultResultCacheMaxBytes
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to create cache directory: %v", err))
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open simulation cache: %w", err)
+	}
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
+	}
+	if _, err := db.Exec(resultCacheSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize simulation cache schema: %w", err)
+	}
+
+	return &ResultCache{db: db, maxBytes: maxBytes}, nil
+}
+
+// Close closes the underlying database.
+func (c *ResultCache) Close() error {
+	return c.db.Close()
+}
+
+// ResultCacheKey derives the cache key for a request that has already been
+// through prepareRequest. encoding/json sorts map keys, so the encoding of
+// ledger entries and protocol config is canonical. For local replays the WASM
+// file contents are hashed too, since the path alone says nothing about them.
+func ResultCacheKey(req *SimulationRequest, simVersion string) (string, error) {
+	var wasmHash string
+	if req.WasmPath != nil && *req.WasmPath != "" {
+		h, err := fileSHA256(*req.WasmPath)
+		if err != nil {
+			return "", err
+		}
+		wasmHash = h
+	}
+
+	payload, err := json.Marshal(struct {
+		Version    string             `json:"v"`
+		SimVersion string             `json:"sim"`
+		WasmHash   string             `json:"wasm,omitempty"`
+		Request    *SimulationRequest `json:"req"`
+	}{resultCacheKeyVersion, simVersion, wasmHash, req})
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the cached response for key, if any, and marks it as recently used.
+func (c *ResultCache) Get(key string) (*SimulationResponse, bool, error) {
+	var raw string
+	err := c.db.QueryRow("SELECT response FROM sim_results WHERE key_hash = ?", key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("simulation cache read failed: %w", err)
+	}
+
+	var resp SimulationResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		// A corrupt row is treated as a miss and dropped.
+		_, _ = c.db.Exec("DELETE FROM sim_results WHERE key_hash = ?", key)
+		return nil, false, nil
+	}
+
+	_, _ = c.db.Exec("UPDATE sim_results SET last_accessed = ? WHERE key_hash = ?", time.Now().UnixNano(), key)
+	return &resp, true, nil
+}
+
+// Put stores resp under key and evicts least recently used entries until the
+// cache fits within its size limit.
+func (c *ResultCache) Put(key string, resp *SimulationResponse) error {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	if int64(len(raw)) > c.maxBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	_, err = c.db.Exec(
+		`INSERT INTO sim_results (key_hash, response, size, created_at, last_accessed)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(key_hash) DO UPDATE SET
+		   response = excluded.response,
+		   size = excluded.size,
+		   last_accessed = excluded.last_accessed`,
+		key, string(raw), len(raw), now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("simulation cache write failed: %w", err)
+	}
+
+	_, err = c.evictTo(c.maxBytes)
+	return err
+}
+
+// Stats reports the number of entries and their total size.
+func (c *ResultCache) Stats() (ResultCacheStats, error) {
+	stats := ResultCacheStats{MaxBytes: c.maxBytes}
+	err := c.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(size), 0) FROM sim_results").Scan(&stats.Entries, &stats.Bytes)
+	if err != nil {
+		return stats, fmt.Errorf("simulation cache stats failed: %w", err)
+	}
+	return stats, nil
+}
+
+// Clean evicts least recently used entries until the cache is at most half
+// of its size limit, matching the file cache's cleanup target. It returns the
+// number of entries removed.
+func (c *ResultCache) Clean() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictTo(c.maxBytes / 2)
+}
+
+// Clear removes every entry.
+func (c *ResultCache) Clear() (int, error) {
+	res, err := c.db.Exec("DELETE FROM sim_results")
+	if err != nil {
+		return 0, fmt.Errorf("simulation cache clear failed: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// evictTo deletes the oldest entries until the total size is <= target.
+// Callers must hold c.mu.
+func (c *ResultCache) evictTo(target int64) (int, error) {
+	var total int64
+	if err := c.db.QueryRow("SELECT COALESCE(SUM(size), 0) FROM sim_results").Scan(&total); err != nil {
+		return 0, fmt.Errorf("simulation cache size query failed: %w", err)
+	}
+	if total <= target {
+		return 0, nil
+	}
+
+	rows, err := c.db.Query("SELECT key_hash, size FROM sim_results ORDER BY last_accessed ASC")
+	if err != nil {
+		return 0, fmt.Errorf("simulation cache eviction failed: %w", err)
+	}
+
+	var victims []string
+	for rows.Next() && total > target {
+		var key string
+		var size int64
+		if err := rows.Scan(&key, &size); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		victims = append(victims, key)
+		total -= size
+	}
+	rows.Close()
+
+	for _, key := range victims {
+		if _, err := c.db.Exec("DELETE FROM sim_results WHERE key_hash = ?", key); err != nil {
+			return 0, fmt.Errorf("simulation cache eviction failed: %w", err)
+		}
+	}
+
+	if len(victims) > 0 {
+		logger.Logger.Debug("Evicted simulation cache entries", "count", len(victims))
+	}
+	return len(victims), nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at path. It fingerprints the
+// simulator binary so cache entries are tied to the build that produced them.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
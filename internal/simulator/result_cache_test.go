@@ -0,0 +1,140 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestResultCache(t *testing.T, maxBytes int64) *ResultCache {
+	t.Helper()
+	rc, err := OpenResultCache(filepath.Join(t.TempDir(), ResultCacheDBName), maxBytes)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rc.Close() })
+	return rc
+}
+
+func TestResultCacheKey_CanonicalAndSensitive(t *testing.T) {
+	a := &SimulationRequest{
+		EnvelopeXdr:   "env",
+		LedgerEntries: map[string]string{"k1": "v1", "k2": "v2", "k3": "v3"},
+		Timestamp:     100,
+	}
+	b := &SimulationRequest{
+		EnvelopeXdr:   "env",
+		LedgerEntries: map[string]string{"k3": "v3", "k1": "v1", "k2": "v2"},
+		Timestamp:     100,
+	}
+
+	keyA, err := ResultCacheKey(a, "sim-1")
+	require.NoError(t, err)
+	keyB, err := ResultCacheKey(b, "sim-1")
+	require.NoError(t, err)
+	assert.Equal(t, keyA, keyB, "ledger entry order must not affect the key")
+
+	otherSim, err := ResultCacheKey(a, "sim-2")
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA, otherSim)
+
+	b.Timestamp = 101
+	otherTime, err := ResultCacheKey(b, "sim-1")
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA, otherTime)
+}
+
+func TestResultCacheKey_HashesWasmContents(t *testing.T) {
+	wasm := filepath.Join(t.TempDir(), "contract.wasm")
+	require.NoError(t, os.WriteFile(wasm, []byte("v1"), 0o644))
+	req := &SimulationRequest{WasmPath: &wasm}
+
+	before, err := ResultCacheKey(req, "sim")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(wasm, []byte("v2"), 0o644))
+	after, err := ResultCacheKey(req, "sim")
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
+
+func TestResultCache_PutGet(t *testing.T) {
+	rc := openTestResultCache(t, 0)
+
+	_, ok, err := rc.Get("missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, rc.Put("key", &SimulationResponse{Status: "success", Logs: []string{"hello"}}))
+
+	resp, ok, err := rc.Get("key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []string{"hello"}, resp.Logs)
+
+	stats, err := rc.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Positive(t, stats.Bytes)
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	big := strings.Repeat("x", 400)
+	rc := openTestResultCache(t, 1000)
+
+	require.NoError(t, rc.Put("first", &SimulationResponse{Status: "success", Error: big}))
+	require.NoError(t, rc.Put("second", &SimulationResponse{Status: "success", Error: big}))
+	_, _, _ = rc.Get("first")
+	require.NoError(t, rc.Put("third", &SimulationResponse{Status: "success", Error: big}))
+
+	_, ok, _ := rc.Get("second")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok, _ = rc.Get("first")
+	assert.True(t, ok)
+	_, ok, _ = rc.Get("third")
+	assert.True(t, ok)
+}
+
+func TestResultCache_CleanAndClear(t *testing.T) {
+	rc := openTestResultCache(t, 1000)
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, rc.Put(k, &SimulationResponse{Status: "success", Error: strings.Repeat("y", 250)}))
+	}
+
+	removed, err := rc.Clean()
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	removed, err = rc.Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+}
+
+func TestRunner_ServesRepeatedRequestsFromCache(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	runner := fakeSim(t, `cat >/dev/null; echo run >> `+counter+`; echo '{"status":"success"}'`)
+	runner.Cache = openTestResultCache(t, 0)
+
+	for i := 0; i < 3; i++ {
+		resp, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+		require.NoError(t, err)
+		assert.Equal(t, "success", resp.Status)
+		require.NotNil(t, resp.ProtocolVersion)
+	}
+
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "run"), "simulator should only run once")
+
+	_, err = runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "BBBB"})
+	require.NoError(t, err)
+	data, _ = os.ReadFile(counter)
+	assert.Equal(t, 2, strings.Count(string(data), "run"))
+}
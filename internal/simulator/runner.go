@@ -5,21 +5,45 @@ package simulator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
 )
 
+// DefaultRunTimeout bounds a single simulation so a contract stuck in an
+// endless loop cannot hang the CLI or the daemon.
+const DefaultRunTimeout = 2 * time.Minute
+
+// DefaultMemoryLimit caps the address space of an erst-sim process. It leaves
+// ample room for the host and a contract's linear memory while making a
+// runaway allocation fail instead of exhausting the machine.
+const DefaultMemoryLimit uint64 = 4 << 30
+
 // Runner handles the execution of the Rust simulator binary
 type Runner struct {
-	BinaryPath string
-	Debug      bool
-	MockTime   int64 // non-zero overrides Timestamp in every SimulationRequest
+	BinaryPath  string
+	Debug       bool
+	MockTime    int64         // non-zero overrides Timestamp in every SimulationRequest
+	Timeout     time.Duration // wall-clock limit per run; zero disables it
+	MemoryLimit uint64        // address-space limit in bytes (Linux only); zero disables it
+	Cache       *ResultCache  // optional; identical prepared requests are served from it
+	// Capabilities is what the binary reported at construction; nil when
+	// unknown, in which case requests are sent unchecked.
+	Capabilities *Capabilities
+
+	fingerprintOnce   sync.Once
+	fingerprint       string
+	unknownFieldsOnce sync.Once
 }
 
 // Compile-time check to ensure Runner implements RunnerInterface
@@ -27,11 +51,12 @@ var _ RunnerInterface = (*Runner)(nil)
 
 // NewRunner creates a new simulator runner.
 // Search order:
-// 1. --sim-path override
+// 1. --sim-path override (a path or a registered simulator name)
 // 2. ENV var
-// 3. Local directory
-// 4. Dev target
-// 5. Global PATH
+// 3. Default registered simulator
+// 4. Local directory
+// 5. Dev target
+// 6. Global PATH
 func NewRunner(simPathOverride string, debug bool) (*Runner, error) {
 	path, source, err := findSimBinary(simPathOverride)
 	if err != nil {
@@ -46,10 +71,37 @@ func NewRunner(simPathOverride string, debug bool) (*Runner, error) {
 		)
 	}
 
-	return &Runner{
-		BinaryPath: path,
-		Debug:      debug,
-	}, nil
+	r := &Runner{
+		BinaryPath:  path,
+		Debug:       debug,
+		Timeout:     DefaultRunTimeout,
+		MemoryLimit: DefaultMemoryLimit,
+	}
+	r.applyLimitsFromEnv()
+	r.loadCapabilities()
+	return r, nil
+}
+
+// applyLimitsFromEnv lets ERST_SIM_TIMEOUT (a Go duration such as "30s", or
+// "0" to disable) and ERST_SIM_MEMORY_MB ("0" to disable) override the default
+// run limits.
+func (r *Runner) applyLimitsFromEnv() {
+	if v := os.Getenv("ERST_SIM_TIMEOUT"); v != "" {
+		if v == "0" {
+			r.Timeout = 0
+		} else if d, err := time.ParseDuration(v); err == nil {
+			r.Timeout = d
+		} else {
+			logger.Logger.Warn("Ignoring invalid ERST_SIM_TIMEOUT", "value", v, "error", err)
+		}
+	}
+	if v := os.Getenv("ERST_SIM_MEMORY_MB"); v != "" {
+		if mb, err := strconv.ParseUint(v, 10, 64); err == nil {
+			r.MemoryLimit = mb << 20
+		} else {
+			logger.Logger.Warn("Ignoring invalid ERST_SIM_MEMORY_MB", "value", v, "error", err)
+		}
+	}
 }
 
 // NewRunnerWithMockTime creates a Runner that overrides the ledger timestamp on
@@ -72,6 +124,9 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		if isExecutable(simPathOverride) {
 			return abs(simPathOverride), "flag --sim-path", nil
 		}
+		if p, ok := registeredSimPath(simPathOverride); ok && isExecutable(p) {
+			return p, "registered simulator " + simPathOverride, nil
+		}
 		return "", "", errors.WrapSimulatorNotFound(simPathOverride)
 	}
 
@@ -82,7 +137,12 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		}
 	}
 
-	// 3. Local directory
+	// 3. Default registered simulator (erst sim use)
+	if p, ok := registeredDefaultSim(); ok {
+		return p, "registered default", nil
+	}
+
+	// 4. Local directory
 	cwd, err := os.Getwd()
 	if err == nil {
 		localCandidates := []string{
@@ -97,7 +157,7 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		}
 	}
 
-	// 4. Dev target
+	// 5. Dev target
 	devCandidates := []string{
 		filepath.Join("simulator", "target", "debug", "erst-sim"),
 		filepath.Join("simulator", "target", "release", "erst-sim"),
@@ -109,7 +169,7 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		}
 	}
 
-	// 5. Global PATH
+	// 6. Global PATH
 	if p, err := exec.LookPath("erst-sim"); err == nil {
 		return p, "global PATH", nil
 	}
@@ -140,21 +200,33 @@ func abs(path string) string {
 
 // -------------------- Execution --------------------
 
+// Run executes req with no caller-supplied context. The runner's own Timeout
+// and MemoryLimit still apply.
 func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
-	proto := GetOrDefault(req.ProtocolVersion)
-
-	if req.ProtocolVersion != nil {
-		if err := Validate(*req.ProtocolVersion); err != nil {
-			return nil, err
-		}
-	}
+	return r.RunContext(context.Background(), req)
+}
 
-	if err := r.applyProtocolConfig(req, proto); err != nil {
+// RunContext executes req in a fresh erst-sim process. The process is killed
+// when ctx is cancelled or the runner's Timeout elapses, and its address space
+// is capped at MemoryLimit bytes where the platform supports it. Failures are
+// reported as ErrSimTimeout, ErrSimOutOfMemory, ErrSimKilled or ErrSimCrash.
+func (r *Runner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	proto, err := r.prepareRequest(req)
+	if err != nil {
 		return nil, err
 	}
 
-	if r.MockTime != 0 {
-		req.Timestamp = r.MockTime
+	cacheKey := r.resultCacheKey(req)
+	if cacheKey != "" {
+		if cached, ok, err := r.Cache.Get(cacheKey); err != nil {
+			logger.Logger.Warn("Simulation cache lookup failed", "error", err)
+		} else if ok {
+			if r.Debug {
+				logger.Logger.Debug("Simulation cache hit", "key", cacheKey)
+			}
+			cached.ProtocolVersion = &proto.Version
+			return cached, nil
+		}
 	}
 
 	inputBytes, err := json.Marshal(req)
@@ -163,16 +235,22 @@ func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 		return nil, errors.WrapMarshalFailed(err)
 	}
 
-	cmd := exec.Command(r.BinaryPath)
-	cmd.Stdin = bytes.NewReader(inputBytes)
+	ctx, cancel, timeout := withRunTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.BinaryPath)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := r.startSim(cmd, inputBytes); err != nil {
+		return nil, errors.WrapSimCrash(err, "")
+	}
+
+	if err := cmd.Wait(); err != nil {
 		logger.Logger.Error("Simulator execution failed", "error", err, "stderr", stderr.String())
-		return nil, errors.WrapSimCrash(err, stderr.String())
+		return nil, r.classifyFailure(ctx, timeout, err, cmd.ProcessState, stderr.String())
 	}
 
 	var resp SimulationResponse
@@ -180,12 +258,180 @@ func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 		logger.Logger.Error("Failed to unmarshal response", "error", err)
 		return nil, errors.WrapUnmarshalFailed(err, stdout.String())
 	}
+	r.warnUnknownFields(stdout.Bytes())
 
 	resp.ProtocolVersion = &proto.Version
 
+	if cacheKey != "" {
+		if err := r.Cache.Put(cacheKey, &resp); err != nil {
+			logger.Logger.Warn("Failed to store simulation result in cache", "error", err)
+		}
+	}
+
 	return &resp, nil
 }
 
+// resultCacheKey returns the cache key for a prepared request, or "" when
+// caching is disabled or the simulator binary cannot be fingerprinted.
+func (r *Runner) resultCacheKey(req *SimulationRequest) string {
+	if r.Cache == nil {
+		return ""
+	}
+
+	r.fingerprintOnce.Do(func() {
+		fp, err := fileSHA256(r.BinaryPath)
+		if err != nil {
+			logger.Logger.Warn("Disabling simulation cache: cannot fingerprint simulator", "path", r.BinaryPath, "error", err)
+			return
+		}
+		r.fingerprint = fp
+	})
+	if r.fingerprint == "" {
+		return ""
+	}
+
+	key, err := ResultCacheKey(req, r.fingerprint)
+	if err != nil {
+		logger.Logger.Warn("Failed to derive simulation cache key", "error", err)
+		return ""
+	}
+	return key
+}
+
+// startSim starts cmd, applies the memory limit and writes input to its stdin.
+// The caller is responsible for wiring stdout and stderr and for calling Wait.
+func (r *Runner) startSim(cmd *exec.Cmd, input []byte) error {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if r.MemoryLimit > 0 {
+		if err := setMemoryLimit(cmd.Process.Pid, r.MemoryLimit); err != nil {
+			logger.Logger.Warn("Failed to apply simulator memory limit", "limit", r.MemoryLimit, "error", err)
+		}
+	}
+
+	// Write the request concurrently so a child that never reads stdin
+	// cannot block us past cancellation.
+	go func() {
+		_, _ = stdin.Write(input)
+		_ = stdin.Close()
+	}()
+
+	return nil
+}
+
+// withRunTimeout bounds ctx by limit, when positive, and returns how long the
+// run was given: limit, or what was left of ctx's own deadline when that
+// expires first. A timeout is reported with that duration, so a caller's
+// deadline is not mistaken for the runner's Timeout.
+func withRunTimeout(ctx context.Context, limit time.Duration) (context.Context, context.CancelFunc, time.Duration) {
+	timeout := limit
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline).Round(time.Millisecond); limit <= 0 || left < limit {
+			timeout = left
+		}
+	}
+	if limit <= 0 {
+		return ctx, func() {}, timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, limit)
+	return ctx, cancel, timeout
+}
+
+// classifyFailure maps a failed erst-sim execution onto the typed simulator
+// errors so callers can tell runaway contracts apart from genuine crashes.
+// timeout is the run's deadline as returned by withRunTimeout.
+func (r *Runner) classifyFailure(ctx context.Context, timeout time.Duration, err error, state *os.ProcessState, stderr string) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return errors.WrapSimTimeout(timeout)
+	case context.Canceled:
+		return errors.WrapSimKilled(ctx.Err(), stderr)
+	}
+
+	if r.MemoryLimit > 0 && isAllocationFailure(stderr) {
+		return errors.WrapSimOutOfMemory(r.MemoryLimit, stderr)
+	}
+
+	if killedBySignal(state) {
+		return errors.WrapSimKilled(err, stderr)
+	}
+
+	return errors.WrapSimCrash(err, stderr)
+}
+
+// Failure kinds returned by ClassifyRunError.
+const (
+	FailureTimeout = "timeout"
+	FailureOOM     = "oom"
+	FailureKilled  = "killed"
+	FailureCrash   = "crash"
+)
+
+// ClassifyRunError reduces an error returned by RunContext to one of the
+// Failure* kinds so harnesses can bucket results without string matching.
+func ClassifyRunError(err error) string {
+	switch {
+	case errors.Is(err, errors.ErrSimTimeout):
+		return FailureTimeout
+	case errors.Is(err, errors.ErrSimOutOfMemory):
+		return FailureOOM
+	case errors.Is(err, errors.ErrSimKilled):
+		return FailureKilled
+	default:
+		return FailureCrash
+	}
+}
+
+// isAllocationFailure recognises the messages Rust and libc print when an
+// allocation is refused under RLIMIT_AS.
+func isAllocationFailure(stderr string) bool {
+	for _, marker := range []string{"memory allocation of", "out of memory", "Cannot allocate memory"} {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareRequest validates the requested protocol version, applies its limits
+// and calibration to req (overridden by on-chain network settings and then by
+// a custom gas model when the request carries them), enforces the mock-time
+// override if set, and checks the result against the binary's capabilities.
+// Every execution path goes through it so erst-sim always receives identical
+// input.
+func (r *Runner) prepareRequest(req *SimulationRequest) (*Protocol, error) {
+	proto := GetOrDefault(req.ProtocolVersion)
+
+	if req.ProtocolVersion != nil {
+		if err := Validate(*req.ProtocolVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.applyProtocolConfig(req, proto); err != nil {
+		return nil, err
+	}
+	applyNetworkSettings(req)
+	applyGasModel(req)
+
+	if r.MockTime != 0 {
+		req.Timestamp = r.MockTime
+	}
+
+	if err := r.negotiate(req, proto); err != nil {
+		return nil, err
+	}
+
+	return proto, nil
+}
+
 func (r *Runner) applyProtocolConfig(req *SimulationRequest, proto *Protocol) error {
 	if req.CustomAuthCfg == nil {
 		req.CustomAuthCfg = make(map[string]interface{})
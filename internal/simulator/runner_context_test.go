@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSim writes a shell script standing in for erst-sim and returns a Runner
+// pointing at it.
+func fakeSim(t *testing.T, script string) *Runner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell-script simulator stand-ins require a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "erst-sim")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	return &Runner{BinaryPath: path}
+}
+
+func TestRunContext_Success(t *testing.T) {
+	runner := fakeSim(t, `cat >/dev/null; echo '{"status":"success"}'`)
+
+	resp, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+}
+
+func TestRunContext_Timeout(t *testing.T) {
+	runner := fakeSim(t, `exec sleep 30`)
+	runner.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimTimeout))
+	assert.Equal(t, FailureTimeout, ClassifyRunError(err))
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestRunContext_CallerDeadline(t *testing.T) {
+	runner := fakeSim(t, `exec sleep 30`)
+	runner.Timeout = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := runner.RunContext(ctx, &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimTimeout))
+	assert.NotContains(t, err.Error(), "1m0s", "the caller's deadline fired, not the runner's")
+	assert.Contains(t, err.Error(), "ms")
+}
+
+func TestRunContext_Cancelled(t *testing.T) {
+	runner := fakeSim(t, `exec sleep 30`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := runner.RunContext(ctx, &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimKilled))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRunContext_OutOfMemory(t *testing.T) {
+	runner := fakeSim(t, `echo "memory allocation of 1073741824 bytes failed" >&2; exit 134`)
+	runner.MemoryLimit = 512 << 20
+
+	_, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimOutOfMemory))
+	assert.Equal(t, FailureOOM, ClassifyRunError(err))
+}
+
+func TestRunContext_KilledBySignal(t *testing.T) {
+	runner := fakeSim(t, `kill -9 $$`)
+
+	_, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimKilled))
+}
+
+func TestRunContext_Crash(t *testing.T) {
+	runner := fakeSim(t, `echo "panicked at host.rs" >&2; exit 101`)
+
+	_, err := runner.RunContext(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimCrash))
+	assert.Contains(t, err.Error(), "panicked at host.rs")
+	assert.Equal(t, FailureCrash, ClassifyRunError(err))
+}
+
+func TestPool_RunContextCancelRespawnsWorker(t *testing.T) {
+	pool := newTestPool(t, PoolConfig{Size: 1})
+
+	before, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pool.RunContext(ctx, &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errors.ErrSimKilled))
+
+	after, err := pool.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, before.Logs)
+	assert.NotEmpty(t, after.Logs)
+}
+
+func TestApplyLimitsFromEnv(t *testing.T) {
+	t.Setenv("ERST_SIM_TIMEOUT", "45s")
+	t.Setenv("ERST_SIM_MEMORY_MB", "256")
+
+	r := &Runner{Timeout: DefaultRunTimeout}
+	r.applyLimitsFromEnv()
+	assert.Equal(t, 45*time.Second, r.Timeout)
+	assert.Equal(t, uint64(256<<20), r.MemoryLimit)
+
+	t.Setenv("ERST_SIM_TIMEOUT", "0")
+	t.Setenv("ERST_SIM_MEMORY_MB", "0")
+	r.applyLimitsFromEnv()
+	assert.Zero(t, r.Timeout)
+	assert.Zero(t, r.MemoryLimit)
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/gasmodel"
+	"github.com/dotandev/hintents/internal/rpc"
 	_ "modernc.org/sqlite"
 )
 
@@ -21,15 +23,34 @@ type SimulationRequest struct {
 	Timestamp       int64             `json:"timestamp,omitempty"`
 	LedgerSequence  uint32            `json:"ledger_sequence,omitempty"`
 	WasmPath        *string           `json:"wasm_path,omitempty"`
+	ContractWasm    *string           `json:"contract_wasm,omitempty"` // base64 WASM, sent in place of WasmPath to a remote simulator
 	MockArgs        *[]string         `json:"mock_args,omitempty"`
 	Profile         bool              `json:"profile,omitempty"`
 	ProtocolVersion *uint32           `json:"protocol_version,omitempty"`
 	MockBaseFee     *uint32           `json:"mock_base_fee,omitempty"`
 	MockGasPrice    *uint64           `json:"mock_gas_price,omitempty"`
 
+	// BaseReserve and NetworkPassphrase complete, with LedgerSequence,
+	// Timestamp and ProtocolVersion, the ledger info erst-sim gives the host;
+	// see PinLedgerContext. BaseFee is the ledger's base fee for fee reports.
+	BaseFee           uint32 `json:"base_fee,omitempty"`
+	BaseReserve       uint32 `json:"base_reserve,omitempty"`
+	NetworkPassphrase string `json:"network_passphrase,omitempty"`
+
 	AuthTraceOpts       *AuthTraceOptions      `json:"auth_trace_opts,omitempty"`
 	CustomAuthCfg       map[string]interface{} `json:"custom_auth_config,omitempty"`
 	ResourceCalibration *ResourceCalibration   `json:"resource_calibration,omitempty"`
+	BudgetLimits        *BudgetLimits          `json:"budget_limits,omitempty"`
+	CostParams          []CostParam            `json:"cost_params,omitempty"`
+
+	// NetworkSettings, when set, replaces the protocol's static limits and
+	// calibration with the values read from the network's CONFIG_SETTING
+	// entries. It is folded into the fields above by prepareRequest.
+	NetworkSettings *rpc.NetworkSettings `json:"-"`
+
+	// GasModel, when set, overrides limits and calibration with the costs of
+	// a custom gas model file. prepareRequest applies it after NetworkSettings.
+	GasModel *gasmodel.GasModel `json:"-"`
 }
 
 type ResourceCalibration struct {
@@ -40,6 +61,21 @@ type ResourceCalibration struct {
 	Ed25519Fixed     uint64 `json:"ed25519_fixed"`
 }
 
+// BudgetLimits replaces the CPU instruction and memory limits of the host
+// budget erst-sim runs the transaction with. Zero keeps the host default.
+type BudgetLimits struct {
+	CPUInstructions uint64 `json:"cpu_instructions,omitempty"`
+	MemoryBytes     uint64 `json:"memory_bytes,omitempty"`
+}
+
+// CostParam replaces the CPU cost model of one host cost type. CostType is the
+// ContractCostType name without its prefix, e.g. "WasmInsnExec".
+type CostParam struct {
+	CostType   string `json:"cost_type"`
+	ConstTerm  uint64 `json:"const_term"`
+	LinearTerm uint64 `json:"linear_term"`
+}
+
 type AuthTraceOptions struct {
 	Enabled              bool `json:"enabled"`
 	TraceCustomContracts bool `json:"trace_custom_contracts"`
@@ -82,6 +118,15 @@ type SimulationResponse struct {
 	StackTrace        *WasmStackTrace      `json:"stack_trace,omitempty"`      // Enhanced WASM stack trace on traps
 	SourceLocation    string               `json:"source_location,omitempty"`
 	WasmOffset        *uint64              `json:"wasm_offset,omitempty"`
+	// LedgerAccess lists the ledger keys the host touched. erst-sim builds
+	// with the ledger_access feature record it; older builds leave it nil.
+	LedgerAccess *LedgerAccess `json:"ledger_access,omitempty"`
+}
+
+// LedgerAccess holds base64 LedgerKeys read and written during execution.
+type LedgerAccess struct {
+	Read    []string `json:"read"`
+	Written []string `json:"written"`
 }
 
 type CategorizedEvent struct {
@@ -0,0 +1,563 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+const (
+	// maxMutationDepth stops recursion into nested Vec/Map/UDT values.
+	maxMutationDepth = 4
+	// hugeCollectionLen and hugeBytesLen size the "huge" boundary values.
+	hugeCollectionLen = 512
+	hugeBytesLen      = 64 * 1024
+	maxSymbolLen      = 32
+)
+
+const symbolChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+
+// ScValMutator mutates the arguments of an InvokeContract call as typed
+// values instead of raw bytes, so mutated envelopes still decode and reach
+// contract code. Argument types come from the contract spec when one is
+// available, otherwise they are inferred from the original values.
+type ScValMutator struct {
+	Spec *ContractSpec
+}
+
+// NewScValMutator returns a mutator driven by spec, which may be nil.
+func NewScValMutator(spec *ContractSpec) *ScValMutator {
+	return &ScValMutator{Spec: spec}
+}
+
+// MutateEnvelope decodes a hex or base64 TransactionEnvelope, mutates the
+// arguments of every InvokeContract operation and returns it base64-encoded,
+// as SimulationRequest.EnvelopeXdr expects.
+func (m *ScValMutator) MutateEnvelope(envelope string, rng *rand.Rand) (string, error) {
+	var env xdr.TransactionEnvelope
+	if raw, err := hex.DecodeString(envelope); err == nil {
+		if err := env.UnmarshalBinary(raw); err != nil {
+			return "", errors.WrapUnmarshalFailed(err, "transaction envelope")
+		}
+	} else if err := xdr.SafeUnmarshalBase64(envelope, &env); err != nil {
+		return "", errors.WrapUnmarshalFailed(err, "transaction envelope")
+	}
+
+	found := false
+	for _, op := range envelopeOperations(env) {
+		if op.Body.Type != xdr.OperationTypeInvokeHostFunction || op.Body.InvokeHostFunctionOp == nil {
+			continue
+		}
+		fn := op.Body.InvokeHostFunctionOp.HostFunction
+		if fn.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract || fn.InvokeContract == nil {
+			continue
+		}
+		found = true
+		fn.InvokeContract.Args = m.MutateArgs(string(fn.InvokeContract.FunctionName), fn.InvokeContract.Args, rng)
+	}
+	if !found {
+		return "", errors.WrapSimulationLogicError("no InvokeContract operation found in transaction")
+	}
+
+	raw, err := env.MarshalBinary()
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func envelopeOperations(env xdr.TransactionEnvelope) []xdr.Operation {
+	switch {
+	case env.IsFeeBump():
+		return env.FeeBump.Tx.InnerTx.V1.Tx.Operations
+	case env.V1 != nil:
+		return env.V1.Tx.Operations
+	case env.V0 != nil:
+		return env.V0.Tx.Operations
+	}
+	return nil
+}
+
+// MutateArgs returns a copy of args for a call to function fn with at least
+// one argument mutated according to its declared type.
+func (m *ScValMutator) MutateArgs(fn string, args []xdr.ScVal, rng *rand.Rand) []xdr.ScVal {
+	var inputs []xdr.ScSpecFunctionInputV0
+	if m.Spec != nil {
+		if f, ok := m.Spec.Functions[fn]; ok {
+			inputs = f.Inputs
+		}
+	}
+
+	out := make([]xdr.ScVal, len(args))
+	copy(out, args)
+	// The spec may declare arguments the original call omitted.
+	for len(out) < len(inputs) {
+		out = append(out, m.generate(inputs[len(out)].Type, rng, 0))
+	}
+	if len(out) == 0 {
+		return out
+	}
+
+	forced := rng.Intn(len(out))
+	for i := range out {
+		if i != forced && rng.Float64() >= 0.5 {
+			continue
+		}
+		if i < len(inputs) {
+			out[i] = m.mutate(inputs[i].Type, out[i], rng, 0)
+		} else {
+			out[i] = m.mutate(typeOfValue(out[i]), out[i], rng, 0)
+		}
+	}
+	return out
+}
+
+// mutate derives a new value of type typ from cur: a boundary value, a fresh
+// random value, or a small change to cur.
+func (m *ScValMutator) mutate(typ xdr.ScSpecTypeDef, cur xdr.ScVal, rng *rand.Rand, depth int) xdr.ScVal {
+	switch typ.Type {
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if vec, ok := cur.GetVec(); ok && vec != nil && len(*vec) > 0 && depth < maxMutationDepth && rng.Intn(2) == 0 {
+			items := append([]xdr.ScVal{}, (*vec)...)
+			i := rng.Intn(len(items))
+			switch rng.Intn(3) {
+			case 0:
+				items[i] = m.mutate(typ.Vec.ElementType, items[i], rng, depth+1)
+			case 1:
+				items = append(items[:i], items[i+1:]...)
+			default:
+				items = append(items, m.generate(typ.Vec.ElementType, rng, depth+1))
+			}
+			return scvVec(items)
+		}
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if mp, ok := cur.GetMap(); ok && mp != nil && len(*mp) > 0 && depth < maxMutationDepth && rng.Intn(2) == 0 {
+			entries := append([]xdr.ScMapEntry{}, (*mp)...)
+			i := rng.Intn(len(entries))
+			entries[i].Val = m.mutate(typ.Map.ValueType, entries[i].Val, rng, depth+1)
+			return scvMap(entries)
+		}
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if st, ok := m.structType(typ); ok && depth < maxMutationDepth {
+			if mp, ok := cur.GetMap(); ok && mp != nil && len(*mp) == len(st.Fields) {
+				entries := append([]xdr.ScMapEntry{}, (*mp)...)
+				i := rng.Intn(len(entries))
+				entries[i].Val = m.mutate(st.Fields[i].Type, entries[i].Val, rng, depth+1)
+				return scvMap(entries)
+			}
+		}
+	}
+	return m.generate(typ, rng, depth)
+}
+
+// generate produces a value of type typ, biased towards boundary values.
+func (m *ScValMutator) generate(typ xdr.ScSpecTypeDef, rng *rand.Rand, depth int) xdr.ScVal {
+	switch typ.Type {
+	case xdr.ScSpecTypeScSpecTypeBool:
+		return scvBool(rng.Intn(2) == 0)
+	case xdr.ScSpecTypeScSpecTypeVoid, xdr.ScSpecTypeScSpecTypeResult, xdr.ScSpecTypeScSpecTypeError:
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	case xdr.ScSpecTypeScSpecTypeU32:
+		return scvU32(uint32(pickUnsigned(rng, math.MaxUint32)))
+	case xdr.ScSpecTypeScSpecTypeI32:
+		return scvI32(int32(pickSigned(rng, math.MinInt32, math.MaxInt32)))
+	case xdr.ScSpecTypeScSpecTypeU64:
+		return scvU64(pickUnsigned(rng, math.MaxUint64))
+	case xdr.ScSpecTypeScSpecTypeI64:
+		return scvI64(pickSigned(rng, math.MinInt64, math.MaxInt64))
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		v := xdr.TimePoint(pickUnsigned(rng, math.MaxUint64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &v}
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		v := xdr.Duration(pickUnsigned(rng, math.MaxUint64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &v}
+	case xdr.ScSpecTypeScSpecTypeU128:
+		return scvU128(pickU128(rng))
+	case xdr.ScSpecTypeScSpecTypeI128:
+		return scvI128(pickI128(rng))
+	case xdr.ScSpecTypeScSpecTypeU256:
+		return scvU256(pickU256(rng))
+	case xdr.ScSpecTypeScSpecTypeI256:
+		return scvI256(pickI256(rng))
+	case xdr.ScSpecTypeScSpecTypeBytes:
+		return scvBytes(pickBytes(rng, -1, depth == 0))
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		return scvBytes(pickBytes(rng, int(typ.BytesN.N), false))
+	case xdr.ScSpecTypeScSpecTypeString:
+		s := xdr.ScString(pickString(rng, depth == 0))
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		return scvSymbol(pickSymbol(rng))
+	case xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		return scvAddress(randomAddress(rng))
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if rng.Intn(3) == 0 {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+		}
+		return m.generate(typ.Option.ValueType, rng, depth)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		return scvVec(m.generateItems(typ.Vec.ElementType, rng, depth))
+	case xdr.ScSpecTypeScSpecTypeMap:
+		keys := m.generateItems(typ.Map.KeyType, rng, depth)
+		entries := make([]xdr.ScMapEntry, len(keys))
+		for i, k := range keys {
+			entries[i] = xdr.ScMapEntry{Key: k, Val: m.generate(typ.Map.ValueType, rng, depth+1)}
+		}
+		return scvMap(entries)
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		items := make([]xdr.ScVal, len(typ.Tuple.ValueTypes))
+		for i, t := range typ.Tuple.ValueTypes {
+			items[i] = m.generate(t, rng, depth+1)
+		}
+		return scvVec(items)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return m.generateUdt(typ.Udt.Name, rng, depth)
+	}
+	return m.generateAny(rng, depth)
+}
+
+// generateItems returns an empty, small or huge list of values; huge lists
+// are only produced at the top level to keep envelopes within size limits.
+func (m *ScValMutator) generateItems(elem xdr.ScSpecTypeDef, rng *rand.Rand, depth int) []xdr.ScVal {
+	if depth >= maxMutationDepth {
+		return nil
+	}
+	var n int
+	switch r := rng.Intn(8); {
+	case r == 0:
+		n = 0
+	case r == 1 && depth == 0:
+		n = hugeCollectionLen
+	default:
+		n = 1 + rng.Intn(4)
+	}
+	items := make([]xdr.ScVal, n)
+	for i := range items {
+		items[i] = m.generate(elem, rng, depth+1)
+	}
+	return items
+}
+
+func (m *ScValMutator) structType(typ xdr.ScSpecTypeDef) (xdr.ScSpecUdtStructV0, bool) {
+	if m.Spec == nil || typ.Udt == nil {
+		return xdr.ScSpecUdtStructV0{}, false
+	}
+	st, ok := m.Spec.Structs[typ.Udt.Name]
+	return st, ok
+}
+
+// generateUdt encodes user-defined types the way soroban-sdk does: structs as
+// maps keyed by field name (or vecs for tuple structs), unions as a vec of
+// the case symbol followed by its values, and enums as their u32 value.
+func (m *ScValMutator) generateUdt(name string, rng *rand.Rand, depth int) xdr.ScVal {
+	if m.Spec == nil || depth >= maxMutationDepth {
+		return m.generateAny(rng, depth)
+	}
+
+	if st, ok := m.Spec.Structs[name]; ok {
+		tuple := len(st.Fields) > 0 && st.Fields[0].Name == "0"
+		if tuple {
+			items := make([]xdr.ScVal, len(st.Fields))
+			for i, f := range st.Fields {
+				items[i] = m.generate(f.Type, rng, depth+1)
+			}
+			return scvVec(items)
+		}
+		entries := make([]xdr.ScMapEntry, len(st.Fields))
+		for i, f := range st.Fields {
+			entries[i] = xdr.ScMapEntry{Key: scvSymbol(f.Name), Val: m.generate(f.Type, rng, depth+1)}
+		}
+		return scvMap(entries)
+	}
+
+	if un, ok := m.Spec.Unions[name]; ok && len(un.Cases) > 0 {
+		c := un.Cases[rng.Intn(len(un.Cases))]
+		if c.VoidCase != nil {
+			return scvVec([]xdr.ScVal{scvSymbol(c.VoidCase.Name)})
+		}
+		items := []xdr.ScVal{scvSymbol(c.TupleCase.Name)}
+		for _, t := range c.TupleCase.Type {
+			items = append(items, m.generate(t, rng, depth+1))
+		}
+		return scvVec(items)
+	}
+
+	if en, ok := m.Spec.Enums[name]; ok && len(en.Cases) > 0 && rng.Intn(4) != 0 {
+		return scvU32(uint32(en.Cases[rng.Intn(len(en.Cases))].Value))
+	}
+	if en, ok := m.Spec.ErrorEnums[name]; ok && len(en.Cases) > 0 && rng.Intn(4) != 0 {
+		return scvU32(uint32(en.Cases[rng.Intn(len(en.Cases))].Value))
+	}
+	// Out-of-range discriminants exercise the contract's own validation.
+	return scvU32(uint32(pickUnsigned(rng, math.MaxUint32)))
+}
+
+// generateAny produces a value of a random type, for untyped (Val) arguments.
+func (m *ScValMutator) generateAny(rng *rand.Rand, depth int) xdr.ScVal {
+	types := []xdr.ScSpecType{
+		xdr.ScSpecTypeScSpecTypeBool, xdr.ScSpecTypeScSpecTypeU32, xdr.ScSpecTypeScSpecTypeI64,
+		xdr.ScSpecTypeScSpecTypeI128, xdr.ScSpecTypeScSpecTypeBytes, xdr.ScSpecTypeScSpecTypeSymbol,
+		xdr.ScSpecTypeScSpecTypeAddress,
+	}
+	return m.generate(xdr.ScSpecTypeDef{Type: types[rng.Intn(len(types))]}, rng, depth)
+}
+
+// typeOfValue infers a spec type from an existing value, used when the
+// contract spec is unavailable or does not cover an argument.
+func typeOfValue(v xdr.ScVal) xdr.ScSpecTypeDef {
+	simple := map[xdr.ScValType]xdr.ScSpecType{
+		xdr.ScValTypeScvBool:      xdr.ScSpecTypeScSpecTypeBool,
+		xdr.ScValTypeScvVoid:      xdr.ScSpecTypeScSpecTypeVoid,
+		xdr.ScValTypeScvU32:       xdr.ScSpecTypeScSpecTypeU32,
+		xdr.ScValTypeScvI32:       xdr.ScSpecTypeScSpecTypeI32,
+		xdr.ScValTypeScvU64:       xdr.ScSpecTypeScSpecTypeU64,
+		xdr.ScValTypeScvI64:       xdr.ScSpecTypeScSpecTypeI64,
+		xdr.ScValTypeScvTimepoint: xdr.ScSpecTypeScSpecTypeTimepoint,
+		xdr.ScValTypeScvDuration:  xdr.ScSpecTypeScSpecTypeDuration,
+		xdr.ScValTypeScvU128:      xdr.ScSpecTypeScSpecTypeU128,
+		xdr.ScValTypeScvI128:      xdr.ScSpecTypeScSpecTypeI128,
+		xdr.ScValTypeScvU256:      xdr.ScSpecTypeScSpecTypeU256,
+		xdr.ScValTypeScvI256:      xdr.ScSpecTypeScSpecTypeI256,
+		xdr.ScValTypeScvBytes:     xdr.ScSpecTypeScSpecTypeBytes,
+		xdr.ScValTypeScvString:    xdr.ScSpecTypeScSpecTypeString,
+		xdr.ScValTypeScvSymbol:    xdr.ScSpecTypeScSpecTypeSymbol,
+		xdr.ScValTypeScvAddress:   xdr.ScSpecTypeScSpecTypeAddress,
+	}
+	if t, ok := simple[v.Type]; ok {
+		return xdr.ScSpecTypeDef{Type: t}
+	}
+
+	anyType := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVal}
+	switch v.Type {
+	case xdr.ScValTypeScvVec:
+		elem := anyType
+		if vec, ok := v.GetVec(); ok && vec != nil && len(*vec) > 0 {
+			elem = typeOfValue((*vec)[0])
+		}
+		return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: elem}}
+	case xdr.ScValTypeScvMap:
+		key, val := anyType, anyType
+		if mp, ok := v.GetMap(); ok && mp != nil && len(*mp) > 0 {
+			key, val = typeOfValue((*mp)[0].Key), typeOfValue((*mp)[0].Val)
+		}
+		return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeMap, Map: &xdr.ScSpecTypeMap{KeyType: key, ValueType: val}}
+	}
+	return anyType
+}
+
+func pickUnsigned(rng *rand.Rand, max uint64) uint64 {
+	boundaries := []uint64{0, 1, 2, max, max - 1, max / 2, max/2 + 1}
+	if rng.Intn(3) != 0 {
+		return boundaries[rng.Intn(len(boundaries))]
+	}
+	return rng.Uint64() & max
+}
+
+func pickSigned(rng *rand.Rand, min, max int64) int64 {
+	boundaries := []int64{0, 1, -1, min, max, min + 1, max - 1}
+	if rng.Intn(3) != 0 {
+		return boundaries[rng.Intn(len(boundaries))]
+	}
+	v := int64(rng.Uint64())
+	if v < min || v > max {
+		v = v % max
+	}
+	return v
+}
+
+func pickU128(rng *rand.Rand) xdr.UInt128Parts {
+	boundaries := []xdr.UInt128Parts{
+		{Hi: 0, Lo: 0},
+		{Hi: 0, Lo: 1},
+		{Hi: 0, Lo: math.MaxUint64},
+		{Hi: 1, Lo: 0},
+		{Hi: math.MaxUint64, Lo: math.MaxUint64},
+		{Hi: math.MaxUint64, Lo: math.MaxUint64 - 1},
+		{Hi: math.MaxInt64, Lo: math.MaxUint64},
+	}
+	if rng.Intn(3) != 0 {
+		return boundaries[rng.Intn(len(boundaries))]
+	}
+	return xdr.UInt128Parts{Hi: xdr.Uint64(rng.Uint64()), Lo: xdr.Uint64(rng.Uint64())}
+}
+
+func pickI128(rng *rand.Rand) xdr.Int128Parts {
+	boundaries := []xdr.Int128Parts{
+		{Hi: 0, Lo: 0},
+		{Hi: 0, Lo: 1},
+		{Hi: -1, Lo: math.MaxUint64}, // -1
+		{Hi: math.MaxInt64, Lo: math.MaxUint64},
+		{Hi: math.MaxInt64, Lo: math.MaxUint64 - 1},
+		{Hi: math.MinInt64, Lo: 0},
+		{Hi: math.MinInt64, Lo: 1},
+		{Hi: 0, Lo: math.MaxUint64},
+		{Hi: -1, Lo: 0},
+	}
+	if rng.Intn(3) != 0 {
+		return boundaries[rng.Intn(len(boundaries))]
+	}
+	return xdr.Int128Parts{Hi: xdr.Int64(rng.Uint64()), Lo: xdr.Uint64(rng.Uint64())}
+}
+
+func pickU256(rng *rand.Rand) xdr.UInt256Parts {
+	switch rng.Intn(4) {
+	case 0:
+		return xdr.UInt256Parts{}
+	case 1:
+		return xdr.UInt256Parts{LoLo: 1}
+	case 2:
+		return xdr.UInt256Parts{HiHi: math.MaxUint64, HiLo: math.MaxUint64, LoHi: math.MaxUint64, LoLo: math.MaxUint64}
+	}
+	return xdr.UInt256Parts{HiHi: xdr.Uint64(rng.Uint64()), HiLo: xdr.Uint64(rng.Uint64()), LoHi: xdr.Uint64(rng.Uint64()), LoLo: xdr.Uint64(rng.Uint64())}
+}
+
+func pickI256(rng *rand.Rand) xdr.Int256Parts {
+	switch rng.Intn(5) {
+	case 0:
+		return xdr.Int256Parts{}
+	case 1:
+		return xdr.Int256Parts{HiHi: -1, HiLo: math.MaxUint64, LoHi: math.MaxUint64, LoLo: math.MaxUint64}
+	case 2:
+		return xdr.Int256Parts{HiHi: math.MaxInt64, HiLo: math.MaxUint64, LoHi: math.MaxUint64, LoLo: math.MaxUint64}
+	case 3:
+		return xdr.Int256Parts{HiHi: math.MinInt64}
+	}
+	return xdr.Int256Parts{HiHi: xdr.Int64(rng.Uint64()), HiLo: xdr.Uint64(rng.Uint64()), LoHi: xdr.Uint64(rng.Uint64()), LoLo: xdr.Uint64(rng.Uint64())}
+}
+
+// pickBytes returns n bytes, or for n < 0 an empty, small or (if allowed)
+// huge slice. Huge values are limited to top-level arguments so that nested
+// collections cannot multiply them into an oversized envelope.
+func pickBytes(rng *rand.Rand, n int, allowHuge bool) []byte {
+	if n < 0 {
+		switch rng.Intn(4) {
+		case 0:
+			n = 0
+		case 1:
+			if allowHuge {
+				n = hugeBytesLen
+			}
+		default:
+			n = 1 + rng.Intn(64)
+		}
+	}
+	b := make([]byte, n)
+	switch rng.Intn(3) {
+	case 0: // all zero
+	case 1:
+		for i := range b {
+			b[i] = 0xff
+		}
+	default:
+		rng.Read(b)
+	}
+	return b
+}
+
+func pickString(rng *rand.Rand, allowHuge bool) string {
+	switch rng.Intn(5) {
+	case 0:
+		return ""
+	case 1:
+		if allowHuge {
+			return strings.Repeat("A", hugeBytesLen)
+		}
+		return strings.Repeat("A", maxSymbolLen+1)
+	case 2:
+		return "é中\U0001F600\x00"
+	}
+	return pickSymbol(rng)
+}
+
+// pickSymbol returns symbols at the length limits, and occasionally ones
+// with characters Soroban does not allow in symbols. Symbols longer than the
+// limit are not generated: XDR itself cannot carry them.
+func pickSymbol(rng *rand.Rand) string {
+	switch rng.Intn(6) {
+	case 0:
+		return ""
+	case 1:
+		return strings.Repeat("a", maxSymbolLen)
+	case 2:
+		return "_"
+	case 3:
+		return "bad-symbol!"
+	}
+	b := make([]byte, 1+rng.Intn(maxSymbolLen))
+	for i := range b {
+		b[i] = symbolChars[rng.Intn(len(symbolChars))]
+	}
+	return string(b)
+}
+
+func randomAddress(rng *rand.Rand) xdr.ScAddress {
+	var key xdr.Uint256
+	rng.Read(key[:])
+	if rng.Intn(2) == 0 {
+		id := xdr.ContractId(key)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+	}
+	account := xdr.AccountId{Type: xdr.PublicKeyTypePublicKeyTypeEd25519, Ed25519: &key}
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account}
+}
+
+func scvBool(v bool) xdr.ScVal { return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &v} }
+
+func scvU32(v uint32) xdr.ScVal {
+	x := xdr.Uint32(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &x}
+}
+
+func scvI32(v int32) xdr.ScVal {
+	x := xdr.Int32(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &x}
+}
+
+func scvU64(v uint64) xdr.ScVal {
+	x := xdr.Uint64(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &x}
+}
+
+func scvI64(v int64) xdr.ScVal {
+	x := xdr.Int64(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &x}
+}
+
+func scvU128(v xdr.UInt128Parts) xdr.ScVal { return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &v} }
+func scvI128(v xdr.Int128Parts) xdr.ScVal  { return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &v} }
+func scvU256(v xdr.UInt256Parts) xdr.ScVal { return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &v} }
+func scvI256(v xdr.Int256Parts) xdr.ScVal  { return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &v} }
+
+func scvBytes(v []byte) xdr.ScVal {
+	b := xdr.ScBytes(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}
+}
+
+func scvSymbol(v string) xdr.ScVal {
+	s := xdr.ScSymbol(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &s}
+}
+
+func scvAddress(v xdr.ScAddress) xdr.ScVal {
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &v}
+}
+
+func scvVec(items []xdr.ScVal) xdr.ScVal {
+	vec := xdr.ScVec(items)
+	p := &vec
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &p}
+}
+
+func scvMap(entries []xdr.ScMapEntry) xdr.ScVal {
+	mp := xdr.ScMap(entries)
+	p := &mp
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &p}
+}
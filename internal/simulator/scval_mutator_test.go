@@ -0,0 +1,230 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specType(t xdr.ScSpecType) xdr.ScSpecTypeDef { return xdr.ScSpecTypeDef{Type: t} }
+
+func testSpecEntries() []xdr.ScSpecEntry {
+	return []xdr.ScSpecEntry{
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name: "transfer",
+				Inputs: []xdr.ScSpecFunctionInputV0{
+					{Name: "from", Type: specType(xdr.ScSpecTypeScSpecTypeAddress)},
+					{Name: "to", Type: specType(xdr.ScSpecTypeScSpecTypeAddress)},
+					{Name: "amount", Type: specType(xdr.ScSpecTypeScSpecTypeI128)},
+				},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+			UdtStructV0: &xdr.ScSpecUdtStructV0{
+				Name: "Config",
+				Fields: []xdr.ScSpecUdtStructFieldV0{
+					{Name: "admin", Type: specType(xdr.ScSpecTypeScSpecTypeAddress)},
+					{Name: "limit", Type: specType(xdr.ScSpecTypeScSpecTypeU64)},
+				},
+			},
+		},
+	}
+}
+
+// testWasm builds a minimal module holding a contractspecv0 custom section.
+func testWasm(t *testing.T, entries []xdr.ScSpecEntry) []byte {
+	t.Helper()
+	var payload bytes.Buffer
+	for _, e := range entries {
+		raw, err := e.MarshalBinary()
+		require.NoError(t, err)
+		payload.Write(raw)
+	}
+
+	name := []byte(ContractSpecSection)
+	body := append([]byte{byte(len(name))}, name...)
+	body = append(body, payload.Bytes()...)
+
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	wasm = append(wasm, 0x00)
+	wasm = append(wasm, uleb(uint64(len(body)))...)
+	return append(wasm, body...)
+}
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+func invokeEnvelope(t *testing.T, fn string, args []xdr.ScVal) string {
+	t.Helper()
+	var contract xdr.ContractId
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &xdr.Uint256{}},
+				Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+				Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract},
+									FunctionName:    xdr.ScSymbol(fn),
+									Args:            args,
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return encoded
+}
+
+// decodeInvokeArgs decodes a base64 envelope the way erst-sim does.
+func decodeInvokeArgs(t *testing.T, envelope string) []xdr.ScVal {
+	t.Helper()
+	var env xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(envelope, &env))
+	return env.V1.Tx.Operations[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.Args
+}
+
+func TestParseContractSpec(t *testing.T) {
+	spec, err := ParseContractSpec(testWasm(t, testSpecEntries()))
+	require.NoError(t, err)
+
+	fn, ok := spec.Functions["transfer"]
+	require.True(t, ok)
+	assert.Len(t, fn.Inputs, 3)
+	assert.Contains(t, spec.Structs, "Config")
+
+	_, err = ParseContractSpec([]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00})
+	assert.Error(t, err)
+}
+
+func TestScValMutator_MutateEnvelopeFollowsSpec(t *testing.T) {
+	spec, err := ParseContractSpec(testWasm(t, testSpecEntries()))
+	require.NoError(t, err)
+
+	from := scvAddress(randomAddress(rand.New(rand.NewSource(1))))
+	base := invokeEnvelope(t, "transfer", []xdr.ScVal{from, from, scvI128(xdr.Int128Parts{Lo: 100})})
+
+	m := NewScValMutator(spec)
+	rng := rand.New(rand.NewSource(7))
+	sawBoundary := false
+	for i := 0; i < 200; i++ {
+		mutated, err := m.MutateEnvelope(base, rng)
+		require.NoError(t, err)
+
+		args := decodeInvokeArgs(t, mutated)
+		require.Len(t, args, 3)
+		assert.Equal(t, xdr.ScValTypeScvAddress, args[0].Type)
+		assert.Equal(t, xdr.ScValTypeScvAddress, args[1].Type)
+		require.Equal(t, xdr.ScValTypeScvI128, args[2].Type)
+		if args[2].I128.Hi == math.MaxInt64 && args[2].I128.Lo == math.MaxUint64 {
+			sawBoundary = true
+		}
+	}
+	assert.True(t, sawBoundary, "i128 max should be among generated amounts")
+}
+
+func TestScValMutator_InfersTypesWithoutSpec(t *testing.T) {
+	base := invokeEnvelope(t, "set", []xdr.ScVal{scvSymbol("key"), scvU32(5)})
+	m := NewScValMutator(nil)
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 50; i++ {
+		mutated, err := m.MutateEnvelope(base, rng)
+		require.NoError(t, err)
+		args := decodeInvokeArgs(t, mutated)
+		require.Len(t, args, 2)
+		assert.Equal(t, xdr.ScValTypeScvSymbol, args[0].Type)
+		assert.Equal(t, xdr.ScValTypeScvU32, args[1].Type)
+	}
+}
+
+func TestScValMutator_AcceptsHexAndReturnsBase64(t *testing.T) {
+	var env xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(invokeEnvelope(t, "set", []xdr.ScVal{scvU32(5)}), &env))
+	raw, err := env.MarshalBinary()
+	require.NoError(t, err)
+
+	mutated, err := NewScValMutator(nil).MutateEnvelope(hex.EncodeToString(raw), rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	assert.Len(t, decodeInvokeArgs(t, mutated), 1)
+}
+
+func TestScValMutator_RejectsNonInvokeEnvelope(t *testing.T) {
+	_, err := NewScValMutator(nil).MutateEnvelope("not an envelope", rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func TestScValMutator_GeneratesStructsAsSymbolMaps(t *testing.T) {
+	spec, err := ParseContractSpec(testWasm(t, testSpecEntries()))
+	require.NoError(t, err)
+
+	val := NewScValMutator(spec).generate(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeUdt,
+		Udt:  &xdr.ScSpecTypeUdt{Name: "Config"},
+	}, rand.New(rand.NewSource(1)), 0)
+
+	mp, ok := val.GetMap()
+	require.True(t, ok)
+	require.Len(t, *mp, 2)
+	assert.Equal(t, xdr.ScSymbol("admin"), *(*mp)[0].Key.Sym)
+	assert.Equal(t, xdr.ScValTypeScvAddress, (*mp)[0].Val.Type)
+	assert.Equal(t, xdr.ScValTypeScvU64, (*mp)[1].Val.Type)
+}
+
+func TestFuzzingHarness_StructuredMutationKeepsEnvelopeDecodable(t *testing.T) {
+	base := invokeEnvelope(t, "set", []xdr.ScVal{scvU64(1)})
+	var seen []string
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		// erst-sim base64-decodes envelope_xdr.
+		seen = append(seen, req.EnvelopeXdr)
+		return &SimulationResponse{Status: "success"}, nil
+	}}
+
+	harness := NewFuzzingHarness(runner, FuzzingConfig{MaxIterations: 20})
+	harness.ArgMutator = NewScValMutator(nil)
+	_, _, err := harness.Fuzz(&FuzzerInput{EnvelopeXdr: base})
+	require.NoError(t, err)
+
+	changed := 0
+	for _, env := range seen {
+		args := decodeInvokeArgs(t, env)
+		require.Len(t, args, 1)
+		if env != base {
+			changed++
+		}
+	}
+	assert.Positive(t, changed)
+}
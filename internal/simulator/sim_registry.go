@@ -0,0 +1,374 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// SimRegistryDirName is the directory under ~/.erst that holds registered
+// simulator binaries and the index describing them.
+const SimRegistryDirName = "sims"
+
+const simRegistryIndex = "registry.json"
+
+// simProbeTimeout bounds `erst-sim --version`.
+const simProbeTimeout = 5 * time.Second
+
+var (
+	simNamePattern    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	simVersionPattern = regexp.MustCompile(`erst-sim\s+(\S+)(?:\s+\(soroban-env-host\s+([^)\s]+)\))?`)
+)
+
+// SimBinaryInfo is what an erst-sim binary reports about itself.
+type SimBinaryInfo struct {
+	Version     string `json:"version,omitempty"`
+	HostVersion string `json:"soroban_env_host,omitempty"`
+	// Protocols is set with `erst sim register --protocols`; otherwise the
+	// supported protocols are derived from MaxProtocol, see SupportedProtocols.
+	Protocols   []uint32 `json:"protocols,omitempty"`
+	MaxProtocol uint32   `json:"max_protocol,omitempty"`
+}
+
+// SupportedProtocols returns Protocols, or the known protocols up to
+// MaxProtocol. The list is derived when the registry is used rather than
+// stored, so protocols added to erst later are picked up.
+func (i *SimBinaryInfo) SupportedProtocols() []uint32 {
+	if len(i.Protocols) == 0 && i.MaxProtocol > 0 {
+		return protocolsUpTo(i.MaxProtocol)
+	}
+	return i.Protocols
+}
+
+// SimBinary is a simulator registered with `erst sim register`.
+type SimBinary struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SimBinaryInfo
+	// Managed binaries were copied into the registry directory and are
+	// deleted with their entry.
+	Managed      bool      `json:"managed,omitempty"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// Supports reports whether b can simulate protocol. Binaries whose
+// protocols are unknown are assumed to support none.
+func (b *SimBinary) Supports(protocol uint32) bool {
+	return slices.Contains(b.SupportedProtocols(), protocol)
+}
+
+// SimRegistry is the index of registered simulators in ~/.erst/sims.
+type SimRegistry struct {
+	// Default is the simulator selected with `erst sim use`; commands fall
+	// back to it when no --sim-path is given.
+	Default  string                `json:"default,omitempty"`
+	Binaries map[string]*SimBinary `json:"binaries"`
+
+	dir string
+}
+
+// SimRegistryDir returns ~/.erst/sims.
+func SimRegistryDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WrapConfigError("failed to get home directory", err)
+	}
+	return filepath.Join(home, ".erst", SimRegistryDirName), nil
+}
+
+// LoadSimRegistry reads the simulator registry. A missing registry is
+// returned empty.
+func LoadSimRegistry() (*SimRegistry, error) {
+	dir, err := SimRegistryDir()
+	if err != nil {
+		return nil, err
+	}
+	return loadSimRegistry(dir)
+}
+
+func loadSimRegistry(dir string) (*SimRegistry, error) {
+	reg := &SimRegistry{Binaries: make(map[string]*SimBinary), dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, simRegistryIndex))
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, errors.WrapConfigError("failed to read simulator registry", err)
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, errors.WrapConfigError("failed to parse simulator registry", err)
+	}
+	if reg.Binaries == nil {
+		reg.Binaries = make(map[string]*SimBinary)
+	}
+	return reg, nil
+}
+
+// Save writes the registry back to disk.
+func (r *SimRegistry) Save() error {
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return errors.WrapConfigError("failed to create simulator registry directory", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.WrapConfigError("failed to marshal simulator registry", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, simRegistryIndex), data, 0600); err != nil {
+		return errors.WrapConfigError("failed to write simulator registry", err)
+	}
+	return nil
+}
+
+// List returns the registered simulators sorted by name.
+func (r *SimRegistry) List() []*SimBinary {
+	out := make([]*SimBinary, 0, len(r.Binaries))
+	for _, b := range r.Binaries {
+		out = append(out, b)
+	}
+	slices.SortFunc(out, func(a, b *SimBinary) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// Get returns the simulator registered as name.
+func (r *SimRegistry) Get(name string) (*SimBinary, error) {
+	b, ok := r.Binaries[name]
+	if !ok {
+		return nil, errors.WrapSimulatorNotFound(fmt.Sprintf("no simulator registered as %q (see erst sim list)", name))
+	}
+	return b, nil
+}
+
+// Register adds the binary at path as name, replacing an existing entry of
+// that name. With managed set the binary is copied into the registry
+// directory so later rebuilds of the original do not change it. The
+// registry is not saved.
+func (r *SimRegistry) Register(name, path string, info SimBinaryInfo, managed bool) (*SimBinary, error) {
+	if !simNamePattern.MatchString(name) {
+		return nil, errors.WrapValidationError(fmt.Sprintf("invalid simulator name %q: use letters, digits, '.', '_' and '-'", name))
+	}
+	if !isExecutable(path) {
+		return nil, errors.WrapSimulatorNotFound(path)
+	}
+	path = abs(path)
+
+	if old, ok := r.Binaries[name]; ok && old.Managed && old.Path != path {
+		_ = os.RemoveAll(filepath.Dir(old.Path))
+	}
+	if managed {
+		copied, err := r.copyBinary(name, path)
+		if err != nil {
+			return nil, err
+		}
+		path = copied
+	}
+
+	b := &SimBinary{Name: name, Path: path, SimBinaryInfo: info, Managed: managed, RegisteredAt: time.Now().UTC()}
+	r.Binaries[name] = b
+	return b, nil
+}
+
+func (r *SimRegistry) copyBinary(name, src string) (string, error) {
+	dst := filepath.Join(r.dir, name, "erst-sim")
+	if runtime.GOOS == "windows" {
+		dst += ".exe"
+	}
+	if src == dst {
+		return dst, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return "", errors.WrapConfigError("failed to create simulator directory", err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", errors.WrapConfigError("failed to read simulator binary", err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return "", errors.WrapConfigError("failed to copy simulator binary", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return "", errors.WrapConfigError("failed to copy simulator binary", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", errors.WrapConfigError("failed to copy simulator binary", err)
+	}
+	return dst, nil
+}
+
+// Remove unregisters name, deleting its copy if the registry manages it.
+// Removing the default simulator clears the default.
+func (r *SimRegistry) Remove(name string) error {
+	b, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+	if b.Managed {
+		if err := os.RemoveAll(filepath.Dir(b.Path)); err != nil {
+			return errors.WrapConfigError("failed to delete simulator binary", err)
+		}
+	}
+	delete(r.Binaries, name)
+	if r.Default == name {
+		r.Default = ""
+	}
+	return nil
+}
+
+// Use makes name the default simulator.
+func (r *SimRegistry) Use(name string) error {
+	if _, err := r.Get(name); err != nil {
+		return err
+	}
+	r.Default = name
+	return nil
+}
+
+// ForProtocol picks the simulator for protocol: the default one if it
+// supports it, otherwise the supporting simulator with the newest
+// soroban-env-host. It returns nil when none supports protocol.
+func (r *SimRegistry) ForProtocol(protocol uint32) *SimBinary {
+	if b, ok := r.Binaries[r.Default]; ok && b.Supports(protocol) {
+		return b
+	}
+	var best *SimBinary
+	for _, b := range r.List() {
+		if b.Supports(protocol) && (best == nil || compareVersions(b.HostVersion, best.HostVersion) > 0) {
+			best = b
+		}
+	}
+	return best
+}
+
+// SelectSimBinary returns the simulator override to pass to NewRunner. A
+// non-empty name, a binary path or registered simulator name, is returned
+// as is. Otherwise, for a non-zero protocol, the path of the registered
+// simulator supporting it is chosen. An empty result means the normal binary
+// discovery of NewRunner applies.
+func SelectSimBinary(name string, protocol uint32) (string, error) {
+	if name != "" || protocol == 0 {
+		return name, nil
+	}
+	reg, err := LoadSimRegistry()
+	if err != nil {
+		return "", err
+	}
+	if b := reg.ForProtocol(protocol); b != nil {
+		return b.Path, nil
+	}
+	return "", nil
+}
+
+// registeredDefaultSim returns the path of the default registered
+// simulator, if any.
+func registeredDefaultSim() (string, bool) {
+	reg, err := LoadSimRegistry()
+	if err != nil || reg.Default == "" {
+		return "", false
+	}
+	b, ok := reg.Binaries[reg.Default]
+	if !ok || !isExecutable(b.Path) {
+		return "", false
+	}
+	return b.Path, true
+}
+
+// registeredSimPath returns the path of the simulator registered as name.
+func registeredSimPath(name string) (string, bool) {
+	reg, err := LoadSimRegistry()
+	if err != nil {
+		return "", false
+	}
+	b, ok := reg.Binaries[name]
+	if !ok {
+		return "", false
+	}
+	return b.Path, true
+}
+
+// ProbeSimBinary runs `path --version` and parses what the binary reports.
+// MaxProtocol is the major version of the embedded soroban-env-host, which is
+// the newest protocol it implements.
+func ProbeSimBinary(ctx context.Context, path string) (SimBinaryInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, simProbeTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "--version")
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return SimBinaryInfo{}, errors.WrapSimCrash(err, "")
+	}
+	return parseSimVersion(stdout.String())
+}
+
+func parseSimVersion(output string) (SimBinaryInfo, error) {
+	m := simVersionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return SimBinaryInfo{}, errors.WrapValidationError(fmt.Sprintf("simulator did not report a version: %q", strings.TrimSpace(output)))
+	}
+	info := SimBinaryInfo{Version: m[1], HostVersion: m[2]}
+	if major, ok := versionMajor(info.HostVersion); ok {
+		info.MaxProtocol = major
+	}
+	return info, nil
+}
+
+// protocolsUpTo returns the known protocols not newer than max, or just max
+// when the registry knows none of them.
+func protocolsUpTo(max uint32) []uint32 {
+	var out []uint32
+	for _, v := range Supported() {
+		if v <= max {
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		out = []uint32{max}
+	}
+	return out
+}
+
+func versionMajor(version string) (uint32, bool) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	n, err := strconv.ParseUint(major, 10, 32)
+	return uint32(n), err == nil
+}
+
+// compareVersions orders dotted numeric versions; unparsable parts compare
+// as zero.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeVersionedSim(t *testing.T, hostVersion string) string {
+	t.Helper()
+	return fakeSim(t, `echo "erst-sim 0.1.0 (soroban-env-host `+hostVersion+`)"`).BinaryPath
+}
+
+func TestParseSimVersion(t *testing.T) {
+	info, err := parseSimVersion("erst-sim 0.2.1 (soroban-env-host 22.1.0)\n")
+	require.NoError(t, err)
+	assert.Equal(t, "0.2.1", info.Version)
+	assert.Equal(t, "22.1.0", info.HostVersion)
+	assert.Equal(t, uint32(22), info.MaxProtocol)
+	assert.Empty(t, info.Protocols, "only the maximum is stored")
+	assert.Contains(t, info.SupportedProtocols(), uint32(22))
+	for _, p := range info.SupportedProtocols() {
+		assert.LessOrEqual(t, p, uint32(22))
+	}
+
+	info, err = parseSimVersion("erst-sim 0.1.0")
+	require.NoError(t, err)
+	assert.Empty(t, info.SupportedProtocols(), "protocols are unknown without a host version")
+
+	_, err = parseSimVersion(`{"status":"error","error":"Invalid JSON"}`)
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+}
+
+func TestProbeSimBinary(t *testing.T) {
+	info, err := ProbeSimBinary(context.Background(), fakeVersionedSim(t, "23.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "23.0.0", info.HostVersion)
+}
+
+func TestSimRegistry_RegisterUseRemove(t *testing.T) {
+	dir := t.TempDir()
+	src := fakeVersionedSim(t, "22.0.0")
+
+	reg, err := loadSimRegistry(dir)
+	require.NoError(t, err)
+	_, err = reg.Register("bad/name", src, SimBinaryInfo{}, false)
+	assert.ErrorIs(t, err, errors.ErrValidationFailed)
+	_, err = reg.Register("missing", filepath.Join(dir, "nope"), SimBinaryInfo{}, false)
+	assert.ErrorIs(t, err, errors.ErrSimulatorNotFound)
+
+	managed, err := reg.Register("host22", src, SimBinaryInfo{Version: "0.1.0", Protocols: []uint32{21, 22}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "host22", "erst-sim"), managed.Path)
+	assert.FileExists(t, managed.Path)
+	_, err = reg.Register("linked", src, SimBinaryInfo{}, false)
+	require.NoError(t, err)
+	require.NoError(t, reg.Use("host22"))
+	assert.ErrorIs(t, reg.Use("unknown"), errors.ErrSimulatorNotFound)
+	require.NoError(t, reg.Save())
+
+	reloaded, err := loadSimRegistry(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "host22", reloaded.Default)
+	require.Len(t, reloaded.List(), 2)
+	assert.Equal(t, "host22", reloaded.List()[0].Name)
+	assert.Equal(t, []uint32{21, 22}, reloaded.Binaries["host22"].Protocols)
+
+	require.NoError(t, reloaded.Remove("host22"))
+	assert.Empty(t, reloaded.Default)
+	assert.NoDirExists(t, filepath.Join(dir, "host22"))
+	require.NoError(t, reloaded.Remove("linked"))
+	assert.FileExists(t, src, "linked binaries are left in place")
+}
+
+func TestSimRegistry_ForProtocol(t *testing.T) {
+	reg := &SimRegistry{Binaries: map[string]*SimBinary{
+		"old":   {Name: "old", SimBinaryInfo: SimBinaryInfo{HostVersion: "21.2.0", Protocols: []uint32{20, 21}}},
+		"new":   {Name: "new", SimBinaryInfo: SimBinaryInfo{HostVersion: "22.0.1", Protocols: []uint32{20, 21, 22}}},
+		"newer": {Name: "newer", SimBinaryInfo: SimBinaryInfo{HostVersion: "22.10.0", Protocols: []uint32{21, 22}}},
+	}}
+
+	assert.Equal(t, "newer", reg.ForProtocol(22).Name, "newest host wins")
+	assert.Equal(t, "new", reg.ForProtocol(20).Name)
+	assert.Nil(t, reg.ForProtocol(30))
+
+	reg.Default = "old"
+	assert.Equal(t, "old", reg.ForProtocol(21).Name, "the default wins when it supports the protocol")
+	assert.Equal(t, "newer", reg.ForProtocol(22).Name)
+}
+
+func TestFindSimBinary_Registered(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ERST_SIM_PATH", "")
+	src := fakeVersionedSim(t, "22.0.0")
+
+	reg, err := LoadSimRegistry()
+	require.NoError(t, err)
+	b, err := reg.Register("host22", src, SimBinaryInfo{Protocols: []uint32{22}}, false)
+	require.NoError(t, err)
+	require.NoError(t, reg.Save())
+
+	path, source, err := findSimBinary("host22")
+	require.NoError(t, err)
+	assert.Equal(t, b.Path, path)
+	assert.Contains(t, source, "registered simulator")
+
+	selected, err := SelectSimBinary("", 22)
+	require.NoError(t, err)
+	assert.Equal(t, b.Path, selected)
+	selected, err = SelectSimBinary("", 19)
+	require.NoError(t, err)
+	assert.Empty(t, selected)
+
+	require.NoError(t, reg.Use("host22"))
+	require.NoError(t, reg.Save())
+	path, source, err = findSimBinary("")
+	require.NoError(t, err)
+	assert.Equal(t, b.Path, path)
+	assert.Equal(t, "registered default", source)
+
+	require.NoError(t, os.Remove(src))
+	_, _, err = findSimBinary("host22")
+	assert.ErrorIs(t, err, errors.ErrSimulatorNotFound)
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// Streaming wire protocol
+//
+// When started with --stream, erst-sim writes one JSON object per line to
+// stdout as execution progresses instead of a single response at exit. Each
+// line is a StreamRecord; the last one has type "result" and carries the
+// final SimulationResponse. Events and logs already streamed may be omitted
+// from that response; RunStream merges them back in.
+const streamFlag = "--stream"
+
+// Stream record types.
+const (
+	RecordEvent  = "event"
+	RecordLog    = "log"
+	RecordBudget = "budget"
+	RecordResult = "result"
+)
+
+// maxStreamLine bounds a single NDJSON record.
+const maxStreamLine = 64 << 20
+
+// streamBuffer is the number of records buffered ahead of a slow consumer.
+const streamBuffer = 256
+
+// StreamRecord is a single line of streaming simulator output.
+type StreamRecord struct {
+	Type     string              `json:"type"`
+	Event    *DiagnosticEvent    `json:"event,omitempty"`
+	Log      string              `json:"log,omitempty"`
+	Budget   *BudgetUsage        `json:"budget,omitempty"`
+	Response *SimulationResponse `json:"response,omitempty"`
+}
+
+// Stream is an in-progress streaming simulation.
+type Stream struct {
+	records chan StreamRecord
+	done    chan struct{}
+	resp    *SimulationResponse
+	err     error
+}
+
+// Records returns the channel of intermediate records (events, logs and
+// budget checkpoints). It is closed when the simulator exits.
+func (s *Stream) Records() <-chan StreamRecord {
+	return s.records
+}
+
+// Wait discards any unread records, waits for the simulator to exit and
+// returns the final response.
+func (s *Stream) Wait() (*SimulationResponse, error) {
+	for range s.records {
+	}
+	<-s.done
+	return s.resp, s.err
+}
+
+// RunStream starts req in streaming mode. Intermediate records are delivered
+// on Stream.Records as erst-sim emits them; Stream.Wait returns the same
+// response RunContext would. Cancellation, Timeout and MemoryLimit behave as
+// in RunContext.
+func (r *Runner) RunStream(ctx context.Context, req *SimulationRequest) (*Stream, error) {
+	proto, err := r.prepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	inputBytes, err := json.Marshal(req)
+	if err != nil {
+		logger.Logger.Error("Failed to marshal simulation request", "error", err)
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	ctx, cancel, timeout := withRunTimeout(ctx, r.Timeout)
+
+	cmd := exec.CommandContext(ctx, r.BinaryPath, streamFlag)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, errors.WrapSimCrash(err, "")
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := r.startSim(cmd, inputBytes); err != nil {
+		cancel()
+		return nil, errors.WrapSimCrash(err, "")
+	}
+
+	s := &Stream{
+		records: make(chan StreamRecord, streamBuffer),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+		defer cancel()
+
+		resp, collected, scanErr := readStream(stdout, s.records)
+		close(s.records)
+		// Keep draining on a malformed record so the child cannot block on a
+		// full pipe before Wait.
+		_, _ = io.Copy(io.Discard, stdout)
+
+		if err := cmd.Wait(); err != nil {
+			logger.Logger.Error("Simulator execution failed", "error", err, "stderr", stderr.String())
+			s.err = r.classifyFailure(ctx, timeout, err, cmd.ProcessState, stderr.String())
+			return
+		}
+		if scanErr != nil {
+			s.err = errors.WrapUnmarshalFailed(scanErr, "")
+			return
+		}
+		if resp == nil {
+			s.err = errors.WrapSimulationLogicError("simulator stream ended without a result")
+			return
+		}
+
+		collected.mergeInto(resp)
+		resp.ProtocolVersion = &proto.Version
+		s.resp = resp
+	}()
+
+	return s, nil
+}
+
+// streamedOutput accumulates intermediate records so they can be folded into
+// a final response that omits them.
+type streamedOutput struct {
+	events []DiagnosticEvent
+	logs   []string
+	budget *BudgetUsage
+}
+
+func (o *streamedOutput) mergeInto(resp *SimulationResponse) {
+	if len(resp.DiagnosticEvents) == 0 {
+		resp.DiagnosticEvents = o.events
+	}
+	if len(resp.Logs) == 0 {
+		resp.Logs = o.logs
+	}
+	if resp.BudgetUsage == nil {
+		resp.BudgetUsage = o.budget
+	}
+}
+
+// readStream decodes NDJSON records from r, forwarding intermediate ones to
+// out and returning the final response. A line without a type is treated as
+// a complete non-streaming SimulationResponse, so simulators that predate
+// --stream still work.
+func readStream(r io.Reader, out chan<- StreamRecord) (*SimulationResponse, *streamedOutput, error) {
+	collected := &streamedOutput{}
+	var result *SimulationResponse
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var rec StreamRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return result, collected, err
+		}
+
+		switch rec.Type {
+		case RecordEvent:
+			if rec.Event != nil {
+				collected.events = append(collected.events, *rec.Event)
+			}
+		case RecordLog:
+			collected.logs = append(collected.logs, rec.Log)
+		case RecordBudget:
+			collected.budget = rec.Budget
+		case RecordResult:
+			result = rec.Response
+			continue
+		case "":
+			var resp SimulationResponse
+			if err := json.Unmarshal(line, &resp); err != nil {
+				return result, collected, err
+			}
+			result = &resp
+			continue
+		default:
+			logger.Logger.Debug("Ignoring unknown stream record", "type", rec.Type)
+			continue
+		}
+
+		out <- rec
+	}
+
+	return result, collected, scanner.Err()
+}
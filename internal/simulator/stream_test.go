@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStream_DeliversRecordsAndMergesResult(t *testing.T) {
+	runner := fakeSim(t, `cat >/dev/null
+[ "$1" = "--stream" ] || exit 2
+echo '{"type":"log","log":"host initialised"}'
+echo '{"type":"event","event":{"event_type":"contract","topics":["transfer"],"data":"1"}}'
+echo '{"type":"budget","budget":{"cpu_instructions":10,"cpu_limit":100}}'
+echo '{"type":"result","response":{"status":"success"}}'`)
+
+	stream, err := runner.RunStream(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	var types []string
+	for rec := range stream.Records() {
+		types = append(types, rec.Type)
+	}
+	assert.Equal(t, []string{RecordLog, RecordEvent, RecordBudget}, types)
+
+	resp, err := stream.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []string{"host initialised"}, resp.Logs)
+	require.Len(t, resp.DiagnosticEvents, 1)
+	assert.Equal(t, []string{"transfer"}, resp.DiagnosticEvents[0].Topics)
+	require.NotNil(t, resp.BudgetUsage)
+	assert.Equal(t, uint64(10), resp.BudgetUsage.CPUInstructions)
+	require.NotNil(t, resp.ProtocolVersion)
+}
+
+func TestRunStream_AcceptsNonStreamingSimulator(t *testing.T) {
+	runner := fakeSim(t, `cat >/dev/null; echo '{"status":"error","error":"trapped"}'`)
+
+	stream, err := runner.RunStream(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	resp, err := stream.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, "error", resp.Status)
+	assert.Equal(t, "trapped", resp.Error)
+}
+
+func TestRunStream_MissingResult(t *testing.T) {
+	runner := fakeSim(t, `cat >/dev/null; echo '{"type":"log","log":"partial"}'`)
+
+	stream, err := runner.RunStream(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	_, err = stream.Wait()
+	assert.True(t, errors.Is(err, errors.ErrSimulationLogicError))
+}
+
+func TestRunStream_Timeout(t *testing.T) {
+	runner := fakeSim(t, `echo '{"type":"log","log":"started"}'; exec sleep 30`)
+	runner.Timeout = 100 * time.Millisecond
+
+	stream, err := runner.RunStream(context.Background(), &SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.NoError(t, err)
+
+	rec, ok := <-stream.Records()
+	require.True(t, ok)
+	assert.Equal(t, "started", rec.Log)
+
+	_, err = stream.Wait()
+	assert.True(t, errors.Is(err, errors.ErrSimTimeout))
+}
+
+func TestReadStream_MalformedLine(t *testing.T) {
+	out := make(chan StreamRecord, 4)
+	_, _, err := readStream(strings.NewReader("{\"type\":\"log\",\"log\":\"ok\"}\nnot json\n"), out)
+	assert.Error(t, err)
+	assert.Len(t, out, 1)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// SweepAxis is the ledger header field a sweep varies.
+type SweepAxis string
+
+const (
+	SweepTimestamp SweepAxis = "timestamp"
+	SweepLedger    SweepAxis = "ledger"
+)
+
+const (
+	defaultSweepInitialProbes = 5
+	defaultSweepMaxProbes     = 64
+)
+
+// SweepProbe is one simulation run at a single point of the range.
+type SweepProbe struct {
+	Step            int    `json:"step"` // order in which the probe ran, from 1
+	Value           int64  `json:"value"`
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+	CPUInstructions uint64 `json:"cpu_instructions"`
+	MemoryBytes     uint64 `json:"memory_bytes"`
+
+	Response *SimulationResponse `json:"-"`
+}
+
+// Outcome is the value compared between probes: the status, plus the error
+// for failed runs so that a change of failure reason also counts as a flip.
+func (p *SweepProbe) Outcome() string {
+	if p.Error == "" {
+		return p.Status
+	}
+	return p.Status + ": " + p.Error
+}
+
+// SweepBoundary is a point where the outcome flips. Last is the highest
+// probed value with the old outcome and First the lowest with the new one;
+// the boundary is exact when they are adjacent.
+type SweepBoundary struct {
+	Last          int64  `json:"last"`
+	First         int64  `json:"first"`
+	BeforeOutcome string `json:"before"`
+	AfterOutcome  string `json:"after"`
+	Exact         bool   `json:"exact"`
+}
+
+// SweepResult holds every probe, ordered by value, and the boundaries found.
+type SweepResult struct {
+	Axis       SweepAxis       `json:"axis"`
+	From       int64           `json:"from"`
+	To         int64           `json:"to"`
+	Probes     []SweepProbe    `json:"probes"`
+	Boundaries []SweepBoundary `json:"boundaries"`
+}
+
+// FirstFailure returns the lowest probe that did not succeed, or nil.
+func (r *SweepResult) FirstFailure() *SweepProbe {
+	for i := range r.Probes {
+		if r.Probes[i].Status != "success" {
+			return &r.Probes[i]
+		}
+	}
+	return nil
+}
+
+// Sweeper simulates a request across a range of timestamps or ledger
+// sequences and bisects to the exact points where the outcome changes.
+//
+// A coarse pass runs InitialProbes evenly spaced probes, both ends included.
+// Every pair of neighbouring probes whose outcomes differ is then bisected
+// until the two values are adjacent. An outcome that flips and flips back
+// between two coarse probes is not seen, so time-locked contracts with
+// several windows want more initial probes.
+type Sweeper struct {
+	Runner RunnerInterface
+	// Base is copied for every probe with only the swept field changed.
+	Base          *SimulationRequest
+	Axis          SweepAxis
+	InitialProbes int
+	// MaxProbes caps the total number of simulations. Boundaries that are
+	// still open when it is reached are reported with Exact false.
+	MaxProbes int
+}
+
+// NewSweeper creates a sweeper with the default probe counts.
+func NewSweeper(runner RunnerInterface, base *SimulationRequest, axis SweepAxis) *Sweeper {
+	return &Sweeper{
+		Runner:        runner,
+		Base:          base,
+		Axis:          axis,
+		InitialProbes: defaultSweepInitialProbes,
+		MaxProbes:     defaultSweepMaxProbes,
+	}
+}
+
+// Sweep probes the inclusive range [from, to].
+func (s *Sweeper) Sweep(ctx context.Context, from, to int64) (*SweepResult, error) {
+	if s.Runner == nil || s.Base == nil {
+		return nil, errors.WrapValidationError("sweep needs a runner and a base request")
+	}
+	if from < 0 || to < from {
+		return nil, errors.WrapValidationError(fmt.Sprintf("invalid sweep range %d..%d", from, to))
+	}
+	switch s.Axis {
+	case SweepTimestamp:
+	case SweepLedger:
+		if to > math.MaxUint32 {
+			return nil, errors.WrapValidationError(fmt.Sprintf("ledger sequence %d out of range", to))
+		}
+	default:
+		return nil, errors.WrapValidationError(fmt.Sprintf("unknown sweep axis %q", s.Axis))
+	}
+
+	initial := s.InitialProbes
+	if initial < 2 {
+		initial = 2
+	}
+	maxProbes := s.MaxProbes
+	if maxProbes < initial {
+		maxProbes = initial
+	}
+
+	probes := make(map[int64]*SweepProbe)
+	probe := func(v int64) (*SweepProbe, error) {
+		if p, ok := probes[v]; ok {
+			return p, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := s.run(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		p.Step = len(probes) + 1
+		probes[v] = p
+		return p, nil
+	}
+
+	coarse := coarsePoints(from, to, initial)
+	for _, v := range coarse {
+		if _, err := probe(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var boundaries []SweepBoundary
+	for i := 1; i < len(coarse); i++ {
+		lo, hi := probes[coarse[i-1]], probes[coarse[i]]
+		if lo.Outcome() == hi.Outcome() {
+			continue
+		}
+		for hi.Value-lo.Value > 1 && len(probes) < maxProbes {
+			mid, err := probe(lo.Value + (hi.Value-lo.Value)/2)
+			if err != nil {
+				return nil, err
+			}
+			if mid.Outcome() == lo.Outcome() {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		boundaries = append(boundaries, SweepBoundary{
+			Last:          lo.Value,
+			First:         hi.Value,
+			BeforeOutcome: lo.Outcome(),
+			AfterOutcome:  hi.Outcome(),
+			Exact:         hi.Value-lo.Value == 1,
+		})
+	}
+
+	result := &SweepResult{Axis: s.Axis, From: from, To: to, Boundaries: boundaries}
+	for _, p := range probes {
+		result.Probes = append(result.Probes, *p)
+	}
+	sort.Slice(result.Probes, func(i, j int) bool { return result.Probes[i].Value < result.Probes[j].Value })
+	return result, nil
+}
+
+func (s *Sweeper) run(ctx context.Context, v int64) (*SweepProbe, error) {
+	req := *s.Base
+	switch s.Axis {
+	case SweepTimestamp:
+		req.Timestamp = v
+	case SweepLedger:
+		req.LedgerSequence = uint32(v)
+	}
+
+	resp, err := s.Runner.RunContext(ctx, &req)
+	if err != nil {
+		return nil, fmt.Errorf("sweep probe at %s %d: %w", s.Axis, v, errors.WrapSimulationFailed(err, ""))
+	}
+
+	p := &SweepProbe{Value: v, Status: resp.Status, Error: resp.Error, Response: resp}
+	if resp.BudgetUsage != nil {
+		p.CPUInstructions = resp.BudgetUsage.CPUInstructions
+		p.MemoryBytes = resp.BudgetUsage.MemoryBytes
+	}
+	return p, nil
+}
+
+// coarsePoints spreads n points evenly over [from, to], both ends included,
+// without duplicates.
+func coarsePoints(from, to int64, n int) []int64 {
+	span := uint64(to - from)
+	if span+1 < uint64(n) {
+		n = int(span + 1)
+	}
+	points := make([]int64, 0, n)
+	for i := 0; i < n; i++ {
+		var off uint64
+		if n > 1 {
+			// span*i can overflow for very wide ranges; split the product.
+			step, rem := span/uint64(n-1), span%uint64(n-1)
+			off = step*uint64(i) + rem*uint64(i)/uint64(n-1)
+		}
+		v := from + int64(off)
+		if len(points) == 0 || points[len(points)-1] != v {
+			points = append(points, v)
+		}
+	}
+	return points
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweeper_FindsUnlockTime(t *testing.T) {
+	const unlock = 1_700_003_217
+	runs := 0
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		runs++
+		if req.Timestamp < unlock {
+			return &SimulationResponse{Status: "error", Error: "HostError: Error(Contract, #4)"}, nil
+		}
+		return &SimulationResponse{Status: "success", BudgetUsage: &BudgetUsage{CPUInstructions: 1000, MemoryBytes: 64}}, nil
+	}}
+
+	base := &SimulationRequest{EnvelopeXdr: "AAAA", LedgerSequence: 50}
+	result, err := NewSweeper(runner, base, SweepTimestamp).Sweep(context.Background(), 1_700_000_000, 1_700_010_000)
+	require.NoError(t, err)
+
+	require.Len(t, result.Boundaries, 1)
+	b := result.Boundaries[0]
+	assert.True(t, b.Exact)
+	assert.Equal(t, int64(unlock-1), b.Last)
+	assert.Equal(t, int64(unlock), b.First)
+	assert.Equal(t, "error: HostError: Error(Contract, #4)", b.BeforeOutcome)
+	assert.Equal(t, "success", b.AfterOutcome)
+
+	assert.Equal(t, runs, len(result.Probes), "every value is simulated once")
+	assert.Less(t, runs, 25)
+	for i := 1; i < len(result.Probes); i++ {
+		assert.Less(t, result.Probes[i-1].Value, result.Probes[i].Value)
+	}
+	assert.Equal(t, int64(1_700_000_000), result.FirstFailure().Value)
+	assert.Zero(t, base.Timestamp, "the base request is not modified")
+}
+
+func TestSweeper_LedgerAxisFindsEveryFlip(t *testing.T) {
+	// An auction open between ledgers 120 and 180.
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		if req.LedgerSequence >= 120 && req.LedgerSequence < 180 {
+			return &SimulationResponse{Status: "success"}, nil
+		}
+		return &SimulationResponse{Status: "error", Error: "auction closed"}, nil
+	}}
+
+	result, err := NewSweeper(runner, &SimulationRequest{}, SweepLedger).Sweep(context.Background(), 100, 200)
+	require.NoError(t, err)
+	require.Len(t, result.Boundaries, 2)
+	assert.Equal(t, int64(120), result.Boundaries[0].First)
+	assert.Equal(t, int64(180), result.Boundaries[1].First)
+	assert.True(t, result.Boundaries[1].Exact)
+}
+
+func TestSweeper_MaxProbesLeavesBoundaryOpen(t *testing.T) {
+	runner := &MockRunner{RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
+		if req.Timestamp < 777 {
+			return &SimulationResponse{Status: "error", Error: "locked"}, nil
+		}
+		return &SimulationResponse{Status: "success"}, nil
+	}}
+
+	s := NewSweeper(runner, &SimulationRequest{}, SweepTimestamp)
+	s.MaxProbes = 6
+	result, err := s.Sweep(context.Background(), 0, 1000)
+	require.NoError(t, err)
+	assert.Len(t, result.Probes, 6)
+	require.Len(t, result.Boundaries, 1)
+	assert.False(t, result.Boundaries[0].Exact)
+	assert.LessOrEqual(t, result.Boundaries[0].Last, int64(776))
+	assert.GreaterOrEqual(t, result.Boundaries[0].First, int64(777))
+}
+
+func TestSweeper_InvalidRange(t *testing.T) {
+	s := NewSweeper(&MockRunner{}, &SimulationRequest{}, SweepLedger)
+	_, err := s.Sweep(context.Background(), 10, 5)
+	assert.Error(t, err)
+	_, err = s.Sweep(context.Background(), 0, 1<<33)
+	assert.Error(t, err)
+}
+
+func TestCoarsePoints(t *testing.T) {
+	assert.Equal(t, []int64{0, 25, 50, 75, 100}, coarsePoints(0, 100, 5))
+	assert.Equal(t, []int64{3, 4}, coarsePoints(3, 4, 5))
+	assert.Equal(t, []int64{9}, coarsePoints(9, 9, 5))
+	pts := coarsePoints(0, 1<<62, 3)
+	assert.Equal(t, int64(1<<62), pts[2])
+}
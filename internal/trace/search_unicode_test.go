@@ -110,7 +110,6 @@ func TestSearchUnicode_Mixed(t *testing.T) {
 			ID:        "1",
 			Function:  "transfer_资金",
 
-			EventData: "Événement créé[OK]",
 			EventData: "Événement créé [DEPLOY]",
 		},
 	}
@@ -22,6 +22,8 @@ func Colorize(text string, color string) string {
 // Success returns a success indicator.
 func Success() string {
 	return defaultRenderer.Success()
+}
+
 // ContractBoundary returns a visual separator for cross-contract call transitions.
 func ContractBoundary(fromContract, toContract string) string {
 	if ColorEnabled() {
@@ -30,21 +32,9 @@ func ContractBoundary(fromContract, toContract string) string {
 	return "--- contract boundary: " + fromContract + " -> " + toContract + " ---"
 }
 
-// Success returns a success indicator: colored checkmark if enabled, "[OK]" otherwise.
-func Success() string {
-	if ColorEnabled() {
-		return themeColors("success") + "[OK]" + sgrReset
-	}
-	return "[OK]"
-}
-
 // Warning returns a warning indicator.
 func Warning() string {
 	return defaultRenderer.Warning()
-	if ColorEnabled() {
-		return themeColors("warning") + "[!]" + sgrReset
-	}
-	return "[!]"
 }
 
 // Error returns an error indicator.
@@ -52,12 +42,6 @@ func Error() string {
 	return defaultRenderer.Error()
 }
 
-// Symbol returns a symbol that may be styled.
-	if ColorEnabled() {
-		return themeColors("error") + "[X]" + sgrReset
-	}
-	return "[X]"
-}
 
 // Info returns an info indicator with theme-aware coloring.
 func Info() string {
@@ -0,0 +1,86 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! The ledger context the host runs with. erst pins it from the header of the
+//! ledger the transaction was applied in, so `env.ledger()` returns what it
+//! returned on chain.
+
+use crate::types::SimulationRequest;
+use sha2::{Digest, Sha256};
+use soroban_env_host::LedgerInfo;
+use std::time::{SystemTime, UNIX_EPOCH};
+
+/// Fallbacks for values the request does not carry, matching Pubnet.
+const DEFAULT_NETWORK_PASSPHRASE: &str = "Public Global Stellar Network ; September 2015";
+const DEFAULT_BASE_RESERVE: u32 = 5_000_000;
+const MIN_TEMP_ENTRY_TTL: u32 = 16;
+const MIN_PERSISTENT_ENTRY_TTL: u32 = 120_960;
+const MAX_ENTRY_TTL: u32 = 3_110_400;
+
+/// Builds the host's ledger info from the request. A zero timestamp means the
+/// caller did not pin one and the current time is used.
+pub fn ledger_info(request: &SimulationRequest) -> LedgerInfo {
+    let passphrase = request
+        .network_passphrase
+        .as_deref()
+        .filter(|p| !p.is_empty())
+        .unwrap_or(DEFAULT_NETWORK_PASSPHRASE);
+
+    let timestamp = u64::try_from(request.timestamp)
+        .ok()
+        .filter(|&t| t > 0)
+        .unwrap_or_else(|| {
+            SystemTime::now()
+                .duration_since(UNIX_EPOCH)
+                .map_or(0, |d| d.as_secs())
+        });
+
+    LedgerInfo {
+        protocol_version: request
+            .protocol_version
+            .unwrap_or(soroban_env_host::meta::INTERFACE_VERSION.protocol),
+        sequence_number: request.ledger_sequence.unwrap_or(0),
+        timestamp,
+        network_id: Sha256::digest(passphrase.as_bytes()).into(),
+        base_reserve: request.base_reserve.unwrap_or(DEFAULT_BASE_RESERVE),
+        min_temp_entry_ttl: MIN_TEMP_ENTRY_TTL,
+        min_persistent_entry_ttl: MIN_PERSISTENT_ENTRY_TTL,
+        max_entry_ttl: MAX_ENTRY_TTL,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn request(json: &str) -> SimulationRequest {
+        serde_json::from_str(json).unwrap()
+    }
+
+    #[test]
+    fn test_pinned_values_are_applied() {
+        let info = ledger_info(&request(
+            r#"{"envelope_xdr":"","ledger_entries":null,"contract_wasm":null,"wasm_path":null,
+                "profile":null,"mock_base_fee":null,"mock_gas_price":null,"resource_calibration":null,
+                "timestamp":1700000000,"ledger_sequence":4242,"protocol_version":22,
+                "base_reserve":1000000,"network_passphrase":"Test SDF Network ; September 2015"}"#,
+        ));
+        assert_eq!(info.timestamp, 1_700_000_000);
+        assert_eq!(info.sequence_number, 4242);
+        assert_eq!(info.protocol_version, 22);
+        assert_eq!(info.base_reserve, 1_000_000);
+        let expected: [u8; 32] = Sha256::digest(b"Test SDF Network ; September 2015").into();
+        assert_eq!(info.network_id, expected);
+    }
+
+    #[test]
+    fn test_missing_values_fall_back() {
+        let info = ledger_info(&request(r#"{"envelope_xdr":""}"#));
+        assert!(info.timestamp > 0);
+        assert_eq!(info.base_reserve, DEFAULT_BASE_RESERVE);
+        assert_eq!(
+            info.protocol_version,
+            soroban_env_host::meta::INTERFACE_VERSION.protocol
+        );
+    }
+}
@@ -5,31 +5,36 @@
 
 mod config;
 mod gas_optimizer;
+mod ledger;
+mod recording;
 mod runner;
+mod serve;
+mod snapshot;
 mod source_map_cache;
 mod source_mapper;
 mod stack_trace;
-mod vm;
 mod types;
+mod vm;
 mod wasm;
 
 use crate::gas_optimizer::{BudgetMetrics, GasOptimizationAdvisor, CPU_LIMIT, MEMORY_LIMIT};
 use crate::source_mapper::SourceMapper;
-use crate::stack_trace::{decode_error, WasmStackTrace};
+use crate::stack_trace::WasmStackTrace;
 use crate::types::*;
 use base64::Engine as _;
-use serde::{Deserialize, Serialize};
+use soroban_env_host::events::{Events, HostEvent};
 use soroban_env_host::xdr::ReadXdr;
 use soroban_env_host::{
     xdr::{Operation, OperationBody},
     Host, HostError,
 };
-use std::collections::HashMap;
 use std::env;
-use std::io::{self, Read};
+use std::io::{self, Read, Write};
 use tracing_subscriber::{fmt, EnvFilter};
 
-// Use types::SimulationRequest directly
+/// Request/response schema version; must match `SimSchemaVersion` in the Go
+/// runner, which refuses binaries reporting anything else.
+const SCHEMA_VERSION: u32 = 1;
 
 fn init_logger() {
     // Check if the environment variable ERST_LOG_FORMAT is set to "json"
@@ -53,46 +58,73 @@ fn init_logger() {
     }
 }
 
-fn send_error(msg: String) {
+fn error_response(msg: String) -> SimulationResponse {
     let trace = WasmStackTrace::from_host_error(&msg);
-    let res = SimulationResponse {
-        status: "error".to_string(),
-        error: Some(msg),
-        events: vec![],
-        diagnostic_events: vec![],
-        categorized_events: vec![],
-        logs: vec![],
-        flamegraph: None,
-        optimization_report: None,
-        budget_usage: None,
-        source_location: None,
-        stack_trace: Some(trace),
-        wasm_offset: None,
-    };
-    println!("{}", serde_json::to_string(&res).unwrap());
-    std::process::exit(1);
+    SimulationResponse::failure(msg, Some(trace))
 }
 
-fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<String>, HostError> {
+/// Runs every operation of the transaction. After each one, its log lines,
+/// the host events it added and a budget checkpoint are passed to on_record.
+fn execute_operations(
+    host: &Host,
+    operations: &[Operation],
+    on_record: &mut dyn FnMut(StreamRecord),
+) -> Result<Vec<String>, HostError> {
     let mut logs = Vec::new();
+    let mut events_seen = 0;
     for op in operations {
-        match &op.body {
+        let first_log = logs.len();
+        let outcome = match &op.body {
             OperationBody::InvokeHostFunction(invoke_op) => {
                 logs.push("Executing InvokeHostFunction...".to_string());
-                let val = host.invoke_function(invoke_op.host_function.clone())?;
-                logs.push(format!("Result: {val:?}"));
+                host.invoke_function(invoke_op.host_function.clone())
+                    .map(|val| logs.push(format!("Result: {val:?}")))
             }
             _ => {
                 logs.push(format!(
                     "Skipping non-Soroban operation: {:?}",
                     op.body.name()
                 ));
+                Ok(())
+            }
+        };
+
+        for log in &logs[first_log..] {
+            on_record(StreamRecord::Log { log: log.clone() });
+        }
+        if let Ok(evs) = host.get_events() {
+            for e in evs.0.iter().skip(events_seen) {
+                on_record(StreamRecord::Event {
+                    event: diagnostic_event(e),
+                });
             }
+            events_seen = evs.0.len();
         }
+        on_record(StreamRecord::Budget {
+            budget: budget_usage(host, operations.len()),
+        });
+
+        outcome?;
     }
     Ok(logs)
 }
 
+fn budget_usage(host: &Host, operations_count: usize) -> BudgetUsage {
+    let budget = host.budget_cloned();
+    let cpu_insns = budget.get_cpu_insns_consumed().unwrap_or(0);
+    let mem_bytes = budget.get_mem_bytes_consumed().unwrap_or(0);
+
+    BudgetUsage {
+        cpu_instructions: cpu_insns,
+        memory_bytes: mem_bytes,
+        operations_count,
+        cpu_limit: CPU_LIMIT,
+        memory_limit: MEMORY_LIMIT,
+        cpu_usage_percent: (cpu_insns as f64 / CPU_LIMIT as f64) * 100.0,
+        memory_usage_percent: (mem_bytes as f64 / MEMORY_LIMIT as f64) * 100.0,
+    }
+}
+
 fn transaction_fee_stroops(envelope: &soroban_env_host::xdr::TransactionEnvelope) -> u64 {
     match envelope {
         soroban_env_host::xdr::TransactionEnvelope::Tx(tx_v1) => tx_v1.tx.fee as u64,
@@ -132,7 +164,36 @@ fn mocked_required_fee_stroops(
     }
 }
 
-fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<CategorizedEvent> {
+fn diagnostic_event(e: &HostEvent) -> DiagnosticEvent {
+    let event_type = match e.event.type_ {
+        soroban_env_host::xdr::ContractEventType::Contract => "contract",
+        soroban_env_host::xdr::ContractEventType::System => "system",
+        soroban_env_host::xdr::ContractEventType::Diagnostic => "diagnostic",
+    }
+    .to_string();
+
+    let contract_id = e.event.contract_id.as_ref().map(|id| format!("{id:?}"));
+    let (topics, data) = match &e.event.body {
+        soroban_env_host::xdr::ContractEventBody::V0(v0) => (
+            v0.topics.iter().map(|t| format!("{t:?}")).collect::<Vec<String>>(),
+            format!("{:?}", v0.data),
+        ),
+    };
+
+    let wasm_instruction = extract_wasm_instruction(&topics, &data);
+    DiagnosticEvent {
+        event_type,
+        contract_id,
+        topics,
+        data,
+        // failed_call=true means the call that emitted this event
+        // actually failed; so a successful call is the inverse.
+        in_successful_contract_call: !e.failed_call,
+        wasm_instruction,
+    }
+}
+
+fn categorize_events(events: &Events) -> Vec<CategorizedEvent> {
     events
         .0
         .iter()
@@ -144,114 +205,76 @@ fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<Categoriz
             }
             .to_string();
 
-            let contract_id = e.event.contract_id.as_ref().map(|id| format!("{id:?}"));
-            let topics = match &e.event.body {
-                soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                    v0.topics
-                        .iter()
-                        .map(|t| format!("{:?}", t))
-                        .collect::<Vec<String>>()
-                    v0.topics.iter().map(|t| format!("{t:?}")).collect()
-                }
-            };
-            let data = match &e.event.body {
-                soroban_env_host::xdr::ContractEventBody::V0(v0) => format!("{:?}", v0.data),
-            };
-
-            let wasm_instruction = extract_wasm_instruction(&topics, &data);
             CategorizedEvent {
                 category,
-                event: DiagnosticEvent {
-                    event_type: match e.event.type_ {
-                        soroban_env_host::xdr::ContractEventType::Contract => {
-                            "contract".to_string()
-                        }
-                        soroban_env_host::xdr::ContractEventType::System => "system".to_string(),
-                        soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                            "diagnostic".to_string()
-                        }
-                    },
-                    contract_id,
-                    topics,
-                    data,
-                    in_successful_contract_call: e.failed_call,
-                    wasm_instruction,
-                    // failed_call=true means the call that emitted this event
-                    // actually failed; so a successful call is the inverse.
-                    in_successful_contract_call: !e.failed_call,
-                },
+                event: diagnostic_event(e),
             }
         })
         .collect()
 }
 
-/// Main entry point for the erst simulator.
-///
-/// Reads a JSON `SimulationRequest` from stdin, 
-/// initializes a Soroban host environment, and outputs a JSON
-/// `SimulationResponse` with simulation results or errors.
-///
-/// # Panics
-///
-/// May panic if JSON serialization of the response fails (should not happen
-/// with valid `SimulationResponse` structures).
-fn main() {
-    // 1. Initialize the logger immediately
-    init_logger();
+/// Collects the host's events as raw strings, structured diagnostic events
+/// and categorized events.
+fn collect_events(host: &Host) -> (Vec<String>, Vec<DiagnosticEvent>, Vec<CategorizedEvent>) {
+    match host.get_events() {
+        Ok(evs) => (
+            evs.0.iter().map(|e| format!("{e:?}")).collect(),
+            evs.0.iter().map(diagnostic_event).collect(),
+            categorize_events(&evs),
+        ),
+        Err(_) => (vec!["Failed to retrieve events".to_string()], vec![], vec![]),
+    }
+}
 
-    // 2. Log that we started
-    tracing::info!(event = "simulator_started", "Simulator initializing...");
+/// Finds the first diagnostic event that points at user code rather than at
+/// Rust stdlib panic wrappers.
+fn user_panic_point(events: &[DiagnosticEvent]) -> Option<String> {
+    for event in events {
+        let mut combined_text = event.data.clone();
+        for topic in &event.topics {
+            combined_text.push(' ');
+            combined_text.push_str(topic);
+        }
 
-    // Read JSON from Stdin
-    let mut buffer = String::new();
-    if let Err(e) = std::io::stdin().read_to_string(&mut buffer) {
-        let err_msg = format!("Failed to read stdin: {}", e);
-        let res = SimulationResponse {
-            status: "error".to_string(),
-            error: Some(err_msg.clone()),
-    if let Err(e) = io::stdin().read_to_string(&mut buffer) {
-        let res = SimulationResponse {
-            status: "error".to_string(),
-            error: Some(format!("Failed to read stdin: {e}")),
-            events: vec![],
-            diagnostic_events: vec![],
-            categorized_events: vec![],
-            logs: vec![],
-            flamegraph: None,
-            optimization_report: None,
-            budget_usage: None,
-            source_location: None,
-            stack_trace: None,
-        };
-        println!("{}", serde_json::to_string(&res).unwrap());
-        eprintln!("{}", err_msg);
-        eprintln!("Failed to read stdin: {e}");
-        return;
-    }
+        if !(combined_text.contains("panicked")
+            || combined_text.contains("Error")
+            || combined_text.contains("Trap"))
+        {
+            continue;
+        }
 
-    // Parse Request
-    let request: SimulationRequest = match serde_json::from_str(&buffer) {
-        Ok(req) => req,
-        Err(e) => {
-            let res = SimulationResponse {
-                status: "error".to_string(),
-                error: Some(format!("Invalid JSON: {e}")),
-                events: vec![],
-                diagnostic_events: vec![],
-                categorized_events: vec![],
-                logs: vec![],
-                flamegraph: None,
-                optimization_report: None,
-                budget_usage: None,
-                source_location: None,
-                stack_trace: None,
-                wasm_offset: None,
-            };
-            println!("{}", serde_json::to_string(&res).expect("Failed to serialize error response"));
-            return;
+        // Ignore known Rust stdlib wrappers commonly seen in Backtrace/Diagnostic events
+        if combined_text.contains("core/src/panicking.rs")
+            || combined_text.contains("core::panicking")
+            || combined_text.contains("rust_begin_unwind")
+            || combined_text.contains("std::rt::lang_start")
+            || combined_text.contains("compiler_builtins")
+            || combined_text.contains("rustc_std_workspace")
+        {
+            continue;
         }
-    };
 
+        // Look for common user paths (like src/lib.rs, etc)
+        if combined_text.contains(".rs") && !combined_text.contains("soroban-env-host") {
+            return Some(combined_text.replace('"', ""));
+        }
+    }
+    None
+}
+
+/// Runs one simulation request against a fresh Soroban host and returns the
+/// response. It never prints to stdout, so both the single-shot mode and the
+/// `--serve` loop can use it.
+pub fn simulate(request: &SimulationRequest) -> SimulationResponse {
+    simulate_with(request, &mut |_| {})
+}
+
+/// Like `simulate`, but passes progress records to on_record while the
+/// operations execute; `--stream` prints them as they arrive.
+pub fn simulate_with(
+    request: &SimulationRequest,
+    on_record: &mut dyn FnMut(StreamRecord),
+) -> SimulationResponse {
     // Decode Envelope XDR
     let envelope = match base64::engine::general_purpose::STANDARD.decode(&request.envelope_xdr) {
         Ok(bytes) => match soroban_env_host::xdr::TransactionEnvelope::from_xdr(
@@ -259,58 +282,39 @@ fn main() {
             soroban_env_host::xdr::Limits::none(),
         ) {
             Ok(env) => env,
-            Err(e) => {
-                send_error(format!("Failed to parse Envelope XDR: {}", e));
-                return;
-            }
+            Err(e) => return error_response(format!("Failed to parse Envelope XDR: {e}")),
         },
-        Err(e) => {
-            send_error(format!("Failed to decode Envelope Base64: {}", e));
-            return;
-        }
+        Err(e) => return error_response(format!("Failed to decode Envelope Base64: {e}")),
     };
 
     // Decode ResultMeta XDR
-    eprintln!(
-        "Debug: Received ResultMetaXdr len: {}",
-        request.result_meta_xdr.len()
-    );
-
-    let _result_meta = if request.result_meta_xdr.is_empty() {
+    if request.result_meta_xdr.is_empty() {
         eprintln!("Warning: ResultMetaXdr is empty. Host storage may be incomplete.");
-        None
     } else {
         match base64::engine::general_purpose::STANDARD.decode(&request.result_meta_xdr) {
+            Ok(bytes) if bytes.is_empty() => {
+                eprintln!("Warning: ResultMetaXdr decoded to 0 bytes.");
+            }
             Ok(bytes) => {
-                if bytes.is_empty() {
-                    eprintln!("Warning: ResultMetaXdr decoded to 0 bytes.");
-                    None
-                } else {
-                    match soroban_env_host::xdr::TransactionResultMeta::from_xdr(
-                        &bytes,
-                        soroban_env_host::xdr::Limits::none(),
-                    ) {
-                        Ok(meta) => Some(meta),
-                        Err(e) => {
-                            eprintln!("Warning: Failed to parse ResultMeta XDR: {}. Proceeding with empty storage.", e);
-                            None
-                        }
-                    }
+                if let Err(e) = soroban_env_host::xdr::TransactionResultMeta::from_xdr(
+                    &bytes,
+                    soroban_env_host::xdr::Limits::none(),
+                ) {
+                    eprintln!("Warning: Failed to parse ResultMeta XDR: {e}. Proceeding with empty storage.");
                 }
             }
             Err(e) => {
                 eprintln!("Warning: Failed to decode ResultMeta Base64: {e}. Proceeding with empty storage.");
-                None
             }
         }
-    };
+    }
 
     // Initialize source mapper if WASM is provided
-    let _source_mapper = if let Some(wasm_base64) = &request.contract_wasm {
+    let source_mapper = if let Some(wasm_base64) = &request.contract_wasm {
         match base64::engine::general_purpose::STANDARD.decode(wasm_base64) {
             Ok(wasm_bytes) => {
                 if let Err(e) = vm::enforce_soroban_compatibility(&wasm_bytes) {
-                    return send_error(format!("Strict VM enforcement failed: {}", e));
+                    return error_response(format!("Strict VM enforcement failed: {e}"));
                 }
                 let mapper = SourceMapper::new(wasm_bytes);
                 if mapper.has_debug_symbols() {
@@ -330,74 +334,55 @@ fn main() {
         None
     };
 
+    // Decode the supplied ledger state; the host reads it through a
+    // recording footprint so the keys it touches can be reported.
+    let snapshot = match &request.ledger_entries {
+        Some(entries) => match snapshot::LedgerSnapshot::from_base64_map(entries) {
+            Ok(snapshot) => snapshot,
+            Err(e) => return error_response(format!("Failed to load ledger entries: {e}")),
+        },
+        None => snapshot::LedgerSnapshot::new(),
+    };
+    let loaded_entries_count = snapshot.len();
+    let store = std::rc::Rc::new(recording::SnapshotStore::new(snapshot));
+
     // Initialize Host
-    let sim_host = runner::SimHost::new(None, request.resource_calibration.clone());
+    let sim_host = runner::SimHost::with_storage(
+        recording::recording_storage(store.clone()),
+        request
+            .budget_limits
+            .map(|l| (l.cpu_instructions, l.memory_bytes)),
+        request.resource_calibration.clone(),
+        &request.cost_params,
+    );
     let host = sim_host.inner;
+    if let Err(e) = host.set_ledger_info(ledger::ledger_info(request)) {
+        return error_response(format!("Failed to set ledger info: {e:?}"));
+    }
 
     // --- START: Local WASM Loading Integration (Issue #70) ---
-    if let Some(path) = &request.wasm_path {
-        match wasm::load_wasm_from_path(path) {
-            Ok(wasm_bytes) => match host.upload_contract_wasm(wasm_bytes) {
-                Ok(hash) => eprintln!("Successfully loaded local WASM. Hash: {:?}", hash),
-                Err(e) => send_error(format!("Host failed to upload local WASM: {:?}", e)),
-            },
-            Err(e) => send_error(format!("Local WASM loading failed: {}", e)),
-        }
+    // A remote erst sends the WASM inline as contract_wasm instead of a path.
+    let local_wasm = match (&request.wasm_path, &request.contract_wasm) {
+        (Some(path), _) => Some(
+            wasm::load_wasm_from_path(path).map_err(|e| format!("Local WASM loading failed: {e}")),
+        ),
+        (None, Some(wasm_base64)) => Some(
+            base64::engine::general_purpose::STANDARD
+                .decode(wasm_base64)
+                .map_err(|e| format!("Invalid contract_wasm: {e}")),
+        ),
+        (None, None) => None,
+    };
+    match local_wasm {
+        Some(Ok(wasm_bytes)) => match host.upload_contract_wasm(wasm_bytes) {
+            Ok(hash) => eprintln!("Successfully loaded local WASM. Hash: {hash:?}"),
+            Err(e) => return error_response(format!("Host failed to upload local WASM: {e:?}")),
+        },
+        Some(Err(e)) => return error_response(e),
+        None => {}
     }
     // --- END: Local WASM Loading Integration ---
 
-    let mut loaded_entries_count = 0;
-
-    // Populate Host Storage
-    if let Some(entries) = &request.ledger_entries {
-        for (key_xdr, entry_xdr) in entries {
-            // Decode Key
-            let _key = match base64::engine::general_purpose::STANDARD.decode(key_xdr) {
-                Ok(b) => match soroban_env_host::xdr::LedgerKey::from_xdr(
-                    b,
-                    soroban_env_host::xdr::Limits::none(),
-                ) {
-                    Ok(k) => k,
-                    Err(e) => {
-                        send_error(format!("Failed to parse LedgerKey XDR: {}", e));
-                        return;
-                    }
-                },
-                Err(e) => {
-                    send_error(format!("Failed to decode LedgerKey Base64: {}", e));
-                    return;
-                }
-            };
-
-            // Decode Entry
-            let _entry = match base64::engine::general_purpose::STANDARD.decode(entry_xdr) {
-                Ok(b) => match soroban_env_host::xdr::LedgerEntry::from_xdr(
-                    b,
-                    soroban_env_host::xdr::Limits::none(),
-                ) {
-                    Ok(e) => e,
-                    Err(e) => {
-                        send_error(format!("Failed to parse LedgerEntry XDR: {}", e));
-                        return;
-                    }
-                },
-                Err(e) => {
-                    send_error(format!("Failed to decode LedgerEntry Base64: {}", e));
-                    return;
-                }
-            };
-
-            // TODO: Inject into host storage.
-            // For MVP, we verify we can parse them.
-            eprintln!("Parsed Ledger Entry: Key={:?}, Entry={:?}", _key, _entry);
-            loaded_entries_count += 1;
-        }
-    } else {
-        snapshot::LedgerSnapshot::new()
-    };
-
-    let loaded_entries_count = snapshot.len();
-
     // Extract Operations and Simulate
     let operations = match &envelope {
         soroban_env_host::xdr::TransactionEnvelope::Tx(tx_v1) => &tx_v1.tx.operations,
@@ -409,26 +394,18 @@ fn main() {
 
     // Wrap the operation execution in panic protection
     let result = std::panic::catch_unwind(std::panic::AssertUnwindSafe(|| {
-        execute_operations(&host, operations)
+        execute_operations(&host, operations, on_record)
     }));
 
     // Budget and Reporting
     let budget = host.budget_cloned();
-    let cpu_insns = budget.get_cpu_insns_consumed().unwrap_or(0);
-    let mem_bytes = budget.get_mem_bytes_consumed().unwrap_or(0);
-
-    let cpu_usage_percent = (cpu_insns as f64 / CPU_LIMIT as f64) * 100.0;
-    let memory_usage_percent = (mem_bytes as f64 / MEMORY_LIMIT as f64) * 100.0;
-
-    let budget_usage = BudgetUsage {
-        cpu_instructions: cpu_insns,
-        memory_bytes: mem_bytes,
-        operations_count: operations.len(),
-        cpu_limit: CPU_LIMIT,
-        memory_limit: MEMORY_LIMIT,
-        cpu_usage_percent,
-        memory_usage_percent,
-    };
+    let ledger_access = host
+        .with_mut_storage(|storage| recording::ledger_access(storage, &store, &budget))
+        .map_err(|e| eprintln!("Failed to record ledger access: {e:?}"))
+        .ok();
+    let budget_usage = budget_usage(&host, operations.len());
+    let cpu_insns = budget_usage.cpu_instructions;
+    let mem_bytes = budget_usage.memory_bytes;
 
     let optimization_report = if request.enable_optimization_advisor {
         let advisor = GasOptimizationAdvisor::new();
@@ -445,7 +422,7 @@ fn main() {
     let mut flamegraph_svg = None;
     if request.profile.unwrap_or(false) {
         // Simple simulated flamegraph for demonstration
-        let folded_data = format!("Total;CPU {}\nTotal;Memory {}\n", cpu_insns, mem_bytes);
+        let folded_data = format!("Total;CPU {cpu_insns}\nTotal;Memory {mem_bytes}\n");
         let mut result_vec = Vec::new();
         let mut options = inferno::flamegraph::Options::default();
         options.title = "Soroban Resource Consumption".to_string();
@@ -461,144 +438,38 @@ fn main() {
 
     match result {
         Ok(Ok(exec_logs)) => {
-            // Extract both raw event strings and structured diagnostic events
-            let (events, diagnostic_events): (Vec<String>, Vec<DiagnosticEvent>) =
-                match host.get_events() {
-                    Ok(evs) => {
-                        let raw_events: Vec<String> =
-                            evs.0.iter().map(|e| format!("{:?}", e)).collect();
-                        let diag_events: Vec<DiagnosticEvent> = evs
-                            .0
-                            .iter()
-                            .map(|event| {
-                                let event_type = match &event.event.type_ {
-                                    soroban_env_host::xdr::ContractEventType::Contract => {
-                                        "contract".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::System => {
-                                        "system".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                                        "diagnostic".to_string()
-                                    }
-                                };
-
-                                let contract_id = event
-                                    .event
-                                    .contract_id
-                                    .as_ref()
-                                    .map(|contract_id| format!("{:?}", contract_id));
-
-                                let (topics, data) = match &event.event.body {
-                                    soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                                        let topics: Vec<String> =
-                                            v0.topics.iter().map(|t| format!("{:?}", t)).collect();
-                                        let data = format!("{:?}", v0.data);
-                                        (topics, data)
-                                    }
-                                };
-
-                                let wasm_instruction = extract_wasm_instruction(&topics, &data);
-                                DiagnosticEvent {
-                                    event_type,
-                                    contract_id,
-                                    topics,
-                                    data,
-                                    in_successful_contract_call: event.failed_call,
-                                    wasm_instruction,
-                                    // failed_call=true means the call failed;
-                                    // negate to get "was this a successful call?".
-                                    in_successful_contract_call: !event.failed_call,
-                                }
-                                soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                                    "diagnostic".to_string()
-                                }
-                            };
-
-                            let contract_id = event
-                                .event
-                                .contract_id
-                                .as_ref()
-                                .map(|contract_id| format!("{contract_id:?}"));
-
-                            let (topics, data) = match &event.event.body {
-                                soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                                    let topics: Vec<String> =
-                                        v0.topics.iter().map(|t| format!("{t:?}")).collect();
-                                    let data = format!("{:?}", v0.data);
-                                    (topics, data)
-                                }
-                            };
-
-                            DiagnosticEvent {
-                                event_type,
-                                contract_id,
-                                topics,
-                                data,
-                                in_successful_contract_call: event.failed_call,
-                            }
-                        })
-                        .collect();
-                    (raw_events, diag_events)
-                }
-                Err(_) => (
-                    vec!["Failed to retrieve events".to_string()],
-                    Vec::<DiagnosticEvent>::new(),
-                ),
-            };
-
-            // Capture categorized events for analyzer
-            let categorized_events = match host.get_events() {
-                Ok(evs) => categorize_events(&evs),
-                Err(_) => vec![],
-            };
+            let (events, diagnostic_events, categorized_events) = collect_events(&host);
 
             let mut final_logs = vec![
-                format!("Host Initialized with Budget: {:?}", budget),
-                format!("Loaded {} Ledger Entries", loaded_entries_count),
+                format!("Host Initialized with Budget: {budget:?}"),
+                format!("Loaded {loaded_entries_count} Ledger Entries"),
                 format!("Captured {} diagnostic events", diagnostic_events.len()),
-                format!("CPU Instructions Used: {}", cpu_insns),
-                format!("Memory Bytes Used: {}", mem_bytes),
+                format!("CPU Instructions Used: {cpu_insns}"),
+                format!("Memory Bytes Used: {mem_bytes}"),
             ];
             final_logs.extend(exec_logs);
 
-            if let Some(required_fee) = mocked_required_fee_stroops(
-                &request,
-                operations.as_slice().len(),
-                cpu_insns,
-                mem_bytes,
-            ) {
+            let mut status = "success".to_string();
+            let mut error = None;
+            if let Some(required_fee) =
+                mocked_required_fee_stroops(request, operations.len(), cpu_insns, mem_bytes)
+            {
                 let declared_fee = transaction_fee_stroops(&envelope);
                 final_logs.push(format!(
-                    "Mock fee check: declared={} required={}",
-                    declared_fee, required_fee
+                    "Mock fee check: declared={declared_fee} required={required_fee}"
                 ));
 
                 if declared_fee < required_fee {
-                    let response = SimulationResponse {
-                        status: "error".to_string(),
-                        error: Some(format!(
-                            "insufficient fee (mocked): declared {} stroops, required {} stroops",
-                            declared_fee, required_fee
-                        )),
-                        events,
-                        diagnostic_events,
-                        categorized_events,
-                        logs: final_logs,
-                        flamegraph: flamegraph_svg,
-                        optimization_report,
-                        budget_usage: Some(budget_usage),
-                        source_location: None,
-                    };
-
-                    println!("{}", serde_json::to_string(&response).unwrap());
-                    return;
+                    status = "error".to_string();
+                    error = Some(format!(
+                        "insufficient fee (mocked): declared {declared_fee} stroops, required {required_fee} stroops"
+                    ));
                 }
             }
 
-            let response = SimulationResponse {
-                status: "success".to_string(),
-                error: None,
+            SimulationResponse {
+                status,
+                error,
                 events,
                 diagnostic_events,
                 categorized_events,
@@ -606,186 +477,58 @@ fn main() {
                 flamegraph: flamegraph_svg,
                 optimization_report,
                 budget_usage: Some(budget_usage),
-                source_location: None,
-                stack_trace: None,
                 // If a WASM with debug symbols was provided, expose the first
                 // mappable source location so callers can correlate failures.
                 source_location: source_mapper
                     .as_ref()
                     .and_then(|m| m.map_wasm_offset_to_source(0))
                     .and_then(|loc| serde_json::to_string(&loc).ok()),
-            };
-
-            println!("{}", serde_json::to_string(&response).unwrap());
+                stack_trace: None,
+                wasm_offset: None,
+                ledger_access,
+            }
+        }
         Ok(Err(host_error)) => {
             // Host error during execution (e.g., contract trap, validation failure)
-            let error_msg = format!("{:?}", host_error);
-            let decoded_msg = decode_error(&error_msg);
-            
-            let structured_error = StructuredError {
-                error_type: "HostError".to_string(),
-                message: decoded_msg.clone(),
-                details: Some(format!(
-                    "Contract execution failed with host error: {}",
-                    decoded_msg
-                )),
-            let error_debug = format!("{:?}", host_error);
+            let error_debug = format!("{host_error:?}");
             let wasm_trace = WasmStackTrace::from_host_error(&error_debug);
+            let (events, diagnostic_events, categorized_events) = collect_events(&host);
 
-            let structured_error = StructuredError {
-                error_type: "HostError".to_string(),
-                message: error_debug.clone(),
-                details: Some(format!(
-                    "Contract execution failed with host error: {}",
-                    error_debug
-                )),
-            };
-
-            let trace_display = wasm_trace.display();
-
-            // Extract both raw event strings and structured diagnostic events
-            let (events, diagnostic_events): (Vec<String>, Vec<DiagnosticEvent>) =
-                match host.get_events() {
-                    Ok(evs) => {
-                        let raw_events: Vec<String> =
-                            evs.0.iter().map(|e| format!("{:?}", e)).collect();
-                        let diag_events: Vec<DiagnosticEvent> = evs
-                            .0
-                            .iter()
-                            .map(|event| {
-                                let event_type = match &event.event.type_ {
-                                    soroban_env_host::xdr::ContractEventType::Contract => {
-                                        "contract".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::System => {
-                                        "system".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                                        "diagnostic".to_string()
-                                    }
-                                };
-
-                                let contract_id = event
-                                    .event
-                                    .contract_id
-                                    .as_ref()
-                                    .map(|contract_id| format!("{:?}", contract_id));
-
-                                let (topics, data) = match &event.event.body {
-                                    soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                                        let topics: Vec<String> =
-                                            v0.topics.iter().map(|t| format!("{:?}", t)).collect();
-                                        let data = format!("{:?}", v0.data);
-                                        (topics, data)
-                                    }
-                                };
-
-                                DiagnosticEvent {
-                                    event_type,
-                                    contract_id,
-                                    topics,
-                                    data,
-                                    in_successful_contract_call: event.failed_call,
-                                }
-                            })
-                            .collect();
-                        (raw_events, diag_events)
-                    }
-                    Err(_) => (
-                        vec!["Failed to retrieve events".to_string()],
-                        Vec::<DiagnosticEvent>::new(),
-                    ),
-                };
-
-            // Capture categorized events for analyzer
-            let categorized_events = match host.get_events() {
-                Ok(evs) => categorize_events(&evs),
-                Err(_) => vec![],
-            };
-
-            // Heuristic to ignore Rust stdlib panic wrappers and find the actual source point
-            let mut user_panic_point = None;
-            for event in &diagnostic_events {
-                let mut combined_text = event.data.clone();
-                for topic in &event.topics {
-                    combined_text.push_str(" ");
-                    combined_text.push_str(topic);
-                }
-
-                if combined_text.contains("panicked")
-                    || combined_text.contains("Error")
-                    || combined_text.contains("Trap")
-                {
-                    // Ignore known Rust stdlib wrappers commonly seen in Backtrace/Diagnostic events
-                    if combined_text.contains("core/src/panicking.rs")
-                        || combined_text.contains("core::panicking")
-                        || combined_text.contains("rust_begin_unwind")
-                        || combined_text.contains("std::rt::lang_start")
-                        || combined_text.contains("compiler_builtins")
-                        || combined_text.contains("rustc_std_workspace")
-                    {
-                        continue;
-                    }
-
-                    // Look for common user paths (like src/lib.rs, etc)
-                    if combined_text.contains(".rs") && !combined_text.contains("soroban-env-host")
-                    {
-                        user_panic_point = Some(combined_text.replace("\"", ""));
-                        // Break after finding the first valid panic point so we don't overwrite it with deeper arbitrary ones
-                        break;
-                    }
-                }
-            }
-
-            let details = if let Some(ref point) = user_panic_point {
-                format!(
-                    "Contract execution failed with host error: {:?}. Panic point: {}",
-                    host_error, point
-                )
-            } else {
-                format!(
-                    "Contract execution failed with host error: {:?}",
-                    host_error
-                )
+            let details = match user_panic_point(&diagnostic_events) {
+                Some(point) => format!(
+                    "Contract execution failed with host error: {error_debug}. Panic point: {point}"
+                ),
+                None => format!("Contract execution failed with host error: {error_debug}"),
             };
-
             let structured_error = StructuredError {
                 error_type: "HostError".to_string(),
-                message: format!("{:?}", host_error),
+                message: error_debug.clone(),
                 details: Some(details),
             };
 
-            let error_msg = format!("{:?}", host_error);
-            let wasm_offset = extract_wasm_offset(&error_msg);
-            
-            let source_location = if let (Some(offset), Some(mapper)) = (wasm_offset, &source_mapper) {
-                mapper.map_wasm_offset_to_source(offset)
-            } else {
-                None
+            let wasm_offset = extract_wasm_offset(&error_debug);
+            let source_location = match (wasm_offset, &source_mapper) {
+                (Some(offset), Some(mapper)) => mapper
+                    .map_wasm_offset_to_source(offset)
+                    .and_then(|loc| serde_json::to_string(&loc).ok()),
+                _ => None,
             };
 
-            let error_msg = format!("{:?}", host_error);
-            let wasm_offset = extract_wasm_offset(&error_msg);
-
-            let response = SimulationResponse {
+            SimulationResponse {
                 status: "error".to_string(),
-                error: Some(serde_json::to_string(&structured_error).unwrap()),
-                events: vec![],
-                diagnostic_events: vec![],
-                categorized_events: vec![],
-                logs: vec![format!("Stack trace:\n{}", trace_display)],
+                error: Some(serde_json::to_string(&structured_error).unwrap_or(error_debug)),
                 events,
                 diagnostic_events,
                 categorized_events,
-                logs: vec![],
+                logs: vec![format!("Stack trace:\n{}", wasm_trace.display())],
                 flamegraph: None,
                 optimization_report: None,
-                budget_usage: None,
-                source_location: None,
+                budget_usage: Some(budget_usage),
+                source_location,
                 stack_trace: Some(wasm_trace),
                 wasm_offset,
-            };
-            println!("{}", serde_json::to_string(&response).unwrap());
+                ledger_access,
+            }
         }
         Err(panic_info) => {
             let panic_msg = if let Some(s) = panic_info.downcast_ref::<&str>() {
@@ -796,24 +539,118 @@ fn main() {
                 "Unknown panic".to_string()
             };
 
-            let wasm_trace = WasmStackTrace::from_panic(&panic_msg);
+            let mut response = SimulationResponse::failure(
+                format!("Simulator panicked: {panic_msg}"),
+                Some(WasmStackTrace::from_panic(&panic_msg)),
+            );
+            response.logs = vec![format!("PANIC: {panic_msg}")];
+            response
+        }
+    }
+}
 
-            let response = SimulationResponse {
-                status: "error".to_string(),
-                error: Some(format!("Simulator panicked: {panic_msg}")),
-                events: vec![],
-                diagnostic_events: vec![],
-                categorized_events: vec![],
-                logs: vec![format!("PANIC: {}", panic_msg)],
-                flamegraph: None,
-                optimization_report: None,
-                budget_usage: None,
-                source_location: None,
-                stack_trace: Some(wasm_trace),
-                wasm_offset: None,
-            };
+/// Main entry point for the erst simulator.
+///
+/// Reads a JSON `SimulationRequest` from stdin, runs it with `simulate` and
+/// writes the JSON `SimulationResponse` to stdout. With `--serve` it instead
+/// answers length-prefixed requests until stdin is closed; see `serve`.
+///
+/// # Panics
+///
+/// May panic if JSON serialization of the response fails (should not happen
+/// with valid `SimulationResponse` structures).
+fn main() {
+    // `erst-sim --version` reports the build so `erst sim register` can record
+    // which soroban-env-host, and therefore which protocol, the binary embeds.
+    if env::args().any(|arg| arg == "--version") {
+        println!(
+            "erst-sim {} (soroban-env-host {})",
+            env!("CARGO_PKG_VERSION"),
+            soroban_env_host::VERSION.pkg
+        );
+        return;
+    }
+
+    // `erst-sim --capabilities` is the handshake erst performs once per
+    // binary before sending requests.
+    if env::args().any(|arg| arg == "--capabilities") {
+        let host_version = soroban_env_host::VERSION.pkg;
+        let max_protocol: u32 = host_version
+            .split('.')
+            .next()
+            .and_then(|major| major.parse().ok())
+            .unwrap_or(0);
+        let capabilities = serde_json::json!({
+            "schema_version": SCHEMA_VERSION,
+            "version": env!("CARGO_PKG_VERSION"),
+            "soroban_env_host": host_version,
+            "max_protocol": max_protocol,
+            "features": ["flamegraph", "stack_trace", "optimization_advisor", "mock_fees", "serve", "stream", "ledger_info", "ledger_access", "budget_limits", "cost_params"],
+        });
+        println!("{capabilities}");
+        return;
+    }
+
+    // 1. Initialize the logger immediately
+    init_logger();
+
+    // `erst-sim --serve` keeps the process alive for erst's simulator pool.
+    if env::args().any(|arg| arg == "--serve") {
+        tracing::info!(event = "simulator_serving", "Simulator serving framed requests...");
+        if let Err(e) = serve::run() {
+            eprintln!("Serve loop failed: {e}");
+            std::process::exit(1);
+        }
+        return;
+    }
+
+    // `erst-sim --stream` writes one JSON record per line as execution
+    // progresses, ending with the full response.
+    let stream = env::args().any(|arg| arg == "--stream");
+
+    // 2. Log that we started
+    tracing::info!(event = "simulator_started", "Simulator initializing...");
+
+    let finish = |response: SimulationResponse| {
+        if stream {
+            print_record(&StreamRecord::Result { response });
+        } else {
             println!("{}", serde_json::to_string(&response).unwrap());
         }
+    };
+
+    // Read JSON from Stdin
+    let mut buffer = String::new();
+    if let Err(e) = io::stdin().read_to_string(&mut buffer) {
+        eprintln!("Failed to read stdin: {e}");
+        finish(SimulationResponse::failure(format!("Failed to read stdin: {e}"), None));
+        return;
+    }
+
+    // Parse Request
+    let request: SimulationRequest = match serde_json::from_str(&buffer) {
+        Ok(req) => req,
+        Err(e) => {
+            finish(SimulationResponse::failure(format!("Invalid JSON: {e}"), None));
+            return;
+        }
+    };
+
+    let response = if stream {
+        simulate_with(&request, &mut |record| print_record(&record))
+    } else {
+        simulate(&request)
+    };
+    finish(response);
+}
+
+/// Writes one NDJSON record to stdout and flushes it so erst sees it
+/// immediately.
+fn print_record(record: &StreamRecord) {
+    let mut stdout = io::stdout().lock();
+    if let Ok(line) = serde_json::to_string(record) {
+        let _ = writeln!(stdout, "{line}");
+        let _ = stdout.flush();
     }
 }
 
@@ -821,14 +658,35 @@ fn extract_wasm_offset(error_msg: &str) -> Option<u64> {
     // Look for patterns like "@ 0x[HEX]" in the error message
     // Soroban/Wasmi errors often contain stack traces like:
     // "  0: func[42] @ 0xa3c"
-    
     for line in error_msg.lines() {
         if let Some(pos) = line.find("@ 0x") {
             let hex_part = &line[pos + 4..];
-            let end = hex_part.find(|c: char| !c.is_ascii_hexdigit()).unwrap_or(hex_part.len());
+            let end = hex_part
+                .find(|c: char| !c.is_ascii_hexdigit())
+                .unwrap_or(hex_part.len());
             if let Ok(offset) = u64::from_str_radix(&hex_part[..end], 16) {
                 return Some(offset);
             }
+        }
+    }
+    None
+}
+
+/// Extracts the WASM instruction named by a budget diagnostic event, whose
+/// data reads like `"Instruction: i32.add"`.
+fn extract_wasm_instruction(topics: &[String], data: &str) -> Option<String> {
+    if !topics.iter().any(|t| t.contains("budget")) {
+        return None;
+    }
+    let pos = data.find("Instruction: ")?;
+    let instruction = data[pos + "Instruction: ".len()..].trim_end_matches('"').trim();
+    if instruction.is_empty() {
+        None
+    } else {
+        Some(instruction.to_string())
+    }
+}
+
 /// Translate a raw soroban / WASM error string into a user-friendly description.
 ///
 /// Protocol 21 standardised the set of VM trap codes emitted by the host.
@@ -853,7 +711,7 @@ pub fn decode_error(raw: &str) -> String {
         if lower.contains("indirect call") || lower.contains("table") {
             return "VM Trap: Indirect-Call Type Mismatch — wrong function signature in call_indirect.".to_string();
         }
-        return format!("VM Trap: {}", raw);
+        return format!("VM Trap: {raw}");
     }
 
     if lower.contains("auth") || lower.contains("unauthorized") {
@@ -878,10 +736,10 @@ mod tests {
 
     #[test]
     fn test_decode_vm_traps() {
-        assert!(decode_error("Error: Wasm Trap: out of bounds memory access").contains("VM Trap: Out of Bounds Access"));
-        assert!(decode_error("Panic: unreachable").contains("VM Trap: Unreachable Instruction"));
-        assert!(decode_error("integer divide by zero").contains("VM Trap: Division by Zero"));
-        assert!(decode_error("stack overflow occurred").contains("VM Trap: Stack Overflow"));
+        assert!(decode_error("Error: Wasm Trap: out of bounds memory access")
+            .contains("VM Trap: Out of Bounds Access"));
+        assert!(decode_error("wasm trap: integer divide by zero").contains("VM Trap: Arithmetic Trap"));
+        assert!(decode_error("wasm trap: stack overflow occurred").contains("VM Trap: Stack Overflow"));
         assert_eq!(decode_error("normal error"), "normal error");
     }
 
@@ -899,26 +757,47 @@ mod tests {
         let topics_none = vec!["other".to_string()];
         let instr3 = extract_wasm_instruction(&topics_none, &data);
         assert_eq!(instr3, None);
-        let msg = decode_error("Error: Wasm Trap: out of bounds memory access");
-        assert!(msg.contains("VM Trap: Out of bounds memory access"));
     }
 
     #[test]
     fn test_decode_unreachable() {
         let msg = decode_error("wasm trap: unreachable");
         assert!(msg.contains("VM Trap: Unreachable"));
-    fn test_enforce_soroban_compatibility_rejects_floats() {
-        let wat = r#"
-            (module
-                (func (export "f") (result f32)
-                    f32.const 0.0
-                )
-            )
-        "#;
-
-        let wasm = wat::parse_str(wat).expect("failed to compile WAT");
-        let result = vm::enforce_soroban_compatibility(&wasm);
-        assert!(result.is_err());
+    }
+
+    #[test]
+    fn test_stream_record_shape() {
+        let record = StreamRecord::Log {
+            log: "Executing InvokeHostFunction...".to_string(),
+        };
+        let json: serde_json::Value = serde_json::to_value(&record).unwrap();
+        assert_eq!(json["type"], "log");
+        assert_eq!(json["log"], "Executing InvokeHostFunction...");
+
+        let result = StreamRecord::Result {
+            response: SimulationResponse::failure("boom".to_string(), None),
+        };
+        let json: serde_json::Value = serde_json::to_value(&result).unwrap();
+        assert_eq!(json["type"], "result");
+        assert_eq!(json["response"]["status"], "error");
+    }
+
+    #[test]
+    fn test_extract_wasm_offset() {
+        assert_eq!(extract_wasm_offset("  0: func[42] @ 0xa3c\n"), Some(0xa3c));
+        assert_eq!(extract_wasm_offset("no offset here"), None);
+    }
+
+    #[test]
+    fn test_simulate_rejects_bad_envelope() {
+        let request: SimulationRequest =
+            serde_json::from_str(r#"{"envelope_xdr":"not base64!","result_meta_xdr":""}"#).unwrap();
+        let response = simulate(&request);
+        assert_eq!(response.status, "error");
+        assert!(response
+            .error
+            .unwrap()
+            .contains("Failed to decode Envelope Base64"));
     }
 
     // ── Protocol-21 host-trait correctness ─────────────────────────────────
@@ -928,7 +807,6 @@ mod tests {
     /// This was silently backwards before the protocol-21 fix.
     #[test]
     fn test_in_successful_contract_call_is_negation_of_failed_call() {
-        use soroban_env_host::events::{Events, HostEvent};
         use soroban_env_host::xdr::{
             ContractEvent, ContractEventBody, ContractEventType, ContractEventV0,
             ExtensionPoint, VecM,
@@ -972,7 +850,6 @@ mod tests {
     /// lowercase string representations.
     #[test]
     fn test_categorize_events_type_labels() {
-        use soroban_env_host::events::{Events, HostEvent};
         use soroban_env_host::xdr::{
             ContractEvent, ContractEventBody, ContractEventType, ContractEventV0,
             ExtensionPoint, VecM,
@@ -1012,8 +889,6 @@ mod tests {
     /// and the `source_location` field stays absent in serialized JSON.
     #[test]
     fn test_source_mapper_no_symbols_gives_no_location() {
-        use crate::source_mapper::SourceMapper;
-
         let wasm_bytes = vec![0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00]; // WASM magic + version
         let mapper = SourceMapper::new(wasm_bytes);
         assert!(!mapper.has_debug_symbols());
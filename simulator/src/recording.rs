@@ -0,0 +1,153 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! Ledger access recording. The host runs with a recording footprint over the
+//! request's ledger snapshot, so contracts read the supplied entries and every
+//! key they touch is reported back to erst as `ledger_access`.
+
+use crate::snapshot::LedgerSnapshot;
+use crate::types::LedgerAccess;
+use base64::Engine;
+use sha2::{Digest, Sha256};
+use soroban_env_host::{
+    budget::Budget,
+    storage::{AccessType, EntryWithLiveUntil, SnapshotSource, Storage},
+    xdr::{Hash, LedgerEntryData, LedgerKey, LedgerKeyTtl, Limits, WriteXdr},
+    HostError,
+};
+use std::rc::Rc;
+
+/// Serves host storage reads from the request's ledger snapshot.
+pub struct SnapshotStore {
+    snapshot: LedgerSnapshot,
+}
+
+impl SnapshotStore {
+    pub fn new(snapshot: LedgerSnapshot) -> Self {
+        Self { snapshot }
+    }
+
+    fn entry(&self, key: &LedgerKey) -> Option<&soroban_env_host::xdr::LedgerEntry> {
+        let bytes = key.to_xdr(Limits::none()).ok()?;
+        self.snapshot.get(&bytes)
+    }
+
+    /// Returns the live-until ledger of a contract data or code entry from
+    /// its TTL entry. Entries supplied without one are treated as live.
+    fn live_until(&self, key: &LedgerKey) -> Option<u32> {
+        if !matches!(key, LedgerKey::ContractData(_) | LedgerKey::ContractCode(_)) {
+            return None;
+        }
+        let key_hash = key
+            .to_xdr(Limits::none())
+            .map(|bytes| Hash(Sha256::digest(bytes).into()))
+            .ok()?;
+        let ttl = self
+            .entry(&LedgerKey::Ttl(LedgerKeyTtl { key_hash }))
+            .and_then(|e| match &e.data {
+                LedgerEntryData::Ttl(ttl) => Some(ttl.live_until_ledger_seq),
+                _ => None,
+            });
+        Some(ttl.unwrap_or(u32::MAX))
+    }
+}
+
+impl SnapshotSource for SnapshotStore {
+    fn get(&self, key: &Rc<LedgerKey>) -> Result<Option<EntryWithLiveUntil>, HostError> {
+        Ok(self
+            .entry(key)
+            .map(|entry| (Rc::new(entry.clone()), self.live_until(key))))
+    }
+}
+
+/// Returns storage that records the footprint while reading from store.
+pub fn recording_storage(store: Rc<SnapshotStore>) -> Storage {
+    Storage::with_recording_footprint(store)
+}
+
+/// Lists the keys the host read and the keys whose entries changed relative
+/// to the snapshot, as base64 LedgerKeys.
+pub fn ledger_access(
+    storage: &Storage,
+    store: &SnapshotStore,
+    budget: &Budget,
+) -> Result<LedgerAccess, HostError> {
+    let encode = |key: &LedgerKey| {
+        key.to_xdr(Limits::none())
+            .map(|bytes| base64::engine::general_purpose::STANDARD.encode(bytes))
+            .ok()
+    };
+
+    let mut access = LedgerAccess::default();
+    for (key, access_type) in storage.footprint.0.iter(budget)? {
+        let Some(encoded) = encode(key) else {
+            continue;
+        };
+        access.read.push(encoded.clone());
+
+        if *access_type != AccessType::ReadWrite {
+            continue;
+        }
+        let after = storage
+            .map
+            .get::<Rc<LedgerKey>>(key, budget)?
+            .and_then(|value| value.as_ref().map(|(entry, _)| entry.as_ref().clone()));
+        let before = store.entry(key).cloned();
+        if after != before {
+            access.written.push(encoded);
+        }
+    }
+    Ok(access)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use soroban_env_host::xdr::{
+        AccountEntry, AccountId, LedgerEntry, LedgerEntryExt, LedgerKeyAccount, PublicKey,
+        SequenceNumber, String32, Thresholds, Uint256,
+    };
+
+    fn account_key() -> LedgerKey {
+        LedgerKey::Account(LedgerKeyAccount {
+            account_id: AccountId(PublicKey::PublicKeyTypeEd25519(Uint256([7; 32]))),
+        })
+    }
+
+    fn account_entry() -> LedgerEntry {
+        LedgerEntry {
+            last_modified_ledger_seq: 1,
+            data: LedgerEntryData::Account(AccountEntry {
+                account_id: AccountId(PublicKey::PublicKeyTypeEd25519(Uint256([7; 32]))),
+                balance: 100,
+                seq_num: SequenceNumber(1),
+                num_sub_entries: 0,
+                inflation_dest: None,
+                flags: 0,
+                home_domain: String32::default(),
+                thresholds: Thresholds([1, 0, 0, 0]),
+                signers: vec![].try_into().unwrap(),
+                ext: soroban_env_host::xdr::AccountEntryExt::V0,
+            }),
+            ext: LedgerEntryExt::V0,
+        }
+    }
+
+    #[test]
+    fn test_snapshot_store_serves_supplied_entries() {
+        let mut snapshot = LedgerSnapshot::new();
+        snapshot.insert(account_key().to_xdr(Limits::none()).unwrap(), account_entry());
+        let store = SnapshotStore::new(snapshot);
+
+        let found = store.get(&Rc::new(account_key())).unwrap();
+        let (entry, live_until) = found.expect("entry should be served");
+        assert_eq!(*entry, account_entry());
+        assert_eq!(live_until, None, "classic entries have no TTL");
+    }
+
+    #[test]
+    fn test_missing_entry_is_none() {
+        let store = SnapshotStore::new(LedgerSnapshot::new());
+        assert!(store.get(&Rc::new(account_key())).unwrap().is_none());
+    }
+}
@@ -18,8 +18,19 @@ pub struct SimHost {
 
 #[allow(dead_code)]
 impl SimHost {
-    /// Initialize a new Host with optional budget settings and resource calibration.
+    /// Initialize a new Host with optional (cpu, mem) budget limits and resource calibration.
     pub fn new(budget_limits: Option<(u64, u64)>, calibration: Option<crate::types::ResourceCalibration>) -> Self {
+        Self::with_storage(Storage::default(), budget_limits, calibration, &[])
+    }
+
+    /// Like `new`, but the host reads and writes ledger entries through storage,
+    /// and cost_params replace the CPU cost models of the types they name.
+    pub fn with_storage(
+        storage: Storage,
+        budget_limits: Option<(u64, u64)>,
+        calibration: Option<crate::types::ResourceCalibration>,
+        cost_params: &[crate::types::CostParam],
+    ) -> Self {
         let budget = Budget::default();
         
         if let Some(calib) = calibration {
@@ -48,13 +59,32 @@ impl SimHost {
             let _ = budget.set_model(ContractCostType::VerifyEd25519Sig, ed25519_model);
         }
 
-        if let Some((_cpu, _mem)) = budget_limits {
-            // Budget customization requires testutils feature or extended API
-            // Using default mainnet budget settings
+        for param in cost_params {
+            use soroban_env_host::budget::CostModel;
+            use soroban_env_host::xdr::ContractCostType;
+
+            // Types this host does not know come from a newer model; skip them.
+            if let Some(cost_type) = ContractCostType::VARIANTS
+                .iter()
+                .find(|t| t.name() == param.cost_type)
+            {
+                let model = CostModel {
+                    const_term: param.const_term as i64,
+                    linear_term: param.linear_term as i64,
+                };
+                let _ = budget.set_model(*cost_type, model);
+            }
+        }
+
+        if let Some((cpu, mem)) = budget_limits {
+            // A zero limit keeps the default, so a request can replace only one.
+            let cpu = if cpu > 0 { cpu } else { budget.get_cpu_insns_remaining().unwrap_or(0) };
+            let mem = if mem > 0 { mem } else { budget.get_mem_bytes_remaining().unwrap_or(0) };
+            let _ = budget.reset_limits(cpu, mem);
         }
 
         // Host::with_storage_and_budget is available in recent versions
-        let host = Host::with_storage_and_budget(Storage::default(), budget);
+        let host = Host::with_storage_and_budget(storage, budget);
 
         // Enable debug mode for better diagnostics
         host.set_diagnostic_level(DiagnosticLevel::Debug)
@@ -103,6 +133,14 @@ mod tests {
         assert!(host.inner.budget_cloned().get_cpu_insns_consumed().is_ok());
     }
 
+    #[test]
+    fn test_budget_limits() {
+        let host = SimHost::new(Some((1_000_000, 0)), None);
+        let budget = host.inner.budget_cloned();
+        assert_eq!(budget.get_cpu_insns_remaining().unwrap(), 1_000_000);
+        assert!(budget.get_mem_bytes_remaining().unwrap() > 0);
+    }
+
     #[test]
     fn test_configuration() {
         let mut host = SimHost::new(None, None);
@@ -0,0 +1,207 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! `erst-sim --serve`: a long-lived worker for erst's simulator pool.
+//!
+//! Requests and replies are framed as a 4-byte big-endian payload length
+//! followed by a JSON payload. Every request frame is answered by exactly one
+//! reply frame carrying the same id; closing stdin ends the loop. Nothing else
+//! may be written to stdout while serving.
+
+use crate::types::{SimulationRequest, SimulationResponse};
+use crate::stack_trace::WasmStackTrace;
+use serde::{Deserialize, Serialize};
+use std::io::{self, BufReader, BufWriter, Read, Write};
+
+/// Bounds a single frame so a corrupted length prefix cannot make the worker
+/// allocate arbitrary amounts of memory. Matches `maxFrameSize` in pool.go.
+const MAX_FRAME_SIZE: usize = 256 << 20;
+
+#[derive(Debug, Deserialize)]
+struct Message {
+    id: u64,
+    #[serde(rename = "type")]
+    kind: String,
+    request: Option<serde_json::Value>,
+}
+
+#[derive(Debug, Serialize)]
+struct Reply {
+    id: u64,
+    #[serde(rename = "type")]
+    kind: &'static str,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    response: Option<SimulationResponse>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    error: Option<String>,
+}
+
+impl Reply {
+    fn result(id: u64, response: SimulationResponse) -> Self {
+        Self {
+            id,
+            kind: "result",
+            response: Some(response),
+            error: None,
+        }
+    }
+
+    fn error(id: u64, error: String) -> Self {
+        Self {
+            id,
+            kind: "result",
+            response: None,
+            error: Some(error),
+        }
+    }
+}
+
+/// Answers framed requests from stdin until it is closed.
+pub fn run() -> io::Result<()> {
+    let stdin = io::stdin();
+    let stdout = io::stdout();
+    let mut input = BufReader::new(stdin.lock());
+    let mut output = BufWriter::new(stdout.lock());
+
+    while let Some(payload) = read_frame(&mut input)? {
+        let reply = handle(&payload);
+        let encoded = serde_json::to_vec(&reply)
+            .map_err(|e| io::Error::new(io::ErrorKind::InvalidData, e))?;
+        write_frame(&mut output, &encoded)?;
+        output.flush()?;
+    }
+    Ok(())
+}
+
+fn handle(payload: &[u8]) -> Reply {
+    let msg: Message = match serde_json::from_slice(payload) {
+        Ok(msg) => msg,
+        Err(e) => return Reply::error(0, format!("invalid frame: {e}")),
+    };
+
+    match msg.kind.as_str() {
+        "ping" => Reply {
+            id: msg.id,
+            kind: "pong",
+            response: None,
+            error: None,
+        },
+        "simulate" => {
+            let Some(value) = msg.request else {
+                return Reply::error(msg.id, "simulate frame without a request".to_string());
+            };
+            let request: SimulationRequest = match serde_json::from_value(value) {
+                Ok(req) => req,
+                Err(e) => {
+                    return Reply::result(
+                        msg.id,
+                        SimulationResponse::failure(format!("Invalid JSON: {e}"), None),
+                    )
+                }
+            };
+            // A panic outside the host's own protection must not take the
+            // worker down with it.
+            let response = std::panic::catch_unwind(std::panic::AssertUnwindSafe(|| {
+                crate::simulate(&request)
+            }))
+            .unwrap_or_else(|panic_info| {
+                let panic_msg = panic_info
+                    .downcast_ref::<&str>()
+                    .map(|s| s.to_string())
+                    .or_else(|| panic_info.downcast_ref::<String>().cloned())
+                    .unwrap_or_else(|| "Unknown panic".to_string());
+                SimulationResponse::failure(
+                    format!("Simulator panicked: {panic_msg}"),
+                    Some(WasmStackTrace::from_panic(&panic_msg)),
+                )
+            });
+            Reply::result(msg.id, response)
+        }
+        other => Reply::error(msg.id, format!("unknown message type {other}")),
+    }
+}
+
+/// Reads one frame, returning `None` on a clean EOF before the header.
+fn read_frame<R: Read>(r: &mut R) -> io::Result<Option<Vec<u8>>> {
+    let mut header = [0u8; 4];
+    match r.read_exact(&mut header) {
+        Ok(()) => {}
+        Err(e) if e.kind() == io::ErrorKind::UnexpectedEof => return Ok(None),
+        Err(e) => return Err(e),
+    }
+
+    let size = u32::from_be_bytes(header) as usize;
+    if size > MAX_FRAME_SIZE {
+        return Err(io::Error::new(
+            io::ErrorKind::InvalidData,
+            format!("frame of {size} bytes exceeds limit of {MAX_FRAME_SIZE} bytes"),
+        ));
+    }
+
+    let mut payload = vec![0u8; size];
+    r.read_exact(&mut payload)?;
+    Ok(Some(payload))
+}
+
+fn write_frame<W: Write>(w: &mut W, payload: &[u8]) -> io::Result<()> {
+    if payload.len() > MAX_FRAME_SIZE {
+        return Err(io::Error::new(
+            io::ErrorKind::InvalidData,
+            format!(
+                "frame of {} bytes exceeds limit of {MAX_FRAME_SIZE} bytes",
+                payload.len()
+            ),
+        ));
+    }
+    w.write_all(&(payload.len() as u32).to_be_bytes())?;
+    w.write_all(payload)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn reply_json(payload: &str) -> serde_json::Value {
+        serde_json::to_value(handle(payload.as_bytes())).unwrap()
+    }
+
+    #[test]
+    fn test_frame_round_trip() {
+        let mut buf = Vec::new();
+        write_frame(&mut buf, b"{\"id\":1}").unwrap();
+        write_frame(&mut buf, b"{}").unwrap();
+
+        let mut reader = buf.as_slice();
+        assert_eq!(read_frame(&mut reader).unwrap().unwrap(), b"{\"id\":1}");
+        assert_eq!(read_frame(&mut reader).unwrap().unwrap(), b"{}");
+        assert!(read_frame(&mut reader).unwrap().is_none());
+    }
+
+    #[test]
+    fn test_oversized_frame_is_rejected() {
+        let header = ((MAX_FRAME_SIZE + 1) as u32).to_be_bytes();
+        let mut reader = &header[..];
+        assert!(read_frame(&mut reader).is_err());
+    }
+
+    #[test]
+    fn test_ping_is_answered_with_pong() {
+        let reply = reply_json(r#"{"id":7,"type":"ping"}"#);
+        assert_eq!(reply["id"], 7);
+        assert_eq!(reply["type"], "pong");
+    }
+
+    #[test]
+    fn test_bad_request_gets_error_response() {
+        let reply = reply_json(r#"{"id":3,"type":"simulate","request":{"envelope_xdr":"!!"}}"#);
+        assert_eq!(reply["id"], 3);
+        assert_eq!(reply["type"], "result");
+        assert_eq!(reply["response"]["status"], "error");
+    }
+
+    #[test]
+    fn test_unknown_message_type() {
+        let reply = reply_json(r#"{"id":4,"type":"shutdown"}"#);
+        assert!(reply["error"].as_str().unwrap().contains("unknown message type"));
+    }
+}
@@ -11,18 +11,37 @@ use std::collections::HashMap;
 #[derive(Debug, Deserialize)]
 pub struct SimulationRequest {
     pub envelope_xdr: String,
+    #[serde(default)]
     pub result_meta_xdr: String,
     pub ledger_entries: Option<HashMap<String, String>>,
     pub contract_wasm: Option<String>,
     pub wasm_path: Option<String>, // Added for local loading
+    #[serde(default)]
     pub enable_optimization_advisor: bool,
     pub profile: Option<bool>,
-    /// RFC 3339 timestamp supplied by the caller.  Preserved for future use
-    /// (e.g. time-locked contract logic); not yet consumed by the simulator.
-    #[allow(dead_code)]
-    pub timestamp: String,
+    /// Unix timestamp supplied by the caller.
+    #[serde(default)]
+    pub timestamp: i64,
     pub mock_base_fee: Option<u32>,
     pub mock_gas_price: Option<u64>,
+    pub resource_calibration: Option<ResourceCalibration>,
+    /// CPU instruction and memory limits for the host budget; see
+    /// `runner::SimHost::with_storage`.
+    #[serde(default)]
+    pub budget_limits: Option<BudgetLimits>,
+    /// CPU cost models that replace the host's defaults, from a gas model.
+    #[serde(default)]
+    pub cost_params: Vec<CostParam>,
+    /// Ledger context of the original execution, applied to the host's
+    /// ledger info; see `ledger::ledger_info`.
+    #[serde(default)]
+    pub ledger_sequence: Option<u32>,
+    #[serde(default)]
+    pub protocol_version: Option<u32>,
+    #[serde(default)]
+    pub base_reserve: Option<u32>,
+    #[serde(default)]
+    pub network_passphrase: Option<String>,
 }
 
 #[derive(Debug, Deserialize, Serialize, Clone)]
@@ -34,6 +53,23 @@ pub struct ResourceCalibration {
     pub ed25519_fixed: u64,
 }
 
+#[derive(Debug, Deserialize, Clone, Copy)]
+pub struct BudgetLimits {
+    /// Zero keeps the host's default limit.
+    #[serde(default)]
+    pub cpu_instructions: u64,
+    #[serde(default)]
+    pub memory_bytes: u64,
+}
+
+#[derive(Debug, Deserialize, Clone)]
+pub struct CostParam {
+    /// `ContractCostType` name, e.g. "WasmInsnExec".
+    pub cost_type: String,
+    pub const_term: u64,
+    pub linear_term: u64,
+}
+
 use crate::source_mapper::SourceLocation;
 
 #[derive(Debug, Serialize)]
@@ -52,6 +88,37 @@ pub struct SimulationResponse {
     #[serde(skip_serializing_if = "Option::is_none")]
     pub stack_trace: Option<WasmStackTrace>,
     pub wasm_offset: Option<u64>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub ledger_access: Option<LedgerAccess>,
+}
+
+/// Ledger keys the host read and wrote, as base64 LedgerKey XDR.
+#[derive(Debug, Default, Serialize)]
+pub struct LedgerAccess {
+    pub read: Vec<String>,
+    pub written: Vec<String>,
+}
+
+impl SimulationResponse {
+    /// Builds an error response carrying only `error` and, when known, the
+    /// trace of the failure.
+    pub fn failure(error: String, stack_trace: Option<WasmStackTrace>) -> Self {
+        Self {
+            status: "error".to_string(),
+            error: Some(error),
+            events: vec![],
+            diagnostic_events: vec![],
+            categorized_events: vec![],
+            logs: vec![],
+            flamegraph: None,
+            optimization_report: None,
+            budget_usage: None,
+            source_location: None,
+            stack_trace,
+            wasm_offset: None,
+            ledger_access: None,
+        }
+    }
 }
 
 #[derive(Debug, Serialize)]
@@ -82,6 +149,17 @@ pub struct BudgetUsage {
     pub memory_usage_percent: f64,
 }
 
+/// One line of `erst-sim --stream` output. Intermediate records are written
+/// as execution progresses; the final `Result` carries the full response.
+#[derive(Debug, Serialize)]
+#[serde(tag = "type", rename_all = "lowercase")]
+pub enum StreamRecord {
+    Event { event: DiagnosticEvent },
+    Log { log: String },
+    Budget { budget: BudgetUsage },
+    Result { response: SimulationResponse },
+}
+
 #[derive(Debug, Serialize)]
 pub struct StructuredError {
     pub error_type: String,